@@ -12,6 +12,7 @@ import (
 	"github.com/wnmay/horo/services/api-gateway/internal/messaging"
 	gw_router "github.com/wnmay/horo/services/api-gateway/internal/router"
 	"github.com/wnmay/horo/shared/env"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 type APIGateway struct {
@@ -43,7 +44,11 @@ func NewAPIGateway(cfg *config.Config) (*APIGateway, error) {
 	app.Use(cors.New())
 
 	// Initialize router
-	router := gw_router.NewRouter(app, cfg, messagingManager.RabbitMQ())
+	router, err := gw_router.NewRouter(app, cfg, messagingManager.RabbitMQ())
+	if err != nil {
+		messagingManager.Close()
+		return nil, err
+	}
 
 	return &APIGateway{
 		app:              app,
@@ -69,6 +74,7 @@ func (gw *APIGateway) Shutdown() error {
 	log.Println("Shutting down API Gateway...")
 
 	gw.messagingManager.Close()
+	gw.router.Close()
 
 	return gw.app.Shutdown()
 }
@@ -88,6 +94,11 @@ func customErrorHandler(c *fiber.Ctx, err error) error {
 
 func main() {
 	_ = env.LoadEnv(service_name)
+	jwt.InitFromEnv(service_name)
+	// Only the gateway vouches for users, with the identity private key
+	if !jwt.CanIssueIdentity() {
+		log.Fatal("IDENTITY_PRIVATE_KEY is not set")
+	}
 	cfg := config.LoadConfig()
 	// Create API Gateway
 	gateway, err := NewAPIGateway(cfg)
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testProject = "horo-test"
+	testKid     = "test-key"
+)
+
+// countingSource counts fetches from the key source it wraps
+type countingSource struct {
+	KeySource
+	fetches atomic.Int32
+}
+
+func (s *countingSource) FetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+	s.fetches.Add(1)
+	return s.KeySource.FetchKeys(ctx)
+}
+
+// newTestKeys generates a signing key and a PEMKeySource serving its public
+// half under testKid
+func newTestKeys(t *testing.T) (*rsa.PrivateKey, *countingSource) {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write public key: %v", err)
+	}
+	source, err := NewPEMKeySource(path, testKid)
+	if err != nil {
+		t.Fatalf("NewPEMKeySource() error = %v", err)
+	}
+	return private, &countingSource{KeySource: source}
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":       "https://securetoken.google.com/" + testProject,
+		"aud":       testProject,
+		"sub":       "user-1",
+		"email":     "user@example.com",
+		"role":      "customer",
+		"iat":       now.Add(-time.Minute).Unix(),
+		"auth_time": now.Add(-time.Minute).Unix(),
+		"exp":       now.Add(time.Hour).Unix(),
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestVerifierAcceptsValidToken(t *testing.T) {
+	private, source := newTestKeys(t)
+	verifier := NewVerifier(NewKeySet(source), testProject)
+
+	claims, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, testKid, validClaims(), private))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Email != "user@example.com" || claims.Role != "customer" {
+		t.Errorf("Verify() = %+v", claims)
+	}
+}
+
+func TestVerifierRejectsInvalidTokens(t *testing.T) {
+	private, source := newTestKeys(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "HS256", token: sign(t, jwt.SigningMethodHS256, testKid, validClaims(), []byte("secret")), wantErr: ErrInvalidToken},
+		{name: "RS512", token: sign(t, jwt.SigningMethodRS512, testKid, validClaims(), private), wantErr: ErrInvalidToken},
+		{name: "none", token: sign(t, jwt.SigningMethodNone, testKid, validClaims(), jwt.UnsafeAllowNoneSignatureType), wantErr: ErrInvalidToken},
+		{name: "wrong audience", token: sign(t, jwt.SigningMethodRS256, testKid, with("aud", "other-project"), private), wantErr: ErrInvalidToken},
+		{name: "wrong issuer", token: sign(t, jwt.SigningMethodRS256, testKid, with("iss", "https://securetoken.google.com/other-project"), private), wantErr: ErrInvalidToken},
+		{name: "expired", token: sign(t, jwt.SigningMethodRS256, testKid, with("exp", time.Now().Add(-time.Minute).Unix()), private), wantErr: ErrInvalidToken},
+		{name: "no expiry", token: sign(t, jwt.SigningMethodRS256, testKid, with("exp", nil), private), wantErr: ErrInvalidToken},
+		{name: "issued in the future", token: sign(t, jwt.SigningMethodRS256, testKid, with("iat", time.Now().Add(time.Hour).Unix()), private), wantErr: ErrInvalidToken},
+		{name: "authenticated in the future", token: sign(t, jwt.SigningMethodRS256, testKid, with("auth_time", time.Now().Add(time.Hour).Unix()), private), wantErr: ErrInvalidToken},
+		{name: "no subject", token: sign(t, jwt.SigningMethodRS256, testKid, with("sub", nil), private), wantErr: ErrInvalidToken},
+		{name: "wrong key", token: sign(t, jwt.SigningMethodRS256, testKid, validClaims(), other), wantErr: ErrInvalidToken},
+		{name: "unknown kid", token: sign(t, jwt.SigningMethodRS256, "other-key", validClaims(), private), wantErr: ErrInvalidToken},
+		{name: "malformed", token: "not.a.token", wantErr: ErrInvalidToken},
+	}
+	verifier := NewVerifier(NewKeySet(source), testProject)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() = %+v, %v, want %v", claims, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeySetThrottlesUnknownKeyRefresh(t *testing.T) {
+	_, source := newTestKeys(t)
+	keys := NewKeySet(source)
+	ctx := context.Background()
+
+	if _, err := keys.Key(ctx, testKid); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := keys.Key(ctx, "other-key"); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("Key(other-key) error = %v, want ErrUnknownKey", err)
+		}
+	}
+	if got := source.fetches.Load(); got != 1 {
+		t.Errorf("fetched %d times within MinKeyRefreshInterval, want 1", got)
+	}
+
+	// Once the interval has passed an unknown key refetches again
+	keys.mu.Lock()
+	keys.lastFetched = time.Now().Add(-MinKeyRefreshInterval)
+	keys.mu.Unlock()
+	if _, err := keys.Key(ctx, "other-key"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(other-key) error = %v, want ErrUnknownKey", err)
+	}
+	if got := source.fetches.Load(); got != 2 {
+		t.Errorf("fetched %d times after MinKeyRefreshInterval, want 2", got)
+	}
+
+	// A known key is served from the cache
+	if _, err := keys.Key(ctx, testKid); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if got := source.fetches.Load(); got != 2 {
+		t.Errorf("fetched %d times for a cached key, want 2", got)
+	}
+}
+
+func TestKeySetRefetchesExpiredKeys(t *testing.T) {
+	_, source := newTestKeys(t)
+	keys := NewKeySet(source)
+	ctx := context.Background()
+
+	if _, err := keys.Key(ctx, testKid); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	keys.mu.Lock()
+	keys.expiresAt = time.Now().Add(-time.Second)
+	keys.mu.Unlock()
+
+	if _, err := keys.Key(ctx, testKid); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if got := source.fetches.Load(); got != 2 {
+		t.Errorf("fetched %d times, want 2", got)
+	}
+}
+
+func TestClaimCache(t *testing.T) {
+	claims := &Claims{UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("hit", func(t *testing.T) {
+		cache := NewClaimCache(time.Minute, 10)
+		cache.Put("token", claims)
+		if got, ok := cache.Get("token"); !ok || got != claims {
+			t.Errorf("Get() = %v, %v", got, ok)
+		}
+		if _, ok := cache.Get("other"); ok {
+			t.Error("Get(other) hit")
+		}
+	})
+
+	t.Run("ttl expiry", func(t *testing.T) {
+		cache := NewClaimCache(time.Minute, 10)
+		cache.Put("token", claims)
+		for key, entry := range cache.entries {
+			entry.expiresAt = time.Now().Add(-time.Second)
+			cache.entries[key] = entry
+		}
+		if _, ok := cache.Get("token"); ok {
+			t.Error("Get() hit after the TTL")
+		}
+		if len(cache.entries) != 0 {
+			t.Errorf("expired entry kept, %d entries", len(cache.entries))
+		}
+	})
+
+	t.Run("token expiry", func(t *testing.T) {
+		cache := NewClaimCache(time.Hour, 10)
+		expiring := &Claims{UserID: "user-1", ExpiresAt: time.Now().Add(time.Minute)}
+		cache.Put("token", expiring)
+		for _, entry := range cache.entries {
+			if entry.expiresAt.After(expiring.ExpiresAt) {
+				t.Errorf("cached until %s, after the token expires at %s", entry.expiresAt, expiring.ExpiresAt)
+			}
+		}
+
+		cache.Put("expired", &Claims{UserID: "user-1", ExpiresAt: time.Now().Add(-time.Second)})
+		if _, ok := cache.Get("expired"); ok {
+			t.Error("Get() hit for an expired token")
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		cache := NewClaimCache(time.Minute, 2)
+		cache.Put("a", claims)
+		cache.Put("b", claims)
+		cache.Put("c", claims)
+		if len(cache.entries) > 2 {
+			t.Errorf("%d entries, want at most 2", len(cache.entries))
+		}
+		if _, ok := cache.Get("c"); !ok {
+			t.Error("Get() missed the newest entry")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// ClaimCache remembers the claims of recently verified tokens for a short
+// while, so a client's burst of requests verifies its token once
+type ClaimCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cachedClaims
+}
+
+type cachedClaims struct {
+	claims    *Claims
+	expiresAt time.Time
+}
+
+func NewClaimCache(ttl time.Duration, maxEntries int) *ClaimCache {
+	return &ClaimCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[[sha256.Size]byte]cachedClaims),
+	}
+}
+
+func (c *ClaimCache) Get(token string) (*Claims, bool) {
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.claims, true
+}
+
+// Put caches a token's claims for the cache's TTL, or until the token
+// expires if that is sooner
+func (c *ClaimCache) Put(token string, claims *Claims) {
+	now := time.Now()
+	expiresAt := now.Add(c.ttl)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expiresAt) {
+		expiresAt = claims.ExpiresAt
+	}
+	if !now.Before(expiresAt) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		c.evictExpired(now)
+	}
+	if len(c.entries) >= c.maxEntries {
+		// Still full of live entries; start over rather than track recency
+		clear(c.entries)
+	}
+	c.entries[sha256.Sum256([]byte(token))] = cachedClaims{claims: claims, expiresAt: expiresAt}
+}
+
+func (c *ClaimCache) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoogleJWKSURL serves the keys Firebase signs ID tokens with
+const GoogleJWKSURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+// KeySource fetches the public keys ID tokens are signed with, by key ID,
+// and how long they may be cached for; zero leaves that to the KeySet
+type KeySource interface {
+	FetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error)
+}
+
+// JWKSSource fetches keys from a JSON Web Key Set URL
+type JWKSSource struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewJWKSSource(url string) *JWKSSource {
+	return &JWKSSource{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// FetchKeys reads the RSA keys in the set, cached for as long as the
+// response's Cache-Control max-age allows
+func (s *JWKSSource) FetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch keys: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("key set returned %d", res.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, 0, fmt.Errorf("failed to read keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		key, err := rsaKey(k.N, k.E)
+		if err != nil {
+			return nil, 0, fmt.Errorf("key %s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, 0, errors.New("key set has no RSA keys")
+	}
+	return keys, maxAge(res.Header.Get("Cache-Control")), nil
+}
+
+func rsaKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent too large")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
+
+// StaticKeySource serves a fixed set of keys, such as a local signing key
+// used in development and tests instead of Firebase's
+type StaticKeySource struct {
+	keys map[string]*rsa.PublicKey
+}
+
+func NewStaticKeySource(keys map[string]*rsa.PublicKey) *StaticKeySource {
+	return &StaticKeySource{keys: keys}
+}
+
+// NewPEMKeySource serves the RSA public key in a PEM file under kid
+func NewPEMKeySource(path, kid string) (*StaticKeySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	var parsed any
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		parsed = cert.PublicKey
+	default:
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+	return NewStaticKeySource(map[string]*rsa.PublicKey{kid: key}), nil
+}
+
+func (s *StaticKeySource) FetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+	return s.keys, 0, nil
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultKeyTTL is how long keys are kept when their source does not say
+	DefaultKeyTTL = time.Hour
+	// MinKeyRefreshInterval stops tokens naming unknown keys from making the
+	// key set refetch more often than this
+	MinKeyRefreshInterval = time.Minute
+)
+
+var (
+	ErrUnknownKey      = errors.New("token is signed with an unknown key")
+	ErrKeysUnavailable = errors.New("signing keys are unavailable")
+)
+
+// KeySet caches a KeySource's keys. It refetches them once they expire, or
+// when a token names a key it does not have, and keeps serving the keys it
+// has if the source cannot be reached.
+type KeySet struct {
+	source KeySource
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	expiresAt   time.Time
+	lastFetched time.Time
+}
+
+func NewKeySet(source KeySource) *KeySet {
+	return &KeySet{source: source}
+}
+
+// Key returns the key a token was signed with
+func (s *KeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	fresh := time.Now().Before(s.expiresAt)
+	s.mu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+
+	if err := s.refresh(ctx, !ok); err != nil {
+		if ok {
+			log.Printf("[KeySet] using expired keys: %v\n", err)
+			return key, nil
+		}
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// Refresh refetches the keys
+func (s *KeySet) Refresh(ctx context.Context) error {
+	return s.refresh(ctx, false)
+}
+
+// StartRefresh refreshes the keys every interval until ctx is done, so
+// requests rarely wait on a fetch
+func (s *KeySet) StartRefresh(ctx context.Context, interval time.Duration) {
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("[KeySet] initial key fetch failed: %v\n", err)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Refresh(ctx); err != nil {
+					log.Printf("[KeySet] key refresh failed: %v\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// refresh refetches the keys unless they are still fresh. Lookups for an
+// unknown key refetch even fresh keys, but no more than once per
+// MinKeyRefreshInterval.
+func (s *KeySet) refresh(ctx context.Context, unknownKey bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if unknownKey {
+		if now.Sub(s.lastFetched) < MinKeyRefreshInterval {
+			if s.keys == nil {
+				return ErrKeysUnavailable
+			}
+			return ErrUnknownKey
+		}
+	} else if s.keys != nil && now.Before(s.expiresAt) {
+		// Another request refreshed them while this one waited for the lock
+		return nil
+	}
+
+	s.lastFetched = now
+	keys, ttl, err := s.source.FetchKeys(ctx)
+	if err != nil {
+		return errors.Join(ErrKeysUnavailable, err)
+	}
+	if ttl <= 0 {
+		ttl = DefaultKeyTTL
+	}
+	s.keys = keys
+	s.expiresAt = now.Add(ttl)
+	return nil
+}
@@ -0,0 +1,86 @@
+package http_handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/shared/env"
+)
+
+// AdminHandler serves back-office actions that span more than one service
+type AdminHandler struct {
+	userManagementURL string
+	courseServiceURL  string
+	client            *http.Client
+}
+
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{
+		userManagementURL: env.GetString("USER_MANAGEMENT_SERVICE_URL", "http://localhost:3003"),
+		courseServiceURL:  env.GetString("COURSE_SERVICE_URL", "http://localhost:3005"),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// SuspendProphet suspends a prophet in user-management, which takes them
+// offline and requires a reason, then hides their courses
+func (h *AdminHandler) SuspendProphet(c *fiber.Ctx) error {
+	return h.setProphetSuspended(c, "suspend")
+}
+
+// ReinstateProphet lifts a prophet's suspension and lists their courses again
+func (h *AdminHandler) ReinstateProphet(c *fiber.Ctx) error {
+	return h.setProphetSuspended(c, "reinstate")
+}
+
+// setProphetSuspended applies action in user-management, then course-service,
+// and answers with the user. Both calls are idempotent, so an admin retries a
+// request that failed part way.
+func (h *AdminHandler) setProphetSuspended(c *fiber.Ctx, action string) error {
+	prophetID := c.Params("id")
+
+	status, body, err := h.call(c, h.userManagementURL, fmt.Sprintf("/api/users/prophets/%s/%s", prophetID, action))
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": fmt.Sprintf("failed to reach user management service: %v", err),
+		})
+	}
+	if status >= fiber.StatusBadRequest {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Status(status).Send(body)
+	}
+
+	courseStatus, _, err := h.call(c, h.courseServiceURL, fmt.Sprintf("/api/prophets/%s/%s", prophetID, action))
+	if err != nil || courseStatus >= fiber.StatusBadRequest {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": fmt.Sprintf("prophet %s was updated but their courses were not; retry the %s", prophetID, action),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(status).Send(body)
+}
+
+// call POSTs the caller's request to a service and returns its response
+func (h *AdminHandler) call(c *fiber.Ctx, serviceURL, path string) (int, []byte, error) {
+	req, err := newServiceRequest(c, "POST", serviceURL, path)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
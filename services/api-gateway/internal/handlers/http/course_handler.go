@@ -52,6 +52,34 @@ func (h *CourseHandler) GetReviewByID(c *fiber.Ctx) error {
 	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s", c.Params("id")))
 }
 
+func (h *CourseHandler) UpdateReview(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "PATCH", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s", c.Params("id")))
+}
+
+func (h *CourseHandler) DeleteReview(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "DELETE", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s", c.Params("id")))
+}
+
+func (h *CourseHandler) ReplyToReview(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s/reply", c.Params("id")))
+}
+
+func (h *CourseHandler) FlagReview(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s/flag", c.Params("id")))
+}
+
+func (h *CourseHandler) ListReviewModerationQueue(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, "/api/courses/reviews/moderation")
+}
+
+func (h *CourseHandler) HideReview(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s/hide", c.Params("id")))
+}
+
+func (h *CourseHandler) RestoreReview(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.courseServiceURL, fmt.Sprintf("/api/courses/review/%s/restore", c.Params("id")))
+}
+
 func (h *CourseHandler) ListReviewsByCourse(c *fiber.Ctx) error {
 	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, fmt.Sprintf("/api/courses/%s/reviews", c.Params("courseId")))
 }
@@ -63,3 +91,27 @@ func (h *CourseHandler) ListCurrentProphetCourses(c *fiber.Ctx) error {
 func (h *CourseHandler) ListPopularCourses(c *fiber.Ctx) error {
 	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, "/api/courses/popular")
 }
+
+func (h *CourseHandler) ListTrendingCourses(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, "/api/courses/trending")
+}
+
+func (h *CourseHandler) ListRecommendedCourses(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, "/api/courses/recommended")
+}
+
+func (h *CourseHandler) SetAvailability(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "PUT", h.courseServiceURL, "/api/courses/availability")
+}
+
+func (h *CourseHandler) GetAvailability(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, fmt.Sprintf("/api/courses/availability/%s", c.Params("prophetId")))
+}
+
+func (h *CourseHandler) ListAvailableSlots(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, fmt.Sprintf("/api/courses/%s/slots", c.Params("id")))
+}
+
+func (h *CourseHandler) ListCurrentProphetBookings(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.courseServiceURL, "/api/courses/bookings/prophet")
+}
@@ -64,3 +64,86 @@ func (h *OrderHandler) MarkProphetCompleted(c *fiber.Ctx) error {
 	id := c.Params("id")
 	return ProxyRequest(c, h.client, "PATCH", h.orderServiceURL, fmt.Sprintf("/api/orders/prophet/%s", id))
 }
+
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "PATCH", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/cancel", id))
+}
+
+func (h *OrderHandler) GetOrderPayment(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/payment", id))
+}
+
+func (h *OrderHandler) RescheduleOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "PATCH", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/reschedule", id))
+}
+
+func (h *OrderHandler) BookNextSession(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/sessions", id))
+}
+
+func (h *OrderHandler) OpenDispute(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/dispute", id))
+}
+
+func (h *OrderHandler) ResolveDispute(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/dispute/resolve", id))
+}
+
+func (h *OrderHandler) CreatePromotion(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, "/api/orders/promotions")
+}
+
+func (h *OrderHandler) ListPromotions(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, "/api/orders/promotions")
+}
+
+func (h *OrderHandler) DeactivatePromotion(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, fmt.Sprintf("/api/orders/promotions/%s/deactivate", id))
+}
+
+func (h *OrderHandler) Subscribe(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, "/api/orders/subscriptions")
+}
+
+func (h *OrderHandler) ListSubscriptions(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, "/api/orders/subscriptions")
+}
+
+func (h *OrderHandler) GetSubscription(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, fmt.Sprintf("/api/orders/subscriptions/%s", id))
+}
+
+func (h *OrderHandler) CancelSubscription(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, fmt.Sprintf("/api/orders/subscriptions/%s/cancel", id))
+}
+
+func (h *OrderHandler) ForceOrderStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/force-status", id))
+}
+
+func (h *OrderHandler) GetOrderAudit(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, fmt.Sprintf("/api/orders/%s/audit", id))
+}
+
+func (h *OrderHandler) GetDashboard(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, "/api/orders/dashboard")
+}
+
+func (h *OrderHandler) ListFailedOutboxMessages(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.orderServiceURL, "/api/orders/outbox/failed")
+}
+
+func (h *OrderHandler) ReplayOutboxMessages(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.orderServiceURL, "/api/orders/outbox/replay")
+}
@@ -43,3 +43,79 @@ func (h *PaymentHandler) CompletePayment(c *fiber.Ctx) error {
 func (h *PaymentHandler) GetProphetBalance(c *fiber.Ctx) error {
 	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, fmt.Sprintf("/api/payments/balance"))
 }
+
+func (h *PaymentHandler) CreateCheckoutSession(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/%s/checkout", id))
+}
+
+func (h *PaymentHandler) GetWebhookHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, fmt.Sprintf("/api/payments/%s/webhooks", id))
+}
+
+// ProviderWebhook forwards provider callbacks untouched so the signature over
+// the raw body and its header still verify downstream
+func (h *PaymentHandler) ProviderWebhook(c *fiber.Ctx) error {
+	provider := c.Params("provider")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/webhooks/%s", provider))
+}
+
+func (h *PaymentHandler) GetLedgerStatement(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, "/api/payments/ledger")
+}
+
+func (h *PaymentHandler) ListPayouts(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, "/api/payments/payouts")
+}
+
+func (h *PaymentHandler) RequestPayout(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, "/api/payments/payouts")
+}
+
+func (h *PaymentHandler) ApprovePayout(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/payouts/%s/approve", id))
+}
+
+func (h *PaymentHandler) RejectPayout(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/payouts/%s/reject", id))
+}
+
+func (h *PaymentHandler) MarkPayoutPaid(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/payouts/%s/paid", id))
+}
+
+func (h *PaymentHandler) CancelPayout(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/payouts/%s/cancel", id))
+}
+
+func (h *PaymentHandler) ListFeeRules(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, "/api/payments/fee-rules")
+}
+
+func (h *PaymentHandler) CreateFeeRule(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, "/api/payments/fee-rules")
+}
+
+func (h *PaymentHandler) EndFeeRule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/fee-rules/%s/end", id))
+}
+
+func (h *PaymentHandler) ListPayments(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, "/api/payments/")
+}
+
+func (h *PaymentHandler) ForcePaymentStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "POST", h.paymentServiceURL, fmt.Sprintf("/api/payments/%s/force-status", id))
+}
+
+func (h *PaymentHandler) GetPaymentAudit(c *fiber.Ctx) error {
+	id := c.Params("id")
+	return ProxyRequest(c, h.client, "GET", h.paymentServiceURL, fmt.Sprintf("/api/payments/%s/audit", id))
+}
@@ -8,37 +8,27 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
-func ProxyRequest(c *fiber.Ctx, client *http.Client, method, serviceURL, path string) error {
-	// Build target URL
-	targetURL := serviceURL + path
+// serviceAudiences names the service behind each service URL; it is set once
+// at startup by SetServiceAudiences
+var serviceAudiences map[string]string
 
-	// Get query parameters
-	query := c.Request().URI().QueryString()
-	if len(query) > 0 {
-		targetURL += "?" + string(query)
-	}
-
-	// Create request body
-	var body io.Reader
-	if method == "POST" || method == "PUT" || method == "PATCH" {
-		body = bytes.NewReader(c.Body())
-	}
+// SetServiceAudiences names the service behind each service URL, so the
+// identity assertions sent to it are addressed to that service alone
+func SetServiceAudiences(audiences map[string]string) {
+	serviceAudiences = audiences
+}
 
-	// Create HTTP request
-	req, err := http.NewRequest(method, targetURL, body)
+func ProxyRequest(c *fiber.Ctx, client *http.Client, method, serviceURL, path string) error {
+	req, err := newServiceRequest(c, method, serviceURL, path)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to create request",
 		})
 	}
 
-	// Copy headers (including Authorization for auth)
-	c.Request().Header.VisitAll(func(key, value []byte) {
-		req.Header.Set(string(key), string(value))
-	})
-
 	// Make request
 	resp, err := client.Do(req)
 	if err != nil {
@@ -75,3 +65,50 @@ func ProxyRequest(c *fiber.Ctx, client *http.Client, method, serviceURL, path st
 
 	return c.Send(respBody)
 }
+
+// newServiceRequest builds a request to a service carrying the caller's
+// request body, query string and headers
+func newServiceRequest(c *fiber.Ctx, method, serviceURL, path string) (*http.Request, error) {
+	// Build target URL
+	targetURL := serviceURL + path
+
+	// Get query parameters
+	query := c.Request().URI().QueryString()
+	if len(query) > 0 {
+		targetURL += "?" + string(query)
+	}
+
+	// Create request body
+	var body io.Reader
+	if method == "POST" || method == "PUT" || method == "PATCH" {
+		body = bytes.NewReader(c.Body())
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequest(method, targetURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy headers. Services are told who the caller is by a signed identity
+	// assertion, not the token the caller sent, which is not passed on.
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		req.Header.Set(string(key), string(value))
+	})
+	req.Header.Del("Authorization")
+	req.Header.Del(jwt.HeaderIdentity)
+	if userID, _ := c.Locals("userId").(string); userID != "" {
+		email, _ := c.Locals("userEmail").(string)
+		role, _ := c.Locals("userRole").(string)
+		audience, ok := serviceAudiences[serviceURL]
+		if !ok {
+			return nil, fmt.Errorf("no service is known at %s", serviceURL)
+		}
+		assertion, err := jwt.IssueIdentity(jwt.Identity{UserID: userID, Email: email, Role: role}, audience)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(jwt.HeaderIdentity, assertion)
+	}
+	return req, nil
+}
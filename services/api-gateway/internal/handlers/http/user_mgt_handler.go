@@ -234,3 +234,58 @@ func (h *UserHandler) UpdateUsername(c *fiber.Ctx) error {
 
 	return c.Status(http.StatusOK).JSON(result)
 }
+
+func (h *UserHandler) GetProphetProfile(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "GET", h.userManagementURL, fmt.Sprintf("/api/users/prophets/%s/profile", c.Params("id")))
+}
+
+// UpdateProphetProfile edits the profile of the prophet making the request
+func (h *UserHandler) UpdateProphetProfile(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "missing user ID",
+		})
+	}
+	return ProxyRequest(c, h.httpClient, "PATCH", h.userManagementURL, fmt.Sprintf("/api/users/%s/profile", userID))
+}
+
+// SetOnlineStatus sets whether the prophet making the request is online
+func (h *UserHandler) SetOnlineStatus(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "missing user ID",
+		})
+	}
+	return ProxyRequest(c, h.httpClient, "PUT", h.userManagementURL, fmt.Sprintf("/api/users/%s/online", userID))
+}
+
+func (h *UserHandler) SetProphetVerified(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "PUT", h.userManagementURL, fmt.Sprintf("/api/users/prophets/%s/verified", c.Params("id")))
+}
+
+// SearchUsers finds users by name or email, role and suspension; admins only
+func (h *UserHandler) SearchUsers(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "GET", h.userManagementURL, "/api/users")
+}
+
+// SetUserRole changes a user's role; admins only
+func (h *UserHandler) SetUserRole(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "PUT", h.userManagementURL, fmt.Sprintf("/api/users/%s/role", c.Params("id")))
+}
+
+// IssueToken exchanges a Firebase ID token for a platform token pair
+func (h *UserHandler) IssueToken(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "POST", h.userManagementURL, "/api/auth/token")
+}
+
+// RefreshToken rotates a refresh token into a new token pair
+func (h *UserHandler) RefreshToken(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "POST", h.userManagementURL, "/api/auth/refresh")
+}
+
+// RevokeToken signs a refresh token's session out
+func (h *UserHandler) RevokeToken(c *fiber.Ctx) error {
+	return ProxyRequest(c, h.httpClient, "POST", h.userManagementURL, "/api/auth/revoke")
+}
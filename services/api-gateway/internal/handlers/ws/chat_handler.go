@@ -13,6 +13,7 @@ import (
 	"github.com/wnmay/horo/services/api-gateway/internal/messaging/publishers"
 	gwWS "github.com/wnmay/horo/services/api-gateway/internal/websocket"
 	"github.com/wnmay/horo/shared/env"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 type ChatWSHandler struct {
@@ -129,8 +130,14 @@ func (h *ChatWSHandler) validateAndJoinRoom(roomID, userID string, conn *gwWS.Co
 		return false
 	}
 	req = req.WithContext(context.Background())
+	assertion, err := jwt.IssueIdentity(jwt.Identity{UserID: userID}, jwt.ServiceChat)
+	if err != nil {
+		log.Printf("[ws] issue identity error: %v", err)
+		_ = conn.Send([]byte(`{"error":"validation request error"}`))
+		return false
+	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-User-Id", userID)
+	req.Header.Set(jwt.HeaderIdentity, assertion)
 
 	res, err := h.httpClient.Do(req)
 	if err != nil {
@@ -1,18 +1,27 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/services/api-gateway/internal/auth"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 type AuthMiddleware struct {
 	authServiceAddr string
+	verifier        *auth.Verifier
+	cache           *auth.ClaimCache
+	httpClient      *http.Client
 }
 
 type AuthResponse struct {
@@ -21,9 +30,15 @@ type AuthResponse struct {
 	Role   string `json:"role"`
 }
 
-func NewAuthMiddleware(authServiceAddr string) *AuthMiddleware {
+// NewAuthMiddleware accepts platform access tokens, and Firebase ID tokens it
+// verifies locally with verifier. The auth service is only asked for the
+// roles of users whose ID tokens carry none.
+func NewAuthMiddleware(authServiceAddr string, verifier *auth.Verifier, cache *auth.ClaimCache) *AuthMiddleware {
 	return &AuthMiddleware{
 		authServiceAddr: authServiceAddr,
+		verifier:        verifier,
+		cache:           cache,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
@@ -41,59 +56,93 @@ func (a *AuthMiddleware) AddClaims(c *fiber.Ctx) error {
 		})
 	}
 
-	url := fmt.Sprintf("%s/api/auth/verify-token?token=%s", a.authServiceAddr, token)
-	res, err := http.Get(url)
+	claims, err := a.claims(c.Context(), token)
 	if err != nil {
-		log.Printf("[AuthMiddleware] error calling auth service: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "failed to contact auth service",
-			"details": err.Error(),
-		})
+		return verifyError(c, err)
+	}
+
+	// Set claims for downstream handlers
+	c.Request().Header.Set("X-User-Id", claims.UserID)
+	c.Request().Header.Set("X-User-Email", claims.Email)
+	c.Request().Header.Set("X-User-Role", claims.Role)
+
+	c.Locals("userId", claims.UserID)
+	c.Locals("userEmail", claims.Email)
+	c.Locals("userRole", claims.Role)
+
+	return c.Next()
+}
+
+// claims verifies a token, or reuses its claims if it was verified recently
+func (a *AuthMiddleware) claims(ctx context.Context, token string) (*auth.Claims, error) {
+	if claims, ok := a.cache.Get(token); ok {
+		return claims, nil
+	}
+
+	// Platform access tokens are signed by user-management alone
+	if platform, err := jwt.ValidateAccessToken(token); err == nil {
+		return &auth.Claims{
+			UserID:    platform.UserID,
+			Email:     platform.Email,
+			Role:      platform.Role,
+			ExpiresAt: platform.ExpiresAt.Time,
+		}, nil
+	}
+
+	claims, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Email == "" {
+		return nil, fmt.Errorf("%w: missing email", auth.ErrInvalidToken)
+	}
+	// Users who registered before roles were added to token claims get theirs
+	// from the auth service, which also adds it to their next token
+	if claims.Role == "" {
+		role, err := a.fetchRole(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		claims.Role = role
+	}
+
+	a.cache.Put(token, claims)
+	return claims, nil
+}
+
+func (a *AuthMiddleware) fetchRole(ctx context.Context, token string) (string, error) {
+	url := fmt.Sprintf("%s/api/auth/verify-token?token=%s", a.authServiceAddr, neturl.QueryEscape(token))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to contact auth service: %w", err)
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(res.Body)
 		log.Printf("[AuthMiddleware] auth service returned %d: %s\n", res.StatusCode, string(bodyBytes))
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid or expired token",
-		})
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "failed to read auth service response",
-			"details": err.Error(),
-		})
+		return "", auth.ErrInvalidToken
 	}
 
 	var authResponse AuthResponse
-	if err := json.Unmarshal(body, &authResponse); err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "failed to unmarshal auth response",
-			"details": err.Error(),
-		})
+	if err := json.NewDecoder(res.Body).Decode(&authResponse); err != nil {
+		return "", fmt.Errorf("failed to read auth service response: %w", err)
 	}
+	return authResponse.Role, nil
+}
 
-	// Validate contents of the auth response
-	if authResponse.UserID == "" || authResponse.Email == "" {
+func verifyError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrUnknownKey) {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "failed to authenticate",
-			"details": string(body),
+			"error": "Invalid or expired token",
 		})
 	}
-
-	// Set claims for downstream handlers
-	c.Request().Header.Set("X-User-Id", authResponse.UserID)
-	c.Request().Header.Set("X-User-Email", authResponse.Email)
-	c.Request().Header.Set("X-User-Role", authResponse.Role)
-
-	c.Locals("userId", authResponse.UserID)
-	c.Locals("userEmail", authResponse.Email)
-	c.Locals("userRole", authResponse.Role)
-
-	log.Printf("[AuthMiddleware] Auth OK: %+v\n", authResponse)
-
-	return c.Next()
+	log.Printf("[AuthMiddleware] failed to verify token: %v\n", err)
+	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+		"error": "failed to verify token",
+	})
 }
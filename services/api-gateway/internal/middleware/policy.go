@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	RoleCustomer = "customer"
+	RoleProphet  = "prophet"
+	RoleAdmin    = "admin"
+)
+
+// OwnerCheck reports whether the user owns the resource a request is for,
+// usually by looking it up in the service it lives in
+type OwnerCheck func(c *fiber.Ctx, userID string) (bool, error)
+
+// Policy is who may call a route: users with one of Roles, or any signed-in
+// user when Roles is empty, who also own the resource when Owner is set.
+// Admins skip ownership checks but still need a listed role.
+type Policy struct {
+	Roles []string
+	Owner OwnerCheck
+}
+
+// Authorize enforces a policy; it runs after AddClaims, which sets the user
+// it checks. Requests the policy refuses get 403.
+func Authorize(p Policy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("userId").(string)
+		role, _ := c.Locals("userRole").(string)
+		if userID == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "failed to authenticate",
+			})
+		}
+
+		if len(p.Roles) > 0 && !slices.Contains(p.Roles, role) {
+			return forbidden(c)
+		}
+		if p.Owner != nil && role != RoleAdmin {
+			owns, err := p.Owner(c, userID)
+			if err != nil {
+				log.Printf("[Authorize] ownership check failed for %s %s: %v\n", c.Method(), c.Path(), err)
+				return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+					"error": "failed to check access",
+				})
+			}
+			if !owns {
+				return forbidden(c)
+			}
+		}
+		return c.Next()
+	}
+}
+
+func forbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": "you do not have access to this resource",
+	})
+}
@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/services/api-gateway/internal/middleware"
+	"github.com/wnmay/horo/shared/jwt"
+)
+
+// Policies for routes any user with the right role may call
+var (
+	signedIn  = middleware.Policy{}
+	customers = middleware.Policy{Roles: []string{middleware.RoleCustomer}}
+	prophets  = middleware.Policy{Roles: []string{middleware.RoleProphet}}
+	admins    = middleware.Policy{Roles: []string{middleware.RoleAdmin}}
+	// Prophets manage their own promotions and payouts; admins all of them
+	prophetsAndAdmins    = middleware.Policy{Roles: []string{middleware.RoleProphet, middleware.RoleAdmin}}
+	customersAndProphets = middleware.Policy{Roles: []string{middleware.RoleCustomer, middleware.RoleProphet}}
+)
+
+// guard authenticates a route's caller and enforces its policy before the
+// handler runs
+func (r *Router) guard(p middleware.Policy, handler fiber.Handler) []fiber.Handler {
+	return []fiber.Handler{r.authMiddleware.AddClaims, middleware.Authorize(p), handler}
+}
+
+// self lets users reach routes whose param is their own user ID
+func self(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		return c.Params(param) == userID, nil
+	}}
+}
+
+// asUser is the context ownership lookups are made in, on the user's behalf,
+// so the services answering them check what the user may see
+func asUser(c *fiber.Ctx, userID string) context.Context {
+	email, _ := c.Locals("userEmail").(string)
+	role, _ := c.Locals("userRole").(string)
+	return jwt.WithIdentity(c.Context(), &jwt.Identity{UserID: userID, Email: email, Role: role})
+}
+
+// orderParty lets the customer and prophet of the order in param through
+func (r *Router) orderParty(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		parties, err := r.ownership.OrderParties(asUser(c, userID), c.Params(param))
+		return parties.Includes(userID), err
+	}}
+}
+
+func (r *Router) orderCustomer(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		parties, err := r.ownership.OrderParties(asUser(c, userID), c.Params(param))
+		return parties.CustomerID == userID, err
+	}}
+}
+
+func (r *Router) orderProphet(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		parties, err := r.ownership.OrderParties(asUser(c, userID), c.Params(param))
+		return parties.ProphetID == userID, err
+	}}
+}
+
+// paymentParty lets the customer and prophet of the order a payment is for
+// through
+func (r *Router) paymentParty(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		parties, err := r.ownership.PaymentParties(asUser(c, userID), c.Params(param))
+		return parties.Includes(userID), err
+	}}
+}
+
+func (r *Router) paymentCustomer(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		parties, err := r.ownership.PaymentParties(asUser(c, userID), c.Params(param))
+		return parties.CustomerID == userID, err
+	}}
+}
+
+// courseOwner lets the prophet who created the course in param through
+func (r *Router) courseOwner(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		prophetID, err := r.ownership.CourseProphet(asUser(c, userID), c.Params(param))
+		return prophetID == userID, err
+	}}
+}
+
+// roomMember lets the members of the chat room in param through
+func (r *Router) roomMember(param string) middleware.Policy {
+	return middleware.Policy{Owner: func(c *fiber.Ctx, userID string) (bool, error) {
+		return r.ownership.InRoom(c.Context(), userID, c.Params(param))
+	}}
+}
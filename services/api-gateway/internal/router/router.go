@@ -2,13 +2,18 @@
 package router
 
 import (
+	"context"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
+	"github.com/wnmay/horo/services/api-gateway/internal/auth"
 	"github.com/wnmay/horo/services/api-gateway/internal/config"
 	http_handler "github.com/wnmay/horo/services/api-gateway/internal/handlers/http"
 	ws_handler "github.com/wnmay/horo/services/api-gateway/internal/handlers/ws"
 	"github.com/wnmay/horo/services/api-gateway/internal/messaging/publishers"
 	"github.com/wnmay/horo/services/api-gateway/internal/middleware"
+	grpc_connection "github.com/wnmay/horo/services/api-gateway/internal/services/grpc"
+	"github.com/wnmay/horo/services/api-gateway/internal/services/ownership"
 	gwWS "github.com/wnmay/horo/services/api-gateway/internal/websocket"
 	"github.com/wnmay/horo/shared/message"
 )
@@ -18,15 +23,53 @@ type Router struct {
 	rmq            *message.RabbitMQ
 	hub            *gwWS.Hub
 	authMiddleware *middleware.AuthMiddleware
+	connections    *grpc_connection.ConnectionManager
+	ownership      *ownership.Lookup
+	stopKeyRefresh context.CancelFunc
 }
 
-func NewRouter(app *fiber.App, cfg *config.Config, rmq *message.RabbitMQ) *Router {
+func NewRouter(app *fiber.App, cfg *config.Config, rmq *message.RabbitMQ) (*Router, error) {
+	keySource, err := newKeySource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	keys := auth.NewKeySet(keySource)
+	ctx, stopKeyRefresh := context.WithCancel(context.Background())
+	keys.StartRefresh(ctx, cfg.KeyRefreshInterval)
+	authMiddleware := middleware.NewAuthMiddleware(
+		cfg.UserManagementServiceURL,
+		auth.NewVerifier(keys, cfg.FirebaseProjectID),
+		auth.NewClaimCache(cfg.ClaimCacheTTL, cfg.ClaimCacheSize),
+	)
+
+	http_handler.SetServiceAudiences(cfg.ServiceAudiences())
+
+	connections := grpc_connection.NewConnectionManager()
+	lookup, err := ownership.NewLookup(connections, cfg.OrderAddr, cfg.CourseAddr, cfg.PaymentAddr, cfg.ChatServiceURL)
+	if err != nil {
+		stopKeyRefresh()
+		connections.CloseAll()
+		return nil, err
+	}
+
 	return &Router{
 		app:            app,
 		rmq:            rmq,
 		hub:            gwWS.NewHub(),
-		authMiddleware: middleware.NewAuthMiddleware(cfg.UserManagementServiceURL),
+		authMiddleware: authMiddleware,
+		connections:    connections,
+		ownership:      lookup,
+		stopKeyRefresh: stopKeyRefresh,
+	}, nil
+}
+
+// newKeySource is Firebase's key set, or a local public key in development
+// and tests, whose tokens are signed with the matching private key
+func newKeySource(cfg *config.Config) (auth.KeySource, error) {
+	if cfg.AuthPublicKeyPath != "" {
+		return auth.NewPEMKeySource(cfg.AuthPublicKeyPath, cfg.AuthPublicKeyID)
 	}
+	return auth.NewJWKSSource(cfg.JWKSURL), nil
 }
 
 func (r *Router) SetupRoutes() {
@@ -48,6 +91,7 @@ func (r *Router) SetupRoutes() {
 	r.setupPaymentRoutes(api)
 	r.setupChatRoutes(api)
 	r.setupCourseRoutes(api)
+	r.setupAdminRoutes(api)
 	r.setupTestRouter(api)
 	r.setupChatRoutes(api)
 }
@@ -57,64 +101,146 @@ func (r *Router) setupUserRoutes(api fiber.Router) {
 
 	users := api.Group("/users")
 	users.Post("/register", userHandler.Register)
-	users.Get("/me", r.authMiddleware.AddClaims, userHandler.GetMe)
-	users.Patch("/update-name", r.authMiddleware.AddClaims, userHandler.UpdateUsername)
+	users.Get("/me", r.guard(signedIn, userHandler.GetMe)...)
+	users.Patch("/update-name", r.guard(signedIn, userHandler.UpdateUsername)...)
+	users.Get("/prophets/:id/profile", userHandler.GetProphetProfile)
+	users.Patch("/profile", r.guard(prophets, userHandler.UpdateProphetProfile)...)
+	users.Put("/online", r.guard(prophets, userHandler.SetOnlineStatus)...)
+	users.Put("/prophets/:id/verified", r.guard(admins, userHandler.SetProphetVerified)...)
+	users.Put("/:id/role", r.guard(admins, userHandler.SetUserRole)...)
+
+	// Platform sessions; callers prove who they are with the tokens they send
+	sessions := api.Group("/auth")
+	sessions.Post("/token", userHandler.IssueToken)
+	sessions.Post("/refresh", userHandler.RefreshToken)
+	sessions.Post("/revoke", userHandler.RevokeToken)
 }
 
 func (r *Router) setupOrderRoutes(api fiber.Router) {
 	orderHandler := http_handler.NewOrderHandler()
 	orders := api.Group("/orders")
 
-	orders.Get("/", r.authMiddleware.AddClaims, orderHandler.GetOrders)
-	orders.Post("/", r.authMiddleware.AddClaims, orderHandler.CreateOrder)
-	orders.Get("/:id", r.authMiddleware.AddClaims, orderHandler.GetOrderByID)
-	orders.Get("/customer/:customerID", r.authMiddleware.AddClaims, orderHandler.GetOrdersByCustomer)
-	orders.Get("/room/:roomID", r.authMiddleware.AddClaims, orderHandler.GetOrdersByRoom)
-	orders.Patch("/:id/status", r.authMiddleware.AddClaims, orderHandler.UpdateOrderStatus)
-	orders.Patch("/customer/:id", r.authMiddleware.AddClaims, orderHandler.MarkCustomerCompleted)
-	orders.Patch("/prophet/:id", r.authMiddleware.AddClaims, orderHandler.MarkProphetCompleted)
+	// Promotions and subscriptions, registered first so /:id does not capture them
+	orders.Post("/promotions", r.guard(prophetsAndAdmins, orderHandler.CreatePromotion)...)
+	orders.Get("/promotions", r.guard(prophetsAndAdmins, orderHandler.ListPromotions)...)
+	orders.Post("/promotions/:id/deactivate", r.guard(prophetsAndAdmins, orderHandler.DeactivatePromotion)...)
+	orders.Post("/subscriptions", r.guard(customers, orderHandler.Subscribe)...)
+	orders.Get("/subscriptions", r.guard(signedIn, orderHandler.ListSubscriptions)...)
+	orders.Get("/subscriptions/:id", r.guard(signedIn, orderHandler.GetSubscription)...)
+	orders.Post("/subscriptions/:id/cancel", r.guard(signedIn, orderHandler.CancelSubscription)...)
+	orders.Get("/", r.guard(admins, orderHandler.GetOrders)...)
+	orders.Post("/", r.guard(customers, orderHandler.CreateOrder)...)
+	orders.Get("/:id", r.guard(r.orderParty("id"), orderHandler.GetOrderByID)...)
+	orders.Get("/customer/:customerID", r.guard(self("customerID"), orderHandler.GetOrdersByCustomer)...)
+	orders.Get("/room/:roomID", r.guard(r.roomMember("roomID"), orderHandler.GetOrdersByRoom)...)
+	orders.Patch("/:id/status", r.guard(admins, orderHandler.UpdateOrderStatus)...)
+	orders.Patch("/customer/:id", r.guard(r.orderCustomer("id"), orderHandler.MarkCustomerCompleted)...)
+	orders.Patch("/prophet/:id", r.guard(r.orderProphet("id"), orderHandler.MarkProphetCompleted)...)
+	orders.Patch("/:id/cancel", r.guard(r.orderParty("id"), orderHandler.CancelOrder)...)
+	orders.Get("/:id/payment", r.guard(r.orderParty("id"), orderHandler.GetOrderPayment)...)
+	orders.Patch("/:id/reschedule", r.guard(r.orderCustomer("id"), orderHandler.RescheduleOrder)...)
+	orders.Post("/:id/sessions", r.guard(r.orderCustomer("id"), orderHandler.BookNextSession)...)
+	orders.Post("/:id/dispute", r.guard(r.orderParty("id"), orderHandler.OpenDispute)...)
+	orders.Post("/:id/dispute/resolve", r.guard(r.orderParty("id"), orderHandler.ResolveDispute)...)
 }
 
 func (r *Router) setupPaymentRoutes(api fiber.Router) {
 	paymentHandler := http_handler.NewPaymentHandler()
 
 	payments := api.Group("/payments")
-	payments.Get("/:id", r.authMiddleware.AddClaims, paymentHandler.GetPayment)
-	payments.Get("/order/:orderID", r.authMiddleware.AddClaims, paymentHandler.GetPaymentByOrder)
-	payments.Put("/:id/complete", r.authMiddleware.AddClaims, paymentHandler.CompletePayment)
-	payments.Get("/balance", r.authMiddleware.AddClaims, paymentHandler.GetProphetBalance)
+	payments.Get("/balance", r.guard(prophets, paymentHandler.GetProphetBalance)...)
+	payments.Get("/ledger", r.guard(prophets, paymentHandler.GetLedgerStatement)...)
+	payments.Get("/payouts", r.guard(prophetsAndAdmins, paymentHandler.ListPayouts)...)
+	payments.Post("/payouts", r.guard(prophets, paymentHandler.RequestPayout)...)
+	payments.Post("/payouts/:id/approve", r.guard(admins, paymentHandler.ApprovePayout)...)
+	payments.Post("/payouts/:id/reject", r.guard(admins, paymentHandler.RejectPayout)...)
+	payments.Post("/payouts/:id/paid", r.guard(admins, paymentHandler.MarkPayoutPaid)...)
+	payments.Post("/payouts/:id/cancel", r.guard(prophets, paymentHandler.CancelPayout)...)
+	payments.Get("/fee-rules", r.guard(admins, paymentHandler.ListFeeRules)...)
+	payments.Post("/fee-rules", r.guard(admins, paymentHandler.CreateFeeRule)...)
+	payments.Post("/fee-rules/:id/end", r.guard(admins, paymentHandler.EndFeeRule)...)
+	payments.Get("/:id", r.guard(r.paymentParty("id"), paymentHandler.GetPayment)...)
+	payments.Get("/order/:orderID", r.guard(r.orderParty("orderID"), paymentHandler.GetPaymentByOrder)...)
+	payments.Put("/:id/complete", r.guard(admins, paymentHandler.CompletePayment)...)
+	payments.Post("/:id/checkout", r.guard(r.paymentCustomer("id"), paymentHandler.CreateCheckoutSession)...)
+	payments.Get("/:id/webhooks", r.guard(r.paymentParty("id"), paymentHandler.GetWebhookHistory)...)
+	// Called by the payment provider, authenticated by its webhook signature
+	payments.Post("/webhooks/:provider", paymentHandler.ProviderWebhook)
 }
 
 func (r *Router) setupChatRoutes(api fiber.Router) {
 	chatHandler := http_handler.NewChatHandler()
 	chats := api.Group("/chat")
-	chats.Get("/:roomID/messages", r.authMiddleware.AddClaims, chatHandler.GetMessagesByRoomID)
-	chats.Post("/rooms", r.authMiddleware.AddClaims, chatHandler.CreateRoom)
-	chats.Get("/customer/rooms", r.authMiddleware.AddClaims, chatHandler.GetChatRoomsByCustomerID)
-	chats.Get("/prophet/rooms", r.authMiddleware.AddClaims, chatHandler.GetChatRoomsByProphetID)
-	chats.Get("/user/rooms", r.authMiddleware.AddClaims, chatHandler.GetChatRoomsByUserID)
+	chats.Get("/:roomID/messages", r.guard(r.roomMember("roomID"), chatHandler.GetMessagesByRoomID)...)
+	chats.Post("/rooms", r.guard(signedIn, chatHandler.CreateRoom)...)
+	chats.Get("/customer/rooms", r.guard(customers, chatHandler.GetChatRoomsByCustomerID)...)
+	chats.Get("/prophet/rooms", r.guard(prophets, chatHandler.GetChatRoomsByProphetID)...)
+	chats.Get("/user/rooms", r.guard(signedIn, chatHandler.GetChatRoomsByUserID)...)
 }
 
 func (r *Router) setupCourseRoutes(api fiber.Router) {
 	courseHandler := http_handler.NewCourseHandler()
-	authMiddleware := r.authMiddleware
 
 	courses := api.Group("/courses")
 
 	// --- Public Routes (no auth) ---
 	courses.Get("/", courseHandler.FindCoursesByFilter)
 	courses.Get("/popular", courseHandler.ListPopularCourses)
+	courses.Get("/trending", courseHandler.ListTrendingCourses)
+	// Authenticated, but must come before /:id
+	courses.Get("/recommended", r.guard(customers, courseHandler.ListRecommendedCourses)...)
 	courses.Get("/prophet/:prophetId/courses", courseHandler.ListCoursesByProphet)
 	courses.Get("/review/:id", courseHandler.GetReviewByID)
 	courses.Get("/:courseId/reviews", courseHandler.ListReviewsByCourse)
+	courses.Get("/availability/:prophetId", courseHandler.GetAvailability)
+	courses.Get("/:id/slots", courseHandler.ListAvailableSlots)
 	courses.Get("/:id", courseHandler.GetCourseByID) // ← Keep this LAST among GET routes!
 
 	// --- Authenticated Routes (require auth) ---
-	courses.Post("/", authMiddleware.AddClaims, courseHandler.CreateCourse)
-	courses.Patch("/:id", authMiddleware.AddClaims, courseHandler.UpdateCourse)
-	courses.Patch("/delete/:id", authMiddleware.AddClaims, courseHandler.DeleteCourse)
-	courses.Get("/prophet/courses", authMiddleware.AddClaims, courseHandler.ListCurrentProphetCourses)
-	courses.Post("/:courseId/review", authMiddleware.AddClaims, courseHandler.CreateReview)
+	courses.Post("/", r.guard(prophets, courseHandler.CreateCourse)...)
+	courses.Patch("/:id", r.guard(r.courseOwner("id"), courseHandler.UpdateCourse)...)
+	courses.Patch("/delete/:id", r.guard(r.courseOwner("id"), courseHandler.DeleteCourse)...)
+	courses.Get("/prophet/courses", r.guard(prophets, courseHandler.ListCurrentProphetCourses)...)
+	courses.Post("/:courseId/review", r.guard(customers, courseHandler.CreateReview)...)
+	courses.Patch("/review/:id", r.guard(customers, courseHandler.UpdateReview)...)
+	courses.Delete("/review/:id", r.guard(customers, courseHandler.DeleteReview)...)
+	courses.Post("/review/:id/reply", r.guard(prophets, courseHandler.ReplyToReview)...)
+	courses.Post("/review/:id/flag", r.guard(customersAndProphets, courseHandler.FlagReview)...)
+	courses.Get("/reviews/moderation", r.guard(admins, courseHandler.ListReviewModerationQueue)...)
+	courses.Post("/review/:id/hide", r.guard(admins, courseHandler.HideReview)...)
+	courses.Post("/review/:id/restore", r.guard(admins, courseHandler.RestoreReview)...)
+	courses.Put("/availability", r.guard(prophets, courseHandler.SetAvailability)...)
+	courses.Get("/bookings/prophet", r.guard(prophets, courseHandler.ListCurrentProphetBookings)...)
+}
+
+// setupAdminRoutes is the back office: every route is for admins only, and
+// each service checks the role again
+func (r *Router) setupAdminRoutes(api fiber.Router) {
+	adminHandler := http_handler.NewAdminHandler()
+	userHandler := http_handler.NewUserHandler()
+	orderHandler := http_handler.NewOrderHandler()
+	paymentHandler := http_handler.NewPaymentHandler()
+	courseHandler := http_handler.NewCourseHandler()
+
+	admin := api.Group("/admin")
+	admin.Get("/users", r.guard(admins, userHandler.SearchUsers)...)
+	admin.Put("/users/:id/role", r.guard(admins, userHandler.SetUserRole)...)
+	admin.Post("/prophets/:id/suspend", r.guard(admins, adminHandler.SuspendProphet)...)
+	admin.Post("/prophets/:id/reinstate", r.guard(admins, adminHandler.ReinstateProphet)...)
+	admin.Get("/dashboard", r.guard(admins, orderHandler.GetDashboard)...)
+	admin.Get("/orders", r.guard(admins, orderHandler.GetOrders)...)
+	admin.Get("/orders/:id", r.guard(admins, orderHandler.GetOrderByID)...)
+	admin.Get("/orders/:id/audit", r.guard(admins, orderHandler.GetOrderAudit)...)
+	admin.Post("/orders/:id/transition", r.guard(admins, orderHandler.ForceOrderStatus)...)
+	admin.Get("/outbox/failed", r.guard(admins, orderHandler.ListFailedOutboxMessages)...)
+	admin.Post("/outbox/replay", r.guard(admins, orderHandler.ReplayOutboxMessages)...)
+	admin.Get("/payments", r.guard(admins, paymentHandler.ListPayments)...)
+	admin.Get("/payments/:id", r.guard(admins, paymentHandler.GetPayment)...)
+	admin.Get("/payments/:id/audit", r.guard(admins, paymentHandler.GetPaymentAudit)...)
+	admin.Post("/payments/:id/transition", r.guard(admins, paymentHandler.ForcePaymentStatus)...)
+	admin.Get("/reviews", r.guard(admins, courseHandler.ListReviewModerationQueue)...)
+	admin.Post("/reviews/:id/hide", r.guard(admins, courseHandler.HideReview)...)
+	admin.Post("/reviews/:id/restore", r.guard(admins, courseHandler.RestoreReview)...)
 }
 
 func (r *Router) setupTestRouter(api fiber.Router) {
@@ -149,3 +275,10 @@ func (r *Router) setupWebsocketRoutes() {
 func (r *Router) GetHub() *gwWS.Hub {
 	return r.hub
 }
+
+// Close stops refreshing signing keys and closes the connections ownership
+// checks use
+func (r *Router) Close() {
+	r.stopKeyRefresh()
+	r.connections.CloseAll()
+}
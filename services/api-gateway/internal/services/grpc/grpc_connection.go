@@ -3,6 +3,7 @@ package grpc_connection
 import (
 	"sync"
 
+	"github.com/wnmay/horo/shared/jwt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -18,7 +19,8 @@ func NewConnectionManager() *ConnectionManager {
 	}
 }
 
-func (cm *ConnectionManager) GetConnection(targetUrl string) (*grpc.ClientConn, error) {
+// GetConnection connects to the audience service at targetUrl
+func (cm *ConnectionManager) GetConnection(targetUrl, audience string) (*grpc.ClientConn, error) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 	var opts []grpc.DialOption
@@ -29,6 +31,8 @@ func (cm *ConnectionManager) GetConnection(targetUrl string) (*grpc.ClientConn,
 
 	opts = append(opts,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// Calls made with jwt.WithIdentity are made on that user's behalf
+		grpc.WithUnaryInterceptor(jwt.UnaryClientIdentity(audience)),
 	)
 
 	// Create gRPC client connection
@@ -0,0 +1,147 @@
+package ownership
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	grpc_connection "github.com/wnmay/horo/services/api-gateway/internal/services/grpc"
+	"github.com/wnmay/horo/shared/jwt"
+	coursepb "github.com/wnmay/horo/shared/proto/course"
+	orderpb "github.com/wnmay/horo/shared/proto/order"
+	paymentpb "github.com/wnmay/horo/shared/proto/payment"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Parties are the customer and prophet an order, or its payment, is between
+type Parties struct {
+	CustomerID string
+	ProphetID  string
+}
+
+// Includes reports whether the user is the order's customer or prophet
+func (p Parties) Includes(userID string) bool {
+	return userID != "" && (p.CustomerID == userID || p.ProphetID == userID)
+}
+
+// Lookup finds who owns a resource by asking the service it lives in, on
+// behalf of the user in ctx. Resources that do not exist, whose IDs are
+// malformed, or that the service will not show the user, belong to nobody.
+type Lookup struct {
+	orders         orderpb.OrderServiceClient
+	courses        coursepb.CourseServiceClient
+	payments       paymentpb.PaymentServiceClient
+	chatServiceURL string
+	httpClient     *http.Client
+}
+
+func NewLookup(connections *grpc_connection.ConnectionManager, orderAddr, courseAddr, paymentAddr, chatServiceURL string) (*Lookup, error) {
+	orderConn, err := connections.GetConnection(orderAddr, jwt.ServiceOrder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to order service: %w", err)
+	}
+	courseConn, err := connections.GetConnection(courseAddr, jwt.ServiceCourse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to course service: %w", err)
+	}
+	paymentConn, err := connections.GetConnection(paymentAddr, jwt.ServicePayment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to payment service: %w", err)
+	}
+
+	return &Lookup{
+		orders:         orderpb.NewOrderServiceClient(orderConn),
+		courses:        coursepb.NewCourseServiceClient(courseConn),
+		payments:       paymentpb.NewPaymentServiceClient(paymentConn),
+		chatServiceURL: chatServiceURL,
+		httpClient:     &http.Client{},
+	}, nil
+}
+
+func (l *Lookup) OrderParties(ctx context.Context, orderID string) (Parties, error) {
+	resp, err := l.orders.GetOrder(ctx, &orderpb.GetOrderRequest{OrderId: orderID})
+	if err != nil {
+		if isNotFound(err) {
+			return Parties{}, nil
+		}
+		return Parties{}, fmt.Errorf("failed to get order: %w", err)
+	}
+	return Parties{
+		CustomerID: resp.GetOrder().GetCustomerId(),
+		ProphetID:  resp.GetOrder().GetProphetId(),
+	}, nil
+}
+
+// PaymentParties are the parties of the order a payment is for. Payments do
+// not record their customer, so payment-service only answers services: the
+// payment is looked up as the gateway, and its order as the user.
+func (l *Lookup) PaymentParties(ctx context.Context, paymentID string) (Parties, error) {
+	resp, err := l.payments.GetPayment(jwt.WithIdentity(ctx, nil), &paymentpb.GetPaymentRequest{PaymentId: paymentID})
+	if err != nil {
+		if isNotFound(err) {
+			return Parties{}, nil
+		}
+		return Parties{}, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return l.OrderParties(ctx, resp.GetPayment().GetOrderId())
+}
+
+// CourseProphet is the prophet who created a course
+func (l *Lookup) CourseProphet(ctx context.Context, courseID string) (string, error) {
+	resp, err := l.courses.GetCourseByID(ctx, &coursepb.GetCourseByIDRequest{Id: courseID})
+	if err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get course: %w", err)
+	}
+	return resp.GetCourse().GetProphetId(), nil
+}
+
+// InRoom reports whether the user is one of a chat room's members. Chat
+// service has no gRPC server running, so this goes through its REST API.
+func (l *Lookup) InRoom(ctx context.Context, userID, roomID string) (bool, error) {
+	body, err := json.Marshal(map[string]string{"roomID": roomID})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.chatServiceURL+"/api/chat/room/validate", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	// Chat only trusts signed identities, so the check is made as the user
+	assertion, err := jwt.IssueIdentity(jwt.Identity{UserID: userID}, jwt.ServiceChat)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(jwt.HeaderIdentity, assertion)
+
+	res, err := l.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to validate room access: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("chat service returned %d validating room access", res.StatusCode)
+	}
+
+	var result struct {
+		Allowed bool `json:"allowed"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to read room access: %w", err)
+	}
+	return result.Allowed, nil
+}
+
+func isNotFound(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.InvalidArgument, codes.PermissionDenied:
+		return true
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // booking notifications render sessions in the prophet's timezone
 
 	http_handler "github.com/wnmay/horo/services/chat-service/internal/adapters/inbound/http"
 	repository "github.com/wnmay/horo/services/chat-service/internal/adapters/outbound/db"
@@ -15,6 +16,8 @@ import (
 	"github.com/wnmay/horo/services/chat-service/internal/infrastructure"
 	"github.com/wnmay/horo/services/chat-service/internal/messaging"
 	"github.com/wnmay/horo/shared/env"
+	"github.com/wnmay/horo/shared/jwt"
+	"github.com/wnmay/horo/shared/message"
 )
 
 func main() {
@@ -22,6 +25,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load environment variables: %v", err)
 	}
+	jwt.InitFromEnv(jwt.ServiceChat)
 	config := config.LoadConfig()
 
 	mongoURI := config.MongoConfig.MongoCommonConfig.URI
@@ -66,8 +70,14 @@ func main() {
 	// Create chat service with repositories and publisher
 	chatService := service.NewChatService(messageRepo, roomRepo, messagePublisher, userProvider, courseProvider)
 
+	// Processed message ledger lets consumers drop redelivered events
+	processedStore, err := message.NewMongoProcessedMessageStore(context.Background(), mongoDB)
+	if err != nil {
+		log.Fatalf("Failed to initialize processed message store: %v", err)
+	}
+
 	// Initialize consumers now that chat service is ready
-	messagingManager.InitializeConsumers(chatService)
+	messagingManager.InitializeConsumers(chatService, processedStore)
 
 	// Initialize Fiber HTTP server
 	messageHandler := http_handler.NewMessageHandler(chatService)
@@ -10,6 +10,7 @@ import (
 	"github.com/wnmay/horo/shared/contract"
 	"github.com/wnmay/horo/shared/env"
 	"github.com/wnmay/horo/shared/message"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type MessageIncomingData struct {
@@ -140,7 +141,10 @@ func publishOrderPaymentBoundNotification(ctx context.Context, rmq *message.Rabb
 		OrderStatus:   "pending_payment",
 		CourseID:      "course-test-789",
 		CourseName:    "Test Course: Payment Processing",
-		Amount:        999.99,
+		Amount:        money.MustParse("899.99"),
+		Currency:      money.DefaultCurrency,
+		Discount:      money.MustParse("100"),
+		CouponCode:    "WELCOME100",
 		PaymentStatus: "pending",
 	}
 
@@ -165,8 +169,8 @@ func publishOrderPaymentBoundNotification(ctx context.Context, rmq *message.Rabb
 	if err != nil {
 		log.Fatalf("Failed to publish order payment bound notification: %v", err)
 	}
-	log.Printf("✓ Published OrderPaymentBound notification - OrderID=%s, PaymentID=%s, Amount=%.2f, RoomID=%s",
-		testData.OrderID, testData.PaymentID, testData.Amount, testData.RoomID)
+	log.Printf("✓ Published OrderPaymentBound notification - OrderID=%s, PaymentID=%s, Amount=%s %s, RoomID=%s",
+		testData.OrderID, testData.PaymentID, testData.Amount, testData.Currency, testData.RoomID)
 }
 
 func publishOrderPaidNotification(ctx context.Context, rmq *message.RabbitMQ) {
@@ -179,7 +183,8 @@ func publishOrderPaidNotification(ctx context.Context, rmq *message.RabbitMQ) {
 		CourseID:      "course-test-789",
 		OrderStatus:   "paid",
 		CourseName:    "Test Course: Payment Success",
-		Amount:        999.99,
+		Amount:        money.MustParse("999.99"),
+		Currency:      money.DefaultCurrency,
 		PaymentStatus: "completed",
 	}
 
@@ -204,6 +209,6 @@ func publishOrderPaidNotification(ctx context.Context, rmq *message.RabbitMQ) {
 	if err != nil {
 		log.Fatalf("Failed to publish order paid notification: %v", err)
 	}
-	log.Printf("✓ Published OrderPaid notification - OrderID=%s, PaymentID=%s, Amount=%.2f, PaymentStatus=%s, RoomID=%s",
-		testData.OrderID, testData.PaymentID, testData.Amount, testData.PaymentStatus, testData.RoomID)
+	log.Printf("✓ Published OrderPaid notification - OrderID=%s, PaymentID=%s, Amount=%s %s, PaymentStatus=%s, RoomID=%s",
+		testData.OrderID, testData.PaymentID, testData.Amount, testData.Currency, testData.PaymentStatus, testData.RoomID)
 }
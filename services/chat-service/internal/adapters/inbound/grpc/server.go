@@ -5,6 +5,7 @@ import (
 	"context"
 
 	inbound_port "github.com/wnmay/horo/services/chat-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/jwt"
 	"github.com/wnmay/horo/shared/proto/chat"
 )
 
@@ -18,6 +19,9 @@ func NewChatGRPCServer(app inbound_port.ChatService) *ChatGRPCServer {
 }
 
 func (s *ChatGRPCServer) ValidateRoomAccess(ctx context.Context, req *chat.ValidateRoomRequest) (*chat.ValidateRoomResponse, error) {
+	if err := jwt.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
 	allow, reason, err := s.app.ValidateRoomAccess(ctx, req.UserId,req.RoomId)
 	if err != nil {
 		return nil, err
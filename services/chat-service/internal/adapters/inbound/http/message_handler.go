@@ -1,10 +1,12 @@
 package http_handler
 
 import (
+	"errors"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
 	inbound_port "github.com/wnmay/horo/services/chat-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/contract"
 )
 
 type ChatHandler struct {
@@ -25,14 +27,21 @@ func NewMessageHandler(chatService inbound_port.ChatService) *ChatHandler {
 	}
 }
 
+// GetMessagesByRoomID pages back through a room's history; each page is
+// oldest first and next_cursor leads to earlier messages
 func (h *ChatHandler) GetMessagesByRoomID(c *fiber.Ctx) error {
 	roomID := c.Params("roomID")
-	messages, err := h.chatService.GetMessagesByRoomID(c.Context(), roomID)
+	page, err := contract.ParsePageRequest(c.Query("cursor"), c.Query("limit"))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
+
+	messages, err := h.chatService.GetMessagesByRoomID(c.Context(), roomID, page)
+	if err != nil {
+		return pageError(c, err)
+	}
 	return c.JSON(messages)
 }
 
@@ -106,23 +115,40 @@ func (h *ChatHandler) GetChatRoomsByUserID(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	log.Printf("[GetChatRoomsByUserID] Fetching rooms for userID: %s", userID)
 	
-	rooms, err := h.chatService.GetChatRoomsByUserID(c.Context(), userID)
+	page, err := contract.ParsePageRequest(c.Query("cursor"), c.Query("limit"))
 	if err != nil {
-		log.Printf("[GetChatRoomsByUserID] Error: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
+
+	rooms, err := h.chatService.GetChatRoomsByUserID(c.Context(), userID, page)
+	if err != nil {
+		log.Printf("[GetChatRoomsByUserID] Error: %v", err)
+		return pageError(c, err)
+	}
 	
-	log.Printf("[GetChatRoomsByUserID] Found %d rooms for userID: %s", len(rooms), userID)
+	log.Printf("[GetChatRoomsByUserID] Found %d rooms for userID: %s", len(rooms.Data), userID)
 	return c.JSON(rooms)
 }
 
+func pageError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	if errors.Is(err, contract.ErrInvalidCursor) {
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// ValidateRoomAccess reports whether the calling user, as verified from the
+// gateway's identity assertion, may join a room
 func (h *ChatHandler) ValidateRoomAccess(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "X-User-Id header is required",
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "user not authenticated",
 		})
 	}
 
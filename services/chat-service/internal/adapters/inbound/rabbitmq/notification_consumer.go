@@ -18,17 +18,20 @@ import (
 type notificationConsumer struct {
 	chatService inbound_port.ChatService
 	rmq         *message.RabbitMQ
+	processed   message.ProcessedMessageStore
 }
 
-func NewNotificationConsumer(chatService inbound_port.ChatService, rmq *message.RabbitMQ) inbound_port.MessageConsumer {
+func NewNotificationConsumer(chatService inbound_port.ChatService, rmq *message.RabbitMQ, processed message.ProcessedMessageStore) inbound_port.MessageConsumer {
 	return &notificationConsumer{
 		chatService: chatService,
 		rmq:         rmq,
+		processed:   processed,
 	}
 }
 
 func (c *notificationConsumer) StartListening() error {
-	return c.rmq.ConsumeMessages(message.NotifyOrderCompleted, c.handleNotification)
+	// Skip redelivered events so the room does not get duplicate system messages
+	return c.rmq.ConsumeMessages(message.NotifyOrderCompleted, message.Idempotent(c.processed, message.NotifyOrderCompleted, c.handleNotification))
 }
 
 // handleNotification routes messages to the appropriate handler based on routing key
@@ -37,18 +40,145 @@ func (c *notificationConsumer) handleNotification(ctx context.Context, delivery
 
 	// Route to appropriate handler based on routing key
 	switch delivery.RoutingKey {
+	case contract.OrderCreatedEvent:
+		return c.handleOrderCreated(ctx, delivery)
 	case contract.OrderCompletedEvent:
 		return c.handleOrderCompleted(ctx, delivery)
+	case contract.OrderSessionCompletedEvent:
+		return c.handleOrderSessionCompleted(ctx, delivery)
 	case contract.OrderPaymentBoundEvent:
 		return c.handleOrderPaymentBound(ctx, delivery)
 	case contract.OrderPaidEvent:
 		return c.handleOrderPaid(ctx, delivery)
+	case contract.OrderCancelledEvent:
+		return c.handleOrderCancelled(ctx, delivery)
+	case contract.OrderDisputedEvent:
+		return c.handleOrderDisputed(ctx, delivery)
+	case contract.OrderDisputeResolvedEvent:
+		return c.handleOrderDisputeResolved(ctx, delivery)
+	case contract.OrderRescheduledEvent:
+		return c.handleOrderRescheduled(ctx, delivery)
+	case contract.SubscriptionRenewedEvent, contract.SubscriptionLapsedEvent:
+		return c.handleSubscription(ctx, delivery)
 	default:
 		log.Printf("Unknown routing key: %s, skipping message", delivery.RoutingKey)
 		return fmt.Errorf("unknown routing key: %s", delivery.RoutingKey)
 	}
 }
 
+func (c *notificationConsumer) handleOrderCreated(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling order created event")
+	var amqpMessage contract.AmqpMessage
+	var orderData message.OrderData
+
+	// Parse the AMQP message
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &orderData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	// Only booked sessions are announced in the room
+	if orderData.RoomID == "" || orderData.BookingID == "" {
+		log.Printf("Order %s has no room or booking, skipping notification", orderData.OrderID)
+		return nil
+	}
+
+	content := service.GenerateOrderBookedMessage(orderData.OrderID, orderData.CourseName, orderData.SessionStart, orderData.SessionEnd, orderData.SessionTimezone)
+
+	messageID, err := c.chatService.SaveMessage(ctx, orderData.RoomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderCreatedEvent))
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+
+	notificationData := message.ChatNotificationOutgoingData[message.OrderBookedNotificationData]{
+		MessageID: messageID,
+		RoomID:    orderData.RoomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Trigger:   contract.OrderCreatedEvent,
+		MessageDetail: &message.OrderBookedNotificationData{
+			OrderID:         orderData.OrderID,
+			CourseID:        orderData.CourseID,
+			CourseName:      orderData.CourseName,
+			BookingID:       orderData.BookingID,
+			SessionStart:    orderData.SessionStart,
+			SessionEnd:      orderData.SessionEnd,
+			SessionTimezone: orderData.SessionTimezone,
+		},
+	}
+
+	err = c.chatService.PublishOrderBookedNotification(ctx, notificationData)
+	if err != nil {
+		log.Printf("Failed to publish order booked notification: %v", err)
+		return err
+	}
+	log.Printf("Published order booked notification: %s", messageID)
+	return nil
+}
+
+func (c *notificationConsumer) handleOrderRescheduled(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling order rescheduled event")
+	var amqpMessage contract.AmqpMessage
+	var orderRescheduledData message.OrderRescheduledData
+
+	// Parse the AMQP message
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &orderRescheduledData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	if orderRescheduledData.RoomID == "" {
+		log.Printf("Order %s has no room, skipping notification", orderRescheduledData.OrderID)
+		return nil
+	}
+
+	content := service.GenerateOrderRescheduledMessage(orderRescheduledData.OrderID, orderRescheduledData.CourseName, orderRescheduledData.SessionStart, orderRescheduledData.SessionEnd, orderRescheduledData.SessionTimezone)
+
+	messageID, err := c.chatService.SaveMessage(ctx, orderRescheduledData.RoomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderRescheduledEvent))
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+
+	notificationData := message.ChatNotificationOutgoingData[message.OrderBookedNotificationData]{
+		MessageID: messageID,
+		RoomID:    orderRescheduledData.RoomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Trigger:   contract.OrderRescheduledEvent,
+		MessageDetail: &message.OrderBookedNotificationData{
+			OrderID:         orderRescheduledData.OrderID,
+			CourseID:        orderRescheduledData.CourseID,
+			CourseName:      orderRescheduledData.CourseName,
+			BookingID:       orderRescheduledData.BookingID,
+			SessionStart:    orderRescheduledData.SessionStart,
+			SessionEnd:      orderRescheduledData.SessionEnd,
+			SessionTimezone: orderRescheduledData.SessionTimezone,
+		},
+	}
+
+	err = c.chatService.PublishOrderBookedNotification(ctx, notificationData)
+	if err != nil {
+		log.Printf("Failed to publish order rescheduled notification: %v", err)
+		return err
+	}
+	log.Printf("Published order rescheduled notification: %s", messageID)
+	return nil
+}
+
 func (c *notificationConsumer) handleOrderCompleted(ctx context.Context, delivery amqp.Delivery) error {
 	log.Printf("Handling order completed event")
 	var amqpMessage contract.AmqpMessage
@@ -79,9 +209,12 @@ func (c *notificationConsumer) handleOrderCompleted(ctx context.Context, deliver
 		log.Printf("Created new chat room with ID: %s", roomID)
 	}
 
-	if err := c.chatService.UpdateRoomIsDone(ctx, roomID, true); err != nil {
-		log.Printf("Failed to update room is done: %v", err)
-		return err
+	// A subscription keeps its room open across periods until it ends
+	if orderCompletedData.SubscriptionID == "" {
+		if err := c.chatService.UpdateRoomIsDone(ctx, roomID, true); err != nil {
+			log.Printf("Failed to update room is done: %v", err)
+			return err
+		}
 	}
 
 	content := service.GenerateOrderCompletedMessage(orderCompletedData.OrderID, orderCompletedData.CourseID, orderCompletedData.OrderStatus, orderCompletedData.CourseName)
@@ -115,6 +248,59 @@ func (c *notificationConsumer) handleOrderCompleted(ctx context.Context, deliver
 	return nil
 }
 
+// handleOrderSessionCompleted tells the room a bundle session is done. The
+// room stays open for the sessions still to come.
+func (c *notificationConsumer) handleOrderSessionCompleted(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling order session completed event")
+	var amqpMessage contract.AmqpMessage
+	var orderCompletedData message.OrderCompletedData
+
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &orderCompletedData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	roomID := orderCompletedData.RoomID
+	if roomID == "" {
+		log.Printf("RoomID is empty for order %s, skipping session notification", orderCompletedData.OrderID)
+		return nil
+	}
+
+	content := service.GenerateOrderSessionCompletedMessage(orderCompletedData.OrderID, orderCompletedData.CourseName, orderCompletedData.Session, orderCompletedData.Sessions)
+
+	messageID, err := c.chatService.SaveMessage(ctx, roomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderSessionCompletedEvent))
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+	notificationData := message.ChatNotificationOutgoingData[message.OrderCompletedNotificationData]{
+		MessageID: messageID,
+		RoomID:    roomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		MessageDetail: &message.OrderCompletedNotificationData{
+			OrderID:     orderCompletedData.OrderID,
+			CourseID:    orderCompletedData.CourseID,
+			OrderStatus: orderCompletedData.OrderStatus,
+			CourseName:  orderCompletedData.CourseName,
+		},
+		Trigger: contract.OrderSessionCompletedEvent,
+	}
+
+	if err := c.chatService.PublishOrderCompletedNotification(ctx, notificationData); err != nil {
+		log.Printf("Failed to publish order session completed message: %v", err)
+		return err
+	}
+	log.Printf("Published order session completed message: %s", messageID)
+	return nil
+}
+
 func (c *notificationConsumer) handleOrderPaymentBound(ctx context.Context, delivery amqp.Delivery) error {
 	log.Printf("Handling order payment bound event")
 	var amqpMessage contract.AmqpMessage
@@ -132,7 +318,8 @@ func (c *notificationConsumer) handleOrderPaymentBound(ctx context.Context, deli
 	}
 
 	// Generate notification content for payment bound
-	content := service.GenerateOrderPaymentBoundMessage(orderPaymentBoundData.OrderID, orderPaymentBoundData.CourseID, orderPaymentBoundData.OrderStatus, orderPaymentBoundData.CourseName)
+	content := service.GenerateOrderPaymentBoundMessage(orderPaymentBoundData.OrderID, orderPaymentBoundData.CourseID, orderPaymentBoundData.OrderStatus, orderPaymentBoundData.CourseName,
+		orderPaymentBoundData.Amount, orderPaymentBoundData.Discount, orderPaymentBoundData.Currency, orderPaymentBoundData.CouponCode)
 
 	messageID, err := c.chatService.SaveMessage(ctx, orderPaymentBoundData.RoomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderPaymentBoundEvent))
 	if err != nil {
@@ -156,6 +343,9 @@ func (c *notificationConsumer) handleOrderPaymentBound(ctx context.Context, deli
 			OrderStatus:   orderPaymentBoundData.OrderStatus,
 			CourseName:    orderPaymentBoundData.CourseName,
 			Amount:        orderPaymentBoundData.Amount,
+			Currency:      orderPaymentBoundData.Currency,
+			Discount:      orderPaymentBoundData.Discount,
+			CouponCode:    orderPaymentBoundData.CouponCode,
 			PaymentStatus: orderPaymentBoundData.PaymentStatus,
 		},
 	}
@@ -186,7 +376,8 @@ func (c *notificationConsumer) handleOrderPaid(ctx context.Context, delivery amq
 	}
 
 	// Generate notification content for payment success
-	content := service.GenerateOrderPaidMessage(orderPaidData.OrderID, orderPaidData.CourseID, orderPaidData.OrderStatus, orderPaidData.CourseName)
+	content := service.GenerateOrderPaidMessage(orderPaidData.OrderID, orderPaidData.CourseID, orderPaidData.OrderStatus, orderPaidData.CourseName,
+		orderPaidData.Amount, orderPaidData.Discount, orderPaidData.Currency, orderPaidData.CouponCode)
 
 	messageID, err := c.chatService.SaveMessage(ctx, orderPaidData.RoomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderPaidEvent))
 	if err != nil {
@@ -210,6 +401,9 @@ func (c *notificationConsumer) handleOrderPaid(ctx context.Context, delivery amq
 			OrderStatus:   orderPaidData.OrderStatus,
 			CourseName:    orderPaidData.CourseName,
 			Amount:        orderPaidData.Amount,
+			Currency:      orderPaidData.Currency,
+			Discount:      orderPaidData.Discount,
+			CouponCode:    orderPaidData.CouponCode,
 			PaymentStatus: orderPaidData.PaymentStatus,
 		},
 	}
@@ -222,3 +416,246 @@ func (c *notificationConsumer) handleOrderPaid(ctx context.Context, delivery amq
 	log.Printf("Published order paid notification: %s", messageID)
 	return nil
 }
+
+func (c *notificationConsumer) handleOrderCancelled(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling order cancelled event")
+	var amqpMessage contract.AmqpMessage
+	var orderCancelledData message.OrderCancelledData
+
+	// Parse the AMQP message
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &orderCancelledData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	roomID := orderCancelledData.RoomID
+	if roomID == "" {
+		log.Printf("RoomID is empty for cancelled order %s, skipping notification", orderCancelledData.OrderID)
+		return nil
+	}
+
+	// The reading will not happen, so close the room like a completed order.
+	// Subscription rooms are closed by subscription.lapsed instead.
+	if orderCancelledData.SubscriptionID == "" {
+		if err := c.chatService.UpdateRoomIsDone(ctx, roomID, true); err != nil {
+			log.Printf("Failed to update room is done: %v", err)
+			return err
+		}
+	}
+
+	content := service.GenerateOrderCancelledMessage(orderCancelledData.OrderID, orderCancelledData.CourseName, orderCancelledData.CancelledBy, orderCancelledData.Reason)
+
+	messageID, err := c.chatService.SaveMessage(ctx, roomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderCancelledEvent))
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+
+	notificationData := message.ChatNotificationOutgoingData[message.OrderCancelledNotificationData]{
+		MessageID: messageID,
+		RoomID:    roomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Trigger:   contract.OrderCancelledEvent,
+		MessageDetail: &message.OrderCancelledNotificationData{
+			OrderID:     orderCancelledData.OrderID,
+			CourseID:    orderCancelledData.CourseID,
+			CourseName:  orderCancelledData.CourseName,
+			OrderStatus: orderCancelledData.OrderStatus,
+			CancelledBy: orderCancelledData.CancelledBy,
+			Reason:      orderCancelledData.Reason,
+		},
+	}
+
+	err = c.chatService.PublishOrderCancelledNotification(ctx, notificationData)
+	if err != nil {
+		log.Printf("Failed to publish order cancelled notification: %v", err)
+		return err
+	}
+	log.Printf("Published order cancelled notification: %s", messageID)
+	return nil
+}
+
+func (c *notificationConsumer) handleOrderDisputed(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling order disputed event")
+	var amqpMessage contract.AmqpMessage
+	var orderDisputedData message.OrderDisputedData
+
+	// Parse the AMQP message
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &orderDisputedData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	roomID := orderDisputedData.RoomID
+	if roomID == "" {
+		log.Printf("RoomID is empty for disputed order %s, skipping notification", orderDisputedData.OrderID)
+		return nil
+	}
+
+	content := service.GenerateOrderDisputedMessage(orderDisputedData.OrderID, orderDisputedData.CourseName, orderDisputedData.Reason)
+
+	messageID, err := c.chatService.SaveMessage(ctx, roomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderDisputedEvent))
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+
+	notificationData := message.ChatNotificationOutgoingData[message.OrderDisputedNotificationData]{
+		MessageID: messageID,
+		RoomID:    roomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Trigger:   contract.OrderDisputedEvent,
+		MessageDetail: &message.OrderDisputedNotificationData{
+			OrderID:    orderDisputedData.OrderID,
+			CourseID:   orderDisputedData.CourseID,
+			CourseName: orderDisputedData.CourseName,
+			Reason:     orderDisputedData.Reason,
+		},
+	}
+
+	err = c.chatService.PublishOrderDisputedNotification(ctx, notificationData)
+	if err != nil {
+		log.Printf("Failed to publish order disputed notification: %v", err)
+		return err
+	}
+	log.Printf("Published order disputed notification: %s", messageID)
+	return nil
+}
+
+// handleOrderDisputeResolved announces the outcome. Closing the room is left to
+// the order completed or cancelled event published alongside it.
+func (c *notificationConsumer) handleOrderDisputeResolved(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling order dispute resolved event")
+	var amqpMessage contract.AmqpMessage
+	var disputeResolvedData message.OrderDisputeResolvedData
+
+	// Parse the AMQP message
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &disputeResolvedData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	roomID := disputeResolvedData.RoomID
+	if roomID == "" {
+		log.Printf("RoomID is empty for order %s, skipping dispute resolved notification", disputeResolvedData.OrderID)
+		return nil
+	}
+
+	content := service.GenerateOrderDisputeResolvedMessage(disputeResolvedData.OrderID, disputeResolvedData.CourseName, disputeResolvedData.Resolution, disputeResolvedData.Note)
+
+	messageID, err := c.chatService.SaveMessage(ctx, roomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, string(contract.OrderDisputeResolvedEvent))
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+
+	notificationData := message.ChatNotificationOutgoingData[message.OrderDisputeResolvedNotificationData]{
+		MessageID: messageID,
+		RoomID:    roomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Trigger:   contract.OrderDisputeResolvedEvent,
+		MessageDetail: &message.OrderDisputeResolvedNotificationData{
+			OrderID:     disputeResolvedData.OrderID,
+			CourseID:    disputeResolvedData.CourseID,
+			CourseName:  disputeResolvedData.CourseName,
+			OrderStatus: disputeResolvedData.OrderStatus,
+			Resolution:  disputeResolvedData.Resolution,
+			Note:        disputeResolvedData.Note,
+		},
+	}
+
+	err = c.chatService.PublishOrderDisputeResolvedNotification(ctx, notificationData)
+	if err != nil {
+		log.Printf("Failed to publish order dispute resolved notification: %v", err)
+		return err
+	}
+	log.Printf("Published order dispute resolved notification: %s", messageID)
+	return nil
+}
+
+// handleSubscription reopens the subscription's room when a period is renewed
+// and closes it when the subscription ends
+func (c *notificationConsumer) handleSubscription(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Handling %s event", delivery.RoutingKey)
+	var amqpMessage contract.AmqpMessage
+	var subscriptionData message.SubscriptionData
+
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	if err := json.Unmarshal(amqpMessage.Data, &subscriptionData); err != nil {
+		log.Printf("Failed to unmarshal message data: %v", err)
+		return err
+	}
+
+	roomID := subscriptionData.RoomID
+	if roomID == "" {
+		log.Printf("RoomID is empty for subscription %s, skipping notification", subscriptionData.SubscriptionID)
+		return nil
+	}
+
+	lapsed := delivery.RoutingKey == contract.SubscriptionLapsedEvent
+	if err := c.chatService.UpdateRoomIsDone(ctx, roomID, lapsed); err != nil {
+		log.Printf("Failed to update room is done: %v", err)
+		return err
+	}
+
+	content := service.GenerateSubscriptionRenewedMessage(subscriptionData.CourseName, subscriptionData.Period, subscriptionData.PeriodEnd)
+	if lapsed {
+		content = service.GenerateSubscriptionLapsedMessage(subscriptionData.CourseName, subscriptionData.Reason)
+	}
+
+	messageID, err := c.chatService.SaveMessage(ctx, roomID, "system", content, domain.MessageTypeNotification, domain.MessageStatusSent, delivery.RoutingKey)
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		return err
+	}
+
+	notificationData := message.ChatNotificationOutgoingData[message.SubscriptionNotificationData]{
+		MessageID: messageID,
+		RoomID:    roomID,
+		SenderID:  "system",
+		Type:      string(domain.MessageTypeNotification),
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Trigger:   delivery.RoutingKey,
+		MessageDetail: &message.SubscriptionNotificationData{
+			SubscriptionID: subscriptionData.SubscriptionID,
+			CourseID:       subscriptionData.CourseID,
+			CourseName:     subscriptionData.CourseName,
+			Status:         subscriptionData.Status,
+			Period:         subscriptionData.Period,
+			PeriodEnd:      subscriptionData.PeriodEnd,
+			Reason:         subscriptionData.Reason,
+		},
+	}
+
+	if err := c.chatService.PublishSubscriptionNotification(ctx, notificationData); err != nil {
+		log.Printf("Failed to publish subscription notification: %v", err)
+		return err
+	}
+	log.Printf("Published subscription notification: %s", messageID)
+	return nil
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/wnmay/horo/services/chat-service/internal/domain"
 	repository_port "github.com/wnmay/horo/services/chat-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/contract"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -19,6 +22,14 @@ type mongoMessageRepository struct {
 func NewMongoMessageRepository(db *mongo.Database, collectionName string) repository_port.MessageRepository {
 	collection := db.Collection(collectionName)
 
+	// Serves a room's history newest first
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Failed to create message history index: %v", err)
+	}
+
 	return &mongoMessageRepository{
 		collection: collection,
 	}
@@ -34,7 +45,16 @@ func (r *mongoMessageRepository) SaveMessage(ctx context.Context, message *domai
 	return messageID, nil
 }
 
-func (r *mongoMessageRepository) FindMessagesByRoomID(ctx context.Context, roomID string) ([]*domain.Message, error) {
+// messageCursor is the position of the oldest message of a page
+type messageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// FindMessagesByRoomID pages back through a room's history from the newest
+// message. Each page is in chronological order; the next cursor leads to the
+// messages before it.
+func (r *mongoMessageRepository) FindMessagesByRoomID(ctx context.Context, roomID string, page contract.PageRequest) (contract.Page[*domain.Message], error) {
 	roomOID, err := primitive.ObjectIDFromHex(roomID)
 	if err != nil {
 		log.Println("Invalid RoomID for MessageModel:", err)
@@ -42,15 +62,33 @@ func (r *mongoMessageRepository) FindMessagesByRoomID(ctx context.Context, roomI
 	}
 
 	filter := bson.M{"room_id": roomOID}
-	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if page.Cursor != "" {
+		var position messageCursor
+		if err := contract.DecodeCursor(page.Cursor, &position); err != nil {
+			return contract.Page[*domain.Message]{}, err
+		}
+		positionOID, err := primitive.ObjectIDFromHex(position.ID)
+		if err != nil {
+			return contract.Page[*domain.Message]{}, contract.ErrInvalidCursor
+		}
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": position.CreatedAt}},
+			bson.M{"created_at": position.CreatedAt, "_id": bson.M{"$lt": positionOID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(page.Limit + 1))
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return contract.Page[*domain.Message]{}, err
 	}
 	defer cursor.Close(ctx)
 
 	var messages []*MessageModel
 	if err := cursor.All(ctx, &messages); err != nil {
-		return nil, err
+		return contract.Page[*domain.Message]{}, err
 	}
 
 	var domainMessages []*domain.Message
@@ -58,5 +96,9 @@ func (r *mongoMessageRepository) FindMessagesByRoomID(ctx context.Context, roomI
 		domainMessages = append(domainMessages, ToDomain(msg))
 	}
 
-	return domainMessages, nil
+	result := contract.NewPage(domainMessages, page.Limit, func(m *domain.Message) any {
+		return messageCursor{CreatedAt: m.CreatedAt, ID: m.ID}
+	})
+	slices.Reverse(result.Data)
+	return result, nil
 }
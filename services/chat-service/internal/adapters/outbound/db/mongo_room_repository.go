@@ -7,6 +7,7 @@ import (
 
 	"github.com/wnmay/horo/services/chat-service/internal/domain"
 	outbound_port "github.com/wnmay/horo/services/chat-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/contract"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -20,6 +21,15 @@ type mongoRoomRepository struct {
 func NewMongoRoomRepository(db *mongo.Database, collectionName string) outbound_port.RoomRepositoryPort {
 	collection := db.Collection(collectionName)
 
+	// Serve a user's rooms newest first, whichever side of the room they are on
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "prophet_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Failed to create room indexes: %v", err)
+	}
+
 	return &mongoRoomRepository{
 		collection: collection,
 	}
@@ -130,31 +140,55 @@ func (r *mongoRoomRepository) IsUserInRoom(ctx context.Context, userID string, r
 	return count > 0, nil
 }
 
-func (r *mongoRoomRepository) GetChatRoomsByUserID(ctx context.Context, userID string) ([]*domain.Room, error) {
+// roomCursor is the position of the last room of a page
+type roomCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+func (r *mongoRoomRepository) GetChatRoomsByUserID(ctx context.Context, userID string, page contract.PageRequest) (contract.Page[*domain.Room], error) {
 	filter := bson.M{"$or": []bson.M{
 		{"prophet_id": userID},
 		{"customer_id": userID},
 	}}
-
-	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}) // newest first
+	if page.Cursor != "" {
+		var position roomCursor
+		if err := contract.DecodeCursor(page.Cursor, &position); err != nil {
+			return contract.Page[*domain.Room]{}, err
+		}
+		positionOID, err := primitive.ObjectIDFromHex(position.ID)
+		if err != nil {
+			return contract.Page[*domain.Room]{}, contract.ErrInvalidCursor
+		}
+		filter = bson.M{"$and": []bson.M{filter, {"$or": []bson.M{
+			{"created_at": bson.M{"$lt": position.CreatedAt}},
+			{"created_at": position.CreatedAt, "_id": bson.M{"$lt": positionOID}},
+		}}}}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}). // newest first
+		SetLimit(int64(page.Limit + 1))
 	cursor, err := r.collection.Find(ctx, filter, findOptions)
 	if err != nil {
 		log.Printf("[GetChatRoomsByUserID] MongoDB Find error: %v", err)
-		return nil, err
+		return contract.Page[*domain.Room]{}, err
 	}
 	defer cursor.Close(ctx)
 
 	var rooms []*RoomModel
 	if err := cursor.All(ctx, &rooms); err != nil {
 		log.Printf("[GetChatRoomsByUserID] Cursor.All error: %v", err)
-		return nil, err
+		return contract.Page[*domain.Room]{}, err
 	}
 
 	var domainRooms []*domain.Room
 	for _, rm := range rooms {
 		domainRooms = append(domainRooms, rm.ToDomain())
 	}
-	return domainRooms, nil
+	return contract.NewPage(domainRooms, page.Limit, func(room *domain.Room) any {
+		return roomCursor{CreatedAt: room.CreatedAt, ID: room.ID}
+	}), nil
 }
 
 func (r *mongoRoomRepository) UpdateRoomIsDoneByRoomID(ctx context.Context, roomID string, isDone bool) error {
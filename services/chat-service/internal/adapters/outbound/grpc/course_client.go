@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/wnmay/horo/shared/jwt"
 	pb "github.com/wnmay/horo/shared/proto/course"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -20,6 +21,7 @@ func NewCourseClient(courseServiceAddr string) (*CourseClient, error) {
 	conn, err := grpc.NewClient(
 		courseServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(jwt.UnaryClientService(jwt.ServiceCourse)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to course service: %w", err)
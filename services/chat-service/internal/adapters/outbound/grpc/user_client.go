@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/wnmay/horo/services/chat-service/internal/domain"
+	"github.com/wnmay/horo/shared/jwt"
 	pb "github.com/wnmay/horo/shared/proto/user-management"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -21,6 +22,7 @@ func NewUserClient(userServiceAddr string) (*UserClient, error) {
 	conn, err := grpc.NewClient(
 		userServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(jwt.UnaryClientService(jwt.ServiceUserManagement)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service: %w", err)
@@ -12,6 +12,7 @@ import (
 	"github.com/wnmay/horo/shared/contract"
 	"github.com/wnmay/horo/shared/message"
 	shared_message "github.com/wnmay/horo/shared/message"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type chatService struct {
@@ -29,7 +30,8 @@ type paymentCreatedChatMessage struct {
 	Content     string  `json:"content"`
 	PaymentID   string  `json:"paymentId"`
 	OrderID     string  `json:"orderId"`
-	Amount      float64 `json:"amount"`
+	Amount      money.Decimal `json:"amount"`
+	Currency    string  `json:"currency"`
 	MessageType string  `json:"messageType"`
 }
 
@@ -72,12 +74,12 @@ func (s *chatService) InitiateChatRoom(ctx context.Context, courseID string, cus
 	return roomID, nil
 }
 
-func (s *chatService) PublishPaymentCreatedMessage(ctx context.Context, paymentID string, orderID string, status string, amount float64) error {
+func (s *chatService) PublishPaymentCreatedMessage(ctx context.Context, paymentID string, orderID string, status string, amount money.Decimal, currency string) error {
 	message := domain.CreateMessage(
 		"",
 		"mock-room-id", // TO DO: filter rooomId from payment details after we enrich the payment event data
 		"system",
-		GeneratePaymentCreatedMessage(paymentID, orderID, status, amount),
+		GeneratePaymentCreatedMessage(paymentID, orderID, status, amount, currency),
 		domain.MessageTypeNotification,
 		domain.MessageStatusSent,
 		contract.PaymentCreatedEvent,
@@ -96,6 +98,7 @@ func (s *chatService) PublishPaymentCreatedMessage(ctx context.Context, paymentI
 		OrderID:     orderID,
 		MessageType: string(message.Type),
 		Amount:      amount,
+		Currency:    currency,
 	})
 	if err != nil {
 		return err
@@ -128,8 +131,8 @@ func (s *chatService) PublishOutgoingMessage(ctx context.Context, message *domai
 	})
 }
 
-func (s *chatService) GetMessagesByRoomID(ctx context.Context, roomID string) ([]*domain.Message, error) {
-	return s.messageRepo.FindMessagesByRoomID(ctx, roomID)
+func (s *chatService) GetMessagesByRoomID(ctx context.Context, roomID string, page contract.PageRequest) (contract.Page[*domain.Message], error) {
+	return s.messageRepo.FindMessagesByRoomID(ctx, roomID, page)
 }
 
 func (s *chatService) GetChatRoomsByCustomerID(ctx context.Context, customerID string) ([]*domain.Room, error) {
@@ -159,15 +162,16 @@ func (s *chatService) ValidateRoomAccess(ctx context.Context, userID string, roo
 	return true, "", nil
 }
 
-func (s *chatService) GetChatRoomsByUserID(ctx context.Context, userID string) ([]*domain.RoomWithName, error) {
-	rooms, err := s.roomRepo.GetChatRoomsByUserID(ctx, userID)
+func (s *chatService) GetChatRoomsByUserID(ctx context.Context, userID string, page contract.PageRequest) (contract.Page[*domain.RoomWithName], error) {
+	roomPage, err := s.roomRepo.GetChatRoomsByUserID(ctx, userID, page)
 	if err != nil {
-		return nil, err
+		return contract.Page[*domain.RoomWithName]{}, err
 	}
+	rooms := roomPage.Data
 
 	// Return empty array if no rooms found
 	if len(rooms) == 0 {
-		return []*domain.RoomWithName{}, nil
+		return contract.Page[*domain.RoomWithName]{Data: []*domain.RoomWithName{}}, nil
 	}
 
 	var userIDs []string
@@ -177,7 +181,7 @@ func (s *chatService) GetChatRoomsByUserID(ctx context.Context, userID string) (
 	}
 	users, err := s.userProvider.MapUserNamesByIDs(ctx, userIDs)
 	if err != nil {
-		return nil, err
+		return contract.Page[*domain.RoomWithName]{}, err
 	}
 
 	var roomWithNames []*domain.RoomWithName
@@ -214,7 +218,10 @@ func (s *chatService) GetChatRoomsByUserID(ctx context.Context, userID string) (
 			CustomerName: customerName,
 		})
 	}
-	return roomWithNames, nil
+	return contract.Page[*domain.RoomWithName]{
+		Data:       roomWithNames,
+		NextCursor: roomPage.NextCursor,
+	}, nil
 
 }
 
@@ -252,6 +259,61 @@ func (s *chatService) PublishOrderPaidNotification(ctx context.Context, notifica
 	})
 }
 
+func (s *chatService) PublishOrderBookedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderBookedNotificationData]) error {
+	data, err := json.Marshal(notificationData)
+	if err != nil {
+		return err
+	}
+	return s.messagePublisher.Publish(ctx, contract.AmqpMessage{
+		OwnerID: notificationData.SenderID,
+		Data:    data,
+	})
+}
+
+func (s *chatService) PublishOrderCancelledNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderCancelledNotificationData]) error {
+	data, err := json.Marshal(notificationData)
+	if err != nil {
+		return err
+	}
+	return s.messagePublisher.Publish(ctx, contract.AmqpMessage{
+		OwnerID: notificationData.SenderID,
+		Data:    data,
+	})
+}
+
+func (s *chatService) PublishOrderDisputedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderDisputedNotificationData]) error {
+	data, err := json.Marshal(notificationData)
+	if err != nil {
+		return err
+	}
+	return s.messagePublisher.Publish(ctx, contract.AmqpMessage{
+		OwnerID: notificationData.SenderID,
+		Data:    data,
+	})
+}
+
+func (s *chatService) PublishOrderDisputeResolvedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderDisputeResolvedNotificationData]) error {
+	data, err := json.Marshal(notificationData)
+	if err != nil {
+		return err
+	}
+	return s.messagePublisher.Publish(ctx, contract.AmqpMessage{
+		OwnerID: notificationData.SenderID,
+		Data:    data,
+	})
+}
+
+func (s *chatService) PublishSubscriptionNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.SubscriptionNotificationData]) error {
+	data, err := json.Marshal(notificationData)
+	if err != nil {
+		return err
+	}
+	return s.messagePublisher.Publish(ctx, contract.AmqpMessage{
+		OwnerID: notificationData.SenderID,
+		Data:    data,
+	})
+}
+
 func (s *chatService) UpdateRoomIsDone(ctx context.Context, roomID string, isDone bool) error {
 	return s.roomRepo.UpdateRoomIsDoneByRoomID(ctx, roomID, isDone)
 }
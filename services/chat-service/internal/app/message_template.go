@@ -1,13 +1,20 @@
 package service
 
-func GeneratePaymentCreatedMessage(paymentID string, orderID string, status string, amount float64) string {
+import (
+	"fmt"
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+func GeneratePaymentCreatedMessage(paymentID string, orderID string, status string, amount money.Decimal, currency string) string {
 	return `
 	<div class="message-container">
 		<div class="message-header">
 			<h3>Payment Created</h3>
 		</div>
 		<div class="message-body">
-			<p>Payment created successfully for order %s with payment ID %s. Status: %s, Amount: %f</p>
+			<p>Payment created successfully for order %s with payment ID %s. Status: %s, Amount: %s %s</p>
 		</div>
 	</div>
 	`
@@ -26,28 +33,191 @@ func GenerateOrderCompletedMessage(orderID string, courseID string, orderStatus
 	`
 }
 
-func GenerateOrderPaymentBoundMessage(orderID string, courseID string, orderStatus string, courseName string) string {
-	return `
+func GenerateOrderSessionCompletedMessage(orderID string, courseName string, session int, sessions int) string {
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Session Completed</h3>
+		</div>
+		<div class="message-body">
+			<p>Session %d of %d of %s is complete. Book your next session from order %s.</p>
+		</div>
+	</div>
+	`, session, sessions, courseName, orderID)
+}
+
+func GenerateOrderPaymentBoundMessage(orderID string, courseID string, orderStatus string, courseName string, amount money.Decimal, discount money.Decimal, currency string, couponCode string) string {
+	return fmt.Sprintf(`
 	<div class="message-container">
 		<div class="message-header">
 			<h3>Successfully create payment for order</h3>
 		</div>
 		<div class="message-body">
-			<p>Order %s payment created successfully for course %s. Status: %s, Course Name: %s</p>
+			<p>Order %s payment created successfully for course %s. Status: %s, Course Name: %s, %s</p>
 		</div>
 	</div>
-	`
+	`, orderID, courseID, orderStatus, courseName, formatCharge(amount, discount, currency, couponCode))
 }
 
-func GenerateOrderPaidMessage(orderID string, courseID string, orderStatus string, courseName string) string {
-	return `
+func GenerateOrderPaidMessage(orderID string, courseID string, orderStatus string, courseName string, amount money.Decimal, discount money.Decimal, currency string, couponCode string) string {
+	return fmt.Sprintf(`
 	<div class="message-container">
 		<div class="message-header">
 			<h3>Order Paid</h3>
 		</div>
 		<div class="message-body">
-			<p>Order %s paid successfully for course %s. Status: %s, Course Name: %s</p>
+			<p>Order %s paid successfully for course %s. Status: %s, Course Name: %s, %s</p>
 		</div>
 	</div>
-	`
-}
\ No newline at end of file
+	`, orderID, courseID, orderStatus, courseName, formatCharge(amount, discount, currency, couponCode))
+}
+
+// formatCharge describes the amount charged and any promotion discount on it
+func formatCharge(amount money.Decimal, discount money.Decimal, currency string, couponCode string) string {
+	places := money.MinorUnits(currency)
+	charge := fmt.Sprintf("Amount: %s %s", amount.StringFixed(places), currency)
+	if !discount.IsPositive() {
+		return charge
+	}
+	promotion := "promotion"
+	if couponCode != "" {
+		promotion = "coupon " + couponCode
+	}
+	return fmt.Sprintf("%s (%s %s off with %s)", charge, discount.StringFixed(places), currency, promotion)
+}
+
+func GenerateOrderCancelledMessage(orderID string, courseName string, cancelledBy string, reason string) string {
+	if reason == "" {
+		reason = "No reason given"
+	}
+	// Orders refunded through a dispute have no cancelling party
+	cancelled := "by the " + cancelledBy
+	if cancelledBy == "dispute" {
+		cancelled = "after a dispute"
+	}
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Order Cancelled</h3>
+		</div>
+		<div class="message-body">
+			<p>Order %s for %s was cancelled %s. Reason: %s. Any payment made will be refunded.</p>
+		</div>
+	</div>
+	`, orderID, courseName, cancelled, reason)
+}
+
+func GenerateOrderDisputedMessage(orderID string, courseName string, reason string) string {
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Order Disputed</h3>
+		</div>
+		<div class="message-body">
+			<p>The customer disputed order %s for %s. Reason: %s. Payment is on hold until the dispute is resolved.</p>
+		</div>
+	</div>
+	`, orderID, courseName, reason)
+}
+
+func GenerateOrderDisputeResolvedMessage(orderID string, courseName string, resolution string, note string) string {
+	outcome := "The payment has been released to the prophet."
+	if resolution == "REFUNDED" {
+		outcome = "The customer will be refunded."
+	}
+	if note != "" {
+		outcome += " Note: " + note
+	}
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Dispute Resolved</h3>
+		</div>
+		<div class="message-body">
+			<p>The dispute on order %s for %s has been resolved. %s</p>
+		</div>
+	</div>
+	`, orderID, courseName, outcome)
+}
+
+// GenerateOrderBookedMessage announces the reserved session in the prophet's
+// timezone. start and end are RFC3339 timestamps.
+func GenerateOrderBookedMessage(orderID string, courseName string, start string, end string, timezone string) string {
+	session := formatSession(start, end, timezone)
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Session Booked</h3>
+		</div>
+		<div class="message-body">
+			<p>Order %s for %s is booked for %s.</p>
+		</div>
+	</div>
+	`, orderID, courseName, session)
+}
+
+// GenerateOrderRescheduledMessage announces the session's new time
+func GenerateOrderRescheduledMessage(orderID string, courseName string, start string, end string, timezone string) string {
+	session := formatSession(start, end, timezone)
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Session Rescheduled</h3>
+		</div>
+		<div class="message-body">
+			<p>Order %s for %s is moved to %s.</p>
+		</div>
+	</div>
+	`, orderID, courseName, session)
+}
+
+// formatSession renders an RFC3339 session in the prophet's timezone
+func formatSession(start string, end string, timezone string) string {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	session := start
+	if startAt, err := time.Parse(time.RFC3339, start); err == nil {
+		session = startAt.In(loc).Format("Mon 2 Jan 2006 15:04")
+		if endAt, err := time.Parse(time.RFC3339, end); err == nil {
+			session += " - " + endAt.In(loc).Format("15:04")
+		}
+		session += " (" + loc.String() + ")"
+	}
+	return session
+}
+
+func GenerateSubscriptionRenewedMessage(courseName string, period int, periodEnd string) string {
+	until := ""
+	if periodEndAt, err := time.Parse(time.RFC3339, periodEnd); err == nil {
+		until = " until " + periodEndAt.Format("2 Jan 2006")
+	}
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Subscription Renewed</h3>
+		</div>
+		<div class="message-body">
+			<p>Your subscription to %s is renewed for month %d%s. Book this month's session from its order.</p>
+		</div>
+	</div>
+	`, courseName, period, until)
+}
+
+func GenerateSubscriptionLapsedMessage(courseName string, reason string) string {
+	ended := "was cancelled"
+	if reason == "unpaid" {
+		ended = "lapsed because the renewal was not paid"
+	}
+	return fmt.Sprintf(`
+	<div class="message-container">
+		<div class="message-header">
+			<h3>Subscription Ended</h3>
+		</div>
+		<div class="message-body">
+			<p>Your subscription to %s %s. This room is now closed.</p>
+		</div>
+	</div>
+	`, courseName, ended)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	http_handler "github.com/wnmay/horo/services/chat-service/internal/adapters/inbound/http"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 // SetupFiberApp initializes and configures the Fiber application
@@ -32,6 +33,7 @@ func SetupFiberApp(ChatHandler *http_handler.ChatHandler) *fiber.App {
 	app.Use(recover.New())
 	app.Use(logger.New())
 	app.Use(cors.New())
+	app.Use(jwt.IdentityFromToken())
 
 	// Health check endpoint
 	app.Get("/health", func(c *fiber.Ctx) error {
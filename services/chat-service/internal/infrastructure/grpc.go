@@ -9,6 +9,7 @@ import (
 
 	grpcin "github.com/wnmay/horo/services/chat-service/internal/adapters/inbound/grpc"
 	inbound_port "github.com/wnmay/horo/services/chat-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/jwt"
 	"github.com/wnmay/horo/shared/proto/chat"
 )
 
@@ -18,7 +19,7 @@ func SetupGRPCServer(app inbound_port.ChatService, addr string) (*grpc.Server, n
 		return nil, nil, err
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(grpc.UnaryInterceptor(jwt.UnaryServerIdentity()))
 	chatServer := grpcin.NewChatGRPCServer(app)
 	chat.RegisterChatServiceServer(server, chatServer)
 
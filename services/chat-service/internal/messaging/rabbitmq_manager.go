@@ -47,9 +47,9 @@ func NewMessagingManager(rabbitmqURI string) (*MessagingManager, outbound_port.M
 	return manager, messagePublisher, nil
 }
 
-func (m *MessagingManager) InitializeConsumers(chatService inbound_port.ChatService) {
+func (m *MessagingManager) InitializeConsumers(chatService inbound_port.ChatService, processed message.ProcessedMessageStore) {
 	m.messageIncomingConsumer = consumerRabbit.NewMessageIncomingConsumer(chatService, m.client)
-	m.notificationConsumer = consumerRabbit.NewNotificationConsumer(chatService, m.client)
+	m.notificationConsumer = consumerRabbit.NewNotificationConsumer(chatService, m.client, processed)
 	log.Println("Consumers initialized successfully")
 }
 
@@ -110,9 +110,17 @@ func setupChatQueues(rmq *message.RabbitMQ) error {
 	if err := rmq.DeclareQueueAndBindEvents(
 		message.NotifyOrderCompleted,
 		[]string{
+			contract.OrderCreatedEvent,
 			contract.OrderCompletedEvent,
+			contract.OrderSessionCompletedEvent,
 			contract.OrderPaymentBoundEvent,
 			contract.OrderPaidEvent,
+			contract.OrderCancelledEvent,
+			contract.OrderDisputedEvent,
+			contract.OrderDisputeResolvedEvent,
+			contract.OrderRescheduledEvent,
+			contract.SubscriptionRenewedEvent,
+			contract.SubscriptionLapsedEvent,
 		},
 	); err != nil {
 		return fmt.Errorf("failed to setup notification queue: %v", err)
@@ -4,21 +4,28 @@ import (
 	"context"
 
 	"github.com/wnmay/horo/services/chat-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 	"github.com/wnmay/horo/shared/message"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type ChatService interface {
 	SaveMessage(ctx context.Context, roomID, senderID, content string, messageType domain.MessageType, status domain.MessageStatus, trigger string) (string, error)
 	InitiateChatRoom(ctx context.Context, courseID string, customerID string) (string, error)
-	PublishPaymentCreatedMessage(ctx context.Context, paymentID string, orderID string, status string, amount float64) error
-	GetMessagesByRoomID(ctx context.Context, roomID string) ([]*domain.Message, error)
+	PublishPaymentCreatedMessage(ctx context.Context, paymentID string, orderID string, status string, amount money.Decimal, currency string) error
+	GetMessagesByRoomID(ctx context.Context, roomID string, page contract.PageRequest) (contract.Page[*domain.Message], error)
 	GetChatRoomsByCustomerID(ctx context.Context, customerID string) ([]*domain.Room, error)
 	GetChatRoomsByProphetID(ctx context.Context, prophetID string) ([]*domain.Room, error)
 	PublishOutgoingMessage(ctx context.Context, message *domain.Message) error
 	ValidateRoomAccess(ctx context.Context, userID, roomID string) (allowed bool, reason string, err error)
-	GetChatRoomsByUserID(ctx context.Context, userID string) ([]*domain.RoomWithName, error)
+	GetChatRoomsByUserID(ctx context.Context, userID string, page contract.PageRequest) (contract.Page[*domain.RoomWithName], error)
 	PublishOrderCompletedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderCompletedNotificationData]) error
 	PublishOrderPaymentBoundNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderPaymentBoundNotificationData]) error
 	PublishOrderPaidNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderPaidNotificationData]) error
+	PublishOrderBookedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderBookedNotificationData]) error
+	PublishOrderCancelledNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderCancelledNotificationData]) error
+	PublishOrderDisputedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderDisputedNotificationData]) error
+	PublishOrderDisputeResolvedNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.OrderDisputeResolvedNotificationData]) error
+	PublishSubscriptionNotification(ctx context.Context, notificationData message.ChatNotificationOutgoingData[message.SubscriptionNotificationData]) error
 	UpdateRoomIsDone(ctx context.Context, roomID string, isDone bool) error
 }
@@ -4,9 +4,10 @@ import (
 	"context"
 
 	"github.com/wnmay/horo/services/chat-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 )
 
 type MessageRepository interface {
 	SaveMessage(ctx context.Context, message *domain.Message) (string, error)
-	FindMessagesByRoomID(ctx context.Context, roomID string) ([]*domain.Message, error)
+	FindMessagesByRoomID(ctx context.Context, roomID string, page contract.PageRequest) (contract.Page[*domain.Message], error)
 }
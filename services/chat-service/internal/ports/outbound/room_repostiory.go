@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/wnmay/horo/services/chat-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 )
 type RoomRepositoryPort interface {
 	FindRoomByID(ctx context.Context, roomID string) (*domain.Room, error)
@@ -12,6 +13,6 @@ type RoomRepositoryPort interface {
 	GetChatRoomsByProphetID(ctx context.Context, prophetID string) ([]*domain.Room, error)
 	RoomExists(ctx context.Context, roomID string) (bool, error)
 	IsUserInRoom(ctx context.Context, roomID string, userID string) (bool, error)
-	GetChatRoomsByUserID(ctx context.Context, userID string) ([]*domain.Room, error)
+	GetChatRoomsByUserID(ctx context.Context, userID string, page contract.PageRequest) (contract.Page[*domain.Room], error)
 	UpdateRoomIsDoneByRoomID(ctx context.Context, roomID string, isDone bool) error
 }
\ No newline at end of file
@@ -42,8 +42,29 @@ func toPbCourse(c *domain.Course) *pb.Course {
 		ProphetId:   c.ProphetID,
 		Coursename:  c.CourseName,
 		Description: c.Description,
-		Price:       c.Price,
+		Price:       c.Price.Float64(),
 		Duration:    toPbDuration(c.Duration),
 		CreatedTime: timestamppb.New(c.CreatedAt),
+		Coursetype:  string(c.CourseType),
+		Currency:    c.Currency,
+		Sessions:    int32(max(c.Sessions, 1)),
+	}
+}
+
+func toPbBooking(b *domain.Booking) *pb.Booking {
+	if b == nil {
+		return nil
+	}
+	return &pb.Booking{
+		Id:         b.ID,
+		CourseId:   b.CourseID,
+		ProphetId:  b.ProphetID,
+		CustomerId: b.CustomerID,
+		OrderId:    b.OrderID,
+		StartTime:  timestamppb.New(b.StartAt),
+		EndTime:    timestamppb.New(b.EndAt),
+		Timezone:   b.Timezone,
+		Status:     string(b.Status),
+		Session:    int32(max(b.Session, 1)),
 	}
 }
@@ -2,9 +2,17 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wnmay/horo/services/course-service/internal/app"
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/jwt"
+	"github.com/wnmay/horo/shared/money"
 	pb "github.com/wnmay/horo/shared/proto/course"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type CourseGRPCServer struct {
@@ -17,12 +25,21 @@ func NewCourseGRPCServer(s app.CourseService) *CourseGRPCServer {
 }
 
 func (s *CourseGRPCServer) CreateCourse(ctx context.Context, req *pb.CreateCourseRequest) (*pb.CreateCourseResponse, error) {
+	if err := jwt.AuthorizeUser(ctx, req.GetProphetId()); err != nil {
+		return nil, err
+	}
+	price, err := money.NewFromFloat(req.GetPrice())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	in := app.CreateCourseInput{
 		ProphetID:   req.GetProphetId(),
 		CourseName:  req.GetCoursename(),
 		Description: req.GetDescription(),
-		Price:       req.GetPrice(),
+		Price:       price,
+		Currency:    req.GetCurrency(),
 		Duration:    toDomainDuration(req.GetDuration()),
+		Sessions:    int(req.GetSessions()),
 	}
 	c, err := s.svc.CreateCourse(ctx, in)
 	if err != nil {
@@ -34,7 +51,55 @@ func (s *CourseGRPCServer) CreateCourse(ctx context.Context, req *pb.CreateCours
 func (s *CourseGRPCServer) GetCourseByID(ctx context.Context, req *pb.GetCourseByIDRequest) (*pb.GetCourseByIDResponse, error) {
 	c, err := s.svc.GetCourseByID(ctx, req.GetId())
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Error(codes.NotFound, domain.ErrCourseNotFound.Error())
+		}
 		return nil, err
 	}
 	return &pb.GetCourseByIDResponse{Course: toPbCourse(c)}, nil
-}
\ No newline at end of file
+}
+
+// ReserveSlot and ReleaseSlot are only for order-service, which books and
+// frees sessions as orders change
+func (s *CourseGRPCServer) ReserveSlot(ctx context.Context, req *pb.ReserveSlotRequest) (*pb.ReserveSlotResponse, error) {
+	if err := jwt.RequireService(ctx, jwt.ServiceOrder); err != nil {
+		return nil, err
+	}
+	if req.GetStartTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time is required")
+	}
+	b, err := s.svc.ReserveSlot(ctx, app.ReserveSlotInput{
+		CourseID:   req.GetCourseId(),
+		CustomerID: req.GetCustomerId(),
+		OrderID:    req.GetOrderId(),
+		Session:    int(req.GetSession()),
+		StartAt:    req.GetStartTime().AsTime(),
+	})
+	if err != nil {
+		return nil, toBookingStatusError(err)
+	}
+	return &pb.ReserveSlotResponse{Booking: toPbBooking(b)}, nil
+}
+
+func (s *CourseGRPCServer) ReleaseSlot(ctx context.Context, req *pb.ReleaseSlotRequest) (*pb.ReleaseSlotResponse, error) {
+	if err := jwt.RequireService(ctx, jwt.ServiceOrder); err != nil {
+		return nil, err
+	}
+	if err := s.svc.ReleaseSlot(ctx, req.GetBookingId()); err != nil {
+		return nil, toBookingStatusError(err)
+	}
+	return &pb.ReleaseSlotResponse{}, nil
+}
+
+func toBookingStatusError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrCourseNotFound), errors.Is(err, domain.ErrBookingNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrNoAvailability), errors.Is(err, domain.ErrSlotUnavailable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrSlotTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -1,12 +1,16 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"log"
 	"strconv"
 	"time"
 
 	"github.com/wnmay/horo/services/course-service/internal/app"
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -25,8 +29,12 @@ func (h *Handler) Register(router fiber.Router) {
 	group := router.Group("/api")
 	group.Post("/courses", h.CreateCourse)
 	group.Get("/courses/popular", h.ListPopularCourses)
+	group.Get("/courses/trending", h.ListTrendingCourses)
+	group.Get("/courses/recommended", h.ListRecommendedCourses)
 	group.Get("/courses/:id", h.GetCourseByID)
 	group.Get("/prophets/:prophetID/courses", h.ListCoursesByProphet)
+	group.Post("/prophets/:prophetID/suspend", h.SuspendProphet)
+	group.Post("/prophets/:prophetID/reinstate", h.ReinstateProphet)
 	group.Patch("/courses/:id", h.UpdateCourse)
 	group.Patch("/courses/delete/:id", h.DeleteCourse)
 	group.Get("/courses", h.FindCoursesByFilter)
@@ -35,17 +43,25 @@ func (h *Handler) Register(router fiber.Router) {
 	group.Post("/courses/:courseID/review", h.CreateReview)
 	group.Get("/courses/review/:id", h.GetReviewByID)
 	group.Get("/courses/:courseID/reviews", h.GetReviewByCourseID)
+	group.Patch("/courses/review/:id", h.UpdateReview)
+	group.Delete("/courses/review/:id", h.DeleteReview)
+	group.Post("/courses/review/:id/reply", h.ReplyToReview)
+	group.Post("/courses/review/:id/flag", h.FlagReview)
+	// Review moderation
+	group.Get("/courses/reviews/moderation", h.ListModerationQueue)
+	group.Post("/courses/review/:id/hide", h.HideReview)
+	group.Post("/courses/review/:id/restore", h.RestoreReview)
+	// Availability & booking
+	group.Put("/courses/availability", h.SetAvailability)
+	group.Get("/courses/availability/:prophetID", h.GetAvailability)
+	group.Get("/courses/bookings/prophet", h.ListCurrentProphetBookings)
+	group.Get("/courses/:id/slots", h.ListAvailableSlots)
 }
 
 func (h *Handler) ListPopularCourses(c *fiber.Ctx) error {
-	limit := c.Query("limit")
-	if limit == "" {
-		limit = "10"
-	}
-	limitInt, err := strconv.Atoi(limit)
-	log.Println("limitInt", limitInt)
+	limitInt, err := listLimit(c)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "invalid limit")
+		return err
 	}
 	courses, err := h.service.ListPopularCourses(c.Context(), limitInt)
 	if err != nil {
@@ -55,6 +71,48 @@ func (h *Handler) ListPopularCourses(c *fiber.Ctx) error {
 	return c.JSON(courses)
 }
 
+// ListTrendingCourses — GET /courses/trending
+func (h *Handler) ListTrendingCourses(c *fiber.Ctx) error {
+	limit, err := listLimit(c)
+	if err != nil {
+		return err
+	}
+	courses, err := h.service.ListTrendingCourses(c.Context(), limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(courses)
+}
+
+// ListRecommendedCourses — GET /courses/recommended, for the current customer
+func (h *Handler) ListRecommendedCourses(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != "customer" {
+		return fiber.NewError(fiber.StatusForbidden, "only customers get recommendations")
+	}
+	limit, err := listLimit(c)
+	if err != nil {
+		return err
+	}
+	courses, err := h.service.ListRecommendedCourses(c.Context(), c.Get("X-User-ID"), limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(courses)
+}
+
+// listLimit reads how many courses a course list should return, 10 by default
+func listLimit(c *fiber.Ctx) (int, error) {
+	limit := c.Query("limit")
+	if limit == "" {
+		return 10, nil
+	}
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil || limitInt < 1 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid limit")
+	}
+	return min(limitInt, contract.MaxPageLimit), nil
+}
+
 // CreateCourse — POST /courses
 func (h *Handler) CreateCourse(c *fiber.Ctx) error {
 	userRole := c.Get("X-User-Role")
@@ -67,8 +125,10 @@ func (h *Handler) CreateCourse(c *fiber.Ctx) error {
 		CourseName  string  `json:"coursename"`
 		CourseType  string  `json:"coursetype"`
 		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		Duration    int32   `json:"duration"`
+		Price       money.Decimal `json:"price"`
+		Currency    string        `json:"currency"`
+		Duration    int32         `json:"duration"`
+		Sessions    int           `json:"sessions"` // more than 1 sells a bundle
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -81,11 +141,19 @@ func (h *Handler) CreateCourse(c *fiber.Ctx) error {
 		CourseType:  domain.CourseType(req.CourseType),
 		Description: req.Description,
 		Price:       req.Price,
+		Currency:    req.Currency,
 		Duration:    domain.DurationEnum(req.Duration),
+		Sessions:    req.Sessions,
 	}
 
 	course, err := h.service.CreateCourse(c.Context(), input)
 	if err != nil {
+		if errors.Is(err, money.ErrInvalidCurrency) || errors.Is(err, domain.ErrInvalidSessions) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, domain.ErrProphetSuspended) {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -124,6 +192,28 @@ func (h *Handler) ListCoursesByProphet(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// SuspendProphet — POST /prophets/:prophetID/suspend
+func (h *Handler) SuspendProphet(c *fiber.Ctx) error {
+	return h.setProphetSuspended(c, h.service.SuspendProphet)
+}
+
+// ReinstateProphet — POST /prophets/:prophetID/reinstate
+func (h *Handler) ReinstateProphet(c *fiber.Ctx) error {
+	return h.setProphetSuspended(c, h.service.ReinstateProphet)
+}
+
+func (h *Handler) setProphetSuspended(c *fiber.Ctx, set func(ctx context.Context, prophetID string) error) error {
+	if c.Get("X-User-Role") != "admin" {
+		return fiber.NewError(fiber.StatusForbidden, "only admins can suspend prophets")
+	}
+
+	prophetID := c.Params("prophetID")
+	if err := set(c.Context(), prophetID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(fiber.Map{"prophet_id": prophetID})
+}
+
 // UpdateCourse — PATCH /courses/:id
 func (h *Handler) UpdateCourse(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -133,6 +223,9 @@ func (h *Handler) UpdateCourse(c *fiber.Ctx) error {
 	}
 	out, err := h.service.UpdateCourse(c.Context(), id, &in)
 	if err != nil {
+		if errors.Is(err, money.ErrInvalidCurrency) || errors.Is(err, domain.ErrInvalidSessions) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(fiber.Map{"message": "updated", "data": out})
@@ -147,9 +240,14 @@ func (h *Handler) DeleteCourse(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"message": "deleted_at"})
 }
 
-// FindCoursesbyFilter — GET /courses?coursename=&prophet_name=&duration=
+// FindCoursesbyFilter — GET /courses?searchterm=&duration=&coursetype=&currency=&minprice=&maxprice=&minrating=&cursor=&limit=
 // GetAllCourses — GET /courses
 func (h *Handler) FindCoursesByFilter(c *fiber.Ctx) error {
+	page, err := contract.ParsePageRequest(c.Query("cursor"), c.Query("limit"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
 	searchTerm := c.Query("searchterm")
 	duration := c.Query("duration")
 	courseType := c.Query("coursetype")
@@ -160,6 +258,28 @@ func (h *Handler) FindCoursesByFilter(c *fiber.Ctx) error {
 		SearchTerm: searchTerm,
 		Duration:   duration,
 		CourseType: app.ParseCourseType(courseType),
+		Currency:   c.Query("currency"),
+	}
+	if v := c.Query("minprice"); v != "" {
+		price, err := money.Parse(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid minprice")
+		}
+		filter.MinPrice = &price
+	}
+	if v := c.Query("maxprice"); v != "" {
+		price, err := money.Parse(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid maxprice")
+		}
+		filter.MaxPrice = &price
+	}
+	if v := c.Query("minrating"); v != "" {
+		rating, err := strconv.ParseFloat(v, 64)
+		if err != nil || rating < 0 || rating > domain.MaxReviewScore {
+			return fiber.NewError(fiber.StatusBadRequest, "minrating must be between 0 and 5")
+		}
+		filter.MinRating = rating
 	}
 
 	sort := app.CourseSort{
@@ -167,32 +287,39 @@ func (h *Handler) FindCoursesByFilter(c *fiber.Ctx) error {
 		Order:  order,
 	}
 
-	courses, err := h.service.FindCoursesByFilter(c.Context(), filter, sort)
+	result, err := h.service.FindCoursesByFilter(c.Context(), filter, sort, page)
 	if err != nil {
+		if errors.Is(err, money.ErrInvalidCurrency) || errors.Is(err, contract.ErrInvalidCursor) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	if len(courses) == 0 {
+	if len(result.Courses) == 0 {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
 			"message": "No courses found matching the filter",
 			"data":    []interface{}{},
+			"total":   result.Total,
+			"facets":  result.Facets,
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"count": len(courses),
-		"data":  courses,
+		"count":       len(result.Courses),
+		"data":        result.Courses,
+		"next_cursor": result.NextCursor,
+		"total":       result.Total,
+		"facets":      result.Facets,
 	})
 }
 
 // CreateReview — POST /courses/:courseID/review
 func (h *Handler) CreateReview(c *fiber.Ctx) error {
 	var req struct {
-		CustomerID   string  `json:"customer_id"`
-		CustomerName string  `json:"customername"`
-		Score        float64 `json:"score"`
-		Title        string  `json:"title"`
-		Description  string  `json:"description"`
+		OrderID     string  `json:"order_id"`
+		Score       float64 `json:"score"`
+		Title       string  `json:"title"`
+		Description string  `json:"description"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -200,34 +327,148 @@ func (h *Handler) CreateReview(c *fiber.Ctx) error {
 	}
 
 	// Validation
-	if req.CustomerID == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "customer_id is required")
-	}
-	if req.CustomerName == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "customername is required")
-	}
-	if req.Score < 0 || req.Score > 5 {
-		return fiber.NewError(fiber.StatusBadRequest, "score must be between 0 and 5")
+	if req.OrderID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "order_id is required")
 	}
 
 	input := app.CreateReviewInput{
-		CourseID:     c.Params("courseId"),
-		CustomerID:   req.CustomerID,
-		CustomerName: req.CustomerName,
-		Score:        req.Score,
-		Title:        req.Title,
-		Description:  req.Description,
-		DeletedAt:    false,
+		CourseID:    c.Params("courseId"),
+		OrderID:     req.OrderID,
+		CustomerID:  c.Get("X-User-ID"),
+		Score:       req.Score,
+		Title:       req.Title,
+		Description: req.Description,
+		DeletedAt:   false,
 	}
 
 	review, err := h.service.CreateReview(c.Context(), input)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return reviewError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(review)
+}
+
+// UpdateReview — PATCH /courses/review/:id
+func (h *Handler) UpdateReview(c *fiber.Ctx) error {
+	var in domain.UpdateReviewInput
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	review, err := h.service.UpdateReview(c.Context(), c.Params("id"), c.Get("X-User-ID"), &in)
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(fiber.Map{"message": "updated", "data": review})
+}
+
+// DeleteReview — DELETE /courses/review/:id
+func (h *Handler) DeleteReview(c *fiber.Ctx) error {
+	if err := h.service.DeleteReview(c.Context(), c.Params("id"), c.Get("X-User-ID")); err != nil {
+		return reviewError(err)
 	}
+	return c.JSON(fiber.Map{"message": "deleted_at"})
+}
 
+// ReplyToReview — POST /courses/review/:id/reply
+func (h *Handler) ReplyToReview(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != "prophet" {
+		return fiber.NewError(fiber.StatusForbidden, "only prophets can reply to reviews")
+	}
+
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	review, err := h.service.ReplyToReview(c.Context(), c.Params("id"), c.Get("X-User-ID"), req.Message)
+	if err != nil {
+		return reviewError(err)
+	}
 	return c.Status(fiber.StatusCreated).JSON(review)
 }
 
+// FlagReview — POST /courses/review/:id/flag
+func (h *Handler) FlagReview(c *fiber.Ctx) error {
+	role := c.Get("X-User-Role")
+	if role != "customer" && role != "prophet" {
+		return fiber.NewError(fiber.StatusForbidden, "only customers and prophets can flag reviews")
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.service.FlagReview(c.Context(), c.Params("id"), c.Get("X-User-ID"), role, req.Reason); err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(fiber.Map{"message": "flagged"})
+}
+
+// ListModerationQueue — GET /courses/reviews/moderation?status= (defaults to PENDING)
+func (h *Handler) ListModerationQueue(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != "admin" {
+		return fiber.NewError(fiber.StatusForbidden, "only admins can moderate reviews")
+	}
+
+	status := domain.ModerationPending
+	if v := c.Query("status"); v != "" {
+		parsed, err := domain.ParseModerationStatus(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		status = parsed
+	}
+
+	reviews, err := h.service.ListModerationQueue(c.Context(), status)
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(fiber.Map{
+		"status":  status,
+		"count":   len(reviews),
+		"reviews": reviews,
+	})
+}
+
+// HideReview — POST /courses/review/:id/hide
+func (h *Handler) HideReview(c *fiber.Ctx) error {
+	return h.moderateReview(c, h.service.HideReview)
+}
+
+// RestoreReview — POST /courses/review/:id/restore
+func (h *Handler) RestoreReview(c *fiber.Ctx) error {
+	return h.moderateReview(c, h.service.RestoreReview)
+}
+
+func (h *Handler) moderateReview(c *fiber.Ctx, moderate func(ctx context.Context, id string, adminID string, note string) (*domain.ModeratedReview, error)) error {
+	if c.Get("X-User-Role") != "admin" {
+		return fiber.NewError(fiber.StatusForbidden, "only admins can moderate reviews")
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	// The note is optional, so an empty body is fine
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	review, err := moderate(c.Context(), c.Params("id"), c.Get("X-User-ID"), req.Note)
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(review)
+}
+
 // GetReviewByID — GET /courses/review/:id
 func (h *Handler) GetReviewByID(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -240,20 +481,29 @@ func (h *Handler) GetReviewByID(c *fiber.Ctx) error {
 	return c.JSON(review)
 }
 
-// GetReviewByCourseID — GET /courses/:courseId/reviews
+// GetReviewByCourseID — GET /courses/:courseId/reviews?cursor=&limit= (newest first)
 func (h *Handler) GetReviewByCourseID(c *fiber.Ctx) error {
 	courseId := c.Params("courseId")
 
-	reviews, err := h.service.ListReviewsByCourse(c.Context(), courseId)
+	page, err := contract.ParsePageRequest(c.Query("cursor"), c.Query("limit"))
 	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	reviews, err := h.service.ListReviewsByCourse(c.Context(), courseId, page)
+	if err != nil {
+		if errors.Is(err, contract.ErrInvalidCursor) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
 	response := fiber.Map{
-		"timestamp": time.Now(),
-		"course_id": courseId,
-		"count":     len(reviews),
-		"reviews":   reviews,
+		"timestamp":   time.Now(),
+		"course_id":   courseId,
+		"count":       len(reviews.Data),
+		"reviews":     reviews.Data,
+		"next_cursor": reviews.NextCursor,
 	}
 
 	return c.JSON(response)
@@ -270,3 +520,110 @@ func (h *Handler) ListCurrentProphetCourses(c *fiber.Ctx) error {
 
 	return c.JSON(courses)
 }
+
+// SetAvailability — PUT /courses/availability
+func (h *Handler) SetAvailability(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != "prophet" {
+		return fiber.NewError(fiber.StatusForbidden, "only prophets can publish availability")
+	}
+
+	var req struct {
+		Timezone   string                         `json:"timezone"`
+		Weekly     []domain.WeeklySlot            `json:"weekly"`
+		Exceptions []domain.AvailabilityException `json:"exceptions"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	availability, err := h.service.SetAvailability(c.Context(), app.SetAvailabilityInput{
+		ProphetID:  c.Get("X-User-ID"),
+		Timezone:   req.Timezone,
+		Weekly:     req.Weekly,
+		Exceptions: req.Exceptions,
+	})
+	if err != nil {
+		return bookingError(err)
+	}
+	return c.JSON(availability)
+}
+
+// GetAvailability — GET /courses/availability/:prophetID
+func (h *Handler) GetAvailability(c *fiber.Ctx) error {
+	availability, err := h.service.GetAvailability(c.Context(), c.Params("prophetID"))
+	if err != nil {
+		return bookingError(err)
+	}
+	return c.JSON(availability)
+}
+
+// ListAvailableSlots — GET /courses/:id/slots?from=&to= (RFC3339, defaults to the next 7 days)
+func (h *Handler) ListAvailableSlots(c *fiber.Ctx) error {
+	from := time.Now()
+	to := from.Add(7 * 24 * time.Hour)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "from must be an RFC3339 timestamp")
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "to must be an RFC3339 timestamp")
+		}
+		to = t
+	}
+
+	slots, err := h.service.ListAvailableSlots(c.Context(), c.Params("id"), from, to)
+	if err != nil {
+		return bookingError(err)
+	}
+	return c.JSON(fiber.Map{
+		"course_id": c.Params("id"),
+		"count":     len(slots),
+		"slots":     slots,
+	})
+}
+
+// ListCurrentProphetBookings — GET /courses/bookings/prophet
+func (h *Handler) ListCurrentProphetBookings(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != "prophet" {
+		return fiber.NewError(fiber.StatusForbidden, "only prophets can view their bookings")
+	}
+
+	bookings, err := h.service.ListProphetBookings(c.Context(), c.Get("X-User-ID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(bookings)
+}
+
+func bookingError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAvailability):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrCourseNotFound), errors.Is(err, domain.ErrNoAvailability), errors.Is(err, domain.ErrBookingNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrSlotUnavailable), errors.Is(err, domain.ErrSlotTaken):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+}
+
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidScore), errors.Is(err, domain.ErrEmptyReply):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrReviewNotAllowed), errors.Is(err, domain.ErrNotReviewAuthor), errors.Is(err, domain.ErrNotCourseProphet):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrReviewNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrAlreadyReviewed), errors.Is(err, domain.ErrAlreadyReplied), errors.Is(err, domain.ErrAlreadyFlagged):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+}
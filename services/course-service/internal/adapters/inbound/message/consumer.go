@@ -0,0 +1,68 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/wnmay/horo/services/course-service/internal/app"
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/message"
+)
+
+type Consumer struct {
+	courseService app.CourseService
+	rabbit        *message.RabbitMQ
+	processed     message.ProcessedMessageStore
+}
+
+func NewConsumer(courseService app.CourseService, rabbit *message.RabbitMQ, processed message.ProcessedMessageStore) *Consumer {
+	return &Consumer{
+		courseService: courseService,
+		rabbit:        rabbit,
+		processed:     processed,
+	}
+}
+
+func (c *Consumer) StartListening() error {
+	// Completed orders feed trending courses and recommendations
+	completionQueue := message.CourseCompletionQueue
+	if err := c.rabbit.DeclareQueue(completionQueue, contract.OrderCompletedEvent); err != nil {
+		return err
+	}
+	return c.rabbit.ConsumeMessages(completionQueue, message.Idempotent(c.processed, completionQueue, c.handleOrderCompleted))
+}
+
+func (c *Consumer) handleOrderCompleted(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Received order completed event: %s", delivery.Body)
+
+	var amqpMessage contract.AmqpMessage
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	var orderCompletedData message.OrderCompletedData
+	if err := json.Unmarshal(amqpMessage.Data, &orderCompletedData); err != nil {
+		log.Printf("Failed to unmarshal order data: %v", err)
+		return err
+	}
+
+	input := app.RecordCompletionInput{
+		OrderID:     orderCompletedData.OrderID,
+		CourseID:    orderCompletedData.CourseID,
+		CustomerID:  orderCompletedData.CustomerID,
+		ProphetID:   orderCompletedData.ProphetID,
+		CourseType:  domain.CourseType(orderCompletedData.CourseType),
+		CompletedAt: delivery.Timestamp,
+	}
+	if err := c.courseService.RecordCompletion(ctx, input); err != nil {
+		log.Printf("Failed to record completion of order %s: %v", orderCompletedData.OrderID, err)
+		return err
+	}
+
+	log.Printf("Recorded completion of order %s for course %s", orderCompletedData.OrderID, orderCompletedData.CourseID)
+	return nil
+}
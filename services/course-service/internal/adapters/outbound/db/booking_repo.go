@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoBookingRepo stores prophet availability and session bookings. Each
+// booked SlotGranularity block also gets a document in "booking_slots" keyed
+// by prophet and block start, so the unique _id index rejects double-booking
+// even when two reservations race.
+type MongoBookingRepo struct {
+	availabilityCol *mongo.Collection
+	bookingCol      *mongo.Collection
+	slotCol         *mongo.Collection
+}
+
+type bookingSlot struct {
+	ID        string    `bson:"_id"`
+	ProphetID string    `bson:"prophet_id"`
+	BookingID string    `bson:"booking_id"`
+	StartAt   time.Time `bson:"start_at"`
+}
+
+func NewMongoBookingRepo(db *mongo.Database) *MongoBookingRepo {
+	return &MongoBookingRepo{
+		availabilityCol: db.Collection("availability"),
+		bookingCol:      db.Collection("bookings"),
+		slotCol:         db.Collection("booking_slots"),
+	}
+}
+
+func (r *MongoBookingRepo) SaveAvailability(ctx context.Context, availability *domain.Availability) error {
+	_, err := r.availabilityCol.ReplaceOne(ctx,
+		bson.M{"prophet_id": availability.ProphetID},
+		availability,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *MongoBookingRepo) FindAvailability(ctx context.Context, prophetID string) (*domain.Availability, error) {
+	var a domain.Availability
+	err := r.availabilityCol.FindOne(ctx, bson.M{"prophet_id": prophetID}).Decode(&a)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNoAvailability
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *MongoBookingRepo) ReserveBooking(ctx context.Context, booking *domain.Booking) error {
+	slots := make([]interface{}, 0)
+	for _, block := range booking.Blocks() {
+		slots = append(slots, bookingSlot{
+			ID:        slotKey(booking.ProphetID, block),
+			ProphetID: booking.ProphetID,
+			BookingID: booking.ID,
+			StartAt:   block,
+		})
+	}
+
+	if _, err := r.slotCol.InsertMany(ctx, slots); err != nil {
+		// Undo the blocks this attempt managed to claim before the conflict
+		_, _ = r.slotCol.DeleteMany(ctx, bson.M{"booking_id": booking.ID})
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrSlotTaken
+		}
+		return err
+	}
+
+	if _, err := r.bookingCol.InsertOne(ctx, booking); err != nil {
+		_, _ = r.slotCol.DeleteMany(ctx, bson.M{"booking_id": booking.ID})
+		return err
+	}
+	return nil
+}
+
+func (r *MongoBookingRepo) MoveBooking(ctx context.Context, from, to *domain.Booking) error {
+	owned := make(map[string]bool)
+	for _, block := range from.Blocks() {
+		owned[slotKey(from.ProphetID, block)] = true
+	}
+
+	slots := make([]interface{}, 0)
+	shared := make([]string, 0)
+	for _, block := range to.Blocks() {
+		key := slotKey(to.ProphetID, block)
+		if owned[key] {
+			shared = append(shared, key)
+			continue
+		}
+		slots = append(slots, bookingSlot{
+			ID:        key,
+			ProphetID: to.ProphetID,
+			BookingID: to.ID,
+			StartAt:   block,
+		})
+	}
+
+	if len(slots) > 0 {
+		if _, err := r.slotCol.InsertMany(ctx, slots); err != nil {
+			_, _ = r.slotCol.DeleteMany(ctx, bson.M{"booking_id": to.ID})
+			if mongo.IsDuplicateKeyError(err) {
+				return domain.ErrSlotTaken
+			}
+			return err
+		}
+	}
+
+	undo := func() {
+		_, _ = r.slotCol.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": shared}, "booking_id": to.ID},
+			bson.M{"$set": bson.M{"booking_id": from.ID}},
+		)
+		_, _ = r.slotCol.DeleteMany(ctx, bson.M{"booking_id": to.ID})
+	}
+	if len(shared) > 0 {
+		if _, err := r.slotCol.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": shared}, "booking_id": from.ID},
+			bson.M{"$set": bson.M{"booking_id": to.ID}},
+		); err != nil {
+			undo()
+			return err
+		}
+	}
+
+	if _, err := r.bookingCol.InsertOne(ctx, to); err != nil {
+		undo()
+		return err
+	}
+	return r.ReleaseBooking(ctx, from.ID)
+}
+
+func (r *MongoBookingRepo) FindBookingByID(ctx context.Context, id string) (*domain.Booking, error) {
+	var b domain.Booking
+	err := r.bookingCol.FindOne(ctx, bson.M{"id": id}).Decode(&b)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *MongoBookingRepo) FindReservedBookingByOrder(ctx context.Context, orderID string, session int) (*domain.Booking, error) {
+	filter := bson.M{"order_id": orderID, "status": domain.BookingStatusReserved, "session": session}
+	if session == 1 {
+		filter["session"] = bson.M{"$in": bson.A{1, nil}}
+	}
+	var b domain.Booking
+	err := r.bookingCol.FindOne(ctx, filter).Decode(&b)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *MongoBookingRepo) ReleaseBooking(ctx context.Context, id string) error {
+	if _, err := r.bookingCol.UpdateOne(ctx,
+		bson.M{"id": id},
+		bson.M{"$set": bson.M{"status": domain.BookingStatusReleased}},
+	); err != nil {
+		return err
+	}
+	_, err := r.slotCol.DeleteMany(ctx, bson.M{"booking_id": id})
+	return err
+}
+
+func (r *MongoBookingRepo) FindBookedBlocks(ctx context.Context, prophetID string, from, to time.Time) ([]time.Time, error) {
+	cur, err := r.slotCol.Find(ctx, bson.M{
+		"prophet_id": prophetID,
+		"start_at":   bson.M{"$gte": from, "$lt": to},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var blocks []time.Time
+	for cur.Next(ctx) {
+		var slot bookingSlot
+		if err := cur.Decode(&slot); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, slot.StartAt)
+	}
+	return blocks, nil
+}
+
+func (r *MongoBookingRepo) FindBookingsByProphet(ctx context.Context, prophetID string, from time.Time) ([]*domain.Booking, error) {
+	cur, err := r.bookingCol.Find(ctx,
+		bson.M{"prophet_id": prophetID, "status": domain.BookingStatusReserved, "end_at": bson.M{"$gt": from}},
+		options.Find().SetSort(bson.D{{Key: "start_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var bookings []*domain.Booking
+	for cur.Next(ctx) {
+		var b domain.Booking
+		if err := cur.Decode(&b); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, &b)
+	}
+	return bookings, nil
+}
+
+func slotKey(prophetID string, block time.Time) string {
+	return prophetID + "|" + block.UTC().Format(time.RFC3339)
+}
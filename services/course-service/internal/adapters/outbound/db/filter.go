@@ -2,18 +2,21 @@ package db
 
 import (
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
+	"github.com/wnmay/horo/shared/money"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 type CourseFilter struct {
 	SearchTerm string
-	ProphetIDs []string
 	Duration   string
 	CourseType string
+	Currency   string
+	MinPrice   *money.Decimal
+	MaxPrice   *money.Decimal
+	MinRating  float64
 }
 
 type CourseSort struct {
@@ -21,38 +24,21 @@ type CourseSort struct {
 	Order  string // "asc", "desc"
 }
 
+// PriceBuckets are the lower bounds of the price facet's buckets, in each
+// course's own currency; the last bucket is open ended
+var PriceBuckets = []int64{0, 500, 1000, 2000, 5000}
+
+// searchScoreField holds a course's text search relevance in the pipeline
+const searchScoreField = "search_score"
+
 func BuildMongoQuery(filter CourseFilter, sort CourseSort) (bson.M, bson.D, error) {
-	mongoFilter := bson.M{}
-
-	// Always filter out deleted courses
-	mongoFilter["deleted_at"] = false
-
-	// Handle SearchTerm with OR logic: match course name OR prophet ID
-	if filter.SearchTerm != "" && len(filter.ProphetIDs) > 0 {
-		// If we have both search term and prophet IDs, use OR logic
-		log.Println("Building OR query with SearchTerm and ProphetIDs")
-		mongoFilter["$or"] = []bson.M{
-			{
-				"coursename": bson.M{
-					"$regex":   strings.TrimSpace(filter.SearchTerm),
-					"$options": "i",
-				},
-			},
-			{
-				"prophet_id": bson.M{"$in": filter.ProphetIDs},
-			},
-		}
-	} else if filter.SearchTerm != "" {
-		// Only search term, search by course name
-		log.Println("Building query with SearchTerm")
-		mongoFilter["coursename"] = bson.M{
-			"$regex":   strings.TrimSpace(filter.SearchTerm),
-			"$options": "i",
-		}
-	} else if len(filter.ProphetIDs) > 0 {
-		log.Println("Building query with ProphetIDs")
-		// Only prophet IDs, search by prophet ID
-		mongoFilter["prophet_id"] = bson.M{"$in": filter.ProphetIDs}
+	// Always filter out deleted courses and those of suspended prophets
+	mongoFilter := listedCourses(bson.M{})
+
+	// SearchTerm matches course name, description and prophet name through
+	// the course text index
+	if term := strings.TrimSpace(filter.SearchTerm); term != "" {
+		mongoFilter["$text"] = bson.M{"$search": term}
 	}
 
 	if filter.Duration != "" {
@@ -70,6 +56,25 @@ func BuildMongoQuery(filter CourseFilter, sort CourseSort) (bson.M, bson.D, erro
 		}
 	}
 
+	if filter.Currency != "" {
+		mongoFilter["currency"] = filter.Currency
+	}
+
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		price := bson.M{}
+		if filter.MinPrice != nil {
+			price["$gte"] = *filter.MinPrice
+		}
+		if filter.MaxPrice != nil {
+			price["$lte"] = *filter.MaxPrice
+		}
+		mongoFilter["price"] = price
+	}
+
+	if filter.MinRating > 0 {
+		mongoFilter["review_score"] = bson.M{"$gte": filter.MinRating}
+	}
+
 	mongoSort := bson.D{}
 	if sort.SortBy != "" {
 		order := 1
@@ -78,6 +83,25 @@ func BuildMongoQuery(filter CourseFilter, sort CourseSort) (bson.M, bson.D, erro
 		}
 		mongoSort = append(mongoSort, bson.E{Key: sort.SortBy, Value: order})
 	}
+	// Rank search results by relevance, after any explicit sort
+	if _, ok := mongoFilter["$text"]; ok {
+		mongoSort = append(mongoSort, bson.E{Key: searchScoreField, Value: -1})
+	}
 
 	return mongoFilter, mongoSort, nil
 }
+
+// priceBucketOf groups a course under the lower bound of its price bucket
+func priceBucketOf() bson.M {
+	branches := bson.A{}
+	for i := 0; i+1 < len(PriceBuckets); i++ {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$lt": bson.A{"$price", PriceBuckets[i+1]}},
+			"then": PriceBuckets[i],
+		})
+	}
+	return bson.M{"$switch": bson.M{
+		"branches": branches,
+		"default":  PriceBuckets[len(PriceBuckets)-1],
+	}}
+}
@@ -2,23 +2,31 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoCourseRepo struct {
-	courseCol *mongo.Collection
-	reviewCol *mongo.Collection
+	courseCol     *mongo.Collection
+	reviewCol     *mongo.Collection
+	completionCol *mongo.Collection
+	suspensionCol *mongo.Collection
 }
 
 func NewMongoCourseRepo(db *mongo.Database) *MongoCourseRepo {
 	return &MongoCourseRepo{
-		courseCol: db.Collection("courses"),
-		reviewCol: db.Collection("reviews"),
+		courseCol:     db.Collection("courses"),
+		reviewCol:     db.Collection("reviews"),
+		completionCol: db.Collection("course_completions"),
+		suspensionCol: db.Collection("suspended_prophets"),
 	}
 }
 
@@ -29,7 +37,7 @@ func (r *MongoCourseRepo) SaveCourse(ctx context.Context, course *domain.Course)
 
 func (r *MongoCourseRepo) FindCourseByID(ctx context.Context, id string) (*domain.Course, error) {
 	var c domain.Course
-	err := r.courseCol.FindOne(ctx, bson.M{"id": id, "deleted_at": false}).Decode(&c)
+	err := r.courseCol.FindOne(ctx, listedCourses(bson.M{"id": id})).Decode(&c)
 	if err != nil {
 		return nil, err
 	}
@@ -38,12 +46,19 @@ func (r *MongoCourseRepo) FindCourseByID(ctx context.Context, id string) (*domai
 
 func (r *MongoCourseRepo) FindCourseDetailByID(ctx context.Context, id string) (*domain.CourseDetail, error) {
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{"id": id, "deleted_at": false}}},
+		{{Key: "$match", Value: listedCourses(bson.M{"id": id})}},
 		{{Key: "$lookup", Value: bson.M{
-			"from":         "reviews",
-			"localField":   "id",
-			"foreignField": "course_id",
-			"as":           "reviews",
+			"from": "reviews",
+			"let":  bson.M{"course_id": "$id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr":             bson.M{"$eq": bson.A{"$course_id", "$$course_id"}},
+					"deleted_at":        false,
+					"moderation_status": bson.M{"$ne": domain.ModerationHidden},
+				}}},
+				{{Key: "$project", Value: bson.M{"flags": 0, "moderation": 0}}},
+			},
+			"as": "reviews",
 		}}},
 		// Optional: ensure fields exist, but don’t recalc
 		{{Key: "$addFields", Value: bson.M{
@@ -71,7 +86,7 @@ func (r *MongoCourseRepo) FindCourseDetailByID(ctx context.Context, id string) (
 }
 
 func (r *MongoCourseRepo) FindCoursesByProphet(ctx context.Context, prophetID string) ([]*domain.Course, error) {
-	cur, err := r.courseCol.Find(ctx, bson.M{"prophet_id": prophetID, "deleted_at": false})
+	cur, err := r.courseCol.Find(ctx, listedCourses(bson.M{"prophet_id": prophetID}))
 	if err != nil {
 		return nil, err
 	}
@@ -89,64 +104,316 @@ func (r *MongoCourseRepo) FindCoursesByProphet(ctx context.Context, prophetID st
 }
 
 func (r *MongoCourseRepo) UpdateCourse(ctx context.Context, id string, updates map[string]interface{}) (*domain.Course, error) {
-	_, err := r.courseCol.UpdateOne(ctx, bson.M{"id": id, "deleted_at": false}, bson.M{"$set": updates})
+	_, err := r.courseCol.UpdateOne(ctx, listedCourses(bson.M{"id": id}), bson.M{"$set": updates})
 	if err != nil {
 		return nil, err
 	}
 	return r.FindCourseByID(ctx, id)
 }
 
+// listedCourses narrows a course filter to ones customers can see: neither
+// deleted nor hidden by their prophet's suspension. Courses from before
+// suspensions have no prophet_suspended.
+func listedCourses(filter bson.M) bson.M {
+	filter["deleted_at"] = false
+	filter["prophet_suspended"] = bson.M{"$ne": true}
+	return filter
+}
+
 func (r *MongoCourseRepo) DeleteCourse(ctx context.Context, id string) error {
 	update := bson.M{"$set": bson.M{"deleted_at": true}}
 	_, err := r.courseCol.UpdateOne(ctx, bson.M{"id": id}, update)
 	return err
 }
 
-func (r *MongoCourseRepo) FindByFilter(ctx context.Context, filter CourseFilter, sort CourseSort) ([]*domain.Course, error) {
+func (r *MongoCourseRepo) SetMissingCurrency(ctx context.Context, currency string) (int64, error) {
+	res, err := r.courseCol.UpdateMany(ctx,
+		bson.M{"currency": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"currency": currency}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *MongoCourseRepo) SetMissingSessions(ctx context.Context) (int64, error) {
+	res, err := r.courseCol.UpdateMany(ctx,
+		bson.M{"sessions": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"sessions": 1}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// EnsureCourseIndexes creates the text index searches rank courses by, with
+// matches in the course name counting most
+func (r *MongoCourseRepo) EnsureCourseIndexes(ctx context.Context) error {
+	_, err := r.courseCol.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "coursename", Value: "text"},
+			{Key: "prophetname", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("course_text").
+			SetWeights(bson.M{"coursename": 10, "prophetname": 5, "description": 1}),
+	})
+	return err
+}
+
+// FindProphetIDsMissingName lists prophets with courses from before the
+// prophet's name was stored on them
+func (r *MongoCourseRepo) FindProphetIDsMissingName(ctx context.Context) ([]string, error) {
+	values, err := r.courseCol.Distinct(ctx, "prophet_id", bson.M{"prophetname": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	prophetIDs := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			prophetIDs = append(prophetIDs, id)
+		}
+	}
+	return prophetIDs, nil
+}
+
+func (r *MongoCourseRepo) SetProphetName(ctx context.Context, prophetID string, name string) (int64, error) {
+	res, err := r.courseCol.UpdateMany(ctx,
+		bson.M{"prophet_id": prophetID},
+		bson.M{"$set": bson.M{"prophetname": name}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// SetProphetSuspended records whether a prophet is suspended and hides or
+// shows all their courses to match. Suspending twice keeps the first time.
+func (r *MongoCourseRepo) SetProphetSuspended(ctx context.Context, prophetID string, suspended bool) (int64, error) {
+	if suspended {
+		_, err := r.suspensionCol.UpdateOne(ctx,
+			bson.M{"prophet_id": prophetID},
+			bson.M{"$setOnInsert": bson.M{"prophet_id": prophetID, "suspended_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return 0, err
+		}
+	} else if _, err := r.suspensionCol.DeleteOne(ctx, bson.M{"prophet_id": prophetID}); err != nil {
+		return 0, err
+	}
+
+	res, err := r.courseCol.UpdateMany(ctx,
+		bson.M{"prophet_id": prophetID},
+		bson.M{"$set": bson.M{"prophet_suspended": suspended}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *MongoCourseRepo) IsProphetSuspended(ctx context.Context, prophetID string) (bool, error) {
+	n, err := r.suspensionCol.CountDocuments(ctx, bson.M{"prophet_id": prophetID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// searchCursor is a position in search results. Relevance ranking has no
+// stable keys to resume from, so searches page by offset.
+type searchCursor struct {
+	Offset int `json:"o"`
+}
+
+// FindByFilter returns one page of the matching courses along with the total
+// and facet counts over all of them, in one aggregation
+func (r *MongoCourseRepo) FindByFilter(ctx context.Context, filter CourseFilter, sort CourseSort, page contract.PageRequest) (contract.Page[*domain.Course], *domain.CourseFacets, error) {
+	var none contract.Page[*domain.Course]
 	filterMongo, sortMongo, err := BuildMongoQuery(filter, sort)
 	if err != nil {
 		log.Printf("Error building MongoDB query: %v", err)
-		return nil, err
+		return none, nil, err
 	}
 
-	// If no filter criteria are provided, return all active courses
-	if filter.SearchTerm == "" && len(filter.ProphetIDs) == 0 && filter.Duration == "" && filter.CourseType == "" {
-		filterMongo = bson.M{"deleted_at": false}
-		log.Println("No filters provided — returning all courses.")
+	var position searchCursor
+	if page.Cursor != "" {
+		if err := contract.DecodeCursor(page.Cursor, &position); err != nil || position.Offset < 0 {
+			return none, nil, contract.ErrInvalidCursor
+		}
 	}
 
-	opts := options.Find()
-	if len(sortMongo) > 0 {
-		opts.SetSort(sortMongo)
+	// $text has to be in the first stage
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filterMongo}}}
+	if _, ok := filterMongo["$text"]; ok {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{
+			searchScoreField: bson.M{"$meta": "textScore"},
+		}}})
+	}
+
+	// _id breaks ties, keeping insertion order when nothing else is sorted on
+	results := bson.A{
+		bson.M{"$sort": append(sortMongo, bson.E{Key: "_id", Value: 1})},
+		bson.M{"$skip": position.Offset},
+		bson.M{"$limit": page.Limit + 1},
+	}
+	countBy := func(groupBy interface{}) bson.A {
+		return bson.A{
+			bson.M{"$group": bson.M{"_id": groupBy, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.M{"_id": 1}},
+		}
 	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"results":    results,
+		"total":      bson.A{bson.M{"$count": "n"}},
+		"coursetype": countBy("$coursetype"),
+		"duration":   countBy("$duration"),
+		"price":      countBy(priceBucketOf()),
+	}}})
 
-	cursor, err := r.courseCol.Find(ctx, filterMongo, opts)
+	cur, err := r.courseCol.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, err
+		return none, nil, err
 	}
-	defer cursor.Close(ctx)
+	defer cur.Close(ctx)
 
-	var courses []*domain.Course
-	for cursor.Next(ctx) {
-		var c domain.Course
-		if err := cursor.Decode(&c); err != nil {
-			return nil, err
+	type facetCount struct {
+		ID    interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	var out struct {
+		Results []*domain.Course `bson:"results"`
+		Total   []struct {
+			N int64 `bson:"n"`
+		} `bson:"total"`
+		CourseType []facetCount `bson:"coursetype"`
+		Duration   []facetCount `bson:"duration"`
+		Price      []facetCount `bson:"price"`
+	}
+	if cur.Next(ctx) {
+		if err := cur.Decode(&out); err != nil {
+			return none, nil, err
 		}
-		courses = append(courses, &c)
+	}
+	if err := cur.Err(); err != nil {
+		return none, nil, err
 	}
 
-	return courses, nil
+	courses := contract.NewPage(out.Results, page.Limit, func(*domain.Course) any {
+		return searchCursor{Offset: position.Offset + page.Limit}
+	})
+	var total int64
+	if len(out.Total) > 0 {
+		total = out.Total[0].N
+	}
+	courses.Total = &total
+
+	facets := &domain.CourseFacets{
+		CourseTypes:  make([]domain.FacetCount, 0, len(out.CourseType)),
+		Durations:    make([]domain.FacetCount, 0, len(out.Duration)),
+		PriceBuckets: make([]domain.PriceBucket, 0, len(out.Price)),
+	}
+	for _, f := range out.CourseType {
+		if f.ID == nil {
+			continue
+		}
+		facets.CourseTypes = append(facets.CourseTypes, domain.FacetCount{Value: fmt.Sprint(f.ID), Count: f.Count})
+	}
+	for _, f := range out.Duration {
+		if f.ID == nil {
+			continue
+		}
+		facets.Durations = append(facets.Durations, domain.FacetCount{Value: fmt.Sprint(f.ID), Count: f.Count})
+	}
+	for _, f := range out.Price {
+		bucket := domain.PriceBucket{Count: f.Count}
+		switch min := f.ID.(type) {
+		case int64:
+			bucket.Min = min
+		case int32:
+			bucket.Min = int64(min)
+		}
+		for _, bound := range PriceBuckets {
+			if bound > bucket.Min {
+				bucket.Max = bound
+				break
+			}
+		}
+		facets.PriceBuckets = append(facets.PriceBuckets, bucket)
+	}
+
+	return courses, facets, nil
+}
+
+// EnsureReviewIndexes lets each order be reviewed once and serves a course's
+// reviews newest first. Reviews from before reviews were tied to orders have
+// no order_id and are left out of the unique index.
+func (r *MongoCourseRepo) EnsureReviewIndexes(ctx context.Context) error {
+	_, err := r.reviewCol.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().
+				SetName("order_id_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{
+					"order_id":   bson.M{"$type": "string"},
+					"deleted_at": false,
+				}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "course_id", Value: 1},
+				{Key: "created_at", Value: -1},
+				{Key: "id", Value: -1},
+			},
+			Options: options.Index().SetName("course_reviews"),
+		},
+	})
+	return err
 }
 
 func (r *MongoCourseRepo) SaveReview(ctx context.Context, review *domain.Review) error {
 	// Insert review
 	if _, err := r.reviewCol.InsertOne(ctx, review); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrAlreadyReviewed
+		}
 		return err
 	}
 
-	// Recalculate review count + average
+	return r.refreshReviewStats(ctx, review.CourseID)
+}
+
+func (r *MongoCourseRepo) UpdateReview(ctx context.Context, id string, updates map[string]interface{}) (*domain.Review, error) {
+	var rv domain.Review
+	err := r.reviewCol.FindOneAndUpdate(ctx,
+		visibleReviews(bson.M{"id": id}),
+		bson.M{"$set": updates},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.refreshReviewStats(ctx, rv.CourseID); err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// refreshReviewStats recalculates a course's review count + average from its
+// remaining reviews; hidden reviews don't count
+func (r *MongoCourseRepo) refreshReviewStats(ctx context.Context, courseID string) error {
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{"course_id": review.CourseID, "deleted_at": false}}},
+		{{Key: "$match", Value: visibleReviews(bson.M{"course_id": courseID})}},
 		{{Key: "$group", Value: bson.M{
 			"_id":   "$course_id",
 			"count": bson.M{"$sum": 1},
@@ -166,28 +433,50 @@ func (r *MongoCourseRepo) SaveReview(ctx context.Context, review *domain.Review)
 		Avg   float64 `bson:"avg"`
 	}
 
+	// No match means the last review was deleted, leaving the zero values
 	if cur.Next(ctx) {
 		if err := cur.Decode(&agg); err != nil {
 			return err
 		}
-
-		update := bson.M{"$set": bson.M{
-			"review_count": agg.Count,
-			"review_score": agg.Avg,
-		}}
-		_, err = r.courseCol.UpdateOne(ctx, bson.M{"id": review.CourseID}, update)
-		if err != nil {
-			return err
-		}
+	}
+	if err := cur.Err(); err != nil {
+		return err
 	}
 
-	return nil
+	update := bson.M{"$set": bson.M{
+		"review_count": agg.Count,
+		"review_score": agg.Avg,
+	}}
+	_, err = r.courseCol.UpdateOne(ctx, bson.M{"id": courseID}, update)
+	return err
 }
 
-func (r *MongoCourseRepo) FindReviewsByCourse(ctx context.Context, courseID string) ([]*domain.Review, error) {
-	cur, err := r.reviewCol.Find(ctx, bson.M{"course_id": courseID, "deleted_at": false})
+// reviewCursor is the position after the last review of a page
+type reviewCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// FindReviewsByCourse pages through a course's visible reviews, newest first
+func (r *MongoCourseRepo) FindReviewsByCourse(ctx context.Context, courseID string, page contract.PageRequest) (contract.Page[*domain.Review], error) {
+	filter := visibleReviews(bson.M{"course_id": courseID})
+	if page.Cursor != "" {
+		var position reviewCursor
+		if err := contract.DecodeCursor(page.Cursor, &position); err != nil {
+			return contract.Page[*domain.Review]{}, err
+		}
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": position.CreatedAt}},
+			bson.M{"created_at": position.CreatedAt, "id": bson.M{"$lt": position.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}}).
+		SetLimit(int64(page.Limit + 1))
+	cur, err := r.reviewCol.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return contract.Page[*domain.Review]{}, err
 	}
 	defer cur.Close(ctx)
 
@@ -195,36 +484,333 @@ func (r *MongoCourseRepo) FindReviewsByCourse(ctx context.Context, courseID stri
 	for cur.Next(ctx) {
 		var rv domain.Review
 		if err := cur.Decode(&rv); err != nil {
-			return nil, err
+			return contract.Page[*domain.Review]{}, err
 		}
 		reviews = append(reviews, &rv)
 	}
-	return reviews, nil
+	return contract.NewPage(reviews, page.Limit, func(rv *domain.Review) any {
+		return reviewCursor{CreatedAt: rv.CreatedAt, ID: rv.ID}
+	}), nil
 }
 
 func (r *MongoCourseRepo) FindReviewByID(ctx context.Context, id string) (*domain.Review, error) {
 	var rv domain.Review
-	err := r.reviewCol.FindOne(ctx, bson.M{"id": id, "deleted_at": false}).Decode(&rv)
+	err := r.reviewCol.FindOne(ctx, visibleReviews(bson.M{"id": id})).Decode(&rv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// SetReviewReply adds the reply to a visible review that has none yet
+func (r *MongoCourseRepo) SetReviewReply(ctx context.Context, id string, reply *domain.ReviewReply) (*domain.Review, error) {
+	var rv domain.Review
+	err := r.reviewCol.FindOneAndUpdate(ctx,
+		visibleReviews(bson.M{"id": id, "reply": nil}),
+		bson.M{"$set": bson.M{"reply": reply}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, err := r.FindReviewByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrAlreadyReplied
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &rv, nil
 }
 
+// AddReviewFlag records a user's flag on a visible review and puts it in the
+// moderation queue. Each user flags a review once.
+func (r *MongoCourseRepo) AddReviewFlag(ctx context.Context, id string, flag domain.ReviewFlag) error {
+	res, err := r.reviewCol.UpdateOne(ctx,
+		visibleReviews(bson.M{"id": id, "flags.user_id": bson.M{"$ne": flag.UserID}}),
+		bson.M{
+			"$push": bson.M{"flags": flag},
+			"$inc":  bson.M{"flag_count": 1},
+			"$set":  bson.M{"moderation_status": domain.ModerationPending},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		if _, err := r.FindReviewByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.ErrAlreadyFlagged
+	}
+	return nil
+}
+
+// FindReviewsByModerationStatus lists the moderation queue, most flagged first
+func (r *MongoCourseRepo) FindReviewsByModerationStatus(ctx context.Context, status domain.ModerationStatus) ([]*domain.Review, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "flag_count", Value: -1}, {Key: "created_at", Value: 1}})
+	cur, err := r.reviewCol.Find(ctx, bson.M{"moderation_status": status, "deleted_at": false}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	reviews := make([]*domain.Review, 0)
+	if err := cur.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ModerateReview records an admin's decision on a review, hidden or not, and
+// recalculates its course's score since hiding changes what counts
+func (r *MongoCourseRepo) ModerateReview(ctx context.Context, id string, status domain.ModerationStatus, moderation *domain.ReviewModeration) (*domain.Review, error) {
+	var rv domain.Review
+	err := r.reviewCol.FindOneAndUpdate(ctx,
+		bson.M{"id": id, "deleted_at": false},
+		bson.M{"$set": bson.M{"moderation_status": status, "moderation": moderation}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.refreshReviewStats(ctx, rv.CourseID); err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// visibleReviews narrows a review filter to ones neither deleted nor hidden
+// by moderation. Reviews from before moderation have no moderation_status.
+func visibleReviews(filter bson.M) bson.M {
+	filter["deleted_at"] = false
+	filter["moderation_status"] = bson.M{"$ne": domain.ModerationHidden}
+	return filter
+}
+
+// FindPopularCourses ranks courses by their rating weighted towards the
+// platform average, so ratings backed by few reviews count for less
 func (r *MongoCourseRepo) FindPopularCourses(ctx context.Context, limit int) ([]*domain.Course, error) {
-	cur, err := r.courseCol.Find(ctx, bson.M{"deleted_at": false}, options.Find().SetLimit(int64(limit)).SetSort(bson.D{{Key: "review_score", Value: -1}}))
+	mean, err := r.meanReviewScore(ctx)
 	if err != nil {
 		return nil, err
 	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: listedCourses(bson.M{})}},
+		{{Key: "$addFields", Value: bson.M{"weighted_rating": weightedRating(mean)}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "weighted_rating", Value: -1},
+			{Key: "review_count", Value: -1},
+			{Key: "id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+	return r.aggregateCourses(ctx, r.courseCol, pipeline)
+}
+
+// meanReviewScore is the average score of all live reviews across courses
+func (r *MongoCourseRepo) meanReviewScore(ctx context.Context) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": false, "review_count": bson.M{"$gt": 0}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"reviews": bson.M{"$sum": "$review_count"},
+			"total":   bson.M{"$sum": bson.M{"$multiply": bson.A{"$review_score", "$review_count"}}},
+		}}},
+	}
+
+	cur, err := r.courseCol.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
 	defer cur.Close(ctx)
 
-	var courses []*domain.Course
-	for cur.Next(ctx) {
-		var c domain.Course
-		if err := cur.Decode(&c); err != nil {
-			return nil, err
+	var agg struct {
+		Reviews int     `bson:"reviews"`
+		Total   float64 `bson:"total"`
+	}
+	// No match means there are no reviews yet
+	if cur.Next(ctx) {
+		if err := cur.Decode(&agg); err != nil {
+			return 0, err
 		}
-		courses = append(courses, &c)
+	}
+	if err := cur.Err(); err != nil {
+		return 0, err
+	}
+	if agg.Reviews == 0 {
+		return 0, nil
+	}
+	return agg.Total / float64(agg.Reviews), nil
+}
+
+// weightedRating is a course's Bayesian rating: its reviews averaged together
+// with RatingPriorWeight reviews at the platform's mean score
+func weightedRating(mean float64) bson.M {
+	count := bson.M{"$ifNull": bson.A{"$review_count", 0}}
+	score := bson.M{"$ifNull": bson.A{"$review_score", 0.0}}
+	return bson.M{"$divide": bson.A{
+		bson.M{"$add": bson.A{
+			bson.M{"$multiply": bson.A{score, count}},
+			mean * domain.RatingPriorWeight,
+		}},
+		bson.M{"$add": bson.A{count, domain.RatingPriorWeight}},
+	}}
+}
+
+// EnsureCompletionIndexes keeps one completion per order, so a redelivered
+// order.completed is only counted once
+func (r *MongoCourseRepo) EnsureCompletionIndexes(ctx context.Context) error {
+	_, err := r.completionCol.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetName("order_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "completed_at", Value: -1}},
+			Options: options.Index().SetName("completed_at"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "customer_id", Value: 1},
+				{Key: "completed_at", Value: -1},
+			},
+			Options: options.Index().SetName("customer_completions"),
+		},
+	})
+	return err
+}
+
+func (r *MongoCourseRepo) SaveCompletion(ctx context.Context, completion *domain.CourseCompletion) error {
+	_, err := r.completionCol.InsertOne(ctx, completion)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// FindCompletionsByCustomer lists a customer's most recent completed orders
+func (r *MongoCourseRepo) FindCompletionsByCustomer(ctx context.Context, customerID string, limit int) ([]*domain.CourseCompletion, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "completed_at", Value: -1}}).
+		SetLimit(int64(limit))
+	cur, err := r.completionCol.Find(ctx, bson.M{"customer_id": customerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	completions := []*domain.CourseCompletion{}
+	if err := cur.All(ctx, &completions); err != nil {
+		return nil, err
+	}
+	return completions, nil
+}
+
+// FindTrendingCourses ranks courses by their orders completed within
+// TrendingWindow of now, each counting half as much every TrendingHalfLife
+func (r *MongoCourseRepo) FindTrendingCourses(ctx context.Context, now time.Time, limit int) ([]*domain.Course, error) {
+	halfLife := domain.TrendingHalfLife.Milliseconds()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"completed_at": bson.M{"$gte": now.Add(-domain.TrendingWindow)}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$course_id",
+			"trending_score": bson.M{"$sum": bson.M{"$pow": bson.A{
+				0.5,
+				bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{now, "$completed_at"}}, halfLife}},
+			}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "trending_score", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         r.courseCol.Name(),
+			"localField":   "_id",
+			"foreignField": "id",
+			"as":           "course",
+		}}},
+		{{Key: "$unwind", Value: "$course"}},
+		{{Key: "$match", Value: bson.M{
+			"course.deleted_at":        false,
+			"course.prophet_suspended": bson.M{"$ne": true},
+		}}},
+		{{Key: "$limit", Value: int64(limit)}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$course"}}},
+	}
+	return r.aggregateCourses(ctx, r.completionCol, pipeline)
+}
+
+// FindRecommendedCourses ranks courses the customer has not taken by how many
+// of their past orders were of the same course type or with the same
+// prophet, then by weighted rating, so customers with little history are
+// shown popular courses
+func (r *MongoCourseRepo) FindRecommendedCourses(ctx context.Context, taste domain.CustomerTaste, limit int) ([]*domain.Course, error) {
+	mean, err := r.meanReviewScore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	courseTypes := make(map[string]int, len(taste.CourseTypes))
+	for courseType, n := range taste.CourseTypes {
+		courseTypes[string(courseType)] = n
+	}
+
+	match := listedCourses(bson.M{})
+	if len(taste.Taken) > 0 {
+		match["id"] = bson.M{"$nin": taste.Taken}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{
+			"affinity": bson.M{"$add": bson.A{
+				countOf("$coursetype", courseTypes),
+				countOf("$prophet_id", taste.Prophets),
+			}},
+			"weighted_rating": weightedRating(mean),
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "affinity", Value: -1},
+			{Key: "weighted_rating", Value: -1},
+			{Key: "id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+	return r.aggregateCourses(ctx, r.courseCol, pipeline)
+}
+
+// countOf looks a course field's value up in counts, or 0 when it is not there
+func countOf(field string, counts map[string]int) interface{} {
+	if len(counts) == 0 {
+		return 0
+	}
+	branches := bson.A{}
+	for value, n := range counts {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$eq": bson.A{field, value}},
+			"then": n,
+		})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": 0}}
+}
+
+func (r *MongoCourseRepo) aggregateCourses(ctx context.Context, col *mongo.Collection, pipeline mongo.Pipeline) ([]*domain.Course, error) {
+	cur, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	courses := []*domain.Course{}
+	if err := cur.All(ctx, &courses); err != nil {
+		return nil, err
 	}
 	return courses, nil
 }
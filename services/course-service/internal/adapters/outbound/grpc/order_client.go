@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/jwt"
+	pb "github.com/wnmay/horo/shared/proto/order"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type OrderClient struct {
+	client pb.OrderServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewOrderClient creates a new order service gRPC client
+func NewOrderClient(orderServiceAddr string) (*OrderClient, error) {
+	conn, err := grpc.NewClient(
+		orderServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(jwt.UnaryClientService(jwt.ServiceOrder)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to order service: %w", err)
+	}
+
+	client := pb.NewOrderServiceClient(conn)
+	log.Printf("Connected to order service at %s", orderServiceAddr)
+
+	return &OrderClient{
+		client: client,
+		conn:   conn,
+	}, nil
+}
+
+// GetOrder fetches an order; unknown and malformed IDs are ErrOrderNotFound
+func (c *OrderClient) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	resp, err := c.client.GetOrder(ctx, &pb.GetOrderRequest{OrderId: orderID})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.InvalidArgument:
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if resp == nil || resp.Order == nil {
+		return nil, fmt.Errorf("nil response from order service")
+	}
+
+	return &domain.Order{
+		ID:         resp.Order.OrderId,
+		CustomerID: resp.Order.CustomerId,
+		CourseID:   resp.Order.CourseId,
+		Status:     resp.Order.Status,
+	}, nil
+}
+
+// Close closes the gRPC connection
+func (c *OrderClient) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
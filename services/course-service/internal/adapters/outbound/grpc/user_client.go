@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/jwt"
 	pb "github.com/wnmay/horo/shared/proto/user-management"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -21,6 +22,7 @@ func NewUserClient(userServiceAddr string) (*UserClient, error) {
 	conn, err := grpc.NewClient(
 		userServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(jwt.UnaryClientService(jwt.ServiceUserManagement)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service: %w", err)
@@ -103,6 +105,57 @@ func (c *UserClient) GetProphetIDsByNames(ctx context.Context, prophetName strin
 	return prophetNames, nil
 }
 
+func (c *UserClient) MapUserNamesByIDs(ctx context.Context, userIDs []string) (map[string]string, error) {
+	req := &pb.MapUserNamesRequest{
+		UserIds: userIDs,
+	}
+	resp, err := c.client.MapUserNames(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map user names: %w", err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("nil response from user service")
+	}
+
+	names := make(map[string]string, len(resp.Users))
+	for userID, user := range resp.Users {
+		names[userID] = user.GetName()
+	}
+	return names, nil
+}
+
+func (c *UserClient) MapProphetSummariesByIDs(ctx context.Context, userIDs []string) (map[string]*domain.ProphetSummary, error) {
+	req := &pb.MapProphetSummariesRequest{
+		UserIds: userIDs,
+	}
+	resp, err := c.client.MapProphetSummaries(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map prophet summaries: %w", err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("nil response from user service")
+	}
+
+	summaries := make(map[string]*domain.ProphetSummary, len(resp.Prophets))
+	for userID, p := range resp.Prophets {
+		specialties := make([]domain.CourseType, 0, len(p.GetSpecialties()))
+		for _, s := range p.GetSpecialties() {
+			specialties = append(specialties, domain.CourseType(s))
+		}
+		summaries[userID] = &domain.ProphetSummary{
+			UserID:            userID,
+			Name:              p.GetName(),
+			AvatarURL:         p.GetAvatarUrl(),
+			Verified:          p.GetVerified(),
+			Online:            p.GetOnline(),
+			Specialties:       specialties,
+			Languages:         append([]string{}, p.GetLanguages()...),
+			YearsOfExperience: int(p.GetYearsOfExperience()),
+		}
+	}
+	return summaries, nil
+}
+
 // Close closes the gRPC connection
 func (c *UserClient) Close() error {
 	if c.conn != nil {
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxSlotRange bounds how far ahead free slots are listed in one request
+const maxSlotRange = 31 * 24 * time.Hour
+
+// Publish or replace the prophet's weekly availability and exceptions
+func (s *courseService) SetAvailability(ctx context.Context, input SetAvailabilityInput) (*domain.Availability, error) {
+	availability := &domain.Availability{
+		ProphetID:  input.ProphetID,
+		Timezone:   input.Timezone,
+		Weekly:     input.Weekly,
+		Exceptions: input.Exceptions,
+		UpdatedAt:  time.Now(),
+	}
+	if availability.Weekly == nil {
+		availability.Weekly = []domain.WeeklySlot{}
+	}
+	if availability.Exceptions == nil {
+		availability.Exceptions = []domain.AvailabilityException{}
+	}
+	if err := availability.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.bookingRepo.SaveAvailability(ctx, availability); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+func (s courseService) GetAvailability(ctx context.Context, prophetID string) (*domain.Availability, error) {
+	return s.bookingRepo.FindAvailability(ctx, prophetID)
+}
+
+// List free session starts for a course between from and to
+func (s courseService) ListAvailableSlots(ctx context.Context, courseID string, from, to time.Time) ([]*domain.AvailableSlot, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: range end must be after its start", domain.ErrInvalidAvailability)
+	}
+	if to.Sub(from) > maxSlotRange {
+		to = from.Add(maxSlotRange)
+	}
+	if now := time.Now(); from.Before(now) {
+		from = now.Truncate(domain.SlotGranularity).Add(domain.SlotGranularity)
+	}
+
+	course, err := s.findCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	availability, err := s.bookingRepo.FindAvailability(ctx, course.ProphetID)
+	if err != nil {
+		return nil, err
+	}
+
+	booked, err := s.bookingRepo.FindBookedBlocks(ctx, course.ProphetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[int64]bool, len(booked))
+	for _, block := range booked {
+		taken[block.Unix()] = true
+	}
+
+	length := time.Duration(course.Duration) * time.Minute
+	slots := make([]*domain.AvailableSlot, 0)
+	for _, start := range availability.SessionStarts(from, to, length) {
+		candidate := domain.Booking{StartAt: start, EndAt: start.Add(length)}
+		free := true
+		for _, block := range candidate.Blocks() {
+			if taken[block.Unix()] {
+				free = false
+				break
+			}
+		}
+		if free {
+			slots = append(slots, &domain.AvailableSlot{
+				StartAt:  start.UTC(),
+				EndAt:    candidate.EndAt.UTC(),
+				Timezone: availability.Timezone,
+			})
+		}
+	}
+	return slots, nil
+}
+
+// Reserve a session for an order. Retrying with the same order, session and
+// time returns the earlier booking; a different time moves that session's
+// booking, releasing the old time only once the new one is secured. The old
+// and new times may overlap. Each session of a bundle has its own booking.
+func (s *courseService) ReserveSlot(ctx context.Context, input ReserveSlotInput) (*domain.Booking, error) {
+	if input.Session == 0 {
+		input.Session = 1
+	}
+	existing, err := s.bookingRepo.FindReservedBookingByOrder(ctx, input.OrderID, input.Session)
+	switch {
+	case err == nil:
+		if existing.StartAt.Equal(input.StartAt) {
+			return existing, nil
+		}
+	case errors.Is(err, domain.ErrBookingNotFound):
+		existing = nil
+	default:
+		return nil, err
+	}
+
+	course, err := s.findCourse(ctx, input.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	availability, err := s.bookingRepo.FindAvailability(ctx, course.ProphetID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := input.StartAt.UTC()
+	end := start.Add(time.Duration(course.Duration) * time.Minute)
+	if !start.Equal(start.Truncate(domain.SlotGranularity)) || !start.After(time.Now()) {
+		return nil, domain.ErrSlotUnavailable
+	}
+	if !availability.Covers(start, end) {
+		return nil, domain.ErrSlotUnavailable
+	}
+
+	booking := &domain.Booking{
+		ID:         generateID("BOOKING"),
+		CourseID:   course.ID,
+		ProphetID:  course.ProphetID,
+		CustomerID: input.CustomerID,
+		OrderID:    input.OrderID,
+		Session:    input.Session,
+		StartAt:    start,
+		EndAt:      end,
+		Timezone:   availability.Timezone,
+		Status:     domain.BookingStatusReserved,
+		CreatedAt:  time.Now(),
+	}
+	if existing == nil {
+		if err := s.bookingRepo.ReserveBooking(ctx, booking); err != nil {
+			return nil, err
+		}
+		return booking, nil
+	}
+
+	if err := s.bookingRepo.MoveBooking(ctx, existing, booking); err != nil {
+		if errors.Is(err, domain.ErrSlotTaken) {
+			return nil, err
+		}
+		// The new booking stands even if releasing the old one failed
+		if _, findErr := s.bookingRepo.FindBookingByID(ctx, booking.ID); findErr != nil {
+			return nil, err
+		}
+		log.Printf("Failed to release previous booking %s for order %s: %v", existing.ID, input.OrderID, err)
+	}
+	return booking, nil
+}
+
+// Free the session so it can be booked again
+func (s *courseService) ReleaseSlot(ctx context.Context, bookingID string) error {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if booking.Status == domain.BookingStatusReleased {
+		return nil
+	}
+	return s.bookingRepo.ReleaseBooking(ctx, bookingID)
+}
+
+// List the prophet's upcoming sessions
+func (s courseService) ListProphetBookings(ctx context.Context, prophetID string) ([]*domain.Booking, error) {
+	bookings, err := s.bookingRepo.FindBookingsByProphet(ctx, prophetID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if bookings == nil {
+		return []*domain.Booking{}, nil
+	}
+	return bookings, nil
+}
+
+func (s courseService) findCourse(ctx context.Context, courseID string) (*domain.Course, error) {
+	course, err := s.repo.FindCourseByID(ctx, courseID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
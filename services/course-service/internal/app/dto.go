@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type CreateCourseInput struct {
@@ -12,20 +13,46 @@ type CreateCourseInput struct {
 	CourseName  string
 	CourseType  domain.CourseType
 	Description string
-	Price       float64
+	Price       money.Decimal
+	Currency    string
 	Duration    domain.DurationEnum
+	Sessions    int // sold together at Price; zero means a single reading
 	CreatedAt   time.Time
 	DeletedAt   bool
 }
 
 type CreateReviewInput struct {
-	ID           string
-	CourseID     string
-	CustomerID   string
-	CustomerName string
-	Score        float64
-	Title        string
-	Description  string
-	CreatedAt    time.Time
-	DeletedAt    bool
+	ID          string
+	CourseID    string
+	OrderID     string // the completed order the customer took the course through
+	CustomerID  string
+	Score       float64
+	Title       string
+	Description string
+	CreatedAt   time.Time
+	DeletedAt   bool
+}
+
+type RecordCompletionInput struct {
+	OrderID     string
+	CourseID    string
+	CustomerID  string
+	ProphetID   string
+	CourseType  domain.CourseType
+	CompletedAt time.Time
+}
+
+type SetAvailabilityInput struct {
+	ProphetID  string
+	Timezone   string
+	Weekly     []domain.WeeklySlot
+	Exceptions []domain.AvailabilityException
+}
+
+type ReserveSlotInput struct {
+	CourseID   string
+	CustomerID string
+	OrderID    string
+	Session    int // which of the order's sessions, starting at 1
+	StartAt    time.Time
 }
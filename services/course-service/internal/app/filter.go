@@ -4,12 +4,17 @@ import (
 	"strings"
 
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type CourseFilter struct {
 	SearchTerm  string
 	Duration    string
 	CourseType  domain.CourseType
+	Currency    string // prices are compared in each course's own currency
+	MinPrice    *money.Decimal
+	MaxPrice    *money.Decimal
+	MinRating   float64
 }
 
 type CourseSort struct {
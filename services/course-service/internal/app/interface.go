@@ -2,8 +2,10 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 )
 
 type CourseService interface {
@@ -13,9 +15,32 @@ type CourseService interface {
 	ListCoursesByProphet(ctx context.Context, prophetID string) ([]*domain.CourseWithProphetName, error)
 	UpdateCourse(ctx context.Context, id string, input *domain.UpdateCourseInput) (*domain.Course, error)
 	DeleteCourse(ctx context.Context, id string) error
-	FindCoursesByFilter(ctx context.Context, filter CourseFilter, sort CourseSort) ([]*domain.CourseWithProphetName, error)
+	FindCoursesByFilter(ctx context.Context, filter CourseFilter, sort CourseSort, page contract.PageRequest) (*domain.CourseSearchResult, error)
+	// BackfillProphetNames stores prophet names on courses created before
+	// search needed them
+	BackfillProphetNames(ctx context.Context) (int64, error)
 	CreateReview(ctx context.Context, input CreateReviewInput) (*domain.Review, error)
 	GetReviewByID(ctx context.Context, id string) (*domain.Review, error)
-	ListReviewsByCourse(ctx context.Context, courseId string) ([]*domain.Review, error)
+	ListReviewsByCourse(ctx context.Context, courseId string, page contract.PageRequest) (contract.Page[*domain.Review], error)
+	UpdateReview(ctx context.Context, id string, customerID string, input *domain.UpdateReviewInput) (*domain.Review, error)
+	DeleteReview(ctx context.Context, id string, customerID string) error
+	ReplyToReview(ctx context.Context, id string, prophetID string, message string) (*domain.Review, error)
+	FlagReview(ctx context.Context, id string, userID string, role string, reason string) error
+	ListModerationQueue(ctx context.Context, status domain.ModerationStatus) ([]*domain.ModeratedReview, error)
+	HideReview(ctx context.Context, id string, adminID string, note string) (*domain.ModeratedReview, error)
+	RestoreReview(ctx context.Context, id string, adminID string, note string) (*domain.ModeratedReview, error)
 	ListPopularCourses(ctx context.Context, limit int) ([]*domain.CourseWithProphetName, error)
+	ListTrendingCourses(ctx context.Context, limit int) ([]*domain.CourseWithProphetName, error)
+	ListRecommendedCourses(ctx context.Context, customerID string, limit int) ([]*domain.CourseWithProphetName, error)
+	// SuspendProphet hides a prophet's courses until ReinstateProphet
+	SuspendProphet(ctx context.Context, prophetID string) error
+	ReinstateProphet(ctx context.Context, prophetID string) error
+	// RecordCompletion notes an order.completed for trending and recommendations
+	RecordCompletion(ctx context.Context, input RecordCompletionInput) error
+	SetAvailability(ctx context.Context, input SetAvailabilityInput) (*domain.Availability, error)
+	GetAvailability(ctx context.Context, prophetID string) (*domain.Availability, error)
+	ListAvailableSlots(ctx context.Context, courseID string, from, to time.Time) ([]*domain.AvailableSlot, error)
+	ReserveSlot(ctx context.Context, input ReserveSlotInput) (*domain.Booking, error)
+	ReleaseSlot(ctx context.Context, bookingID string) error
+	ListProphetBookings(ctx context.Context, prophetID string) ([]*domain.Booking, error)
 }
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+)
+
+// Post the course prophet's public reply to a review; one per review
+func (s *courseService) ReplyToReview(ctx context.Context, id string, prophetID string, message string) (*domain.Review, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, domain.ErrEmptyReply
+	}
+
+	review, err := s.repo.FindReviewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	course, err := s.repo.FindCourseByID(ctx, review.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.ProphetID != prophetID {
+		return nil, domain.ErrNotCourseProphet
+	}
+
+	return s.repo.SetReviewReply(ctx, id, &domain.ReviewReply{
+		ProphetID: prophetID,
+		Message:   message,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Flag a review for admins to look at
+func (s *courseService) FlagReview(ctx context.Context, id string, userID string, role string, reason string) error {
+	return s.repo.AddReviewFlag(ctx, id, domain.ReviewFlag{
+		UserID:    userID,
+		Role:      role,
+		Reason:    strings.TrimSpace(reason),
+		CreatedAt: time.Now(),
+	})
+}
+
+// List reviews in one moderation status, PENDING being the queue to work through
+func (s *courseService) ListModerationQueue(ctx context.Context, status domain.ModerationStatus) ([]*domain.ModeratedReview, error) {
+	reviews, err := s.repo.FindReviewsByModerationStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	moderated := make([]*domain.ModeratedReview, 0, len(reviews))
+	for _, review := range reviews {
+		moderated = append(moderated, toModeratedReview(review))
+	}
+	return moderated, nil
+}
+
+// Hide a review from the course page and its score
+func (s *courseService) HideReview(ctx context.Context, id string, adminID string, note string) (*domain.ModeratedReview, error) {
+	return s.moderateReview(ctx, id, domain.ModerationHidden, adminID, note)
+}
+
+// Clear a flagged or hidden review, putting it back on the course page
+func (s *courseService) RestoreReview(ctx context.Context, id string, adminID string, note string) (*domain.ModeratedReview, error) {
+	return s.moderateReview(ctx, id, domain.ModerationCleared, adminID, note)
+}
+
+func (s *courseService) moderateReview(ctx context.Context, id string, status domain.ModerationStatus, adminID string, note string) (*domain.ModeratedReview, error) {
+	review, err := s.repo.ModerateReview(ctx, id, status, &domain.ReviewModeration{
+		AdminID:     adminID,
+		Note:        strings.TrimSpace(note),
+		ModeratedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModeratedReview(review), nil
+}
+
+func toModeratedReview(review *domain.Review) *domain.ModeratedReview {
+	flags := review.Flags
+	if flags == nil {
+		flags = []domain.ReviewFlag{}
+	}
+	return &domain.ModeratedReview{
+		Review:     review,
+		Flags:      flags,
+		Moderation: review.Moderation,
+	}
+}
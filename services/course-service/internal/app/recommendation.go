@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Record a completed order towards trending courses and the customer's
+// recommendations
+func (s *courseService) RecordCompletion(ctx context.Context, input RecordCompletionInput) error {
+	completion := &domain.CourseCompletion{
+		OrderID:     input.OrderID,
+		CourseID:    input.CourseID,
+		CustomerID:  input.CustomerID,
+		ProphetID:   input.ProphetID,
+		CourseType:  input.CourseType,
+		CompletedAt: input.CompletedAt,
+	}
+	if completion.CompletedAt.IsZero() {
+		completion.CompletedAt = time.Now()
+	}
+
+	// Orders from before events carried the course type take it from the course
+	if completion.CourseType == "" || completion.ProphetID == "" {
+		course, err := s.repo.FindCourseByID(ctx, input.CourseID)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		if course != nil {
+			completion.CourseType = course.CourseType
+			completion.ProphetID = course.ProphetID
+		}
+	}
+
+	return s.repo.SaveCompletion(ctx, completion)
+}
+
+// List the courses most completed lately
+func (s *courseService) ListTrendingCourses(ctx context.Context, limit int) ([]*domain.CourseWithProphetName, error) {
+	courses, err := s.repo.FindTrendingCourses(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.withProphets(ctx, courses)
+}
+
+// List courses like the ones the customer has completed orders for
+func (s *courseService) ListRecommendedCourses(ctx context.Context, customerID string, limit int) ([]*domain.CourseWithProphetName, error) {
+	completions, err := s.repo.FindCompletionsByCustomer(ctx, customerID, domain.RecommendationHistorySize)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := s.repo.FindRecommendedCourses(ctx, domain.NewCustomerTaste(completions), limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.withProphets(ctx, courses)
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,17 +11,23 @@ import (
 	"github.com/wnmay/horo/services/course-service/internal/adapters/outbound/db"
 	"github.com/wnmay/horo/services/course-service/internal/domain"
 	"github.com/wnmay/horo/services/course-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type courseService struct {
-	repo          outbound.CourseRepository
-	user_provider outbound.UserProvider
+	repo           outbound.CourseRepository
+	bookingRepo    outbound.BookingRepository
+	user_provider  outbound.UserProvider
+	order_provider outbound.OrderProvider
 }
 
-func NewCourseService(r outbound.CourseRepository, u outbound.UserProvider) CourseService {
+func NewCourseService(r outbound.CourseRepository, b outbound.BookingRepository, u outbound.UserProvider, o outbound.OrderProvider) CourseService {
 	return &courseService{
-		repo:          r,
-		user_provider: u,
+		repo:           r,
+		bookingRepo:    b,
+		user_provider:  u,
+		order_provider: o,
 	}
 }
 
@@ -39,26 +46,63 @@ func (s courseService) GetCourseDetailByID(ctx context.Context, id string) (*dom
 		return nil, fmt.Errorf("course not found")
 	}
 
-	// Enrich with prophet name from user service
-	prophetName, err := s.user_provider.GetProphetName(ctx, courseDetail.ProphetID)
+	// Enrich with the prophet's profile from user service; the name stored on
+	// the course stands in if they have none
+	prophets, err := s.prophetSummaries(ctx, []string{courseDetail.ProphetID})
 	if err != nil {
 		return nil, err
 	}
-	courseDetail.ProphetName = prophetName
+	if prophet, ok := prophets[courseDetail.ProphetID]; ok {
+		courseDetail.ProphetName = prophet.Name
+		courseDetail.Prophet = prophet
+	}
 
 	return courseDetail, nil
 }
 
 // Create new course
 func (s *courseService) CreateCourse(ctx context.Context, input CreateCourseInput) (*domain.Course, error) {
+	// Prices are declared in the prophet's currency, the platform's by default
+	currency, err := money.NormalizeCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+	sessions := input.Sessions
+	if sessions == 0 {
+		sessions = 1
+	}
+	if err := domain.ValidateSessions(sessions); err != nil {
+		return nil, err
+	}
+
+	suspended, err := s.repo.IsProphetSuspended(ctx, input.ProphetID)
+	if err != nil {
+		return nil, err
+	}
+	if suspended {
+		return nil, domain.ErrProphetSuspended
+	}
+
+	// A prophet's name changes rarely; refresh it on all their courses so
+	// search keeps finding them by it
+	prophetName, err := s.user_provider.GetProphetName(ctx, input.ProphetID)
+	if err != nil {
+		log.Printf("Warning: failed to get prophet name for %s: %v", input.ProphetID, err)
+	} else if _, err := s.repo.SetProphetName(ctx, input.ProphetID, prophetName); err != nil {
+		log.Printf("Warning: failed to refresh prophet name for %s: %v", input.ProphetID, err)
+	}
+
 	c := &domain.Course{
 		ID:          generateID("COURSE"),
 		ProphetID:   input.ProphetID,
+		ProphetName: prophetName,
 		CourseName:  input.CourseName,
 		CourseType:  input.CourseType,
 		Description: input.Description,
-		Price:       input.Price,
+		Price:       money.RoundTo(input.Price, currency),
+		Currency:    currency,
 		Duration:    input.Duration,
+		Sessions:    sessions,
 		CreatedAt:   time.Now(),
 		DeletedAt:   false,
 		ReviewCount: 0,
@@ -80,12 +124,34 @@ func (s *courseService) UpdateCourse(ctx context.Context, id string, input *doma
 	if input.Description != "" {
 		updates["description"] = input.Description
 	}
-	if input.Price != nil {
-		updates["price"] = *input.Price
+	if input.Price != nil || input.Currency != "" {
+		currency := input.Currency
+		if currency == "" {
+			course, err := s.repo.FindCourseByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			currency = course.Currency
+		}
+		currency, err := money.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		updates["currency"] = currency
+		if input.Price != nil {
+			updates["price"] = money.RoundTo(*input.Price, currency)
+		}
 	}
 	if input.Duration != nil {
 		updates["duration"] = *input.Duration
 	}
+	// Orders already placed keep the sessions they bought
+	if input.Sessions != nil {
+		if err := domain.ValidateSessions(*input.Sessions); err != nil {
+			return nil, err
+		}
+		updates["sessions"] = *input.Sessions
+	}
 	return s.repo.UpdateCourse(ctx, id, updates)
 }
 
@@ -96,60 +162,29 @@ func (s *courseService) DeleteCourse(ctx context.Context, id string) error {
 
 // List all courses for a given prophet, with prophet name attached
 func (s courseService) ListCoursesByProphet(ctx context.Context, prophetID string) ([]*domain.CourseWithProphetName, error) {
-	prophetName, err := s.user_provider.GetProphetName(ctx, prophetID)
-	if err != nil {
-		return nil, err
-	}
-
 	courses, err := s.repo.FindCoursesByProphet(ctx, prophetID)
 	if err != nil {
 		return nil, err
 	}
-
-	results := make([]*domain.CourseWithProphetName, 0, len(courses))
-	for _, course := range courses {
-		results = append(results, &domain.CourseWithProphetName{
-			ID:          course.ID,
-			ProphetID:   course.ProphetID,
-			ProphetName: prophetName,
-			CourseName:  course.CourseName,
-			CourseType:  course.CourseType,
-			Description: course.Description,
-			Price:       course.Price,
-			Duration:    course.Duration,
-			CreatedAt:   course.CreatedAt,
-			DeletedAt:   course.DeletedAt,
-			ReviewCount: course.ReviewCount,
-			ReviewScore: course.ReviewScore,
-		})
-	}
-	return results, nil
+	return s.withProphets(ctx, courses)
 }
 
-// Find courses by filter (supports filtering and sorting)
-func (s courseService) FindCoursesByFilter(ctx context.Context, filter CourseFilter, sort CourseSort) ([]*domain.CourseWithProphetName, error) {
-	var prophetIDs []string
-
-	if filter.SearchTerm != "" {
-		prophets, err := s.user_provider.GetProphetIDsByNames(ctx, filter.SearchTerm)
-		if err != nil {
-			log.Printf("Warning: Failed to get prophet IDs for search term '%s': %v. Continuing with course name search only.", filter.SearchTerm, err)
-			// Don't return error, just use empty prophetIDs
-			prophetIDs = []string{}
-		} else {
-			prophetIDs = make([]string, len(prophets))
-			for i, p := range prophets {
-				prophetIDs[i] = p.UserID
-			}
-			log.Printf("Found %d prophets matching search term '%s'", len(prophetIDs), filter.SearchTerm)
-		}
-	}
-
+// Find courses by filter (supports text search, filtering, sorting and facets)
+func (s courseService) FindCoursesByFilter(ctx context.Context, filter CourseFilter, sort CourseSort, page contract.PageRequest) (*domain.CourseSearchResult, error) {
 	repoFilter := db.CourseFilter{
 		SearchTerm: filter.SearchTerm,
-		ProphetIDs: prophetIDs,
 		Duration:   filter.Duration,
 		CourseType: string(filter.CourseType),
+		MinPrice:   filter.MinPrice,
+		MaxPrice:   filter.MaxPrice,
+		MinRating:  filter.MinRating,
+	}
+	if filter.Currency != "" {
+		currency, err := money.NormalizeCurrency(filter.Currency)
+		if err != nil {
+			return nil, err
+		}
+		repoFilter.Currency = currency
 	}
 
 	repoSort := db.CourseSort{
@@ -157,69 +192,82 @@ func (s courseService) FindCoursesByFilter(ctx context.Context, filter CourseFil
 		Order:  sort.Order,
 	}
 
-	log.Printf("Calling repo.FindByFilter with SearchTerm='%s', ProphetIDs count=%d", filter.SearchTerm, len(prophetIDs))
-	courses, err := s.repo.FindByFilter(ctx, repoFilter, repoSort)
+	found, facets, err := s.repo.FindByFilter(ctx, repoFilter, repoSort, page)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(courses) == 0 {
-		return []*domain.CourseWithProphetName{}, nil
+	courses, err := s.withProphets(ctx, found.Data)
+	if err != nil {
+		return nil, err
 	}
 
-	// Collect unique prophet IDs from the filtered courses
-	prophetIDMap := make(map[string]bool)
-	for _, course := range courses {
-		prophetIDMap[course.ProphetID] = true
-	}
+	return &domain.CourseSearchResult{
+		Courses:    courses,
+		NextCursor: found.NextCursor,
+		Total:      *found.Total,
+		Facets:     *facets,
+	}, nil
+}
 
-	uniqueProphetIDs := make([]string, 0, len(prophetIDMap))
-	for id := range prophetIDMap {
-		uniqueProphetIDs = append(uniqueProphetIDs, id)
+// Store prophet names on courses created before courses carried them
+func (s *courseService) BackfillProphetNames(ctx context.Context) (int64, error) {
+	prophetIDs, err := s.repo.FindProphetIDsMissingName(ctx)
+	if err != nil || len(prophetIDs) == 0 {
+		return 0, err
 	}
 
-	// Get prophet names map from gRPC
-	prophetNames, err := s.user_provider.MapProphetNamesByIDs(ctx, uniqueProphetIDs)
+	prophetNames, err := s.user_provider.MapProphetNamesByIDs(ctx, prophetIDs)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	// Convert to map for quick lookup
-	nameMap := make(map[string]string)
+	var updated int64
 	for _, pn := range prophetNames {
-		nameMap[pn.UserID] = pn.Name
+		n, err := s.repo.SetProphetName(ctx, pn.UserID, pn.Name)
+		if err != nil {
+			return updated, err
+		}
+		updated += n
 	}
+	return updated, nil
+}
 
-	// Map courses to CourseWithProphetName
-	results := make([]*domain.CourseWithProphetName, 0, len(courses))
-	for _, course := range courses {
-		prophetName := nameMap[course.ProphetID]
-		results = append(results, &domain.CourseWithProphetName{
-			ID:          course.ID,
-			ProphetID:   course.ProphetID,
-			ProphetName: prophetName,
-			CourseName:  course.CourseName,
-			CourseType:  course.CourseType,
-			Description: course.Description,
-			Price:       course.Price,
-			Duration:    course.Duration,
-			CreatedAt:   course.CreatedAt,
-			DeletedAt:   course.DeletedAt,
-			ReviewCount: course.ReviewCount,
-			ReviewScore: course.ReviewScore,
-		})
+// Create a review of a completed order and automatically update course’s
+// denormalized score. The customer's name comes from user-management.
+func (s *courseService) CreateReview(ctx context.Context, input CreateReviewInput) (*domain.Review, error) {
+	if err := domain.ValidateReviewScore(input.Score); err != nil {
+		return nil, err
 	}
 
-	return results, nil
-}
+	order, err := s.order_provider.GetOrder(ctx, input.OrderID)
+	if errors.Is(err, domain.ErrOrderNotFound) {
+		return nil, fmt.Errorf("%w: order %s not found", domain.ErrReviewNotAllowed, input.OrderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID != input.CustomerID || order.CourseID != input.CourseID {
+		return nil, fmt.Errorf("%w: order %s is not yours for this course", domain.ErrReviewNotAllowed, order.ID)
+	}
+	if order.Status != domain.OrderStatusCompleted {
+		return nil, fmt.Errorf("%w: order %s is %s", domain.ErrReviewNotAllowed, order.ID, order.Status)
+	}
+
+	names, err := s.user_provider.MapUserNamesByIDs(ctx, []string{input.CustomerID})
+	if err != nil {
+		return nil, err
+	}
+	customerName, ok := names[input.CustomerID]
+	if !ok {
+		return nil, fmt.Errorf("customer %s not found", input.CustomerID)
+	}
 
-// Create a new review and automatically update course’s denormalized score
-func (s *courseService) CreateReview(ctx context.Context, input CreateReviewInput) (*domain.Review, error) {
 	review := &domain.Review{
 		ID:           generateID("REVIEW"),
 		CourseID:     input.CourseID,
+		OrderID:      order.ID,
 		CustomerID:   input.CustomerID,
-		CustomerName: input.CustomerName,
+		CustomerName: customerName,
 		Score:        input.Score,
 		Title:        input.Title,
 		Description:  input.Description,
@@ -233,14 +281,52 @@ func (s *courseService) CreateReview(ctx context.Context, input CreateReviewInpu
 	return review, nil
 }
 
+// Edit or delete the customer's own review; the course's score follows
+func (s *courseService) UpdateReview(ctx context.Context, id string, customerID string, input *domain.UpdateReviewInput) (*domain.Review, error) {
+	review, err := s.repo.FindReviewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.CustomerID != customerID {
+		return nil, domain.ErrNotReviewAuthor
+	}
+
+	updates := make(map[string]interface{})
+	if input.Score != 0 {
+		if err := domain.ValidateReviewScore(input.Score); err != nil {
+			return nil, err
+		}
+		updates["score"] = input.Score
+	}
+	if input.Title != "" {
+		updates["title"] = input.Title
+	}
+	if input.Description != "" {
+		updates["description"] = input.Description
+	}
+	if input.DeletedAt {
+		updates["deleted_at"] = true
+	}
+	if len(updates) == 0 {
+		return review, nil
+	}
+	return s.repo.UpdateReview(ctx, id, updates)
+}
+
+// Soft delete the customer's own review
+func (s *courseService) DeleteReview(ctx context.Context, id string, customerID string) error {
+	_, err := s.UpdateReview(ctx, id, customerID, &domain.UpdateReviewInput{DeletedAt: true})
+	return err
+}
+
 // Get a single review by ID
 func (s courseService) GetReviewByID(ctx context.Context, id string) (*domain.Review, error) {
 	return s.repo.FindReviewByID(ctx, id)
 }
 
 // List all reviews for a given course
-func (s courseService) ListReviewsByCourse(ctx context.Context, courseID string) ([]*domain.Review, error) {
-	return s.repo.FindReviewsByCourse(ctx, courseID)
+func (s courseService) ListReviewsByCourse(ctx context.Context, courseID string, page contract.PageRequest) (contract.Page[*domain.Review], error) {
+	return s.repo.FindReviewsByCourse(ctx, courseID, page)
 }
 
 func (s courseService) ListPopularCourses(ctx context.Context, limit int) ([]*domain.CourseWithProphetName, error) {
@@ -248,57 +334,70 @@ func (s courseService) ListPopularCourses(ctx context.Context, limit int) ([]*do
 	if err != nil {
 		return nil, err
 	}
+	return s.withProphets(ctx, courses)
+}
 
-	if len(courses) == 0 {
-		return []*domain.CourseWithProphetName{}, nil
-	}
-
-	// Collect unique prophet IDs
-	prophetIDMap := make(map[string]bool)
+// withProphets attaches their prophets' names and profile summaries to a
+// list of courses
+func (s courseService) withProphets(ctx context.Context, courses []*domain.Course) ([]*domain.CourseWithProphetName, error) {
+	prophetIDs := make([]string, 0, len(courses))
 	for _, course := range courses {
-		prophetIDMap[course.ProphetID] = true
+		prophetIDs = append(prophetIDs, course.ProphetID)
 	}
-
-	prophetIDs := make([]string, 0, len(prophetIDMap))
-	for id := range prophetIDMap {
-		prophetIDs = append(prophetIDs, id)
-	}
-
-	// Get prophet names map from gRPC - now returns map[userID]name directly
-	prophetNames, err := s.user_provider.MapProphetNamesByIDs(ctx, prophetIDs)
+	prophets, err := s.prophetSummaries(ctx, prophetIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to map for quick lookup
-	nameMap := make(map[string]string)
-	for _, pn := range prophetNames {
-		nameMap[pn.UserID] = pn.Name
-	}
-
-	// Map courses to CourseWithProphetName
 	results := make([]*domain.CourseWithProphetName, 0, len(courses))
 	for _, course := range courses {
-		prophetName := nameMap[course.ProphetID]
-		results = append(results, &domain.CourseWithProphetName{
-			ID:          course.ID,
-			ProphetID:   course.ProphetID,
-			ProphetName: prophetName,
-			CourseName:  course.CourseName,
-			CourseType:  course.CourseType,
-			Description: course.Description,
-			Price:       course.Price,
-			Duration:    course.Duration,
-			CreatedAt:   course.CreatedAt,
-			DeletedAt:   course.DeletedAt,
-			ReviewCount: course.ReviewCount,
-			ReviewScore: course.ReviewScore,
-		})
+		results = append(results, withProphet(course, prophets[course.ProphetID]))
 	}
-
 	return results, nil
 }
 
+// prophetSummaries looks up the profile summaries of prophets, keyed by ID
+func (s courseService) prophetSummaries(ctx context.Context, prophetIDs []string) (map[string]*domain.ProphetSummary, error) {
+	seen := make(map[string]bool, len(prophetIDs))
+	unique := make([]string, 0, len(prophetIDs))
+	for _, id := range prophetIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return map[string]*domain.ProphetSummary{}, nil
+	}
+	return s.user_provider.MapProphetSummariesByIDs(ctx, unique)
+}
+
+// withProphet lists a course with its prophet. Courses store their prophet's
+// name for search, which stands in when the prophet has no summary.
+func withProphet(course *domain.Course, prophet *domain.ProphetSummary) *domain.CourseWithProphetName {
+	prophetName := course.ProphetName
+	if prophet != nil {
+		prophetName = prophet.Name
+	}
+	return &domain.CourseWithProphetName{
+		ID:          course.ID,
+		ProphetID:   course.ProphetID,
+		ProphetName: prophetName,
+		CourseName:  course.CourseName,
+		CourseType:  course.CourseType,
+		Description: course.Description,
+		Price:       course.Price,
+		Currency:    course.Currency,
+		Duration:    course.Duration,
+		Sessions:    course.Sessions,
+		CreatedAt:   course.CreatedAt,
+		DeletedAt:   course.DeletedAt,
+		ReviewCount: course.ReviewCount,
+		ReviewScore: course.ReviewScore,
+		Prophet:     prophet,
+	}
+}
+
 // helper
 func generateID(prefix string) string {
 	return prefix + "-" + uuid.New().String()
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"log"
+)
+
+// Hide all of a suspended prophet's courses and stop them creating more.
+// Orders already placed and their bookings are left alone.
+func (s *courseService) SuspendProphet(ctx context.Context, prophetID string) error {
+	n, err := s.repo.SetProphetSuspended(ctx, prophetID, true)
+	if err != nil {
+		return err
+	}
+	log.Printf("Suspended prophet %s, hiding %d courses", prophetID, n)
+	return nil
+}
+
+// Show a reinstated prophet's courses again
+func (s *courseService) ReinstateProphet(ctx context.Context, prophetID string) error {
+	n, err := s.repo.SetProphetSuspended(ctx, prophetID, false)
+	if err != nil {
+		return err
+	}
+	log.Printf("Reinstated prophet %s, showing %d courses", prophetID, n)
+	return nil
+}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//
+// ─── AVAILABILITY & BOOKING ───────────────────────────────────────────────────
+//
+
+// SlotGranularity is the grid sessions start on and the unit bookings are
+// reserved in. Every course duration is a multiple of it.
+const SlotGranularity = 15 * time.Minute
+
+const (
+	clockLayout = "15:04"
+	dateLayout  = "2006-01-02"
+)
+
+var (
+	ErrCourseNotFound      = errors.New("course not found")
+	ErrInvalidAvailability = errors.New("invalid availability")
+	ErrNoAvailability      = errors.New("prophet has not published availability")
+	ErrSlotUnavailable     = errors.New("requested time is outside the prophet's availability")
+	ErrSlotTaken           = errors.New("requested time is already booked")
+	ErrBookingNotFound     = errors.New("booking not found")
+)
+
+// TimeWindow is a range of wall-clock time ("HH:MM") in the prophet's timezone
+type TimeWindow struct {
+	Start string `bson:"start" json:"start"`
+	End   string `bson:"end"   json:"end"`
+}
+
+// WeeklySlot is a recurring window on a day of the week (0 = Sunday)
+type WeeklySlot struct {
+	Weekday    time.Weekday `bson:"weekday" json:"weekday"`
+	TimeWindow `bson:",inline"`
+}
+
+// AvailabilityException replaces the weekly windows on a single date. An
+// exception without windows marks the prophet as away for the whole day.
+type AvailabilityException struct {
+	Date    string       `bson:"date"    json:"date"`
+	Windows []TimeWindow `bson:"windows" json:"windows"`
+}
+
+// Stored in "availability" collection, one document per prophet
+type Availability struct {
+	ProphetID  string                  `bson:"prophet_id" json:"prophet_id"`
+	Timezone   string                  `bson:"timezone"   json:"timezone"`
+	Weekly     []WeeklySlot            `bson:"weekly"     json:"weekly"`
+	Exceptions []AvailabilityException `bson:"exceptions" json:"exceptions"`
+	UpdatedAt  time.Time               `bson:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the timezone and that every window is well formed
+func (a *Availability) Validate() error {
+	if _, err := time.LoadLocation(a.Timezone); err != nil || a.Timezone == "" {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidAvailability, a.Timezone)
+	}
+	for _, slot := range a.Weekly {
+		if slot.Weekday < time.Sunday || slot.Weekday > time.Saturday {
+			return fmt.Errorf("%w: weekday must be between 0 and 6", ErrInvalidAvailability)
+		}
+		if err := slot.TimeWindow.validate(); err != nil {
+			return err
+		}
+	}
+	seen := make(map[string]bool)
+	for _, exception := range a.Exceptions {
+		if _, err := time.Parse(dateLayout, exception.Date); err != nil {
+			return fmt.Errorf("%w: exception date must be YYYY-MM-DD", ErrInvalidAvailability)
+		}
+		if seen[exception.Date] {
+			return fmt.Errorf("%w: duplicate exception for %s", ErrInvalidAvailability, exception.Date)
+		}
+		seen[exception.Date] = true
+		for _, window := range exception.Windows {
+			if err := window.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Location returns the prophet's timezone
+func (a *Availability) Location() *time.Location {
+	loc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Covers reports whether a session from start to end falls entirely inside
+// one of the prophet's windows for that local date
+func (a *Availability) Covers(start, end time.Time) bool {
+	loc := a.Location()
+	localStart, localEnd := start.In(loc), end.In(loc)
+	for _, window := range a.windowsOn(localStart) {
+		windowStart, windowEnd := window.on(localStart, loc)
+		if !localStart.Before(windowStart) && !localEnd.After(windowEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionStarts lists every grid-aligned start time between from and to at
+// which a session of the given length fits the prophet's windows
+func (a *Availability) SessionStarts(from, to time.Time, length time.Duration) []time.Time {
+	loc := a.Location()
+	var starts []time.Time
+	day := time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
+	for !day.After(to) {
+		for _, window := range a.windowsOn(day) {
+			windowStart, windowEnd := window.on(day, loc)
+			for start := windowStart; !start.Add(length).After(windowEnd); start = start.Add(SlotGranularity) {
+				if start.Before(from) || start.Add(length).After(to) {
+					continue
+				}
+				starts = append(starts, start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return starts
+}
+
+func (a *Availability) windowsOn(localDay time.Time) []TimeWindow {
+	date := localDay.Format(dateLayout)
+	for _, exception := range a.Exceptions {
+		if exception.Date == date {
+			return exception.Windows
+		}
+	}
+	var windows []TimeWindow
+	for _, slot := range a.Weekly {
+		if slot.Weekday == localDay.Weekday() {
+			windows = append(windows, slot.TimeWindow)
+		}
+	}
+	return windows
+}
+
+func (w TimeWindow) validate() error {
+	start, err := time.Parse(clockLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("%w: start %q must be HH:MM", ErrInvalidAvailability, w.Start)
+	}
+	end, err := time.Parse(clockLayout, w.End)
+	if err != nil && w.End != "24:00" {
+		return fmt.Errorf("%w: end %q must be HH:MM", ErrInvalidAvailability, w.End)
+	}
+	if w.End != "24:00" && !end.After(start) {
+		return fmt.Errorf("%w: window %s-%s ends before it starts", ErrInvalidAvailability, w.Start, w.End)
+	}
+	granularity := int(SlotGranularity / time.Minute)
+	if start.Minute()%granularity != 0 || end.Minute()%granularity != 0 {
+		return fmt.Errorf("%w: windows must start and end on a %d minute boundary", ErrInvalidAvailability, granularity)
+	}
+	return nil
+}
+
+// on resolves the window to absolute times on the given local date
+func (w TimeWindow) on(localDay time.Time, loc *time.Location) (time.Time, time.Time) {
+	year, month, day := localDay.Date()
+	start, _ := time.Parse(clockLayout, w.Start)
+	windowStart := time.Date(year, month, day, start.Hour(), start.Minute(), 0, 0, loc)
+	if w.End == "24:00" {
+		return windowStart, time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	}
+	end, _ := time.Parse(clockLayout, w.End)
+	return windowStart, time.Date(year, month, day, end.Hour(), end.Minute(), 0, 0, loc)
+}
+
+type BookingStatus string
+
+const (
+	BookingStatusReserved BookingStatus = "RESERVED"
+	BookingStatusReleased BookingStatus = "RELEASED"
+)
+
+// Stored in "bookings" collection. Times are kept in UTC together with the
+// prophet's timezone so clients can render the session in local time.
+type Booking struct {
+	ID         string        `bson:"id"          json:"id"`
+	CourseID   string        `bson:"course_id"   json:"course_id"`
+	ProphetID  string        `bson:"prophet_id"  json:"prophet_id"`
+	CustomerID string        `bson:"customer_id" json:"customer_id"`
+	OrderID    string        `bson:"order_id"    json:"order_id"`
+	Session    int           `bson:"session"     json:"session"`
+	StartAt    time.Time     `bson:"start_at"    json:"start_at"`
+	EndAt      time.Time     `bson:"end_at"      json:"end_at"`
+	Timezone   string        `bson:"timezone"    json:"timezone"`
+	Status     BookingStatus `bson:"status"      json:"status"`
+	CreatedAt  time.Time     `bson:"created_at"  json:"created_at"`
+}
+
+// Blocks returns the start of every SlotGranularity block the booking occupies
+func (b *Booking) Blocks() []time.Time {
+	var blocks []time.Time
+	for block := b.StartAt; block.Before(b.EndAt); block = block.Add(SlotGranularity) {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// AvailableSlot is a free session start offered to customers
+type AvailableSlot struct {
+	StartAt  time.Time `json:"start_at"`
+	EndAt    time.Time `json:"end_at"`
+	Timezone string    `json:"timezone"`
+}
@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
 
 //
 // ─── COURSE STRUCTS ────────────────────────────────────────────────────────────
@@ -13,16 +18,39 @@ type Course struct {
 	CourseName   string       `bson:"coursename"    json:"coursename"`
 	CourseType   CourseType   `bson:"coursetype"    json:"coursetype"`
 	Description  string       `bson:"description"   json:"description"`
-	Price        float64      `bson:"price"         json:"price"`
+	Price        money.Decimal `bson:"price"         json:"price"`
+	Currency     string        `bson:"currency"      json:"currency"`
 	Duration     DurationEnum `bson:"duration"      json:"duration"`
+	Sessions     int          `bson:"sessions"      json:"sessions"`
 	CreatedAt    time.Time    `bson:"created_time"  json:"created_time"`
 	DeletedAt    bool         `bson:"deleted_at"    json:"deleted_at"`
 
+	// Set while an admin has the prophet suspended, hiding the course
+	ProphetSuspended bool `bson:"prophet_suspended,omitempty" json:"-"`
+
+	// Denormalized for text search — refreshed whenever the prophet creates a
+	// course, and backfilled at startup
+	ProphetName string `bson:"prophetname,omitempty" json:"prophetname,omitempty"`
+
 	// Denormalized fields — automatically updated when reviews change
 	ReviewCount int     `bson:"review_count" json:"review_count"`
 	ReviewScore float64 `bson:"review_score" json:"review_score"`
 }
 
+// MaxBundleSessions caps how many sessions one course sells
+const MaxBundleSessions = 12
+
+var ErrInvalidSessions = errors.New("sessions must be between 1 and 12")
+
+// ValidateSessions checks the number of sessions a course sells. A course of
+// one session is a single reading; more make it a bundle sold at Price.
+func ValidateSessions(sessions int) error {
+	if sessions < 1 || sessions > MaxBundleSessions {
+		return ErrInvalidSessions
+	}
+	return nil
+}
+
 // Course with Prophet Name (useful for listing / joins)
 type CourseWithProphetName struct {
 	ID           string       `bson:"id"            json:"id"`
@@ -31,12 +59,17 @@ type CourseWithProphetName struct {
 	CourseName   string       `bson:"coursename"    json:"coursename"`
 	CourseType   CourseType   `bson:"coursetype"    json:"coursetype"`
 	Description  string       `bson:"description"   json:"description"`
-	Price        float64      `bson:"price"         json:"price"`
+	Price        money.Decimal `bson:"price"         json:"price"`
+	Currency     string        `bson:"currency"      json:"currency"`
 	Duration     DurationEnum `bson:"duration"      json:"duration"`
+	Sessions     int          `bson:"sessions"      json:"sessions"`
 	CreatedAt    time.Time    `bson:"created_time"  json:"created_time"`
 	DeletedAt    bool         `bson:"deleted_at"    json:"deleted_at"`
 	ReviewCount  int          `bson:"review_count"  json:"review_count"`
 	ReviewScore  float64      `bson:"review_score"  json:"review_score"`
+
+	// Prophet is filled in from user-management when listing courses
+	Prophet *ProphetSummary `bson:"-" json:"prophet,omitempty"`
 }
 
 // CourseDetail — returned when joining course + reviews
@@ -47,8 +80,10 @@ type CourseDetail struct {
 	CourseName   string       `bson:"coursename"    json:"coursename"`
 	CourseType   CourseType   `bson:"coursetype"    json:"coursetype"`
 	Description  string       `bson:"description"   json:"description"`
-	Price        float64      `bson:"price"         json:"price"`
+	Price        money.Decimal `bson:"price"         json:"price"`
+	Currency     string        `bson:"currency"      json:"currency"`
 	Duration     DurationEnum `bson:"duration"      json:"duration"`
+	Sessions     int          `bson:"sessions"      json:"sessions"`
 	CreatedAt    time.Time    `bson:"created_time"  json:"created_time"`
 	DeletedAt    bool         `bson:"deleted_at"    json:"deleted_at"`
 
@@ -58,16 +93,22 @@ type CourseDetail struct {
 
 	// Embedded array of reviews (from $lookup)
 	Reviews []*Review `bson:"reviews" json:"reviews"`
+
+	// Prophet is filled in from user-management
+	Prophet *ProphetSummary `bson:"-" json:"prophet,omitempty"`
 }
 
 //
 // ─── REVIEW STRUCTS ────────────────────────────────────────────────────────────
 //
 
-// Stored in "reviews" collection
+// Stored in "reviews" collection. Each review belongs to the completed order
+// its customer took the course through; reviews written before that carry no
+// OrderID.
 type Review struct {
 	ID           string    `bson:"id"            json:"id"`
 	CourseID     string    `bson:"course_id"     json:"course_id"`
+	OrderID      string    `bson:"order_id"      json:"order_id"`
 	CustomerID   string    `bson:"customer_id"   json:"customer_id"`
 	CustomerName string    `bson:"customername"  json:"customername"`
 	Score        float64   `bson:"score"         json:"score"`
@@ -75,6 +116,92 @@ type Review struct {
 	Description  string    `bson:"description"   json:"description"`
 	CreatedAt    time.Time `bson:"created_at"    json:"created_at"`
 	DeletedAt    bool      `bson:"deleted_at"    json:"deleted_at"`
+
+	// The course prophet's public answer, at most one per review
+	Reply *ReviewReply `bson:"reply,omitempty" json:"reply,omitempty"`
+
+	// Moderation. Who flagged a review and what admins noted stay out of
+	// public responses; admins see them through ModeratedReview.
+	Flags            []ReviewFlag      `bson:"flags,omitempty"      json:"-"`
+	FlagCount        int               `bson:"flag_count"           json:"flag_count"`
+	ModerationStatus ModerationStatus  `bson:"moderation_status"    json:"moderation_status,omitempty"`
+	Moderation       *ReviewModeration `bson:"moderation,omitempty" json:"-"`
+}
+
+type ReviewReply struct {
+	ProphetID string    `bson:"prophet_id" json:"prophet_id"`
+	Message   string    `bson:"message"    json:"message"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// ReviewFlag is one user's report of a review
+type ReviewFlag struct {
+	UserID    string    `bson:"user_id"    json:"user_id"`
+	Role      string    `bson:"role"       json:"role"`
+	Reason    string    `bson:"reason"     json:"reason"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// ReviewModeration records the admin's last decision on a review
+type ReviewModeration struct {
+	AdminID     string    `bson:"admin_id"     json:"admin_id"`
+	Note        string    `bson:"note"         json:"note"`
+	ModeratedAt time.Time `bson:"moderated_at" json:"moderated_at"`
+}
+
+// ModerationStatus tracks a review through the admin moderation queue. Reviews
+// nobody has flagged have none. Flagging puts a review in the queue as
+// PENDING; an admin then hides it or restores it to CLEARED. A cleared review
+// flagged again goes back in the queue.
+type ModerationStatus string
+
+const (
+	ModerationPending ModerationStatus = "PENDING"
+	ModerationHidden  ModerationStatus = "HIDDEN"
+	ModerationCleared ModerationStatus = "CLEARED"
+)
+
+func ParseModerationStatus(s string) (ModerationStatus, error) {
+	switch status := ModerationStatus(s); status {
+	case ModerationPending, ModerationHidden, ModerationCleared:
+		return status, nil
+	}
+	return "", ErrInvalidModerationStatus
+}
+
+// ModeratedReview is a review as admins see it in the moderation queue
+type ModeratedReview struct {
+	*Review
+	Flags      []ReviewFlag      `json:"flags"`
+	Moderation *ReviewModeration `json:"moderation,omitempty"`
+}
+
+// Review scores are star ratings within this range
+const (
+	MinReviewScore = 1
+	MaxReviewScore = 5
+)
+
+var (
+	ErrInvalidScore     = errors.New("score must be between 1 and 5")
+	ErrReviewNotFound   = errors.New("review not found")
+	ErrReviewNotAllowed = errors.New("only customers who completed an order for this course can review it")
+	ErrAlreadyReviewed  = errors.New("order has already been reviewed")
+	ErrNotReviewAuthor  = errors.New("only the customer who wrote the review can change it")
+
+	ErrEmptyReply              = errors.New("reply message is required")
+	ErrNotCourseProphet        = errors.New("only the course's prophet can reply to its reviews")
+	ErrAlreadyReplied          = errors.New("review already has a reply")
+	ErrAlreadyFlagged          = errors.New("review already flagged by this user")
+	ErrInvalidModerationStatus = errors.New("moderation status must be PENDING, HIDDEN or CLEARED")
+)
+
+// ValidateReviewScore checks a review's score is within the star range
+func ValidateReviewScore(score float64) error {
+	if score < MinReviewScore || score > MaxReviewScore {
+		return ErrInvalidScore
+	}
+	return nil
 }
 
 //
@@ -84,8 +211,10 @@ type Review struct {
 type UpdateCourseInput struct {
 	CourseName  string        `bson:"coursename,omitempty"  json:"coursename,omitempty"`
 	Description string        `bson:"description,omitempty" json:"description,omitempty"`
-	Price       *float64      `bson:"price,omitempty"       json:"price,omitempty"`
+	Price       *money.Decimal `bson:"price,omitempty"       json:"price,omitempty"`
+	Currency    string         `bson:"currency,omitempty"    json:"currency,omitempty"`
 	Duration    *DurationEnum `bson:"duration,omitempty"    json:"duration,omitempty"`
+	Sessions    *int          `bson:"sessions,omitempty"    json:"sessions,omitempty"`
 	DeletedAt   bool          `bson:"deleted_at,omitempty"  json:"deleted_at,omitempty"`
 }
 
@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+// OrderStatusCompleted is the order-service status of an order whose sessions
+// are done and can no longer be disputed
+const OrderStatusCompleted = "COMPLETED"
+
+var ErrOrderNotFound = errors.New("order not found")
+
+// Order is what course-service needs to know about an order-service order
+type Order struct {
+	ID         string
+	CustomerID string
+	CourseID   string
+	Status     string
+}
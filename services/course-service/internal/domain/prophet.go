@@ -1,6 +1,24 @@
 package domain
 
+import "errors"
+
+// ErrProphetSuspended is returned when a suspended prophet creates a course
+var ErrProphetSuspended = errors.New("prophet is suspended")
+
 type ProphetName struct {
 	UserID string
 	Name string
-}
\ No newline at end of file
+}
+
+// ProphetSummary is the part of a prophet's user-management profile shown
+// alongside their courses
+type ProphetSummary struct {
+	UserID            string       `json:"user_id"`
+	Name              string       `json:"name"`
+	AvatarURL         string       `json:"avatar_url,omitempty"`
+	Verified          bool         `json:"verified"`
+	Online            bool         `json:"online"`
+	Specialties       []CourseType `json:"specialties"`
+	Languages         []string     `json:"languages"`
+	YearsOfExperience int          `json:"years_of_experience"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// CourseCompletion records an order for a course that completed, as
+// published by order-service on order.completed. Trending courses count
+// recent ones; recommendations follow each customer's own.
+type CourseCompletion struct {
+	OrderID     string     `bson:"order_id"     json:"order_id"`
+	CourseID    string     `bson:"course_id"    json:"course_id"`
+	CustomerID  string     `bson:"customer_id"  json:"customer_id"`
+	ProphetID   string     `bson:"prophet_id"   json:"prophet_id"`
+	CourseType  CourseType `bson:"coursetype"   json:"coursetype"`
+	CompletedAt time.Time  `bson:"completed_at" json:"completed_at"`
+}
+
+// RatingPriorWeight is how many reviews at the platform's average score every
+// course's rating starts with when ranking popular courses, so a course with
+// a handful of perfect reviews does not outrank one with hundreds of good ones
+const RatingPriorWeight = 10
+
+const (
+	// TrendingWindow is how far back completed orders count towards trending
+	TrendingWindow = 14 * 24 * time.Hour
+	// TrendingHalfLife is how long it takes a completed order to count half
+	// as much towards trending as one completed just now
+	TrendingHalfLife = 3 * 24 * time.Hour
+)
+
+// RecommendationHistorySize caps how many of a customer's most recent
+// completed orders their recommendations are based on
+const RecommendationHistorySize = 50
+
+// CustomerTaste is what a customer's completed orders say they like: how many
+// were of each course type and with each prophet, and which courses they have
+// already taken
+type CustomerTaste struct {
+	CourseTypes map[CourseType]int
+	Prophets    map[string]int
+	Taken       []string
+}
+
+// NewCustomerTaste sums up a customer's completed orders
+func NewCustomerTaste(completions []*CourseCompletion) CustomerTaste {
+	taste := CustomerTaste{
+		CourseTypes: make(map[CourseType]int),
+		Prophets:    make(map[string]int),
+	}
+	taken := make(map[string]bool)
+	for _, c := range completions {
+		if c.CourseType != "" {
+			taste.CourseTypes[c.CourseType]++
+		}
+		if c.ProphetID != "" {
+			taste.Prophets[c.ProphetID]++
+		}
+		if !taken[c.CourseID] {
+			taken[c.CourseID] = true
+			taste.Taken = append(taste.Taken, c.CourseID)
+		}
+	}
+	return taste
+}
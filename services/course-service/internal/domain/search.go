@@ -0,0 +1,29 @@
+package domain
+
+// CourseSearchResult is a page of courses matching a search, with the total
+// and facet counts over everything that matched
+type CourseSearchResult struct {
+	Courses    []*CourseWithProphetName `json:"courses"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	Total      int64                    `json:"total"`
+	Facets     CourseFacets             `json:"facets"`
+}
+
+type CourseFacets struct {
+	CourseTypes  []FacetCount  `json:"coursetype"`
+	Durations    []FacetCount  `json:"duration"`
+	PriceBuckets []PriceBucket `json:"price"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PriceBucket counts courses priced from Min up to, but not including, Max.
+// The last bucket has no Max.
+type PriceBucket struct {
+	Min   int64 `json:"min"`
+	Max   int64 `json:"max,omitempty"`
+	Count int   `json:"count"`
+}
@@ -10,4 +10,13 @@ type UserProvider interface {
 	MapProphetNamesByIDs(ctx context.Context, userIDs []string) ([]domain.ProphetName, error)
 	GetProphetName(ctx context.Context, userID string) (string, error)
 	GetProphetIDsByNames(ctx context.Context, prophetName string) ([]domain.ProphetName, error)
+	// MapUserNamesByIDs resolves the names of users of any role, keyed by ID
+	MapUserNamesByIDs(ctx context.Context, userIDs []string) (map[string]string, error)
+	// MapProphetSummariesByIDs looks up prophets' profile summaries, keyed by
+	// ID; IDs that are not prophets are left out
+	MapProphetSummariesByIDs(ctx context.Context, userIDs []string) (map[string]*domain.ProphetSummary, error)
+}
+
+type OrderProvider interface {
+	GetOrder(ctx context.Context, orderID string) (*domain.Order, error)
 }
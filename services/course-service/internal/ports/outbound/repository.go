@@ -2,9 +2,11 @@ package outbound
 
 import (
 	"context"
+	"time"
 
 	"github.com/wnmay/horo/services/course-service/internal/adapters/outbound/db"
 	"github.com/wnmay/horo/services/course-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 )
 
 type CourseRepository interface {
@@ -14,16 +16,62 @@ type CourseRepository interface {
 	FindCoursesByProphet(ctx context.Context, prophetID string) ([]*domain.Course, error)
 	UpdateCourse(ctx context.Context, id string, updates map[string]interface{}) (*domain.Course, error)
 	DeleteCourse(ctx context.Context, id string) error
+	// SetMissingCurrency declares currency on courses priced before courses
+	// carried one
+	SetMissingCurrency(ctx context.Context, currency string) (int64, error)
+	// SetMissingSessions marks courses created before bundles as single readings
+	SetMissingSessions(ctx context.Context) (int64, error)
+	// SetProphetName stores the prophet's name on all their courses for search
+	SetProphetName(ctx context.Context, prophetID string, name string) (int64, error)
+	FindProphetIDsMissingName(ctx context.Context) ([]string, error)
+	// SetProphetSuspended hides or shows all of a prophet's courses
+	SetProphetSuspended(ctx context.Context, prophetID string, suspended bool) (int64, error)
+	IsProphetSuspended(ctx context.Context, prophetID string) (bool, error)
 	
 	//Filter, sort
-	FindByFilter(ctx context.Context, filter db.CourseFilter, sort db.CourseSort) ([]*domain.Course, error)
+	FindByFilter(ctx context.Context, filter db.CourseFilter, sort db.CourseSort, page contract.PageRequest) (contract.Page[*domain.Course], *domain.CourseFacets, error)
 
 	//Review
 	SaveReview(ctx context.Context, review *domain.Review) error
 	FindReviewByID(ctx context.Context, id string) (*domain.Review, error)
-	FindReviewsByCourse(ctx context.Context, courseID string) ([]*domain.Review, error)
+	FindReviewsByCourse(ctx context.Context, courseID string, page contract.PageRequest) (contract.Page[*domain.Review], error)
+	// UpdateReview changes a live review and recalculates its course's
+	// ReviewCount and ReviewScore; setting deleted_at removes it from both
+	UpdateReview(ctx context.Context, id string, updates map[string]interface{}) (*domain.Review, error)
+	SetReviewReply(ctx context.Context, id string, reply *domain.ReviewReply) (*domain.Review, error)
+	AddReviewFlag(ctx context.Context, id string, flag domain.ReviewFlag) error
+
+	//Review moderation
+	FindReviewsByModerationStatus(ctx context.Context, status domain.ModerationStatus) ([]*domain.Review, error)
+	ModerateReview(ctx context.Context, id string, status domain.ModerationStatus, moderation *domain.ReviewModeration) (*domain.Review, error)
 
 	//Course with review
 	FindCourseDetailByID(ctx context.Context, id string) (*domain.CourseDetail, error)
 	FindPopularCourses(ctx context.Context, limit int) ([]*domain.Course, error)
+
+	//Completed orders, for trending and recommended courses
+	SaveCompletion(ctx context.Context, completion *domain.CourseCompletion) error
+	FindCompletionsByCustomer(ctx context.Context, customerID string, limit int) ([]*domain.CourseCompletion, error)
+	FindTrendingCourses(ctx context.Context, now time.Time, limit int) ([]*domain.Course, error)
+	FindRecommendedCourses(ctx context.Context, taste domain.CustomerTaste, limit int) ([]*domain.Course, error)
+}
+
+type BookingRepository interface {
+	//Availability
+	SaveAvailability(ctx context.Context, availability *domain.Availability) error
+	FindAvailability(ctx context.Context, prophetID string) (*domain.Availability, error)
+
+	//Booking
+	ReserveBooking(ctx context.Context, booking *domain.Booking) error
+	// MoveBooking reserves to in place of from, then releases from. Blocks
+	// the two share pass from one to the other, so a session can move by
+	// less than its length.
+	MoveBooking(ctx context.Context, from, to *domain.Booking) error
+	FindBookingByID(ctx context.Context, id string) (*domain.Booking, error)
+	// FindReservedBookingByOrder finds the booking of one of an order's
+	// sessions; bookings made before bundles count as session 1
+	FindReservedBookingByOrder(ctx context.Context, orderID string, session int) (*domain.Booking, error)
+	ReleaseBooking(ctx context.Context, id string) error
+	FindBookedBlocks(ctx context.Context, prophetID string, from, to time.Time) ([]time.Time, error)
+	FindBookingsByProphet(ctx context.Context, prophetID string, from time.Time) ([]*domain.Booking, error)
 }
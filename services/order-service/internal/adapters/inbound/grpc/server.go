@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/jwt"
+	pb "github.com/wnmay/horo/shared/proto/order"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OrderGRPCServer lets other services look up orders, e.g. course-service
+// checking that a reviewer completed the course, and the gateway check who
+// an order belongs to as the user asking
+type OrderGRPCServer struct {
+	pb.UnimplementedOrderServiceServer
+	svc inbound.OrderService
+}
+
+func NewOrderGRPCServer(s inbound.OrderService) *OrderGRPCServer {
+	return &OrderGRPCServer{svc: s}
+}
+
+func (s *OrderGRPCServer) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "order_id must be a UUID")
+	}
+	order, err := s.svc.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	// Users may only look up their own orders; course-service checks the
+	// orders reviews are left for
+	if !jwt.FromService(ctx, jwt.ServiceCourse) {
+		if err := jwt.AuthorizeUser(ctx, order.CustomerID, order.ProphetID); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.GetOrderResponse{Order: &pb.Order{
+		OrderId:    order.OrderID.String(),
+		CustomerId: order.CustomerID,
+		CourseId:   order.CourseID,
+		ProphetId:  order.ProphetID,
+		Status:     string(order.Status),
+	}}, nil
+}
@@ -0,0 +1,269 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+)
+
+const (
+	// defaultDashboardPeriod is how far back the dashboard looks when no
+	// from is given
+	defaultDashboardPeriod = 30 * 24 * time.Hour
+	maxTopProphets         = 50
+
+	defaultOutboxPageSize = 100
+	maxOutboxPageSize     = 500
+)
+
+type ForceOrderStatusRequest struct {
+	Status string `json:"status" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+}
+
+// ReplayOutboxMessagesRequest names the parked messages to replay; no IDs
+// replays them all
+type ReplayOutboxMessagesRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// OutboxMessageResponse is a parked outbox message as admins see it
+type OutboxMessageResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	RoutingKey string          `json:"routing_key"`
+	OwnerID    string          `json:"owner_id"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FailedAt   *time.Time      `json:"failed_at"`
+}
+
+// requireAdmin returns the ID of the admin calling, or false after
+// responding when the caller is not one
+func requireAdmin(c *fiber.Ctx) (string, bool) {
+	if role, _ := c.Locals("role").(string); role != "admin" {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only admins can do this",
+		})
+		return "", false
+	}
+	userID, _ := c.Locals("userID").(string)
+	return userID, true
+}
+
+func (h *Handler) ForceOrderStatus(c *fiber.Ctx) error {
+	adminID, ok := requireAdmin(c)
+	if !ok {
+		return nil
+	}
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	var req ForceOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	status, err := domain.ParseOrderStatus(req.Status)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	order, err := h.orderService.ForceOrderStatus(c.Context(), inbound.ForceOrderStatusCommand{
+		OrderID: orderID,
+		AdminID: adminID,
+		Status:  status,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAuditReasonRequired):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrTransitionNotAllowed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order status changed successfully",
+		"order":   order,
+	})
+}
+
+func (h *Handler) GetOrderAudit(c *fiber.Ctx) error {
+	if _, ok := requireAdmin(c); !ok {
+		return nil
+	}
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	audits, err := h.orderService.GetOrderAudit(c.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(audits)
+}
+
+// GetDashboard summarises the orders placed between the from and to query
+// parameters (RFC3339), the last 30 days by default, with the top prophets
+// in each currency up to the top parameter
+func (h *Handler) GetDashboard(c *fiber.Ctx) error {
+	if _, ok := requireAdmin(c); !ok {
+		return nil
+	}
+
+	to := time.Now()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "to must be an RFC3339 time",
+			})
+		}
+		to = t
+	}
+	from := to.Add(-defaultDashboardPeriod)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "from must be an RFC3339 time",
+			})
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from must be before to",
+		})
+	}
+
+	top := domain.DefaultTopProphets
+	if v := c.Query("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxTopProphets {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "top must be between 1 and 50",
+			})
+		}
+		top = n
+	}
+
+	dashboard, err := h.orderService.GetDashboard(c.Context(), from, to, top)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(dashboard)
+}
+
+// ListFailedOutboxMessages lists the events the outbox relay parked, oldest
+// first, up to the limit query parameter
+func (h *Handler) ListFailedOutboxMessages(c *fiber.Ctx) error {
+	if _, ok := requireAdmin(c); !ok {
+		return nil
+	}
+
+	limit := defaultOutboxPageSize
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxOutboxPageSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 500",
+			})
+		}
+		limit = n
+	}
+
+	messages, err := h.outboxService.ListFailedMessages(c.Context(), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	response := make([]OutboxMessageResponse, len(messages))
+	for i, message := range messages {
+		response[i] = OutboxMessageResponse{
+			ID:         message.ID,
+			RoutingKey: message.RoutingKey,
+			OwnerID:    message.OwnerID,
+			Payload:    json.RawMessage(message.Payload),
+			Attempts:   message.Attempts,
+			LastError:  message.LastError,
+			CreatedAt:  message.CreatedAt,
+			FailedAt:   message.FailedAt,
+		}
+	}
+	return c.JSON(response)
+}
+
+// ReplayOutboxMessages hands parked events back to the outbox relay once
+// whatever made the broker reject them is fixed
+func (h *Handler) ReplayOutboxMessages(c *fiber.Ctx) error {
+	if _, ok := requireAdmin(c); !ok {
+		return nil
+	}
+
+	var req ReplayOutboxMessagesRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	replayed, err := h.outboxService.ReplayFailedMessages(c.Context(), req.IDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Outbox messages queued for replay",
+		"replayed": replayed,
+	})
+}
@@ -1,22 +1,31 @@
 package http
 
 import (
-	"log"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/wnmay/horo/services/order-service/internal/domain"
 	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type Handler struct {
-	orderService inbound.OrderService
+	orderService        inbound.OrderService
+	promotionService    inbound.PromotionService
+	subscriptionService inbound.SubscriptionService
+	outboxService       inbound.OutboxService
 }
 
-func NewHandler(orderService inbound.OrderService) *Handler {
+func NewHandler(orderService inbound.OrderService, promotionService inbound.PromotionService, subscriptionService inbound.SubscriptionService, outboxService inbound.OutboxService) *Handler {
 	return &Handler{
-		orderService: orderService,
+		orderService:        orderService,
+		promotionService:    promotionService,
+		subscriptionService: subscriptionService,
+		outboxService:       outboxService,
 	}
 }
 
@@ -24,37 +33,49 @@ func (h *Handler) Register(app *fiber.App) {
 	api := app.Group("/api")
 	orders := api.Group("/orders")
 
-	// Public routes
-	orders.Get("/", h.GetOrders)
-	orders.Get("/:id", h.GetOrderByID)
-	orders.Get("/room/:roomID", h.AuthMiddleware, h.GetOrdersByRoom)
+	// Promotions, registered first so /:id does not capture them
+	orders.Post("/promotions", h.AuthMiddleware, h.CreatePromotion)
+	orders.Get("/promotions", h.AuthMiddleware, h.ListPromotions)
+	orders.Post("/promotions/:id/deactivate", h.AuthMiddleware, h.DeactivatePromotion)
+
+	// Subscriptions, registered first for the same reason
+	orders.Post("/subscriptions", h.AuthMiddleware, h.Subscribe)
+	orders.Get("/subscriptions", h.AuthMiddleware, h.ListSubscriptions)
+	orders.Get("/subscriptions/:id", h.AuthMiddleware, h.GetSubscription)
+	orders.Post("/subscriptions/:id/cancel", h.AuthMiddleware, h.CancelSubscription)
+
+	// Admin back office
+	orders.Get("/dashboard", h.AuthMiddleware, h.GetDashboard)
+	orders.Get("/outbox/failed", h.AuthMiddleware, h.ListFailedOutboxMessages)
+	orders.Post("/outbox/replay", h.AuthMiddleware, h.ReplayOutboxMessages)
+	orders.Post("/:id/force-status", h.AuthMiddleware, h.ForceOrderStatus)
+	orders.Get("/:id/audit", h.AuthMiddleware, h.GetOrderAudit)
 
 	// require authentication
+	orders.Get("/", h.AuthMiddleware, h.GetOrders)
+	orders.Get("/:id", h.AuthMiddleware, h.GetOrderByID)
+	orders.Get("/room/:roomID", h.AuthMiddleware, h.GetOrdersByRoom)
 	orders.Post("/", h.AuthMiddleware, h.CreateOrder)
 	orders.Get("/customer/:customerID", h.AuthMiddleware, h.GetOrdersByCustomer)
 	orders.Patch("/:id/status", h.AuthMiddleware, h.UpdateOrderStatus)
 	orders.Patch("/customer/:id", h.AuthMiddleware, h.MarkCustomerCompleted)
 	orders.Patch("/prophet/:id", h.AuthMiddleware, h.MarkProphetCompleted)
+	orders.Patch("/:id/cancel", h.AuthMiddleware, h.CancelOrder)
+	orders.Get("/:id/payment", h.AuthMiddleware, h.GetOrderPayment)
+	orders.Patch("/:id/reschedule", h.AuthMiddleware, h.RescheduleOrder)
+	orders.Post("/:id/sessions", h.AuthMiddleware, h.BookNextSession)
+	orders.Post("/:id/dispute", h.AuthMiddleware, h.OpenDispute)
+	orders.Post("/:id/dispute/resolve", h.AuthMiddleware, h.ResolveDispute)
 }
 
-// AuthMiddleware validates user identity from headers injected by API Gateway
+// AuthMiddleware requires a user, whose identity jwt.IdentityFromToken has
+// set in the headers from the gateway's signed assertion
 func (h *Handler) AuthMiddleware(c *fiber.Ctx) error {
-	// Read user ID from header injected by API Gateway
 	userID := c.Get("X-User-Id")
-	
-	// If header is missing, try to extract from Bearer token (for direct testing)
 	if userID == "" {
-		authHeader := c.Get("Authorization")
-		if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-			// For testing: use a mock user ID
-			// In production, this would verify the token with Firebase
-			userID = "test-user-from-token"
-			log.Printf("Warning: Using mock user ID for direct API testing. Use API Gateway in production.")
-		} else {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "User not authenticated - X-User-Uid header missing",
-			})
-		}
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "user not authenticated",
+		})
 	}
 
 	// Optionally read other user info
@@ -65,19 +86,64 @@ func (h *Handler) AuthMiddleware(c *fiber.Ctx) error {
 	c.Locals("userID", userID)
 	c.Locals("email", email)
 	c.Locals("role", role)
-	
+
 	return c.Next()
 }
 
 type CreateOrderRequest struct {
-	CourseID string `json:"courseId" validate:"required"`
-	RoomID   string `json:"roomId" validate:"required"`
+	CourseID  string `json:"courseId" validate:"required"`
+	RoomID    string `json:"roomId" validate:"required"`
+	StartTime string `json:"startTime" validate:"required"` // RFC3339
+	Currency  string `json:"currency"`                      // defaults to the course currency
+	Coupon    string `json:"coupon"`                        // optional coupon code
+}
+
+type CreatePromotionRequest struct {
+	Code           string        `json:"code"` // empty applies the promotion automatically
+	Description    string        `json:"description"`
+	Kind           string        `json:"kind" validate:"required"` // PERCENTAGE | FIXED
+	Value          money.Decimal `json:"value" validate:"required"`
+	Currency       string        `json:"currency"`  // FIXED only
+	ProphetID      string        `json:"prophetId"` // admins only; prophets are always scoped to themselves
+	FirstOrderOnly bool          `json:"firstOrderOnly"`
+	MaxRedemptions int           `json:"maxRedemptions"`
+	MaxPerCustomer int           `json:"maxPerCustomer"`
+	StartsAt       *time.Time    `json:"startsAt"` // defaults to now
+	ExpiresAt      *time.Time    `json:"expiresAt"`
+}
+
+type SubscribeRequest struct {
+	CourseID  string `json:"courseId" validate:"required"`
+	RoomID    string `json:"roomId" validate:"required"`
+	StartTime string `json:"startTime" validate:"required"` // RFC3339, the first period's session
+	Currency  string `json:"currency"`                      // defaults to the course currency
+}
+
+type RescheduleOrderRequest struct {
+	StartTime string `json:"startTime" validate:"required"` // RFC3339
+}
+
+type BookSessionRequest struct {
+	StartTime string `json:"startTime" validate:"required"` // RFC3339
 }
 
 type UpdateOrderStatusRequest struct {
 	Status string `json:"status" validate:"required"`
 }
 
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+type OpenDisputeRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type ResolveDisputeRequest struct {
+	Resolution string `json:"resolution" validate:"required"` // RELEASED | REFUNDED
+	Note       string `json:"note"`
+}
+
 func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := c.Locals("userID").(string)
@@ -106,16 +172,47 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 		})
 	}
 
+	startAt, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Start time is required in RFC3339 format",
+		})
+	}
+
 	// Create command with authenticated user ID
 	cmd := inbound.CreateOrderCommand{
 		CustomerID: userID, // From JWT token
 		CourseID:   req.CourseID,
 		RoomID:     req.RoomID,
+		StartAt:    startAt,
+		Currency:   req.Currency,
+		CouponCode: req.Coupon,
 	}
 
 	// Call service
 	order, err := h.orderService.CreateOrder(c.Context(), cmd)
 	if err != nil {
+		if errors.Is(err, money.ErrInvalidCurrency) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrCourseUnavailable) || errors.Is(err, domain.ErrRateUnavailable) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrCouponNotFound) || errors.Is(err, domain.ErrCouponExpired) ||
+			errors.Is(err, domain.ErrCouponNotApplicable) || errors.Is(err, domain.ErrPromotionExhausted) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrSlotUnavailable) || errors.Is(err, domain.ErrSlotTaken) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -123,10 +220,37 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(order)
 }
+// GetOrders pages through all orders, or those in the status query
+// parameter, newest first
+// GetOrders lists every order, so only admins may call it
 func (h *Handler) GetOrders(c *fiber.Ctx) error {
-	orders, err := h.orderService.GetOrders(c.Context())
+	if _, ok := requireAdmin(c); !ok {
+		return nil
+	}
+
+	page, err := contract.ParsePageRequest(c.Query("cursor"), c.Query("limit"))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var status domain.OrderStatus
+	if v := c.Query("status"); v != "" {
+		if status, err = domain.ParseOrderStatus(v); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	orders, err := h.orderService.GetOrders(c.Context(), status, page)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, contract.ErrInvalidCursor) {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -147,10 +271,25 @@ func (h *Handler) GetOrderByID(c *fiber.Ctx) error {
 			"error": err.Error(),
 		})
 	}
+	if !canViewOrder(c, order) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": domain.ErrNotOrderParticipant.Error(),
+		})
+	}
 
 	return c.JSON(order)
 }
 
+// canViewOrder reports whether the caller is the order's customer, its
+// prophet or an admin
+func canViewOrder(c *fiber.Ctx, order *domain.Order) bool {
+	if role, _ := c.Locals("role").(string); role == "admin" {
+		return true
+	}
+	userID, _ := c.Locals("userID").(string)
+	return userID != "" && (order.CustomerID == userID || order.ProphetID == userID)
+}
+
 func (h *Handler) GetOrdersByCustomer(c *fiber.Ctx) error {
 	// Get authenticated user ID
 	userID, ok := c.Locals("userID").(string)
@@ -268,6 +407,11 @@ func (h *Handler) MarkCustomerCompleted(c *fiber.Ctx) error {
 	}
 
 	if err := h.orderService.MarkCustomerCompleted(c.Context(), orderID); err != nil {
+		if errors.Is(err, domain.ErrNoSessionBooked) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -307,6 +451,11 @@ func (h *Handler) MarkProphetCompleted(c *fiber.Ctx) error {
 	// For now, just mark as completed
 
 	if err := h.orderService.MarkProphetCompleted(c.Context(), orderID); err != nil {
+		if errors.Is(err, domain.ErrNoSessionBooked) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -325,3 +474,640 @@ func (h *Handler) MarkProphetCompleted(c *fiber.Ctx) error {
 		"order":   updatedOrder,
 	})
 }
+
+func (h *Handler) CancelOrder(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	// Reason is optional, so an empty body is accepted
+	var req CancelOrderRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.Context(), orderID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	order, err := h.orderService.CancelOrder(c.Context(), inbound.CancelOrderCommand{
+		OrderID: orderID,
+		UserID:  userID,
+		Role:    role,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotOrderParticipant), errors.Is(err, domain.ErrInvalidCanceller):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrOrderAlreadyCancelled), errors.Is(err, domain.ErrOrderNotCancellable),
+			errors.Is(err, domain.ErrSubscriptionOrder):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order cancelled successfully",
+		"order":   order,
+	})
+}
+
+func (h *Handler) GetOrderPayment(c *fiber.Ctx) error {
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	order, err := h.orderService.GetOrderByID(c.Context(), orderID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+	if !canViewOrder(c, order) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": domain.ErrNotOrderParticipant.Error(),
+		})
+	}
+
+	payment, err := h.orderService.GetOrderPayment(c.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(payment)
+}
+
+func (h *Handler) RescheduleOrder(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	var req RescheduleOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	startAt, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Start time is required in RFC3339 format",
+		})
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.Context(), orderID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	order, err := h.orderService.RescheduleOrder(c.Context(), inbound.RescheduleOrderCommand{
+		OrderID:    orderID,
+		CustomerID: userID,
+		StartAt:    startAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotOrderParticipant):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrOrderNotReschedulable), errors.Is(err, domain.ErrSlotUnavailable), errors.Is(err, domain.ErrSlotTaken):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order rescheduled successfully",
+		"order":   order,
+	})
+}
+
+// BookNextSession books the next session of a bundle order
+func (h *Handler) BookNextSession(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	var req BookSessionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	startAt, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Start time is required in RFC3339 format",
+		})
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.Context(), orderID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	order, err := h.orderService.BookNextSession(c.Context(), inbound.BookSessionCommand{
+		OrderID:    orderID,
+		CustomerID: userID,
+		StartAt:    startAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotOrderParticipant):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrOrderNotBookable), errors.Is(err, domain.ErrSessionInProgress),
+			errors.Is(err, domain.ErrNoSessionsLeft), errors.Is(err, domain.ErrSlotUnavailable), errors.Is(err, domain.ErrSlotTaken):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Session booked successfully",
+		"order":   order,
+	})
+}
+
+func (h *Handler) OpenDispute(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	var req OpenDisputeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Reason is required",
+		})
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.Context(), orderID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	order, err := h.orderService.OpenDispute(c.Context(), inbound.OpenDisputeCommand{
+		OrderID:    orderID,
+		CustomerID: userID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotOrderParticipant):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrOrderNotDisputable), errors.Is(err, domain.ErrDisputeWindowClosed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Dispute opened successfully",
+		"order":   order,
+	})
+}
+
+func (h *Handler) ResolveDispute(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid order ID format",
+		})
+	}
+
+	var req ResolveDisputeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.Context(), orderID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Order not found",
+		})
+	}
+
+	order, err := h.orderService.ResolveDispute(c.Context(), inbound.ResolveDisputeCommand{
+		OrderID:    orderID,
+		UserID:     userID,
+		Role:       role,
+		Resolution: domain.DisputeResolution(strings.ToUpper(req.Resolution)),
+		Note:       req.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidResolution):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrNotOrderParticipant), errors.Is(err, domain.ErrResolutionNotAllowed):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrOrderNotDisputed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Dispute resolved successfully",
+		"order":   order,
+	})
+}
+
+func (h *Handler) CreatePromotion(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	var req CreatePromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	cmd := inbound.CreatePromotionCommand{
+		UserID:         userID,
+		Role:           role,
+		Code:           req.Code,
+		Description:    req.Description,
+		Kind:           domain.PromotionKind(strings.ToUpper(req.Kind)),
+		Value:          req.Value,
+		Currency:       req.Currency,
+		ProphetID:      req.ProphetID,
+		FirstOrderOnly: req.FirstOrderOnly,
+		MaxRedemptions: req.MaxRedemptions,
+		MaxPerCustomer: req.MaxPerCustomer,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if req.StartsAt != nil {
+		cmd.StartsAt = *req.StartsAt
+	}
+
+	promotion, err := h.promotionService.CreatePromotion(c.Context(), cmd)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotPromotionOwner):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrInvalidPromotion), errors.Is(err, money.ErrInvalidCurrency):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrCouponCodeTaken):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(promotion)
+}
+
+func (h *Handler) ListPromotions(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	promotions, err := h.promotionService.ListPromotions(c.Context(), userID, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotPromotionOwner) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(promotions)
+}
+
+func (h *Handler) DeactivatePromotion(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	promotionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid promotion ID format",
+		})
+	}
+
+	promotion, err := h.promotionService.DeactivatePromotion(c.Context(), promotionID, userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPromotionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrNotPromotionOwner):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message":   "Promotion deactivated successfully",
+		"promotion": promotion,
+	})
+}
+
+func (h *Handler) Subscribe(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+
+	var req SubscribeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.CourseID == "" || req.RoomID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Course ID and room ID are required",
+		})
+	}
+
+	startAt, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Start time is required in RFC3339 format",
+		})
+	}
+
+	subscription, order, err := h.subscriptionService.Subscribe(c.Context(), inbound.SubscribeCommand{
+		CustomerID: userID,
+		CourseID:   req.CourseID,
+		RoomID:     req.RoomID,
+		StartAt:    startAt,
+		Currency:   req.Currency,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, money.ErrInvalidCurrency):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrCourseUnavailable), errors.Is(err, domain.ErrRateUnavailable):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrSlotUnavailable), errors.Is(err, domain.ErrSlotTaken):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"subscription": subscription,
+		"order":        order,
+	})
+}
+
+func (h *Handler) ListSubscriptions(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	subscriptions, err := h.subscriptionService.ListSubscriptions(c.Context(), userID, role)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(subscriptions)
+}
+
+func (h *Handler) GetSubscription(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid subscription ID format",
+		})
+	}
+
+	subscription, orders, err := h.subscriptionService.GetSubscription(c.Context(), subscriptionID, userID, role)
+	if err != nil {
+		return subscriptionError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"subscription": subscription,
+		"orders":       orders,
+	})
+}
+
+func (h *Handler) CancelSubscription(c *fiber.Ctx) error {
+	// Get authenticated user ID
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid subscription ID format",
+		})
+	}
+
+	subscription, err := h.subscriptionService.CancelSubscription(c.Context(), subscriptionID, userID)
+	if err != nil {
+		return subscriptionError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message":      "Subscription cancelled successfully",
+		"subscription": subscription,
+	})
+}
+
+func subscriptionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSubscriptionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrNotSubscriber):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrSubscriptionEnded):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+}
@@ -3,6 +3,7 @@ package message
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -10,18 +11,21 @@ import (
 	"github.com/wnmay/horo/services/order-service/internal/domain"
 	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
 	"github.com/wnmay/horo/shared/message"
+	"github.com/wnmay/horo/shared/money"
 	"github.com/wnmay/horo/shared/contract"
 )
 
 type Consumer struct {
 	orderService inbound.OrderService
 	rabbit       *message.RabbitMQ
+	processed    message.ProcessedMessageStore
 }
 
-func NewConsumer(orderService inbound.OrderService, rabbit *message.RabbitMQ) *Consumer {
+func NewConsumer(orderService inbound.OrderService, rabbit *message.RabbitMQ, processed message.ProcessedMessageStore) *Consumer {
 	return &Consumer{
 		orderService: orderService,
 		rabbit:       rabbit,
+		processed:    processed,
 	}
 }
 
@@ -44,20 +48,34 @@ func (c *Consumer) StartListening() error {
 		return err
 	}
 
+	// Queue 3: Listen to payment refunded events (for cancelling orders an
+	// admin refunded)
+	paymentRefundedQueue := message.CancelRefundedOrderQueue
+	if err := c.rabbit.DeclareQueue(paymentRefundedQueue, contract.PaymentRefundedEvent); err != nil {
+		return err
+	}
+
 	// Start consuming payment success messages
 	go func() {
-		if err := c.rabbit.ConsumeMessages(paymentSuccessQueue, c.handlePaymentSuccess); err != nil {
+		if err := c.rabbit.ConsumeMessages(paymentSuccessQueue, message.Idempotent(c.processed, paymentSuccessQueue, c.handlePaymentSuccess)); err != nil {
 			log.Printf("Error consuming payment success messages: %v", err)
 		}
 	}()
 
 	// Start consuming payment created messages
 	go func() {
-		if err := c.rabbit.ConsumeMessages(paymentCreatedQueue, c.handlePaymentCreated); err != nil {
+		if err := c.rabbit.ConsumeMessages(paymentCreatedQueue, message.Idempotent(c.processed, paymentCreatedQueue, c.handlePaymentCreated)); err != nil {
 			log.Printf("Error consuming payment created messages: %v", err)
 		}
 	}()
 
+	// Start consuming payment refunded messages
+	go func() {
+		if err := c.rabbit.ConsumeMessages(paymentRefundedQueue, message.Idempotent(c.processed, paymentRefundedQueue, c.handlePaymentRefunded)); err != nil {
+			log.Printf("Error consuming payment refunded messages: %v", err)
+		}
+	}()
+
 	log.Println("Order service message consumers started successfully")
 	return nil
 }
@@ -80,7 +98,7 @@ func (c *Consumer) handlePaymentSuccess(ctx context.Context, delivery amqp.Deliv
 		PaymentID string  `json:"paymentId"`
 		OrderID   string  `json:"orderId"`
 		Status    string  `json:"status"`
-		Amount    float64 `json:"amount"`
+		Amount    money.Decimal `json:"amount"`
 	}
 
 	if err := json.Unmarshal(amqpMessage.Data, &paymentData); err != nil {
@@ -106,6 +124,10 @@ func (c *Consumer) handlePaymentSuccess(ctx context.Context, delivery amqp.Deliv
 
 	// Update order status to confirmed
 	if err := c.orderService.UpdateOrderStatus(ctx, orderID, domain.StatusConfirmed); err != nil {
+		if errors.Is(err, domain.ErrOrderAlreadyCancelled) {
+			log.Printf("Order %s was cancelled before payment completed, skipping confirmation", paymentData.OrderID)
+			return nil
+		}
 		log.Printf("Failed to update order status for order %s: %v", paymentData.OrderID, err)
 		return err
 	}
@@ -156,4 +178,31 @@ func (c *Consumer) handlePaymentCreated(ctx context.Context, delivery amqp.Deliv
 
     log.Printf("Successfully updated order %s with payment ID %s", paymentData.OrderID, paymentData.PaymentID)
     return nil
-}
\ No newline at end of file
+}
+// handlePaymentRefunded cancels the order of a payment refunded outside the
+// order's own flow, such as by an admin, so the two services agree
+func (c *Consumer) handlePaymentRefunded(ctx context.Context, delivery amqp.Delivery) error {
+	var amqpMessage contract.AmqpMessage
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	var paymentData message.PaymentRefundedData
+	if err := json.Unmarshal(amqpMessage.Data, &paymentData); err != nil {
+		log.Printf("Failed to unmarshal payment refunded data: %v", err)
+		return err
+	}
+
+	orderID, err := uuid.Parse(paymentData.OrderID)
+	if err != nil {
+		log.Printf("Invalid order ID format: %s", paymentData.OrderID)
+		return err
+	}
+
+	if err := c.orderService.CancelRefundedOrder(ctx, orderID); err != nil {
+		log.Printf("Failed to cancel refunded order %s: %v", paymentData.OrderID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+)
+
+// AutoCompleter periodically completes orders whose dispute window has run
+// out, so the prophet is settled even if the customer never confirms
+type AutoCompleter struct {
+	orderService inbound.OrderService
+	interval     time.Duration
+	batchSize    int
+}
+
+func NewAutoCompleter(orderService inbound.OrderService, interval time.Duration, batchSize int) *AutoCompleter {
+	return &AutoCompleter{
+		orderService: orderService,
+		interval:     interval,
+		batchSize:    batchSize,
+	}
+}
+
+// Start polls for due orders until ctx is cancelled
+func (a *AutoCompleter) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		a.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *AutoCompleter) run(ctx context.Context) {
+	for {
+		completed, err := a.orderService.AutoCompleteDueOrders(ctx, time.Now(), a.batchSize)
+		if completed > 0 {
+			log.Printf("Auto-completed %d orders", completed)
+		}
+		if err != nil {
+			log.Printf("Order auto-completion failed: %v", err)
+			return
+		}
+		// A short batch means nothing else is due right now
+		if completed < a.batchSize {
+			return
+		}
+	}
+}
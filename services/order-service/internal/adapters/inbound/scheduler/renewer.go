@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+)
+
+// Renewer periodically renews subscriptions whose paid period has run out and
+// lapses those left unpaid past their grace
+type Renewer struct {
+	subscriptionService inbound.SubscriptionService
+	interval            time.Duration
+	batchSize           int
+}
+
+func NewRenewer(subscriptionService inbound.SubscriptionService, interval time.Duration, batchSize int) *Renewer {
+	return &Renewer{
+		subscriptionService: subscriptionService,
+		interval:            interval,
+		batchSize:           batchSize,
+	}
+}
+
+// Start polls for due subscriptions until ctx is cancelled
+func (r *Renewer) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Renewer) run(ctx context.Context) {
+	processed, err := r.subscriptionService.RenewDueSubscriptions(ctx, time.Now(), r.batchSize)
+	if processed > 0 {
+		log.Printf("Processed %d due subscriptions", processed)
+	}
+	if err != nil {
+		log.Printf("Subscription renewal failed: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+)
+
+type OrderAudit struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key"`
+	OrderID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	AdminID    string    `gorm:"type:varchar(255);not null"`
+	FromStatus string    `gorm:"type:varchar(20);not null"`
+	ToStatus   string    `gorm:"type:varchar(20);not null"`
+	Reason     string    `gorm:"type:text;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (a *OrderAudit) TableName() string {
+	return "order_audits"
+}
+
+// AuditRepository implements the AuditRepository interface
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+var _ outbound.AuditRepository = (*AuditRepository)(nil)
+
+// NewAuditRepository creates a new PostgreSQL order audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, audit *domain.OrderAudit) error {
+	return conn(ctx, r.db).Create(&OrderAudit{
+		ID:         audit.ID,
+		OrderID:    audit.OrderID,
+		AdminID:    audit.AdminID,
+		FromStatus: string(audit.FromStatus),
+		ToStatus:   string(audit.ToStatus),
+		Reason:     audit.Reason,
+		CreatedAt:  audit.CreatedAt,
+	}).Error
+}
+
+func (r *AuditRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderAudit, error) {
+	var models []OrderAudit
+	err := conn(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("created_at").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*domain.OrderAudit, len(models))
+	for i, model := range models {
+		audits[i] = &domain.OrderAudit{
+			ID:         model.ID,
+			OrderID:    model.OrderID,
+			AdminID:    model.AdminID,
+			FromStatus: domain.OrderStatus(model.FromStatus),
+			ToStatus:   domain.OrderStatus(model.ToStatus),
+			Reason:     model.Reason,
+			CreatedAt:  model.CreatedAt,
+		}
+	}
+	return audits, nil
+}
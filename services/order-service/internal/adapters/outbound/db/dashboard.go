@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// CountByStatus counts the orders placed in [from, to) in each status
+func (r *Repository) CountByStatus(ctx context.Context, from, to time.Time) (map[domain.OrderStatus]int, error) {
+	var rows []struct {
+		Status string
+		Orders int
+	}
+	err := conn(ctx, r.db).Model(&Order{}).
+		Select("status, COUNT(*) AS orders").
+		Where("order_date >= ? AND order_date < ?", from, to).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.OrderStatus]int, len(rows))
+	for _, row := range rows {
+		counts[domain.OrderStatus(row.Status)] = row.Orders
+	}
+	return counts, nil
+}
+
+// SalesByProphet totals the charges of paid orders placed in [from, to) per
+// prophet and charge currency, largest first
+func (r *Repository) SalesByProphet(ctx context.Context, from, to time.Time) ([]*domain.ProphetSales, error) {
+	var rows []struct {
+		ProphetID      string
+		ChargeCurrency string
+		Amount         money.Decimal
+		Orders         int
+	}
+	err := conn(ctx, r.db).Model(&Order{}).
+		Select("prophet_id, charge_currency, SUM(charge_amount) AS amount, COUNT(*) AS orders").
+		Where("order_date >= ? AND order_date < ? AND status IN ?", from, to, domain.PaidStatuses).
+		Group("prophet_id, charge_currency").
+		Order("amount DESC, prophet_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sales := make([]*domain.ProphetSales, len(rows))
+	for i, row := range rows {
+		sales[i] = &domain.ProphetSales{
+			ProphetID: row.ProphetID,
+			Currency:  row.ChargeCurrency,
+			Amount:    row.Amount,
+			Orders:    row.Orders,
+		}
+	}
+	return sales, nil
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+)
+
+type OutboxMessage struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key"`
+	RoutingKey  string     `gorm:"type:varchar(255);not null"`
+	OwnerID     string     `gorm:"type:varchar(255)"`
+	Payload     []byte     `gorm:"type:bytea;not null"`
+	Attempts    int        `gorm:"default:0;not null"`
+	LastError   string     `gorm:"type:text"`
+	CreatedAt   time.Time  `gorm:"not null;index"`
+	PublishedAt *time.Time `gorm:"default:null;index"`
+	FailedAt    *time.Time `gorm:"default:null;index"`
+}
+
+func (m *OutboxMessage) TableName() string {
+	return "order_outbox"
+}
+
+// OutboxRepository implements the OutboxRepository interface
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+var _ outbound.OutboxRepository = (*OutboxRepository)(nil)
+
+// NewOutboxRepository creates a new PostgreSQL outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// Enqueue stores a message, joining the transaction carried by ctx if any
+func (r *OutboxRepository) Enqueue(ctx context.Context, message *domain.OutboxMessage) error {
+	return conn(ctx, r.db).Create(toOutboxModel(message)).Error
+}
+
+// ProcessPending hands unpublished messages to fn oldest first. Rows are locked
+// with SKIP LOCKED so several relays can drain the same table safely.
+func (r *OutboxRepository) ProcessPending(ctx context.Context, limit int, maxAttempts int, fn func(ctx context.Context, message *domain.OutboxMessage) error) (int, error) {
+	published := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var models []OutboxMessage
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND failed_at IS NULL").
+			Order("created_at").
+			Limit(limit).
+			Find(&models)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		for i := range models {
+			model := &models[i]
+			message := toOutboxEntity(model)
+			if err := fn(ctx, message); err != nil {
+				parked := message.RecordFailure(err, maxAttempts, time.Now())
+				if err := tx.Model(model).Updates(map[string]interface{}{
+					"attempts":   message.Attempts,
+					"last_error": message.LastError,
+					"failed_at":  message.FailedAt,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to record outbox message %s failure: %w", model.ID, err)
+				}
+				if !parked {
+					// Keep the message pending; later ones wait so events stay in order
+					return nil
+				}
+
+				// Parked, so it stops holding back the ones after it
+				log.Printf("Parking outbox message %s (%s) after %d rejections: %v", model.ID, model.RoutingKey, message.Attempts, err)
+				continue
+			}
+
+			now := time.Now()
+			if err := tx.Model(model).Updates(map[string]interface{}{
+				"published_at": now,
+				"last_error":   "",
+			}).Error; err != nil {
+				return fmt.Errorf("failed to mark outbox message %s as published: %w", model.ID, err)
+			}
+			published++
+		}
+
+		return nil
+	})
+
+	return published, err
+}
+
+// ListFailed returns up to limit parked messages, oldest first
+func (r *OutboxRepository) ListFailed(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	var models []OutboxMessage
+	if err := r.db.WithContext(ctx).
+		Where("published_at IS NULL AND failed_at IS NOT NULL").
+		Order("created_at").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	messages := make([]*domain.OutboxMessage, len(models))
+	for i := range models {
+		messages[i] = toOutboxEntity(&models[i])
+	}
+	return messages, nil
+}
+
+// ReplayFailed puts parked messages back in the queue with their attempts
+// reset. They keep their creation time, so the relay sends them before any
+// newer message.
+func (r *OutboxRepository) ReplayFailed(ctx context.Context, ids []uuid.UUID) (int, error) {
+	query := r.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("published_at IS NULL AND failed_at IS NOT NULL")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Updates(map[string]interface{}{
+		"attempts":  0,
+		"failed_at": nil,
+	})
+	return int(result.RowsAffected), result.Error
+}
+
+func toOutboxModel(message *domain.OutboxMessage) *OutboxMessage {
+	return &OutboxMessage{
+		ID:          message.ID,
+		RoutingKey:  message.RoutingKey,
+		OwnerID:     message.OwnerID,
+		Payload:     message.Payload,
+		Attempts:    message.Attempts,
+		LastError:   message.LastError,
+		CreatedAt:   message.CreatedAt,
+		PublishedAt: message.PublishedAt,
+		FailedAt:    message.FailedAt,
+	}
+}
+
+func toOutboxEntity(model *OutboxMessage) *domain.OutboxMessage {
+	return &domain.OutboxMessage{
+		ID:          model.ID,
+		RoutingKey:  model.RoutingKey,
+		OwnerID:     model.OwnerID,
+		Payload:     model.Payload,
+		Attempts:    model.Attempts,
+		LastError:   model.LastError,
+		CreatedAt:   model.CreatedAt,
+		PublishedAt: model.PublishedAt,
+		FailedAt:    model.FailedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+type Promotion struct {
+	ID             uuid.UUID     `gorm:"type:uuid;primary_key"`
+	Code           *string       `gorm:"type:varchar(64);uniqueIndex"`
+	Description    string        `gorm:"type:text"`
+	Kind           string        `gorm:"type:varchar(20);not null"`
+	Value          money.Decimal `gorm:"type:numeric(20,8);not null"`
+	Currency       string        `gorm:"type:varchar(3)"`
+	ProphetID      string        `gorm:"type:varchar(255);index"`
+	FirstOrderOnly bool          `gorm:"default:false;not null"`
+	MaxRedemptions int           `gorm:"default:0;not null"`
+	MaxPerCustomer int           `gorm:"default:0;not null"`
+	StartsAt       time.Time     `gorm:"not null"`
+	ExpiresAt      *time.Time    `gorm:"default:null"`
+	Active         bool          `gorm:"default:true;not null;index"`
+	CreatedBy      string        `gorm:"type:varchar(255)"`
+	CreatedAt      time.Time     `gorm:"not null"`
+	// Redemptions is counted from promotion_redemptions when reading
+	Redemptions int `gorm:"->;-:migration"`
+}
+
+func (p *Promotion) TableName() string {
+	return "promotions"
+}
+
+type PromotionRedemption struct {
+	OrderID     uuid.UUID     `gorm:"type:uuid;primary_key"`
+	PromotionID uuid.UUID     `gorm:"type:uuid;not null;index:idx_promotion_redemptions_customer"`
+	CustomerID  string        `gorm:"type:varchar(255);not null;index:idx_promotion_redemptions_customer"`
+	Discount    money.Decimal `gorm:"type:numeric(20,8);not null"`
+	Currency    string        `gorm:"type:varchar(3)"`
+	RedeemedAt  time.Time     `gorm:"not null"`
+}
+
+func (r *PromotionRedemption) TableName() string {
+	return "promotion_redemptions"
+}
+
+// withRedemptions selects promotions along with how often each was redeemed
+const withRedemptions = "promotions.*, (SELECT COUNT(*) FROM promotion_redemptions r WHERE r.promotion_id = promotions.id) AS redemptions"
+
+// PromotionRepository implements the PromotionRepository interface
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+var _ outbound.PromotionRepository = (*PromotionRepository)(nil)
+
+// NewPromotionRepository creates a new PostgreSQL promotion repository
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, promotion *domain.Promotion) error {
+	return conn(ctx, r.db).Create(toPromotionModel(promotion)).Error
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, promotionID uuid.UUID) (*domain.Promotion, error) {
+	return r.first(conn(ctx, r.db).Select(withRedemptions).Where("id = ?", promotionID))
+}
+
+// GetByIDForUpdate locks the promotion row before counting its redemptions,
+// so the count includes every redemption committed before the lock was taken
+func (r *PromotionRepository) GetByIDForUpdate(ctx context.Context, promotionID uuid.UUID) (*domain.Promotion, error) {
+	db := conn(ctx, r.db)
+	promotion, err := r.first(db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", promotionID))
+	if err != nil {
+		return nil, err
+	}
+
+	var redemptions int64
+	if err := db.Model(&PromotionRedemption{}).Where("promotion_id = ?", promotionID).Count(&redemptions).Error; err != nil {
+		return nil, err
+	}
+	promotion.Redemptions = int(redemptions)
+	return promotion, nil
+}
+
+func (r *PromotionRepository) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	return r.first(conn(ctx, r.db).Select(withRedemptions).Where("code = ?", domain.NormalizeCouponCode(code)))
+}
+
+func (r *PromotionRepository) List(ctx context.Context, prophetID string) ([]*domain.Promotion, error) {
+	query := conn(ctx, r.db).Select(withRedemptions)
+	if prophetID != "" {
+		query = query.Where("prophet_id = ?", prophetID)
+	}
+	return r.list(query)
+}
+
+func (r *PromotionRepository) ListAutomatic(ctx context.Context, t time.Time) ([]*domain.Promotion, error) {
+	return r.list(conn(ctx, r.db).
+		Select(withRedemptions).
+		Where("code IS NULL AND active = ? AND starts_at <= ? AND (expires_at IS NULL OR expires_at > ?)", true, t, t))
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, promotion *domain.Promotion) error {
+	return conn(ctx, r.db).Save(toPromotionModel(promotion)).Error
+}
+
+func (r *PromotionRepository) CountCustomerRedemptions(ctx context.Context, promotionID uuid.UUID, customerID string) (int, error) {
+	var count int64
+	result := conn(ctx, r.db).
+		Model(&PromotionRedemption{}).
+		Where("promotion_id = ? AND customer_id = ?", promotionID, customerID).
+		Count(&count)
+	return int(count), result.Error
+}
+
+func (r *PromotionRepository) AddRedemption(ctx context.Context, redemption *domain.PromotionRedemption) error {
+	return conn(ctx, r.db).Create(&PromotionRedemption{
+		OrderID:     redemption.OrderID,
+		PromotionID: redemption.PromotionID,
+		CustomerID:  redemption.CustomerID,
+		Discount:    redemption.Discount,
+		Currency:    redemption.Currency,
+		RedeemedAt:  redemption.RedeemedAt,
+	}).Error
+}
+
+func (r *PromotionRepository) RemoveRedemption(ctx context.Context, orderID uuid.UUID) error {
+	return conn(ctx, r.db).Delete(&PromotionRedemption{}, "order_id = ?", orderID).Error
+}
+
+func (r *PromotionRepository) first(query *gorm.DB) (*domain.Promotion, error) {
+	var model Promotion
+	if err := query.First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return toPromotionEntity(&model), nil
+}
+
+func (r *PromotionRepository) list(query *gorm.DB) ([]*domain.Promotion, error) {
+	var models []Promotion
+	if err := query.Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	promotions := make([]*domain.Promotion, len(models))
+	for i := range models {
+		promotions[i] = toPromotionEntity(&models[i])
+	}
+	return promotions, nil
+}
+
+func toPromotionModel(promotion *domain.Promotion) *Promotion {
+	model := &Promotion{
+		ID:             promotion.ID,
+		Description:    promotion.Description,
+		Kind:           string(promotion.Kind),
+		Value:          promotion.Value,
+		Currency:       promotion.Currency,
+		ProphetID:      promotion.ProphetID,
+		FirstOrderOnly: promotion.FirstOrderOnly,
+		MaxRedemptions: promotion.MaxRedemptions,
+		MaxPerCustomer: promotion.MaxPerCustomer,
+		StartsAt:       promotion.StartsAt,
+		ExpiresAt:      promotion.ExpiresAt,
+		Active:         promotion.Active,
+		CreatedBy:      promotion.CreatedBy,
+		CreatedAt:      promotion.CreatedAt,
+	}
+	// Automatic promotions have no code; NULL keeps them out of the unique index
+	if promotion.Code != "" {
+		code := promotion.Code
+		model.Code = &code
+	}
+	return model
+}
+
+func toPromotionEntity(model *Promotion) *domain.Promotion {
+	promotion := &domain.Promotion{
+		ID:             model.ID,
+		Description:    model.Description,
+		Kind:           domain.PromotionKind(model.Kind),
+		Value:          model.Value,
+		Currency:       model.Currency,
+		ProphetID:      model.ProphetID,
+		FirstOrderOnly: model.FirstOrderOnly,
+		MaxRedemptions: model.MaxRedemptions,
+		MaxPerCustomer: model.MaxPerCustomer,
+		Redemptions:    model.Redemptions,
+		StartsAt:       model.StartsAt,
+		ExpiresAt:      model.ExpiresAt,
+		Active:         model.Active,
+		CreatedBy:      model.CreatedBy,
+		CreatedAt:      model.CreatedAt,
+	}
+	if model.Code != nil {
+		promotion.Code = *model.Code
+	}
+	return promotion
+}
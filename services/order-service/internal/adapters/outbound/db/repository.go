@@ -10,7 +10,10 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderStatus string
@@ -28,11 +31,45 @@ type Order struct {
 	PaymentID   uuid.UUID   `gorm:"type:uuid"` 
 	RoomID      string      `gorm:"type:varchar(255)"`
 	Status      OrderStatus `gorm:"type:varchar(20);not null"`
-	OrderDate   time.Time   `gorm:"not null"`
+	OrderDate   time.Time   `gorm:"not null;index"`
 	IsCustomerCompleted  bool        `gorm:"default:false;not null"`
 	IsProphetCompleted   bool        `gorm:"default:false;not null"`
 	CustomerCompletedAt  *time.Time  `gorm:"default:null"`
 	ProphetCompletedAt   *time.Time  `gorm:"default:null"`
+	CancelledBy          string      `gorm:"type:varchar(20)"`
+	CancelReason         string      `gorm:"type:text"`
+	CancelledAt          *time.Time  `gorm:"default:null"`
+	CourseName           string      `gorm:"type:varchar(255)"`
+	CourseType           string      `gorm:"type:varchar(50)"`
+	ProphetID            string      `gorm:"type:varchar(255);index"`
+	Price                money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	Currency             string      `gorm:"type:varchar(3)"`
+	PromotionID          *uuid.UUID  `gorm:"type:uuid;default:null"`
+	CouponCode           string      `gorm:"type:varchar(64)"`
+	DiscountAmount       money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	ChargeAmount         money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	ChargeCurrency       string      `gorm:"type:varchar(3)"`
+	ExchangeRate         money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	RateSource           string      `gorm:"type:varchar(100)"`
+	RateAsOf             *time.Time  `gorm:"default:null"`
+	DurationMinutes      int         `gorm:"not null;default:0"`
+	SubscriptionID       *uuid.UUID  `gorm:"type:uuid;index"`
+	SubscriptionPeriod   int         `gorm:"not null;default:0"`
+	Sessions             int         `gorm:"not null;default:1"`
+	SessionsBooked       int         `gorm:"not null;default:1"`
+	SessionsCompleted    int         `gorm:"not null;default:0"`
+	BookingID            string      `gorm:"type:varchar(255)"`
+	SessionStartAt       *time.Time  `gorm:"default:null"`
+	SessionEndAt         *time.Time  `gorm:"default:null"`
+	SessionTimezone      string      `gorm:"type:varchar(64)"`
+	AutoCompleteAt       *time.Time  `gorm:"default:null;index"`
+	AutoCompleted        bool        `gorm:"default:false;not null"`
+	DisputeReason        string      `gorm:"type:text"`
+	DisputedAt           *time.Time  `gorm:"default:null"`
+	DisputeResolution    string      `gorm:"type:varchar(20)"`
+	DisputeResolvedBy    string      `gorm:"type:varchar(255)"`
+	DisputeNote          string      `gorm:"type:text"`
+	DisputeResolvedAt    *time.Time  `gorm:"default:null"`
 }
 
 func (o *Order) TableName() string {
@@ -51,34 +88,94 @@ func NewRepository(db *gorm.DB) *Repository {
 	}
 }
 
+// WithinTransaction runs fn inside a database transaction. Nested calls reuse
+// the transaction already carried by ctx.
+func (r *Repository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, or the plain connection, for
+// stores outside this package that take part in the same transactions
+func (r *Repository) Conn(ctx context.Context) *gorm.DB {
+	return conn(ctx, r.db)
+}
+
 // Create saves a new order to the database
 func (r *Repository) Create(ctx context.Context, order *domain.Order) error {
 	orderModel := toOrderModel(order)
-	result := r.db.WithContext(ctx).Create(orderModel)
+	// Write every column so zero values such as an unbooked subscription
+	// renewal's SessionsBooked are not replaced by the column defaults
+	result := conn(ctx, r.db).Select("*").Create(orderModel)
 	return result.Error
 }
-// Get all orders
-func (r *Repository) GetAll(ctx context.Context) ([]*domain.Order, error) {
+// orderCursor is the position of the last order of a page
+type orderCursor struct {
+	OrderDate time.Time `json:"t"`
+	OrderID   uuid.UUID `json:"id"`
+}
+
+// GetAll pages through all orders, or those in status if it is set, newest
+// first
+func (r *Repository) GetAll(ctx context.Context, status domain.OrderStatus, page contract.PageRequest) (contract.Page[*domain.Order], error) {
+	query := conn(ctx, r.db).Order("order_date DESC, order_id DESC").Limit(page.Limit + 1)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if page.Cursor != "" {
+		var position orderCursor
+		if err := contract.DecodeCursor(page.Cursor, &position); err != nil {
+			return contract.Page[*domain.Order]{}, err
+		}
+		query = query.Where("(order_date, order_id) < (?, ?)", position.OrderDate, position.OrderID)
+	}
+
 	var orderModels []Order
-	result := r.db.WithContext(ctx).Find(&orderModels)
+	result := query.Find(&orderModels)
 	if result.Error != nil {
-		return nil, result.Error
+		return contract.Page[*domain.Order]{}, result.Error
 	}
 	orders := make([]*domain.Order, len(orderModels))
 	for i, model := range orderModels {
 		orders[i] = toOrderEntity(&model)
 	
 	}
-	return orders, nil
+	return contract.NewPage(orders, page.Limit, func(order *domain.Order) any {
+		return orderCursor{OrderDate: order.OrderDate, OrderID: order.OrderID}
+	}), nil
 }
 // GetByID retrieves an order by its ID
 func (r *Repository) GetByID(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
 	var orderModel Order
-	result := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&orderModel)
+	result := conn(ctx, r.db).Where("order_id = ?", orderID).First(&orderModel)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, result.Error
+	}
+
+	return toOrderEntity(&orderModel), nil
+}
+
+// GetByIDForUpdate retrieves an order and locks its row until the transaction
+// carried by ctx ends
+func (r *Repository) GetByIDForUpdate(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
+	var orderModel Order
+	result := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		First(&orderModel)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, domain.ErrOrderNotFound
 		}
 		return nil, result.Error
 	}
@@ -86,10 +183,64 @@ func (r *Repository) GetByID(ctx context.Context, orderID uuid.UUID) (*domain.Or
 	return toOrderEntity(&orderModel), nil
 }
 
+// BackfillAutoCompleteAt gives confirmed orders the prophet completed without
+// a deadline, such as those completed before auto-completion existed, the
+// deadline disputeWindow after the prophet completed them
+func (r *Repository) BackfillAutoCompleteAt(ctx context.Context, disputeWindow time.Duration) (int64, error) {
+	result := conn(ctx, r.db).
+		Model(&Order{}).
+		Where("status = ? AND is_prophet_completed = ? AND auto_complete_at IS NULL AND prophet_completed_at IS NOT NULL", domain.StatusConfirmed, true).
+		Update("auto_complete_at", gorm.Expr("prophet_completed_at + ? * INTERVAL '1 second'", int64(disputeWindow/time.Second)))
+
+	return result.RowsAffected, result.Error
+}
+
+// GetDueForAutoCompletion returns confirmed orders whose dispute window ended
+// at or before now, oldest deadline first
+func (r *Repository) GetDueForAutoCompletion(ctx context.Context, now time.Time, limit int) ([]*domain.Order, error) {
+	var orderModels []Order
+	result := conn(ctx, r.db).
+		Where("status = ? AND is_prophet_completed = ? AND auto_complete_at <= ?", domain.StatusConfirmed, true, now).
+		Order("auto_complete_at").
+		Limit(limit).
+		Find(&orderModels)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	orders := make([]*domain.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = toOrderEntity(&model)
+	}
+
+	return orders, nil
+}
+
+// GetBySubscriptionID retrieves the orders billing a subscription's periods,
+// first period first
+func (r *Repository) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*domain.Order, error) {
+	var orderModels []Order
+	result := conn(ctx, r.db).
+		Where("subscription_id = ?", subscriptionID).
+		Order("subscription_period").
+		Find(&orderModels)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	orders := make([]*domain.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = toOrderEntity(&model)
+	}
+	return orders, nil
+}
+
 // GetByCustomerID retrieves all orders for a specific customer
 func (r *Repository) GetByCustomerID(ctx context.Context, customerID string) ([]*domain.Order, error) {
 	var orderModels []Order
-	result := r.db.WithContext(ctx).Where("customer_id = ?", customerID).Find(&orderModels)
+	result := conn(ctx, r.db).Where("customer_id = ?", customerID).Find(&orderModels)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -105,7 +256,7 @@ func (r *Repository) GetByCustomerID(ctx context.Context, customerID string) ([]
 
 func (r *Repository) GetByRoomID(ctx context.Context, roomID string) ([]*domain.Order, error) {
 	var orderModels []Order
-	result := r.db.WithContext(ctx).Where("room_id = ?", roomID).Find(&orderModels)
+	result := conn(ctx, r.db).Where("room_id = ?", roomID).Find(&orderModels)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -122,24 +273,25 @@ func (r *Repository) GetByRoomID(ctx context.Context, roomID string) ([]*domain.
 // Update saves changes to an existing order
 func (r *Repository) Update(ctx context.Context, order *domain.Order) error {
 	orderModel := toOrderModel(order)
-	result := r.db.WithContext(ctx).Save(orderModel)
+	result := conn(ctx, r.db).Save(orderModel)
 	return result.Error
 }
 
 // Delete removes an order from the database
 func (r *Repository) Delete(ctx context.Context, orderID uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&Order{}, "order_id = ?", orderID)
+	result := conn(ctx, r.db).Delete(&Order{}, "order_id = ?", orderID)
 	return result.Error
 }
 
-// AutoMigrate runs database migrations for the Order table
+// AutoMigrate runs database migrations for the Order, outbox, promotion,
+// subscription and audit tables
 func (r *Repository) AutoMigrate() error {
-	if err := r.db.AutoMigrate(&Order{}); err != nil {
+	if err := r.db.AutoMigrate(&Order{}, &OutboxMessage{}, &Promotion{}, &PromotionRedemption{}, &Subscription{}, &OrderAudit{}); err != nil {
 		log.Printf("Migration failed: %v", err)
 		return err
 	}
 
-	log.Printf("Orders table migrated successfully")
+	log.Printf("Orders, outbox, promotion, subscription and audit tables migrated successfully")
 	return nil
 }
 
@@ -156,6 +308,40 @@ func toOrderModel(order *domain.Order) *Order {
 		IsProphetCompleted:  order.IsProphetCompleted,
 		CustomerCompletedAt: order.CustomerCompletedAt,
 		ProphetCompletedAt:  order.ProphetCompletedAt,
+		CancelledBy:         string(order.CancelledBy),
+		CancelReason:        order.CancelReason,
+		CancelledAt:         order.CancelledAt,
+		CourseName:          order.CourseName,
+		CourseType:          order.CourseType,
+		ProphetID:           order.ProphetID,
+		Price:               order.Price,
+		Currency:            order.Currency,
+		PromotionID:         order.PromotionID,
+		CouponCode:          order.CouponCode,
+		DiscountAmount:      order.DiscountAmount,
+		ChargeAmount:        order.ChargeAmount,
+		ChargeCurrency:      order.ChargeCurrency,
+		ExchangeRate:        order.ExchangeRate,
+		RateSource:          order.RateSource,
+		RateAsOf:            order.RateAsOf,
+		DurationMinutes:     order.DurationMinutes,
+		SubscriptionID:      order.SubscriptionID,
+		SubscriptionPeriod:  order.SubscriptionPeriod,
+		Sessions:            order.Sessions,
+		SessionsBooked:      order.SessionsBooked,
+		SessionsCompleted:   order.SessionsCompleted,
+		BookingID:           order.BookingID,
+		SessionStartAt:      order.SessionStartAt,
+		SessionEndAt:        order.SessionEndAt,
+		SessionTimezone:     order.SessionTimezone,
+		AutoCompleteAt:      order.AutoCompleteAt,
+		AutoCompleted:       order.AutoCompleted,
+		DisputeReason:       order.DisputeReason,
+		DisputedAt:          order.DisputedAt,
+		DisputeResolution:   string(order.DisputeResolution),
+		DisputeResolvedBy:   order.DisputeResolvedBy,
+		DisputeNote:         order.DisputeNote,
+		DisputeResolvedAt:   order.DisputeResolvedAt,
 	}
 
 	if order.PaymentID != nil {
@@ -177,11 +363,58 @@ func toOrderEntity(model *Order) *domain.Order {
 		IsProphetCompleted:  model.IsProphetCompleted,
 		CustomerCompletedAt: model.CustomerCompletedAt,
 		ProphetCompletedAt:  model.ProphetCompletedAt,
+		CancelledBy:         domain.CancelledBy(model.CancelledBy),
+		CancelReason:        model.CancelReason,
+		CancelledAt:         model.CancelledAt,
+		CourseName:          model.CourseName,
+		CourseType:          model.CourseType,
+		ProphetID:           model.ProphetID,
+		Price:               model.Price,
+		Currency:            model.Currency,
+		PromotionID:         model.PromotionID,
+		CouponCode:          model.CouponCode,
+		DiscountAmount:      model.DiscountAmount,
+		ChargeAmount:        model.ChargeAmount,
+		ChargeCurrency:      model.ChargeCurrency,
+		ExchangeRate:        model.ExchangeRate,
+		RateSource:          model.RateSource,
+		RateAsOf:            model.RateAsOf,
+		DurationMinutes:     model.DurationMinutes,
+		SubscriptionID:      model.SubscriptionID,
+		SubscriptionPeriod:  model.SubscriptionPeriod,
+		Sessions:            model.Sessions,
+		SessionsBooked:      model.SessionsBooked,
+		SessionsCompleted:   model.SessionsCompleted,
+		BookingID:           model.BookingID,
+		SessionStartAt:      model.SessionStartAt,
+		SessionEndAt:        model.SessionEndAt,
+		SessionTimezone:     model.SessionTimezone,
+		AutoCompleteAt:      model.AutoCompleteAt,
+		AutoCompleted:       model.AutoCompleted,
+		DisputeReason:       model.DisputeReason,
+		DisputedAt:          model.DisputedAt,
+		DisputeResolution:   domain.DisputeResolution(model.DisputeResolution),
+		DisputeResolvedBy:   model.DisputeResolvedBy,
+		DisputeNote:         model.DisputeNote,
+		DisputeResolvedAt:   model.DisputeResolvedAt,
 	}
 
 	if model.PaymentID != (uuid.UUID{}) {
 		order.PaymentID = &model.PaymentID
 	}
 
+	// Orders placed before conversion was recorded were charged their price
+	if order.ChargeCurrency == "" {
+		order.ChargeAmount = order.Price
+		order.ChargeCurrency = order.Currency
+		order.ExchangeRate = money.NewFromInt(1)
+		order.RateSource = domain.IdentityRateSource
+	}
+
+	// Orders placed before bundles were single sessions
+	if order.Status == domain.StatusCompleted && order.SessionsCompleted == 0 {
+		order.SessionsCompleted = order.SessionCount()
+	}
+
 	return order
 }
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+type Subscription struct {
+	ID                 uuid.UUID     `gorm:"type:uuid;primary_key"`
+	CustomerID         string        `gorm:"type:varchar(255);not null;index"`
+	CourseID           string        `gorm:"type:varchar(255);not null"`
+	CourseName         string        `gorm:"type:varchar(255)"`
+	CourseType         string        `gorm:"type:varchar(255)"`
+	ProphetID          string        `gorm:"type:varchar(255);not null;index"`
+	RoomID             string        `gorm:"type:varchar(255)"`
+	Status             string        `gorm:"type:varchar(20);not null;index"`
+	Price              money.Decimal `gorm:"type:numeric(20,8);not null"`
+	Currency           string        `gorm:"type:varchar(3);not null"`
+	ChargeCurrency     string        `gorm:"type:varchar(3);not null"`
+	Periods            int           `gorm:"not null;default:0"`
+	CurrentPeriodStart *time.Time    `gorm:"default:null"`
+	CurrentPeriodEnd   *time.Time    `gorm:"default:null;index"`
+	PendingOrderID     *uuid.UUID    `gorm:"type:uuid"`
+	PendingPaymentID   string        `gorm:"type:varchar(255)"`
+	GraceEndsAt        *time.Time    `gorm:"default:null;index"`
+	CancelAtPeriodEnd  bool          `gorm:"default:false;not null"`
+	CancelledAt        *time.Time    `gorm:"default:null"`
+	EndedAt            *time.Time    `gorm:"default:null"`
+	CreatedAt          time.Time     `gorm:"not null"`
+}
+
+func (s *Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// SubscriptionRepository implements the SubscriptionRepository interface
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+var _ outbound.SubscriptionRepository = (*SubscriptionRepository)(nil)
+
+// NewSubscriptionRepository creates a new PostgreSQL subscription repository
+func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		db: db,
+	}
+}
+
+func (r *SubscriptionRepository) Create(ctx context.Context, subscription *domain.Subscription) error {
+	return conn(ctx, r.db).Create(toSubscriptionModel(subscription)).Error
+}
+
+func (r *SubscriptionRepository) GetByID(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	return r.first(conn(ctx, r.db).Where("id = ?", subscriptionID))
+}
+
+func (r *SubscriptionRepository) GetByIDForUpdate(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	return r.first(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", subscriptionID))
+}
+
+func (r *SubscriptionRepository) ListByCustomer(ctx context.Context, customerID string) ([]*domain.Subscription, error) {
+	return r.list(conn(ctx, r.db).Where("customer_id = ?", customerID).Order("created_at DESC"))
+}
+
+func (r *SubscriptionRepository) ListByProphet(ctx context.Context, prophetID string) ([]*domain.Subscription, error) {
+	return r.list(conn(ctx, r.db).Where("prophet_id = ?", prophetID).Order("created_at DESC"))
+}
+
+func (r *SubscriptionRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error) {
+	return r.list(conn(ctx, r.db).
+		Where("(status = ? AND current_period_end <= ?) OR (status IN ? AND grace_ends_at <= ?) OR (status = ? AND pending_payment_id = '')",
+			domain.SubscriptionActive, now,
+			[]domain.SubscriptionStatus{domain.SubscriptionPending, domain.SubscriptionPastDue}, now,
+			domain.SubscriptionPastDue).
+		Order("created_at").
+		Limit(limit))
+}
+
+func (r *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription) error {
+	return conn(ctx, r.db).Save(toSubscriptionModel(subscription)).Error
+}
+
+func (r *SubscriptionRepository) first(query *gorm.DB) (*domain.Subscription, error) {
+	var model Subscription
+	if err := query.First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return toSubscriptionEntity(&model), nil
+}
+
+func (r *SubscriptionRepository) list(query *gorm.DB) ([]*domain.Subscription, error) {
+	var models []Subscription
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*domain.Subscription, len(models))
+	for i := range models {
+		subscriptions[i] = toSubscriptionEntity(&models[i])
+	}
+	return subscriptions, nil
+}
+
+func toSubscriptionModel(subscription *domain.Subscription) *Subscription {
+	return &Subscription{
+		ID:                 subscription.ID,
+		CustomerID:         subscription.CustomerID,
+		CourseID:           subscription.CourseID,
+		CourseName:         subscription.CourseName,
+		CourseType:         subscription.CourseType,
+		ProphetID:          subscription.ProphetID,
+		RoomID:             subscription.RoomID,
+		Status:             string(subscription.Status),
+		Price:              subscription.Price,
+		Currency:           subscription.Currency,
+		ChargeCurrency:     subscription.ChargeCurrency,
+		Periods:            subscription.Periods,
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		PendingOrderID:     subscription.PendingOrderID,
+		PendingPaymentID:   subscription.PendingPaymentID,
+		GraceEndsAt:        subscription.GraceEndsAt,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		CancelledAt:        subscription.CancelledAt,
+		EndedAt:            subscription.EndedAt,
+		CreatedAt:          subscription.CreatedAt,
+	}
+}
+
+func toSubscriptionEntity(model *Subscription) *domain.Subscription {
+	return &domain.Subscription{
+		ID:                 model.ID,
+		CustomerID:         model.CustomerID,
+		CourseID:           model.CourseID,
+		CourseName:         model.CourseName,
+		CourseType:         model.CourseType,
+		ProphetID:          model.ProphetID,
+		RoomID:             model.RoomID,
+		Status:             domain.SubscriptionStatus(model.Status),
+		Price:              model.Price,
+		Currency:           model.Currency,
+		ChargeCurrency:     model.ChargeCurrency,
+		Periods:            model.Periods,
+		CurrentPeriodStart: model.CurrentPeriodStart,
+		CurrentPeriodEnd:   model.CurrentPeriodEnd,
+		PendingOrderID:     model.PendingOrderID,
+		PendingPaymentID:   model.PendingPaymentID,
+		GraceEndsAt:        model.GraceEndsAt,
+		CancelAtPeriodEnd:  model.CancelAtPeriodEnd,
+		CancelledAt:        model.CancelledAt,
+		EndedAt:            model.EndedAt,
+		CreatedAt:          model.CreatedAt,
+	}
+}
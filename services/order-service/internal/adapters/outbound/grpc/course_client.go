@@ -2,12 +2,20 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/jwt"
+	"github.com/wnmay/horo/shared/money"
 	pb "github.com/wnmay/horo/shared/proto/course"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type CourseClient struct {
@@ -20,6 +28,7 @@ func NewCourseClient(courseServiceAddr string) (*CourseClient, error) {
 	conn, err := grpc.NewClient(
 		courseServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(jwt.UnaryClientService(jwt.ServiceCourse)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to course service: %w", err)
@@ -55,7 +64,7 @@ func (c *CourseClient) GetCourseByID(ctx context.Context, courseID string) (*pb.
 
 	if resp.Course == nil {
 		log.Printf("GetCourseByID response has nil course")
-		return nil, fmt.Errorf("course not found")
+		return nil, domain.ErrCourseUnavailable
 	}
 
 	log.Printf("Successfully fetched course: ID=%s, Name=%s, Price=%.2f",
@@ -64,6 +73,81 @@ func (c *CourseClient) GetCourseByID(ctx context.Context, courseID string) (*pb.
 	return resp.Course, nil
 }
 
+// GetProphetID returns the ID of the prophet who owns the course
+func (c *CourseClient) GetProphetID(ctx context.Context, courseID string) (string, error) {
+	course, err := c.GetCourseByID(ctx, courseID)
+	if err != nil {
+		return "", err
+	}
+	return course.ProphetId, nil
+}
+
+// GetCourseSnapshot fetches the course details an order captures when it is placed
+func (c *CourseClient) GetCourseSnapshot(ctx context.Context, courseID string) (*domain.CourseSnapshot, error) {
+	course, err := c.GetCourseByID(ctx, courseID)
+	if err != nil {
+		if status.Code(errors.Unwrap(err)) == codes.NotFound {
+			return nil, domain.ErrCourseUnavailable
+		}
+		return nil, err
+	}
+	price, err := money.NewFromFloat(course.Price)
+	if err != nil {
+		return nil, fmt.Errorf("invalid course price: %w", err)
+	}
+	return &domain.CourseSnapshot{
+		CourseID:        course.Id,
+		CourseName:      course.Coursename,
+		CourseType:      course.Coursetype,
+		ProphetID:       course.ProphetId,
+		Price:           price,
+		Currency:        course.Currency,
+		DurationMinutes: int(course.Duration),
+		Sessions:        int(course.Sessions),
+	}, nil
+}
+
+// ReserveSlot books the order's current session to start at startAt
+func (c *CourseClient) ReserveSlot(ctx context.Context, order *domain.Order, startAt time.Time) (*domain.SessionBooking, error) {
+	resp, err := c.client.ReserveSlot(ctx, &pb.ReserveSlotRequest{
+		CourseId:   order.CourseID,
+		CustomerId: order.CustomerID,
+		OrderId:    order.OrderID.String(),
+		StartTime:  timestamppb.New(startAt),
+		Session:    int32(order.CurrentSession()),
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			return nil, domain.ErrSlotTaken
+		case codes.FailedPrecondition:
+			return nil, fmt.Errorf("%w: %s", domain.ErrSlotUnavailable, status.Convert(err).Message())
+		case codes.NotFound:
+			return nil, domain.ErrCourseUnavailable
+		}
+		return nil, fmt.Errorf("failed to reserve slot: %w", err)
+	}
+
+	booking := resp.GetBooking()
+	if booking == nil {
+		return nil, fmt.Errorf("nil booking from course service")
+	}
+	return &domain.SessionBooking{
+		BookingID: booking.GetId(),
+		StartAt:   booking.GetStartTime().AsTime(),
+		EndAt:     booking.GetEndTime().AsTime(),
+		Timezone:  booking.GetTimezone(),
+	}, nil
+}
+
+// ReleaseSlot frees a previously reserved session
+func (c *CourseClient) ReleaseSlot(ctx context.Context, bookingID string) error {
+	if _, err := c.client.ReleaseSlot(ctx, &pb.ReleaseSlotRequest{BookingId: bookingID}); err != nil {
+		return fmt.Errorf("failed to release slot: %w", err)
+	}
+	return nil
+}
+
 // Close closes the gRPC connection
 func (c *CourseClient) Close() error {
 	if c.conn != nil {
@@ -3,26 +3,106 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
 	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/jwt"
+	"github.com/wnmay/horo/shared/money"
+	pb "github.com/wnmay/horo/shared/proto/payment"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
-// PaymentClient implements the PaymentService interface
+// PaymentClient implements the PaymentService interface over gRPC
 type PaymentClient struct {
-	// This could be a gRPC client to payment service
-	// For now, we'll simulate it
+	client pb.PaymentServiceClient
+	conn   *grpc.ClientConn
 }
 
-func NewPaymentClient() outbound.PaymentService {
-	return &PaymentClient{}
+var _ outbound.PaymentService = (*PaymentClient)(nil)
+
+// NewPaymentClient creates a new payment service gRPC client
+func NewPaymentClient(paymentServiceAddr string) (*PaymentClient, error) {
+	conn, err := grpc.NewClient(
+		paymentServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(jwt.UnaryClientService(jwt.ServicePayment)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to payment service: %w", err)
+	}
+
+	client := pb.NewPaymentServiceClient(conn)
+	log.Printf("✅ Connected to payment service at %s", paymentServiceAddr)
+
+	return &PaymentClient{
+		client: client,
+		conn:   conn,
+	}, nil
+}
+
+// CreatePayment asks payment-service to create the payment for an order.
+// Payment-service reuses the existing payment if the order already has one.
+func (p *PaymentClient) CreatePayment(ctx context.Context, orderID uuid.UUID, amount money.Decimal, currency string) (*domain.PaymentInfo, error) {
+	resp, err := p.client.CreatePayment(ctx, &pb.CreatePaymentRequest{
+		OrderId:  orderID.String(),
+		Amount:   amount.Float64(),
+		Currency: currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+	if resp.GetPayment() == nil {
+		return nil, fmt.Errorf("nil payment from payment service")
+	}
+	return toPaymentInfo(resp.GetPayment())
 }
 
-func (p *PaymentClient) CreatePayment(ctx context.Context, orderID uuid.UUID, amount float64) error {
-	// TODO: Implement actual gRPC call to payment service
-	// For now, just log the action
-	fmt.Printf("Creating payment for order: %s, amount: %.2f\n", orderID, amount)
-	
-	// Simulate success
+// GetPaymentByOrderID fetches the current state of the payment for an order
+func (p *PaymentClient) GetPaymentByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.PaymentInfo, error) {
+	resp, err := p.client.GetPaymentByOrder(ctx, &pb.GetPaymentByOrderRequest{
+		OrderId: orderID.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if resp.GetPayment() == nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+	return toPaymentInfo(resp.GetPayment())
+}
+
+// Close closes the gRPC connection
+func (p *PaymentClient) Close() error {
+	if p.conn != nil {
+		return p.conn.Close()
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func toPaymentInfo(payment *pb.Payment) (*domain.PaymentInfo, error) {
+	amount, err := money.NewFromFloat(payment.GetAmount())
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment amount: %w", err)
+	}
+	return &domain.PaymentInfo{
+		PaymentID:   payment.GetPaymentId(),
+		OrderID:     payment.GetOrderId(),
+		ProphetID:   payment.GetProphetId(),
+		Amount:      amount,
+		Currency:    payment.GetCurrency(),
+		Status:      strings.TrimPrefix(payment.GetStatus().String(), "PAYMENT_STATUS_"),
+		Provider:    payment.GetProvider(),
+		ProviderRef: payment.GetProviderRef(),
+		CreatedAt:   payment.GetCreatedTime().AsTime(),
+		UpdatedAt:   payment.GetUpdatedTime().AsTime(),
+	}, nil
+}
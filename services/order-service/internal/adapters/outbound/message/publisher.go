@@ -4,44 +4,59 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"time"
 
-	"github.com/wnmay/horo/services/order-service/internal/adapters/outbound/grpc"
 	"github.com/wnmay/horo/services/order-service/internal/domain"
 	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
 	"github.com/wnmay/horo/shared/contract"
 	"github.com/wnmay/horo/shared/message"
 )
 
+// Publisher records domain events in the outbox. They are written with the
+// transaction carried by ctx and delivered to RabbitMQ later by the Relay.
+// Course details come from the snapshot taken when the order was placed, so
+// every event reports the same price and course regardless of later edits.
+// Amounts are what the customer is charged, in the order's charge currency.
 type Publisher struct {
-	rabbit       *message.RabbitMQ
-	courseClient *grpc.CourseClient
+	outbox outbound.OutboxRepository
 }
 
-func NewPublisher(rabbit *message.RabbitMQ, courseClient *grpc.CourseClient) outbound.EventPublisher {
+func NewPublisher(outbox outbound.OutboxRepository) outbound.EventPublisher {
 	return &Publisher{
-		rabbit:       rabbit,
-		courseClient: courseClient,
+		outbox: outbox,
 	}
 }
 
-func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
-	course, err := p.courseClient.GetCourseByID(ctx, order.CourseID)
-
-	// Default values if course fetch fails
-	coursePrice := 200.0
-	if err != nil {
-		log.Printf("Warning: Failed to fetch course details for %s: %v. Using default price.", order.CourseID, err)
-	} else if course != nil {
-		coursePrice = course.Price
-	}
+func (p *Publisher) enqueue(ctx context.Context, routingKey string, amqpMessage contract.AmqpMessage) error {
+	return p.outbox.Enqueue(ctx, domain.NewOutboxMessage(routingKey, amqpMessage.OwnerID, amqpMessage.Data))
+}
 
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
 	// Create order data for the event
 	orderData := message.OrderData{
-		OrderID:    order.OrderID.String(),
-		CustomerID: order.CustomerID,
-		Status:     string(order.Status),
-		Amount:     coursePrice,
+		OrderID:         order.OrderID.String(),
+		CustomerID:      order.CustomerID,
+		Status:          string(order.Status),
+		Amount:          order.ChargeAmount,
+		Currency:        order.ChargeCurrency,
+		Price:           order.Price,
+		PriceCurrency:   order.Currency,
+		ExchangeRate:    order.ExchangeRate,
+		Discount:        order.ChargeDiscount(),
+		CouponCode:      order.CouponCode,
+		CourseID:        order.CourseID,
+		CourseName:      order.CourseName,
+		CourseType:      order.CourseType,
+		ProphetID:       order.ProphetID,
+		DurationMinutes: order.DurationMinutes,
+		Sessions:        order.SessionCount(),
+		RoomID:          order.RoomID,
+		BookingID:       order.BookingID,
+		SessionTimezone: order.SessionTimezone,
+	}
+	if order.SessionStartAt != nil && order.SessionEndAt != nil {
+		orderData.SessionStart = order.SessionStartAt.UTC().Format(time.RFC3339)
+		orderData.SessionEnd = order.SessionEndAt.UTC().Format(time.RFC3339)
 	}
 
 	// Marshal the order data
@@ -56,38 +71,52 @@ func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order
 		Data:    data,
 	}
 
-	// Publish the message with routing key using PublishMessage method
-	if err := p.rabbit.PublishMessage(ctx, contract.OrderCreatedEvent, amqpMessage); err != nil {
-		return fmt.Errorf("failed to publish order created event: %w", err)
+	// Record the message in the outbox for the relay to publish
+	if err := p.enqueue(ctx, contract.OrderCreatedEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order created event: %w", err)
 	}
 
-	fmt.Printf("Published order created event for order: %s with price: %.2f\n", order.OrderID, coursePrice)
+	fmt.Printf("Queued order created event for order: %s with charge: %s %s\n", order.OrderID, order.ChargeAmount, order.ChargeCurrency)
 	return nil
 }
 
 func (p *Publisher) PublishOrderCompleted(ctx context.Context, order *domain.Order) error {
-	// Fetch course details from course service
-	course, err := p.courseClient.GetCourseByID(ctx, order.CourseID)
+	if err := p.publishCompletion(ctx, contract.OrderCompletedEvent, order); err != nil {
+		return fmt.Errorf("failed to queue order completed event: %w", err)
+	}
 
-	// Default values if course fetch fails
-	courseName := "Unknown Course"
-	prophetID := ""
+	fmt.Printf("Queued order completed event for order: %s, course: %s, prophet: %s\n", order.OrderID, order.CourseName, order.ProphetID)
+	return nil
+}
 
-	if err != nil {
-		log.Printf("Warning: Failed to fetch course details for %s: %v. Using default course name.", order.CourseID, err)
-	} else if course != nil {
-		courseName = course.Coursename
-		prophetID = course.ProphetId
+// PublishOrderSessionCompleted reports a completed bundle session that was
+// not the order's last
+func (p *Publisher) PublishOrderSessionCompleted(ctx context.Context, order *domain.Order) error {
+	if err := p.publishCompletion(ctx, contract.OrderSessionCompletedEvent, order); err != nil {
+		return fmt.Errorf("failed to queue order session completed event: %w", err)
 	}
 
+	fmt.Printf("Queued order session completed event for order: %s, session %d of %d\n", order.OrderID, order.SessionsCompleted, order.SessionCount())
+	return nil
+}
+
+func (p *Publisher) publishCompletion(ctx context.Context, routingKey string, order *domain.Order) error {
 	orderCompletedData := message.OrderCompletedData{
 		OrderID:     order.OrderID.String(),
 		CourseID:    order.CourseID,
-		CourseName:  courseName,
+		CourseName:  order.CourseName,
+		CourseType:  order.CourseType,
 		OrderStatus: string(order.Status),
-		ProphetID:   prophetID,
+		ProphetID:   order.ProphetID,
 		RoomID:      order.RoomID,
 		CustomerID:  order.CustomerID,
+		Amount:      order.ChargeAmount,
+		Currency:    order.ChargeCurrency,
+		Session:     max(order.SessionsCompleted, 1),
+		Sessions:    order.SessionCount(),
+	}
+	if order.SubscriptionID != nil {
+		orderCompletedData.SubscriptionID = order.SubscriptionID.String()
 	}
 
 	data, err := json.Marshal(orderCompletedData)
@@ -100,28 +129,10 @@ func (p *Publisher) PublishOrderCompleted(ctx context.Context, order *domain.Ord
 		Data:    data,
 	}
 
-	if err := p.rabbit.PublishMessage(ctx, contract.OrderCompletedEvent, amqpMessage); err != nil {
-		return fmt.Errorf("failed to publish order completed event: %w", err)
-	}
-
-	fmt.Printf("Published order completed event for order: %s, course: %s, prophet: %s\n", order.OrderID, courseName, prophetID)
-	return nil
+	return p.enqueue(ctx, routingKey, amqpMessage)
 }
 
 func (p *Publisher) PublishOrderPaid(ctx context.Context, order *domain.Order) error {
-	course, err := p.courseClient.GetCourseByID(ctx, order.CourseID)
-
-	// Default values if course fetch fails
-	courseName := "Unknown Course"
-	coursePrice := 200.0
-
-	if err != nil {
-		log.Printf("Warning: Failed to fetch course details for %s: %v. Using defaults.", order.CourseID, err)
-	} else if course != nil {
-		courseName = course.Coursename
-		coursePrice = course.Price
-	}
-
 	orderPaidData := message.OrderPaidData{
 		OrderID:       order.OrderID.String(),
 		PaymentID:     order.PaymentID.String(),
@@ -129,8 +140,12 @@ func (p *Publisher) PublishOrderPaid(ctx context.Context, order *domain.Order) e
 		CustomerID:    order.CustomerID,
 		CourseID:      order.CourseID,
 		OrderStatus:   string(order.Status),
-		CourseName:    courseName,
-		Amount:        coursePrice,
+		CourseName:    order.CourseName,
+		Amount:        order.ChargeAmount,
+		Currency:      order.ChargeCurrency,
+		Discount:      order.ChargeDiscount(),
+		CouponCode:    order.CouponCode,
+		ProphetID:     order.ProphetID,
 		PaymentStatus: "COMPLETED",
 	}
 
@@ -144,28 +159,15 @@ func (p *Publisher) PublishOrderPaid(ctx context.Context, order *domain.Order) e
 		Data:    data,
 	}
 
-	if err := p.rabbit.PublishMessage(ctx, contract.OrderPaidEvent, amqpMessage); err != nil {
-		return fmt.Errorf("failed to publish order paid event: %w", err)
+	if err := p.enqueue(ctx, contract.OrderPaidEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order paid event: %w", err)
 	}
 
-	fmt.Printf("Published order paid event for order: %s, payment: %s\n", order.OrderID, order.PaymentID)
+	fmt.Printf("Queued order paid event for order: %s, payment: %s\n", order.OrderID, order.PaymentID)
 	return nil
 }
 
 func (p *Publisher) PublishOrderPaymentBound(ctx context.Context, order *domain.Order) error {
-	course, err := p.courseClient.GetCourseByID(ctx, order.CourseID)
-
-	// Default values if course fetch fails
-	courseName := "Unknown Course"
-	coursePrice := 200.0
-
-	if err != nil {
-		log.Printf("Warning: Failed to fetch course details for %s: %v. Using defaults.", order.CourseID, err)
-	} else if course != nil {
-		courseName = course.Coursename
-		coursePrice = course.Price
-	}
-
 	orderPaymentBoundData := message.OrderPaymentBoundData{
 		OrderID:       order.OrderID.String(),
 		PaymentID:     order.PaymentID.String(),
@@ -173,8 +175,12 @@ func (p *Publisher) PublishOrderPaymentBound(ctx context.Context, order *domain.
 		CustomerID:    order.CustomerID,
 		OrderStatus:   string(order.Status),
 		CourseID:      order.CourseID,
-		CourseName:    courseName,
-		Amount:        coursePrice,
+		CourseName:    order.CourseName,
+		Amount:        order.ChargeAmount,
+		Currency:      order.ChargeCurrency,
+		Discount:      order.ChargeDiscount(),
+		CouponCode:    order.CouponCode,
+		ProphetID:     order.ProphetID,
 		PaymentStatus: "PENDING",
 	}
 
@@ -188,10 +194,237 @@ func (p *Publisher) PublishOrderPaymentBound(ctx context.Context, order *domain.
 		Data:    data,
 	}
 
-	if err := p.rabbit.PublishMessage(ctx, contract.OrderPaymentBoundEvent, amqpMessage); err != nil {
-		return fmt.Errorf("failed to publish order payment bound event: %w", err)
+	if err := p.enqueue(ctx, contract.OrderPaymentBoundEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order payment bound event: %w", err)
+	}
+
+	fmt.Printf("Queued order payment bound event for order: %s, payment: %s\n", order.OrderID, order.PaymentID)
+	return nil
+}
+
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, previousStatus domain.OrderStatus) error {
+	paymentID := ""
+	if order.PaymentID != nil {
+		paymentID = order.PaymentID.String()
+	}
+
+	orderCancelledData := message.OrderCancelledData{
+		OrderID:        order.OrderID.String(),
+		PaymentID:      paymentID,
+		RoomID:         order.RoomID,
+		CustomerID:     order.CustomerID,
+		CourseID:       order.CourseID,
+		CourseName:     order.CourseName,
+		ProphetID:      order.ProphetID,
+		Amount:         order.ChargeAmount,
+		Currency:       order.ChargeCurrency,
+		OrderStatus:    string(order.Status),
+		PreviousStatus: string(previousStatus),
+		CancelledBy:    string(order.CancelledBy),
+		Reason:         order.CancelReason,
+	}
+	if order.SubscriptionID != nil {
+		orderCancelledData.SubscriptionID = order.SubscriptionID.String()
+	}
+
+	data, err := json.Marshal(orderCancelledData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order cancelled data: %w", err)
+	}
+
+	amqpMessage := contract.AmqpMessage{
+		OwnerID: order.OrderID.String(),
+		Data:    data,
+	}
+
+	if err := p.enqueue(ctx, contract.OrderCancelledEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order cancelled event: %w", err)
+	}
+
+	fmt.Printf("Queued order cancelled event for order: %s, cancelled by: %s\n", order.OrderID, order.CancelledBy)
+	return nil
+}
+
+func (p *Publisher) PublishOrderDisputed(ctx context.Context, order *domain.Order) error {
+	paymentID := ""
+	if order.PaymentID != nil {
+		paymentID = order.PaymentID.String()
+	}
+
+	orderDisputedData := message.OrderDisputedData{
+		OrderID:    order.OrderID.String(),
+		PaymentID:  paymentID,
+		RoomID:     order.RoomID,
+		CustomerID: order.CustomerID,
+		CourseID:   order.CourseID,
+		CourseName: order.CourseName,
+		ProphetID:  order.ProphetID,
+		Amount:     order.ChargeAmount,
+		Currency:   order.ChargeCurrency,
+		Reason:     order.DisputeReason,
+	}
+	if order.DisputedAt != nil {
+		orderDisputedData.DisputedAt = order.DisputedAt.UTC().Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal(orderDisputedData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order disputed data: %w", err)
+	}
+
+	amqpMessage := contract.AmqpMessage{
+		OwnerID: order.OrderID.String(),
+		Data:    data,
+	}
+
+	if err := p.enqueue(ctx, contract.OrderDisputedEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order disputed event: %w", err)
+	}
+
+	fmt.Printf("Queued order disputed event for order: %s\n", order.OrderID)
+	return nil
+}
+
+func (p *Publisher) PublishOrderDisputeResolved(ctx context.Context, order *domain.Order) error {
+	paymentID := ""
+	if order.PaymentID != nil {
+		paymentID = order.PaymentID.String()
+	}
+
+	orderDisputeResolvedData := message.OrderDisputeResolvedData{
+		OrderID:     order.OrderID.String(),
+		PaymentID:   paymentID,
+		RoomID:      order.RoomID,
+		CustomerID:  order.CustomerID,
+		CourseID:    order.CourseID,
+		CourseName:  order.CourseName,
+		ProphetID:   order.ProphetID,
+		Amount:      order.ChargeAmount,
+		Currency:    order.ChargeCurrency,
+		OrderStatus: string(order.Status),
+		Resolution:  string(order.DisputeResolution),
+		ResolvedBy:  order.DisputeResolvedBy,
+		Note:        order.DisputeNote,
+	}
+	if order.DisputeResolvedAt != nil {
+		orderDisputeResolvedData.ResolvedAt = order.DisputeResolvedAt.UTC().Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal(orderDisputeResolvedData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order dispute resolved data: %w", err)
+	}
+
+	amqpMessage := contract.AmqpMessage{
+		OwnerID: order.OrderID.String(),
+		Data:    data,
+	}
+
+	if err := p.enqueue(ctx, contract.OrderDisputeResolvedEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order dispute resolved event: %w", err)
+	}
+
+	fmt.Printf("Queued order dispute resolved event for order: %s, resolution: %s\n", order.OrderID, order.DisputeResolution)
+	return nil
+}
+
+// PublishSubscriptionRenewed reports the subscription period order paid for
+func (p *Publisher) PublishSubscriptionRenewed(ctx context.Context, subscription *domain.Subscription, order *domain.Order) error {
+	subscriptionData := subscriptionData(subscription)
+	subscriptionData.OrderID = order.OrderID.String()
+	subscriptionData.Amount = order.ChargeAmount
+	subscriptionData.Currency = order.ChargeCurrency
+
+	if err := p.publishSubscription(ctx, contract.SubscriptionRenewedEvent, subscriptionData); err != nil {
+		return fmt.Errorf("failed to queue subscription renewed event: %w", err)
+	}
+
+	fmt.Printf("Queued subscription renewed event for subscription: %s, period %d\n", subscription.ID, subscription.Periods)
+	return nil
+}
+
+// PublishSubscriptionLapsed reports a subscription that will not bill again
+func (p *Publisher) PublishSubscriptionLapsed(ctx context.Context, subscription *domain.Subscription, reason string) error {
+	subscriptionData := subscriptionData(subscription)
+	subscriptionData.Reason = reason
+
+	if err := p.publishSubscription(ctx, contract.SubscriptionLapsedEvent, subscriptionData); err != nil {
+		return fmt.Errorf("failed to queue subscription lapsed event: %w", err)
+	}
+
+	fmt.Printf("Queued subscription lapsed event for subscription: %s, reason: %s\n", subscription.ID, reason)
+	return nil
+}
+
+func subscriptionData(subscription *domain.Subscription) message.SubscriptionData {
+	subscriptionData := message.SubscriptionData{
+		SubscriptionID: subscription.ID.String(),
+		CustomerID:     subscription.CustomerID,
+		ProphetID:      subscription.ProphetID,
+		CourseID:       subscription.CourseID,
+		CourseName:     subscription.CourseName,
+		RoomID:         subscription.RoomID,
+		Status:         string(subscription.Status),
+		Period:         subscription.Periods,
+		Amount:         subscription.Price,
+		Currency:       subscription.Currency,
+	}
+	if subscription.CurrentPeriodStart != nil && subscription.CurrentPeriodEnd != nil {
+		subscriptionData.PeriodStart = subscription.CurrentPeriodStart.UTC().Format(time.RFC3339)
+		subscriptionData.PeriodEnd = subscription.CurrentPeriodEnd.UTC().Format(time.RFC3339)
+	}
+	return subscriptionData
+}
+
+func (p *Publisher) publishSubscription(ctx context.Context, routingKey string, subscriptionData message.SubscriptionData) error {
+	data, err := json.Marshal(subscriptionData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscription data: %w", err)
+	}
+
+	amqpMessage := contract.AmqpMessage{
+		OwnerID: subscriptionData.SubscriptionID,
+		Data:    data,
+	}
+
+	return p.enqueue(ctx, routingKey, amqpMessage)
+}
+
+func (p *Publisher) PublishOrderRescheduled(ctx context.Context, order *domain.Order, previousStart *time.Time) error {
+	orderRescheduledData := message.OrderRescheduledData{
+		OrderID:         order.OrderID.String(),
+		RoomID:          order.RoomID,
+		CustomerID:      order.CustomerID,
+		CourseID:        order.CourseID,
+		CourseName:      order.CourseName,
+		ProphetID:       order.ProphetID,
+		BookingID:       order.BookingID,
+		Session:         order.CurrentSession(),
+		Sessions:        order.SessionCount(),
+		SessionTimezone: order.SessionTimezone,
+	}
+	if previousStart != nil {
+		orderRescheduledData.PreviousStart = previousStart.UTC().Format(time.RFC3339)
+	}
+	if order.SessionStartAt != nil && order.SessionEndAt != nil {
+		orderRescheduledData.SessionStart = order.SessionStartAt.UTC().Format(time.RFC3339)
+		orderRescheduledData.SessionEnd = order.SessionEndAt.UTC().Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal(orderRescheduledData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order rescheduled data: %w", err)
+	}
+
+	amqpMessage := contract.AmqpMessage{
+		OwnerID: order.OrderID.String(),
+		Data:    data,
+	}
+
+	if err := p.enqueue(ctx, contract.OrderRescheduledEvent, amqpMessage); err != nil {
+		return fmt.Errorf("failed to queue order rescheduled event: %w", err)
 	}
 
-	fmt.Printf("Published order payment bound event for order: %s, payment: %s\n", order.OrderID, order.PaymentID)
+	fmt.Printf("Queued order rescheduled event for order: %s, session %d\n", order.OrderID, order.CurrentSession())
 	return nil
 }
@@ -0,0 +1,115 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/message"
+)
+
+// Broker is the part of the RabbitMQ client the relay publishes through
+type Broker interface {
+	PublishMessage(ctx context.Context, routingKey string, message contract.AmqpMessage) error
+}
+
+// Relay drains the outbox into RabbitMQ. A message is only marked published
+// after the broker confirms it, so delivery is at-least-once.
+type Relay struct {
+	outbox    outbound.OutboxRepository
+	rabbit    Broker
+	interval  time.Duration
+	batchSize int
+	// maxAttempts is how often the broker may reject a message before it is
+	// parked, so one it keeps refusing does not block the rest. Failing to
+	// reach the broker is not the message's fault and does not count.
+	maxAttempts int
+
+	// failures counts failed publishes in a row; the relay backs off until
+	// retryAt instead of hammering a broker that is down
+	failures int
+	retryAt  time.Time
+}
+
+// maxRelayBackoff caps the wait between retries of a failing message
+const maxRelayBackoff = 5 * time.Minute
+
+func NewRelay(outbox outbound.OutboxRepository, rabbit Broker, interval time.Duration, batchSize int, maxAttempts int) *Relay {
+	return &Relay{
+		outbox:      outbox,
+		rabbit:      rabbit,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start polls the outbox until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) drain(ctx context.Context) {
+	if time.Now().Before(r.retryAt) {
+		return
+	}
+	for {
+		published, err := r.outbox.ProcessPending(ctx, r.batchSize, r.maxAttempts, r.publish)
+		if err != nil {
+			log.Printf("Outbox relay failed: %v", err)
+			return
+		}
+		// A short batch means the outbox is empty or a message failed
+		if published < r.batchSize {
+			return
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, outboxMessage *domain.OutboxMessage) error {
+	// Reusing the outbox ID keeps the message ID stable across re-publishes
+	amqpMessage := contract.AmqpMessage{
+		MessageID: outboxMessage.ID.String(),
+		OwnerID:   outboxMessage.OwnerID,
+		Data:      outboxMessage.Payload,
+	}
+
+	if err := r.rabbit.PublishMessage(ctx, outboxMessage.RoutingKey, amqpMessage); err != nil {
+		log.Printf("Failed to relay outbox message %s (%s): %v", outboxMessage.ID, outboxMessage.RoutingKey, err)
+		r.failures++
+		r.retryAt = time.Now().Add(r.backoff())
+		if errors.Is(err, message.ErrMessageRejected) {
+			return fmt.Errorf("%w: %v", domain.ErrOutboxMessageRejected, err)
+		}
+		return err
+	}
+
+	r.failures = 0
+	r.retryAt = time.Time{}
+	return nil
+}
+
+// backoff doubles the poll interval with each failure in a row
+func (r *Relay) backoff() time.Duration {
+	backoff := r.interval
+	for i := 1; i < r.failures && backoff < maxRelayBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRelayBackoff)
+}
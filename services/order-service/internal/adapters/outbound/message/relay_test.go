@@ -0,0 +1,196 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/message"
+)
+
+// memoryOutbox keeps the outbox contract of the Postgres repository in memory
+type memoryOutbox struct {
+	messages []*domain.OutboxMessage
+}
+
+func (o *memoryOutbox) Enqueue(ctx context.Context, message *domain.OutboxMessage) error {
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+func (o *memoryOutbox) ProcessPending(ctx context.Context, limit int, maxAttempts int, fn func(ctx context.Context, message *domain.OutboxMessage) error) (int, error) {
+	published := 0
+	for _, m := range o.messages {
+		if m.PublishedAt != nil || m.FailedAt != nil {
+			continue
+		}
+		if limit == 0 {
+			break
+		}
+		limit--
+
+		if err := fn(ctx, m); err != nil {
+			if !m.RecordFailure(err, maxAttempts, time.Now()) {
+				return published, nil
+			}
+			continue
+		}
+		now := time.Now()
+		m.PublishedAt = &now
+		published++
+	}
+	return published, nil
+}
+
+func (o *memoryOutbox) ListFailed(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	var failed []*domain.OutboxMessage
+	for _, m := range o.messages {
+		if m.PublishedAt == nil && m.FailedAt != nil && len(failed) < limit {
+			failed = append(failed, m)
+		}
+	}
+	return failed, nil
+}
+
+func (o *memoryOutbox) ReplayFailed(ctx context.Context, ids []uuid.UUID) (int, error) {
+	replayed := 0
+	for _, m := range o.messages {
+		if m.PublishedAt != nil || m.FailedAt == nil {
+			continue
+		}
+		if len(ids) > 0 && !containsID(ids, m.ID) {
+			continue
+		}
+		m.Attempts = 0
+		m.FailedAt = nil
+		replayed++
+	}
+	return replayed, nil
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeBroker fails publishes with err, or with rejections for the routing
+// keys in rejects, and records what it accepted
+type fakeBroker struct {
+	err     error
+	rejects map[string]bool
+	sent    []string
+}
+
+func (b *fakeBroker) PublishMessage(ctx context.Context, routingKey string, amqpMessage contract.AmqpMessage) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.rejects[routingKey] {
+		return fmt.Errorf("%w: message with routing key %s was nacked by the broker", message.ErrMessageRejected, routingKey)
+	}
+	b.sent = append(b.sent, routingKey)
+	return nil
+}
+
+func newTestRelay(outbox *memoryOutbox, broker *fakeBroker, maxAttempts int) *Relay {
+	return NewRelay(outbox, broker, time.Millisecond, 10, maxAttempts)
+}
+
+// drainNow drains the outbox without waiting out the relay's backoff
+func drainNow(relay *Relay, times int) {
+	for i := 0; i < times; i++ {
+		relay.retryAt = time.Time{}
+		relay.drain(context.Background())
+	}
+}
+
+func enqueue(outbox *memoryOutbox, routingKeys ...string) {
+	for _, key := range routingKeys {
+		outbox.Enqueue(context.Background(), domain.NewOutboxMessage(key, "owner-1", []byte(`{}`)))
+	}
+}
+
+func TestRelayRetriesBrokerOutagesWithoutLimit(t *testing.T) {
+	outbox := &memoryOutbox{}
+	enqueue(outbox, "order.created", "order.paid")
+	broker := &fakeBroker{err: errors.New("connection refused")}
+	relay := newTestRelay(outbox, broker, 3)
+
+	drainNow(relay, 10)
+	for _, m := range outbox.messages {
+		if m.FailedAt != nil || m.Attempts != 0 {
+			t.Fatalf("message %s parked or counted during an outage: attempts %d, failed at %v", m.RoutingKey, m.Attempts, m.FailedAt)
+		}
+	}
+	if outbox.messages[0].LastError == "" {
+		t.Error("LastError was not recorded")
+	}
+
+	broker.err = nil
+	drainNow(relay, 1)
+	if got := fmt.Sprint(broker.sent); got != "[order.created order.paid]" {
+		t.Errorf("sent %s after the outage, want both in order", got)
+	}
+}
+
+func TestRelayParksRejectedMessages(t *testing.T) {
+	outbox := &memoryOutbox{}
+	enqueue(outbox, "order.created", "order.poison", "order.paid")
+	broker := &fakeBroker{rejects: map[string]bool{"order.poison": true}}
+	relay := newTestRelay(outbox, broker, 3)
+
+	// The rejected message holds back the one after it until it is parked
+	drainNow(relay, 2)
+	if got := fmt.Sprint(broker.sent); got != "[order.created]" {
+		t.Fatalf("sent %s before parking, want only the first", got)
+	}
+
+	drainNow(relay, 1)
+	poison := outbox.messages[1]
+	if poison.FailedAt == nil || poison.Attempts != 3 {
+		t.Fatalf("rejected message: attempts %d, failed at %v, want parked after 3", poison.Attempts, poison.FailedAt)
+	}
+	if got := fmt.Sprint(broker.sent); got != "[order.created order.paid]" {
+		t.Errorf("sent %s after parking, want the later message too", got)
+	}
+
+	// Once the broker accepts it again, a replay sends it
+	delete(broker.rejects, "order.poison")
+	replayed, err := outbox.ReplayFailed(context.Background(), []uuid.UUID{poison.ID})
+	if err != nil || replayed != 1 {
+		t.Fatalf("ReplayFailed() = %d, %v", replayed, err)
+	}
+	drainNow(relay, 1)
+	if poison.PublishedAt == nil {
+		t.Error("replayed message was not published")
+	}
+}
+
+func TestRelayBackoff(t *testing.T) {
+	relay := NewRelay(&memoryOutbox{}, &fakeBroker{}, time.Second, 10, 3)
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Second},
+		{failures: 2, want: 2 * time.Second},
+		{failures: 4, want: 8 * time.Second},
+		{failures: 20, want: maxRelayBackoff},
+	}
+	for _, tt := range tests {
+		relay.failures = tt.failures
+		if got := relay.backoff(); got != tt.want {
+			t.Errorf("backoff() after %d failures = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
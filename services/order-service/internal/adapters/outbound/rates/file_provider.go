@@ -0,0 +1,111 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// FileRateSource marks rates read from a local rates file
+const FileRateSource = "file"
+
+// rateFile is the on-disk format: how many units of each currency one unit
+// of Base buys, e.g. {"base":"THB","as_of":"2026-01-01T00:00:00Z","rates":{"USD":"0.0278"}}
+type rateFile struct {
+	Base  string                   `json:"base"`
+	AsOf  time.Time                `json:"as_of"`
+	Rates map[string]money.Decimal `json:"rates"`
+}
+
+// FileRateProvider serves exchange rates from a JSON file. The file is
+// re-read whenever it changes, so rates can be updated without a restart.
+type FileRateProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	rates   *rateFile
+}
+
+func NewFileRateProvider(path string) (outbound.ExchangeRateProvider, error) {
+	p := &FileRateProvider{path: path}
+	if _, err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileRateProvider) GetRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	rates, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	fromRate, ok := rates.rate(from)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRateUnavailable, from)
+	}
+	toRate, ok := rates.rate(to)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRateUnavailable, to)
+	}
+
+	return &domain.ExchangeRate{
+		From:   from,
+		To:     to,
+		Rate:   toRate.Div(fromRate),
+		Source: FileRateSource,
+		AsOf:   rates.AsOf,
+	}, nil
+}
+
+// rate is the price of currency in the base currency. The base itself is 1.
+func (f *rateFile) rate(currency string) (money.Decimal, bool) {
+	if currency == f.Base {
+		return money.NewFromInt(1), true
+	}
+	rate, ok := f.Rates[currency]
+	if !ok || !rate.IsPositive() {
+		return money.Zero, false
+	}
+	return rate, true
+}
+
+// load returns the cached rates, re-reading the file if it has been modified
+func (p *FileRateProvider) load() (*rateFile, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat exchange rates file: %w", err)
+	}
+	if p.rates != nil && info.ModTime().Equal(p.modTime) {
+		return p.rates, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates file: %w", err)
+	}
+	var rates rateFile
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates file: %w", err)
+	}
+	base, err := money.NormalizeCurrency(rates.Base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base currency in exchange rates file: %w", err)
+	}
+	rates.Base = base
+
+	p.rates = &rates
+	p.modTime = info.ModTime()
+	return p.rates, nil
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// ForceOrderStatus moves an order into another status on an admin's say-so,
+// recording the move and its reason in the same transaction. The move
+// publishes the same events as the usual flows into that status, so
+// payment-service captures, settles or refunds the payment to match. Moving a
+// disputed order resolves its dispute in the admin's name.
+func (s *OrderService) ForceOrderStatus(ctx context.Context, cmd inbound.ForceOrderStatusCommand) (*domain.Order, error) {
+	reason := strings.TrimSpace(cmd.Reason)
+	if reason == "" {
+		return nil, domain.ErrAuditReasonRequired
+	}
+
+	var order *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		previousStatus := order.Status
+		if err := order.ForceStatus(cmd.Status, cmd.AdminID, reason); err != nil {
+			return err
+		}
+
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		if err := s.auditRepo.Create(ctx, domain.NewOrderAudit(order, previousStatus, cmd.AdminID, reason)); err != nil {
+			return fmt.Errorf("failed to record order audit: %w", err)
+		}
+
+		if previousStatus == domain.StatusDisputed {
+			if err := s.eventPublisher.PublishOrderDisputeResolved(ctx, order); err != nil {
+				return fmt.Errorf("failed to publish order dispute resolved event: %w", err)
+			}
+		}
+
+		switch order.Status {
+		case domain.StatusConfirmed:
+			if err := s.eventPublisher.PublishOrderPaid(ctx, order); err != nil {
+				return fmt.Errorf("failed to publish order paid event: %w", err)
+			}
+			if order.SubscriptionID != nil {
+				return s.startSubscriptionPeriod(ctx, order)
+			}
+		case domain.StatusCancelled:
+			if err := s.releasePromotion(ctx, order); err != nil {
+				return err
+			}
+			if err := s.eventPublisher.PublishOrderCancelled(ctx, order, previousStatus); err != nil {
+				return fmt.Errorf("failed to publish order cancelled event: %w", err)
+			}
+		case domain.StatusCompleted:
+			if err := s.eventPublisher.PublishOrderCompleted(ctx, order); err != nil {
+				return fmt.Errorf("failed to publish order completed event: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == domain.StatusCancelled {
+		releaseSlot(ctx, s.bookingProvider, order)
+	}
+
+	return order, nil
+}
+
+// GetOrderAudit lists the times admins forced the order between statuses
+func (s *OrderService) GetOrderAudit(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderAudit, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	audits, err := s.auditRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order audit: %w", err)
+	}
+	return audits, nil
+}
+
+// GetDashboard summarises the orders placed in [from, to): the value of the
+// paid ones in each currency, how many are in each status and the top
+// prophets by value in each currency
+func (s *OrderService) GetDashboard(ctx context.Context, from, to time.Time, top int) (*domain.Dashboard, error) {
+	counts, err := s.orderRepo.CountByStatus(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+	sales, err := s.orderRepo.SalesByProphet(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total sales: %w", err)
+	}
+
+	dashboard := &domain.Dashboard{
+		From:           from,
+		To:             to,
+		GMV:            []*domain.CurrencySales{},
+		OrdersByStatus: counts,
+		TopProphets:    []*domain.ProphetSales{},
+	}
+
+	// Sales come largest first, so each currency's top prophets come first
+	gmv := make(map[string]*domain.CurrencySales)
+	for _, sale := range sales {
+		total, ok := gmv[sale.Currency]
+		if !ok {
+			total = &domain.CurrencySales{Currency: sale.Currency, Amount: money.Zero}
+			gmv[sale.Currency] = total
+			dashboard.GMV = append(dashboard.GMV, total)
+		}
+		if countProphets(dashboard.TopProphets, sale.Currency) < top {
+			dashboard.TopProphets = append(dashboard.TopProphets, sale)
+		}
+		total.Amount = total.Amount.Add(sale.Amount)
+		total.Orders += sale.Orders
+	}
+
+	return dashboard, nil
+}
+
+func countProphets(sales []*domain.ProphetSales, currency string) int {
+	n := 0
+	for _, sale := range sales {
+		if sale.Currency == currency {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,369 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+)
+
+// The fakes below keep the outbound ports in memory. Repositories hand out
+// copies, as the database would, so a change only sticks once it is saved.
+
+type memoryOrders struct {
+	orders map[uuid.UUID]domain.Order
+}
+
+func newMemoryOrders(orders ...*domain.Order) *memoryOrders {
+	repo := &memoryOrders{orders: map[uuid.UUID]domain.Order{}}
+	for _, order := range orders {
+		repo.orders[order.OrderID] = *order
+	}
+	return repo
+}
+
+func (r *memoryOrders) saved(orderID uuid.UUID) *domain.Order {
+	order := r.orders[orderID]
+	return &order
+}
+
+func (r *memoryOrders) Create(ctx context.Context, order *domain.Order) error {
+	r.orders[order.OrderID] = *order
+	return nil
+}
+
+func (r *memoryOrders) GetAll(ctx context.Context, status domain.OrderStatus, page contract.PageRequest) (contract.Page[*domain.Order], error) {
+	return contract.Page[*domain.Order]{}, nil
+}
+
+func (r *memoryOrders) GetByID(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
+	order, ok := r.orders[orderID]
+	if !ok {
+		return nil, domain.ErrOrderNotFound
+	}
+	return &order, nil
+}
+
+func (r *memoryOrders) GetByIDForUpdate(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
+	return r.GetByID(ctx, orderID)
+}
+
+func (r *memoryOrders) BackfillAutoCompleteAt(ctx context.Context, disputeWindow time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryOrders) GetDueForAutoCompletion(ctx context.Context, now time.Time, limit int) ([]*domain.Order, error) {
+	var due []*domain.Order
+	for _, order := range r.orders {
+		if order.AutoCompleteDue(now) && len(due) < limit {
+			due = append(due, &order)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryOrders) GetByCustomerID(ctx context.Context, customerID string) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		if order.CustomerID == customerID {
+			orders = append(orders, &order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *memoryOrders) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*domain.Order, error) {
+	return nil, nil
+}
+
+func (r *memoryOrders) GetByRoomID(ctx context.Context, roomID string) ([]*domain.Order, error) {
+	return nil, nil
+}
+
+func (r *memoryOrders) Update(ctx context.Context, order *domain.Order) error {
+	if _, ok := r.orders[order.OrderID]; !ok {
+		return domain.ErrOrderNotFound
+	}
+	r.orders[order.OrderID] = *order
+	return nil
+}
+
+func (r *memoryOrders) Delete(ctx context.Context, orderID uuid.UUID) error {
+	delete(r.orders, orderID)
+	return nil
+}
+
+func (r *memoryOrders) CountByStatus(ctx context.Context, from, to time.Time) (map[domain.OrderStatus]int, error) {
+	return nil, nil
+}
+
+func (r *memoryOrders) SalesByProphet(ctx context.Context, from, to time.Time) ([]*domain.ProphetSales, error) {
+	return nil, nil
+}
+
+type memoryPromotions struct {
+	promotions  map[uuid.UUID]domain.Promotion
+	redemptions []*domain.PromotionRedemption
+}
+
+func newMemoryPromotions(promotions ...*domain.Promotion) *memoryPromotions {
+	repo := &memoryPromotions{promotions: map[uuid.UUID]domain.Promotion{}}
+	for _, promotion := range promotions {
+		repo.promotions[promotion.ID] = *promotion
+	}
+	return repo
+}
+
+// withRedemptions fills in the redemption count the database would join in
+func (r *memoryPromotions) withRedemptions(promotion domain.Promotion) *domain.Promotion {
+	promotion.Redemptions = 0
+	for _, redemption := range r.redemptions {
+		if redemption.PromotionID == promotion.ID {
+			promotion.Redemptions++
+		}
+	}
+	return &promotion
+}
+
+func (r *memoryPromotions) Create(ctx context.Context, promotion *domain.Promotion) error {
+	for _, existing := range r.promotions {
+		if promotion.IsCoupon() && existing.Code == promotion.Code {
+			return domain.ErrCouponCodeTaken
+		}
+	}
+	r.promotions[promotion.ID] = *promotion
+	return nil
+}
+
+func (r *memoryPromotions) GetByID(ctx context.Context, promotionID uuid.UUID) (*domain.Promotion, error) {
+	promotion, ok := r.promotions[promotionID]
+	if !ok {
+		return nil, domain.ErrPromotionNotFound
+	}
+	return r.withRedemptions(promotion), nil
+}
+
+func (r *memoryPromotions) GetByIDForUpdate(ctx context.Context, promotionID uuid.UUID) (*domain.Promotion, error) {
+	return r.GetByID(ctx, promotionID)
+}
+
+func (r *memoryPromotions) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	for _, promotion := range r.promotions {
+		if promotion.IsCoupon() && promotion.Code == code {
+			return r.withRedemptions(promotion), nil
+		}
+	}
+	return nil, domain.ErrPromotionNotFound
+}
+
+func (r *memoryPromotions) List(ctx context.Context, prophetID string) ([]*domain.Promotion, error) {
+	var promotions []*domain.Promotion
+	for _, promotion := range r.promotions {
+		if prophetID == "" || promotion.ProphetID == prophetID {
+			promotions = append(promotions, r.withRedemptions(promotion))
+		}
+	}
+	return promotions, nil
+}
+
+func (r *memoryPromotions) ListAutomatic(ctx context.Context, t time.Time) ([]*domain.Promotion, error) {
+	var promotions []*domain.Promotion
+	for _, promotion := range r.promotions {
+		if !promotion.IsCoupon() && promotion.LiveAt(t) {
+			promotions = append(promotions, r.withRedemptions(promotion))
+		}
+	}
+	return promotions, nil
+}
+
+func (r *memoryPromotions) Update(ctx context.Context, promotion *domain.Promotion) error {
+	r.promotions[promotion.ID] = *promotion
+	return nil
+}
+
+func (r *memoryPromotions) CountCustomerRedemptions(ctx context.Context, promotionID uuid.UUID, customerID string) (int, error) {
+	count := 0
+	for _, redemption := range r.redemptions {
+		if redemption.PromotionID == promotionID && redemption.CustomerID == customerID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryPromotions) AddRedemption(ctx context.Context, redemption *domain.PromotionRedemption) error {
+	r.redemptions = append(r.redemptions, redemption)
+	return nil
+}
+
+func (r *memoryPromotions) RemoveRedemption(ctx context.Context, orderID uuid.UUID) error {
+	kept := r.redemptions[:0]
+	for _, redemption := range r.redemptions {
+		if redemption.OrderID != orderID {
+			kept = append(kept, redemption)
+		}
+	}
+	r.redemptions = kept
+	return nil
+}
+
+// fakeTx runs the unit of work in place; the fakes have nothing to roll back
+type fakeTx struct{}
+
+func (fakeTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// recordingPublisher records the name of every event published, and the
+// orders they were about
+type recordingPublisher struct {
+	events []string
+	orders []*domain.Order
+}
+
+func (p *recordingPublisher) record(event string, order *domain.Order) error {
+	p.events = append(p.events, event)
+	p.orders = append(p.orders, order)
+	return nil
+}
+
+func (p *recordingPublisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.record("created", order)
+}
+
+func (p *recordingPublisher) PublishOrderCompleted(ctx context.Context, order *domain.Order) error {
+	return p.record("completed", order)
+}
+
+func (p *recordingPublisher) PublishOrderSessionCompleted(ctx context.Context, order *domain.Order) error {
+	return p.record("session_completed", order)
+}
+
+func (p *recordingPublisher) PublishOrderPaid(ctx context.Context, order *domain.Order) error {
+	return p.record("paid", order)
+}
+
+func (p *recordingPublisher) PublishOrderPaymentBound(ctx context.Context, order *domain.Order) error {
+	return p.record("payment_bound", order)
+}
+
+func (p *recordingPublisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, previousStatus domain.OrderStatus) error {
+	return p.record(fmt.Sprintf("cancelled from %s", previousStatus), order)
+}
+
+func (p *recordingPublisher) PublishOrderDisputed(ctx context.Context, order *domain.Order) error {
+	return p.record("disputed", order)
+}
+
+func (p *recordingPublisher) PublishOrderDisputeResolved(ctx context.Context, order *domain.Order) error {
+	return p.record("dispute_resolved", order)
+}
+
+func (p *recordingPublisher) PublishOrderRescheduled(ctx context.Context, order *domain.Order, previousStart *time.Time) error {
+	return p.record("rescheduled", order)
+}
+
+func (p *recordingPublisher) PublishSubscriptionRenewed(ctx context.Context, subscription *domain.Subscription, order *domain.Order) error {
+	return p.record("subscription_renewed", order)
+}
+
+func (p *recordingPublisher) PublishSubscriptionLapsed(ctx context.Context, subscription *domain.Subscription, reason string) error {
+	return p.record("subscription_lapsed", nil)
+}
+
+type fakeCourses struct {
+	courses map[string]*domain.CourseSnapshot
+}
+
+func (c *fakeCourses) GetProphetID(ctx context.Context, courseID string) (string, error) {
+	course, ok := c.courses[courseID]
+	if !ok {
+		return "", domain.ErrCourseUnavailable
+	}
+	return course.ProphetID, nil
+}
+
+func (c *fakeCourses) GetCourseSnapshot(ctx context.Context, courseID string) (*domain.CourseSnapshot, error) {
+	course, ok := c.courses[courseID]
+	if !ok {
+		return nil, domain.ErrCourseUnavailable
+	}
+	snapshot := *course
+	return &snapshot, nil
+}
+
+// fakeBookings reserves every slot asked for and records the releases
+type fakeBookings struct {
+	released []string
+}
+
+func (b *fakeBookings) ReserveSlot(ctx context.Context, order *domain.Order, startAt time.Time) (*domain.SessionBooking, error) {
+	return &domain.SessionBooking{
+		BookingID: "booking-" + order.OrderID.String(),
+		StartAt:   startAt,
+		EndAt:     startAt.Add(time.Duration(order.DurationMinutes) * time.Minute),
+		Timezone:  "UTC",
+	}, nil
+}
+
+func (b *fakeBookings) ReleaseSlot(ctx context.Context, bookingID string) error {
+	b.released = append(b.released, bookingID)
+	return nil
+}
+
+type fakeRates struct {
+	rates map[string]*domain.ExchangeRate
+}
+
+func (r *fakeRates) GetRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	rate, ok := r.rates[from+to]
+	if !ok {
+		return nil, domain.ErrRateUnavailable
+	}
+	return rate, nil
+}
+
+// orderFixture wires an OrderService to in-memory ports
+type orderFixture struct {
+	service    *OrderService
+	orders     *memoryOrders
+	promotions *memoryPromotions
+	publisher  *recordingPublisher
+	courses    *fakeCourses
+	bookings   *fakeBookings
+	rates      *fakeRates
+}
+
+func newOrderFixture(orders ...*domain.Order) *orderFixture {
+	f := &orderFixture{
+		orders:     newMemoryOrders(orders...),
+		promotions: newMemoryPromotions(),
+		publisher:  &recordingPublisher{},
+		courses:    &fakeCourses{courses: map[string]*domain.CourseSnapshot{}},
+		bookings:   &fakeBookings{},
+		rates:      &fakeRates{rates: map[string]*domain.ExchangeRate{}},
+	}
+	f.service = NewOrderService(f.orders, fakeTx{}, f.publisher, nil, f.courses, f.bookings, f.rates, f.promotions, nil, nil, 24*time.Hour).(*OrderService)
+	return f
+}
+
+// confirmedOrder is a paid order for a session an hour away
+func confirmedOrder(customerID, prophetID string) *domain.Order {
+	start := time.Now().Add(time.Hour).UTC()
+	end := start.Add(time.Hour)
+	return &domain.Order{
+		OrderID:        uuid.New(),
+		CustomerID:     customerID,
+		CourseID:       "course-1",
+		ProphetID:      prophetID,
+		Status:         domain.StatusConfirmed,
+		Sessions:       1,
+		SessionsBooked: 1,
+		BookingID:      "booking-1",
+		SessionStartAt: &start,
+		SessionEndAt:   &end,
+	}
+}
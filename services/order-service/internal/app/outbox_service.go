@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+)
+
+type OutboxService struct {
+	outboxRepo outbound.OutboxRepository
+}
+
+func NewOutboxService(outboxRepo outbound.OutboxRepository) inbound.OutboxService {
+	return &OutboxService{
+		outboxRepo: outboxRepo,
+	}
+}
+
+func (s *OutboxService) ListFailedMessages(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	messages, err := s.outboxRepo.ListFailed(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parked outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+// ReplayFailedMessages hands parked messages back to the relay, which sends
+// them ahead of newer events since the outbox is drained oldest first
+func (s *OutboxService) ReplayFailedMessages(ctx context.Context, ids []uuid.UUID) (int, error) {
+	replayed, err := s.outboxRepo.ReplayFailed(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replay parked outbox messages: %w", err)
+	}
+	return replayed, nil
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+)
+
+const (
+	roleProphet = "prophet"
+	roleAdmin   = "admin"
+)
+
+type PromotionService struct {
+	promotionRepo outbound.PromotionRepository
+}
+
+func NewPromotionService(promotionRepo outbound.PromotionRepository) inbound.PromotionService {
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+	}
+}
+
+// CreatePromotion creates a promotion. Prophets can only promote their own
+// courses, so their promotions are always scoped to themselves.
+func (s *PromotionService) CreatePromotion(ctx context.Context, cmd inbound.CreatePromotionCommand) (*domain.Promotion, error) {
+	prophetID := cmd.ProphetID
+	switch cmd.Role {
+	case roleAdmin:
+	case roleProphet:
+		if prophetID != "" && prophetID != cmd.UserID {
+			return nil, domain.ErrNotPromotionOwner
+		}
+		prophetID = cmd.UserID
+	default:
+		return nil, domain.ErrNotPromotionOwner
+	}
+
+	promotion, err := domain.NewPromotion(cmd.Code, cmd.Description, cmd.Kind, cmd.Value, cmd.Currency, prophetID,
+		cmd.FirstOrderOnly, cmd.MaxRedemptions, cmd.MaxPerCustomer, cmd.StartsAt, cmd.ExpiresAt, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if promotion.IsCoupon() {
+		_, err := s.promotionRepo.GetByCode(ctx, promotion.Code)
+		if err == nil {
+			return nil, domain.ErrCouponCodeTaken
+		}
+		if !errors.Is(err, domain.ErrPromotionNotFound) {
+			return nil, fmt.Errorf("failed to check coupon code: %w", err)
+		}
+	}
+
+	if err := s.promotionRepo.Create(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return promotion, nil
+}
+
+// ListPromotions returns every promotion to admins and a prophet's own
+// promotions to that prophet
+func (s *PromotionService) ListPromotions(ctx context.Context, userID string, role string) ([]*domain.Promotion, error) {
+	var prophetID string
+	switch role {
+	case roleAdmin:
+	case roleProphet:
+		prophetID = userID
+	default:
+		return nil, domain.ErrNotPromotionOwner
+	}
+
+	promotions, err := s.promotionRepo.List(ctx, prophetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+// DeactivatePromotion stops a promotion from applying to new orders. Orders
+// that already used it keep their discount.
+func (s *PromotionService) DeactivatePromotion(ctx context.Context, promotionID uuid.UUID, userID string, role string) (*domain.Promotion, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, promotionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPromotionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	if role != roleAdmin && (role != roleProphet || promotion.ProphetID != userID) {
+		return nil, domain.ErrNotPromotionOwner
+	}
+
+	promotion.Deactivate()
+	if err := s.promotionRepo.Update(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to deactivate promotion: %w", err)
+	}
+	return promotion, nil
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// promotion is a live promotion; an empty code makes it automatic
+func promotion(code string, kind domain.PromotionKind, value string, edit func(p *domain.Promotion)) *domain.Promotion {
+	p := &domain.Promotion{
+		ID:       uuid.New(),
+		Code:     code,
+		Kind:     kind,
+		Value:    money.MustParse(value),
+		Active:   true,
+		StartsAt: time.Now().Add(-time.Hour),
+	}
+	if kind == domain.PromotionFixed {
+		p.Currency = "THB"
+	}
+	if edit != nil {
+		edit(p)
+	}
+	return p
+}
+
+// promotionFixture sells prophet-1's 500 THB course-1
+func promotionFixture(promotions ...*domain.Promotion) *orderFixture {
+	f := newOrderFixture()
+	f.promotions = newMemoryPromotions(promotions...)
+	f.service.promotionRepo = f.promotions
+	f.courses.courses["course-1"] = &domain.CourseSnapshot{CourseID: "course-1", ProphetID: "prophet-1", Price: money.NewFromInt(500), Currency: "THB", DurationMinutes: 60}
+	return f
+}
+
+func TestOrderServiceCreateOrderWithPromotion(t *testing.T) {
+	tenPercent := promotion("", domain.PromotionPercentage, "10", nil)
+	hundredOff := promotion("HUNDRED", domain.PromotionFixed, "100", nil)
+	otherProphet := promotion("OTHER", domain.PromotionFixed, "100", func(p *domain.Promotion) { p.ProphetID = "prophet-2" })
+	usedUp := promotion("GONE", domain.PromotionFixed, "100", func(p *domain.Promotion) { p.MaxRedemptions = 1 })
+	firstOnly := promotion("WELCOME", domain.PromotionFixed, "100", func(p *domain.Promotion) { p.FirstOrderOnly = true })
+	expired := promotion("OLD", domain.PromotionFixed, "100", func(p *domain.Promotion) { p.Active = false })
+	tenPercentForOther := promotion("", domain.PromotionPercentage, "10", func(p *domain.Promotion) { p.ProphetID = "prophet-2" })
+
+	tests := []struct {
+		name         string
+		promotions   []*domain.Promotion
+		coupon       string
+		repeat       bool
+		want         *domain.Promotion
+		wantDiscount string
+		wantErr      error
+	}{
+		{name: "no promotion", wantDiscount: "0"},
+		{name: "automatic promotion", promotions: []*domain.Promotion{tenPercent}, want: tenPercent, wantDiscount: "50"},
+		{name: "better coupon wins", promotions: []*domain.Promotion{tenPercent, hundredOff}, coupon: " hundred ", want: hundredOff, wantDiscount: "100"},
+		{name: "automatic promotion for another prophet", promotions: []*domain.Promotion{tenPercentForOther}, wantDiscount: "0"},
+		{name: "first order coupon", promotions: []*domain.Promotion{firstOnly}, coupon: "WELCOME", want: firstOnly, wantDiscount: "100"},
+		{name: "unknown coupon", promotions: []*domain.Promotion{tenPercent}, coupon: "NOPE", wantErr: domain.ErrCouponNotFound},
+		{name: "coupon for another prophet", promotions: []*domain.Promotion{tenPercent, otherProphet}, coupon: "OTHER", wantErr: domain.ErrCouponNotApplicable},
+		{name: "used up coupon", promotions: []*domain.Promotion{usedUp}, coupon: "GONE", wantErr: domain.ErrPromotionExhausted},
+		{name: "first order coupon on a repeat order", promotions: []*domain.Promotion{firstOnly}, coupon: "WELCOME", repeat: true, wantErr: domain.ErrCouponNotApplicable},
+		{name: "inactive coupon", promotions: []*domain.Promotion{expired}, coupon: "OLD", wantErr: domain.ErrCouponExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := promotionFixture(tt.promotions...)
+			if tt.repeat {
+				f.orders.orders[uuid.New()] = *confirmedOrder("customer-1", "prophet-1")
+			}
+			for _, p := range tt.promotions {
+				if p == usedUp {
+					f.promotions.redemptions = append(f.promotions.redemptions, &domain.PromotionRedemption{PromotionID: p.ID, OrderID: uuid.New(), CustomerID: "customer-2"})
+				}
+			}
+			redeemed := len(f.promotions.redemptions)
+
+			order, err := f.service.CreateOrder(context.Background(), inbound.CreateOrderCommand{CustomerID: "customer-1", CourseID: "course-1", StartAt: time.Now().Add(24 * time.Hour), CouponCode: tt.coupon})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if got := fmt.Sprint(f.publisher.events); got != "[]" || len(f.promotions.redemptions) != redeemed {
+					t.Errorf("failed order published %s and left %d redemptions, want none added", got, len(f.promotions.redemptions))
+				}
+				return
+			}
+
+			wantDiscount := money.MustParse(tt.wantDiscount)
+			if !order.DiscountAmount.Equal(wantDiscount) || !order.ChargeAmount.Equal(money.NewFromInt(500).Sub(wantDiscount)) {
+				t.Errorf("order discounts %s and charges %s, want %s off 500", order.DiscountAmount, order.ChargeAmount, wantDiscount)
+			}
+			if tt.want == nil {
+				if order.PromotionID != nil || len(f.promotions.redemptions) != 0 {
+					t.Errorf("order took promotion %v with %d redemptions, want none", order.PromotionID, len(f.promotions.redemptions))
+				}
+				return
+			}
+			if order.PromotionID == nil || *order.PromotionID != tt.want.ID || order.CouponCode != tt.want.Code {
+				t.Fatalf("order took promotion %v %q, want %s %q", order.PromotionID, order.CouponCode, tt.want.ID, tt.want.Code)
+			}
+			if len(f.promotions.redemptions) != redeemed+1 {
+				t.Fatalf("recorded %d redemptions, want one more than %d", len(f.promotions.redemptions), redeemed)
+			}
+			redemption := f.promotions.redemptions[redeemed]
+			if redemption.OrderID != order.OrderID || redemption.CustomerID != "customer-1" || !redemption.Discount.Equal(wantDiscount) {
+				t.Errorf("redemption of %s by %s for %s, want order %s by customer-1 for %s", redemption.OrderID, redemption.CustomerID, redemption.Discount, order.OrderID, wantDiscount)
+			}
+		})
+	}
+}
+
+func TestOrderServiceCreateOrderCustomerLimit(t *testing.T) {
+	once := promotion("ONCE", domain.PromotionFixed, "50", func(p *domain.Promotion) { p.MaxPerCustomer = 1 })
+	f := promotionFixture(once)
+	cmd := inbound.CreateOrderCommand{CustomerID: "customer-1", CourseID: "course-1", StartAt: time.Now().Add(24 * time.Hour), CouponCode: "ONCE"}
+
+	if _, err := f.service.CreateOrder(context.Background(), cmd); err != nil {
+		t.Fatalf("first CreateOrder() error = %v", err)
+	}
+	if _, err := f.service.CreateOrder(context.Background(), cmd); !errors.Is(err, domain.ErrPromotionExhausted) {
+		t.Fatalf("second CreateOrder() error = %v, want %v", err, domain.ErrPromotionExhausted)
+	}
+
+	cmd.CustomerID = "customer-2"
+	if _, err := f.service.CreateOrder(context.Background(), cmd); err != nil {
+		t.Errorf("CreateOrder() for another customer error = %v", err)
+	}
+}
+
+func TestPromotionServiceCreatePromotion(t *testing.T) {
+	tests := []struct {
+		name          string
+		role          string
+		prophetID     string
+		code          string
+		wantProphetID string
+		wantErr       error
+	}{
+		{name: "admin platform promotion", role: roleAdmin, code: "SPRING"},
+		{name: "admin promotion for a prophet", role: roleAdmin, prophetID: "prophet-2", code: "SPRING", wantProphetID: "prophet-2"},
+		{name: "prophet promotion is scoped to the prophet", role: roleProphet, code: "SPRING", wantProphetID: "prophet-1"},
+		{name: "prophet naming themselves", role: roleProphet, prophetID: "prophet-1", code: "SPRING", wantProphetID: "prophet-1"},
+		{name: "prophet naming another prophet", role: roleProphet, prophetID: "prophet-2", code: "SPRING", wantErr: domain.ErrNotPromotionOwner},
+		{name: "customer", role: "customer", code: "SPRING", wantErr: domain.ErrNotPromotionOwner},
+		{name: "code in use", role: roleAdmin, code: " taken ", wantErr: domain.ErrCouponCodeTaken},
+		{name: "automatic promotions need no code", role: roleAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryPromotions(promotion("TAKEN", domain.PromotionPercentage, "10", nil), promotion("", domain.PromotionPercentage, "5", nil))
+			service := NewPromotionService(repo)
+			userID := "admin-1"
+			if tt.role == roleProphet {
+				userID = "prophet-1"
+			}
+
+			got, err := service.CreatePromotion(context.Background(), inbound.CreatePromotionCommand{
+				UserID:    userID,
+				Role:      tt.role,
+				Code:      tt.code,
+				Kind:      domain.PromotionPercentage,
+				Value:     money.NewFromInt(15),
+				ProphetID: tt.prophetID,
+				StartsAt:  time.Now(),
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePromotion() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(repo.promotions) != 2 {
+					t.Errorf("failed create stored a promotion")
+				}
+				return
+			}
+			if got.ProphetID != tt.wantProphetID || got.CreatedBy != userID {
+				t.Errorf("promotion for %q by %q, want %q by %q", got.ProphetID, got.CreatedBy, tt.wantProphetID, userID)
+			}
+			if _, err := repo.GetByID(context.Background(), got.ID); err != nil {
+				t.Errorf("promotion was not stored: %v", err)
+			}
+		})
+	}
+}
+
+func TestPromotionServiceListPromotions(t *testing.T) {
+	platform := promotion("PLATFORM", domain.PromotionPercentage, "10", nil)
+	own := promotion("OWN", domain.PromotionPercentage, "10", func(p *domain.Promotion) { p.ProphetID = "prophet-1" })
+	other := promotion("OTHER", domain.PromotionPercentage, "10", func(p *domain.Promotion) { p.ProphetID = "prophet-2" })
+
+	tests := []struct {
+		name    string
+		userID  string
+		role    string
+		want    int
+		wantErr error
+	}{
+		{name: "admin sees every promotion", userID: "admin-1", role: roleAdmin, want: 3},
+		{name: "prophet sees their own", userID: "prophet-1", role: roleProphet, want: 1},
+		{name: "customer", userID: "customer-1", role: "customer", wantErr: domain.ErrNotPromotionOwner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewPromotionService(newMemoryPromotions(platform, own, other))
+			got, err := service.ListPromotions(context.Background(), tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListPromotions() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("listed %d promotions, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionServiceDeactivatePromotion(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		role    string
+		wantErr error
+	}{
+		{name: "admin", userID: "admin-1", role: roleAdmin},
+		{name: "owning prophet", userID: "prophet-1", role: roleProphet},
+		{name: "another prophet", userID: "prophet-2", role: roleProphet, wantErr: domain.ErrNotPromotionOwner},
+		{name: "customer with the prophet's id", userID: "prophet-1", role: "customer", wantErr: domain.ErrNotPromotionOwner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owned := promotion("OWN", domain.PromotionPercentage, "10", func(p *domain.Promotion) { p.ProphetID = "prophet-1" })
+			repo := newMemoryPromotions(owned)
+			service := NewPromotionService(repo)
+
+			if _, err := service.DeactivatePromotion(context.Background(), owned.ID, tt.userID, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeactivatePromotion() error = %v, want %v", err, tt.wantErr)
+			}
+			if saved := repo.promotions[owned.ID]; saved.Active != (tt.wantErr != nil) {
+				t.Errorf("Active = %v after deactivation error %v", saved.Active, tt.wantErr)
+			}
+		})
+	}
+
+	service := NewPromotionService(newMemoryPromotions())
+	if _, err := service.DeactivatePromotion(context.Background(), uuid.New(), "admin-1", roleAdmin); !errors.Is(err, domain.ErrPromotionNotFound) {
+		t.Errorf("DeactivatePromotion() of an unknown promotion error = %v, want %v", err, domain.ErrPromotionNotFound)
+	}
+}
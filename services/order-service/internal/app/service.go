@@ -2,60 +2,276 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/wnmay/horo/services/order-service/internal/domain"
 	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
 	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type OrderService struct {
-	orderRepo      outbound.OrderRepository
-	eventPublisher outbound.EventPublisher
-	paymentService outbound.PaymentService
+	orderRepo        outbound.OrderRepository
+	txManager        outbound.TransactionManager
+	eventPublisher   outbound.EventPublisher
+	paymentService   outbound.PaymentService
+	courseProvider   outbound.CourseProvider
+	bookingProvider  outbound.BookingProvider
+	rateProvider     outbound.ExchangeRateProvider
+	promotionRepo    outbound.PromotionRepository
+	subscriptionRepo outbound.SubscriptionRepository
+	auditRepo        outbound.AuditRepository
+	// disputeWindow is how long the customer has to dispute a session after
+	// the prophet marks it done
+	disputeWindow time.Duration
 }
 
 func NewOrderService(
 	orderRepo outbound.OrderRepository,
+	txManager outbound.TransactionManager,
 	eventPublisher outbound.EventPublisher,
 	paymentService outbound.PaymentService,
+	courseProvider outbound.CourseProvider,
+	bookingProvider outbound.BookingProvider,
+	rateProvider outbound.ExchangeRateProvider,
+	promotionRepo outbound.PromotionRepository,
+	subscriptionRepo outbound.SubscriptionRepository,
+	auditRepo outbound.AuditRepository,
+	disputeWindow time.Duration,
 ) inbound.OrderService {
 	return &OrderService{
-		orderRepo:      orderRepo,
-		eventPublisher: eventPublisher,
-		paymentService: paymentService,
+		orderRepo:        orderRepo,
+		txManager:        txManager,
+		eventPublisher:   eventPublisher,
+		paymentService:   paymentService,
+		courseProvider:   courseProvider,
+		bookingProvider:  bookingProvider,
+		rateProvider:     rateProvider,
+		promotionRepo:    promotionRepo,
+		subscriptionRepo: subscriptionRepo,
+		auditRepo:        auditRepo,
+		disputeWindow:    disputeWindow,
 	}
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, cmd inbound.CreateOrderCommand) (*domain.Order, error) {
+	// Snapshot the course so the order keeps the price the customer saw
+	course, err := s.courseProvider.GetCourseSnapshot(ctx, cmd.CourseID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCourseUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if err := course.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Fix the conversion now so the charge does not move with later rates
+	rate, err := chargeRate(ctx, s.rateProvider, course, cmd.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create new order entity
-	order := domain.NewOrder(cmd.CustomerID, cmd.CourseID, cmd.RoomID)
+	order := domain.NewOrder(cmd.CustomerID, course, cmd.RoomID, rate)
 
-	// Save order to repository
-	if err := s.orderRepo.Create(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	// Discount the price before it is converted and sent to payment-service
+	promotion, discount, err := s.selectPromotion(ctx, order, cmd.CouponCode)
+	if err != nil {
+		return nil, err
+	}
+	if promotion != nil {
+		order.ApplyPromotion(promotion, discount, rate)
 	}
 
-	// Publish order created event
-	if err := s.eventPublisher.PublishOrderCreated(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to publish order created event: %w", err)
+	// Hold the requested session before the order exists so two customers
+	// cannot both be confirmed for the same time
+	booking, err := s.bookingProvider.ReserveSlot(ctx, order, cmd.StartAt)
+	if err != nil {
+		return nil, err
 	}
+	order.AttachBooking(booking)
+
+	// Save order and its created event atomically
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if order.PromotionID != nil {
+			if err := s.redeemPromotion(ctx, order); err != nil {
+				return err
+			}
+		}
+
+		if err := s.orderRepo.Create(ctx, order); err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
 
-	// Create payment asynchronously (could be done via event as well)
-	// Note: Payment amount will be determined by the payment service based on course pricing
-	if err := s.paymentService.CreatePayment(ctx, order.OrderID, 0.0); err != nil {
-		// Log error but don't fail the order creation
-		// Payment creation will be retried via message queue
-		fmt.Printf("Failed to create payment for order %s: %v\n", order.OrderID, err)
+		if err := s.eventPublisher.PublishOrderCreated(ctx, order); err != nil {
+			return fmt.Errorf("failed to publish order created event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		releaseSlot(ctx, s.bookingProvider, order)
+		return nil, err
 	}
 
+	// Payment-service creates the payment with the discounted charge when it
+	// consumes the order created event
 	return order, nil
 }
-func (s *OrderService) GetOrders(ctx context.Context) ([]*domain.Order, error) {
-	orders, err := s.orderRepo.GetAll(ctx)
+
+// selectPromotion picks the best promotion for the order among the automatic
+// ones and the customer's coupon. Promotions do not stack. A coupon that
+// cannot be used fails the order so the customer is not charged unexpectedly.
+func (s *OrderService) selectPromotion(ctx context.Context, order *domain.Order, couponCode string) (*domain.Promotion, money.Decimal, error) {
+	now := time.Now()
+	candidates, err := s.promotionRepo.ListAutomatic(ctx, now)
+	if err != nil {
+		return nil, money.Zero, fmt.Errorf("failed to get promotions: %w", err)
+	}
+
+	var coupon *domain.Promotion
+	if code := domain.NormalizeCouponCode(couponCode); code != "" {
+		coupon, err = s.promotionRepo.GetByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, domain.ErrPromotionNotFound) {
+				return nil, money.Zero, domain.ErrCouponNotFound
+			}
+			return nil, money.Zero, fmt.Errorf("failed to get coupon: %w", err)
+		}
+		candidates = append(candidates, coupon)
+	}
+	if len(candidates) == 0 {
+		return nil, money.Zero, nil
+	}
+
+	firstOrder, err := s.isFirstOrder(ctx, order.CustomerID)
+	if err != nil {
+		return nil, money.Zero, err
+	}
+
+	eligible := make([]*domain.Promotion, 0, len(candidates))
+	for _, promotion := range candidates {
+		redemptions, err := s.promotionRepo.CountCustomerRedemptions(ctx, promotion.ID, order.CustomerID)
+		if err != nil {
+			return nil, money.Zero, fmt.Errorf("failed to count promotion redemptions: %w", err)
+		}
+		err = promotion.CheckEligible(domain.PromotionTarget{
+			ProphetID:           order.ProphetID,
+			Currency:            order.Currency,
+			FirstOrder:          firstOrder,
+			CustomerRedemptions: redemptions,
+		}, now)
+		if err != nil {
+			if promotion == coupon {
+				return nil, money.Zero, err
+			}
+			continue
+		}
+		eligible = append(eligible, promotion)
+	}
+
+	best, discount := domain.BestPromotion(eligible, order.Price, order.Currency)
+	return best, discount, nil
+}
+
+// isFirstOrder reports whether the customer has no orders other than
+// cancelled ones
+func (s *OrderService) isFirstOrder(ctx context.Context, customerID string) (bool, error) {
+	orders, err := s.orderRepo.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get orders for customer: %w", err)
+	}
+	for _, order := range orders {
+		if order.Status != domain.StatusCancelled {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// redeemPromotion records the order's use of its promotion. The promotion is
+// locked and its limits re-checked so concurrent orders cannot overrun them.
+func (s *OrderService) redeemPromotion(ctx context.Context, order *domain.Order) error {
+	promotion, err := s.promotionRepo.GetByIDForUpdate(ctx, *order.PromotionID)
+	if err != nil {
+		return fmt.Errorf("failed to get promotion: %w", err)
+	}
+	customerRedemptions, err := s.promotionRepo.CountCustomerRedemptions(ctx, promotion.ID, order.CustomerID)
+	if err != nil {
+		return fmt.Errorf("failed to count promotion redemptions: %w", err)
+	}
+	if !promotion.LiveAt(time.Now()) {
+		return domain.ErrCouponExpired
+	}
+	if err := promotion.CheckUsage(promotion.Redemptions, customerRedemptions); err != nil {
+		return err
+	}
+
+	err = s.promotionRepo.AddRedemption(ctx, &domain.PromotionRedemption{
+		PromotionID: promotion.ID,
+		OrderID:     order.OrderID,
+		CustomerID:  order.CustomerID,
+		Discount:    order.DiscountAmount,
+		Currency:    order.Currency,
+		RedeemedAt:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to redeem promotion: %w", err)
+	}
+	return nil
+}
+
+// releasePromotion gives a cancelled order's promotion use back so it no
+// longer counts against the usage limits
+func (s *OrderService) releasePromotion(ctx context.Context, order *domain.Order) error {
+	if order.PromotionID == nil {
+		return nil
+	}
+	if err := s.promotionRepo.RemoveRedemption(ctx, order.OrderID); err != nil {
+		return fmt.Errorf("failed to release promotion: %w", err)
+	}
+	return nil
+}
+
+// chargeRate is the rate from the course's currency to the one the customer
+// pays in, which defaults to the course's
+func chargeRate(ctx context.Context, rateProvider outbound.ExchangeRateProvider, course *domain.CourseSnapshot, chargeCurrency string) (*domain.ExchangeRate, error) {
+	from, err := money.NormalizeCurrency(course.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("course %s: %w", course.CourseID, err)
+	}
+	if chargeCurrency == "" {
+		return domain.IdentityRate(from), nil
+	}
+	to, err := money.NormalizeCurrency(chargeCurrency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get orders: %w", err)
+		return nil, err
+	}
+	if to == from {
+		return domain.IdentityRate(from), nil
+	}
+
+	rate, err := rateProvider.GetRate(ctx, from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrRateUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	return rate, nil
+}
+
+func (s *OrderService) GetOrders(ctx context.Context, status domain.OrderStatus, page contract.PageRequest) (contract.Page[*domain.Order], error) {
+	orders, err := s.orderRepo.GetAll(ctx, status, page)
+	if err != nil {
+		return contract.Page[*domain.Order]{}, fmt.Errorf("failed to get orders: %w", err)
 	}
 	return orders, nil
 }
@@ -68,6 +284,19 @@ func (s *OrderService) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*do
 	return order, nil
 }
 
+// GetOrderPayment asks payment-service for the current payment state of an order
+func (s *OrderService) GetOrderPayment(ctx context.Context, orderID uuid.UUID) (*domain.PaymentInfo, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	payment, err := s.paymentService.GetPaymentByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
 func (s *OrderService) GetOrdersByCustomer(ctx context.Context, customerID string) ([]*domain.Order, error) {
 	orders, err := s.orderRepo.GetByCustomerID(ctx, customerID)
 	if err != nil {
@@ -90,21 +319,65 @@ func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID,
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 
+	// A payment that completes after cancellation must not revive the order;
+	// the refund for it is driven by the order cancelled event
+	if order.Status == domain.StatusCancelled && status != domain.StatusCancelled {
+		return domain.ErrOrderAlreadyCancelled
+	}
+
 	// Update order status
 	order.Status = status
 
-	// Save updated order
-	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return fmt.Errorf("failed to update order: %w", err)
-	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Save updated order
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		if status == domain.StatusCancelled {
+			if err := s.releasePromotion(ctx, order); err != nil {
+				return err
+			}
+		}
+
+		// If order is confirmed, publish order paid event
+		if status == domain.StatusConfirmed {
+			if err := s.eventPublisher.PublishOrderPaid(ctx, order); err != nil {
+				return fmt.Errorf("failed to publish order paid event: %w", err)
+			}
 
-	// If order is confirmed, publish order paid event
-	if status == domain.StatusConfirmed {
-		if err := s.eventPublisher.PublishOrderPaid(ctx, order); err != nil {
-			return fmt.Errorf("failed to publish order paid event: %w", err)
+			if order.SubscriptionID != nil {
+				if err := s.startSubscriptionPeriod(ctx, order); err != nil {
+					return err
+				}
+			}
 		}
+
+		return nil
+	})
+}
+
+// startSubscriptionPeriod starts the subscription period the order paid for.
+// Renewals are announced so the subscription's chat room is reopened.
+func (s *OrderService) startSubscriptionPeriod(ctx context.Context, order *domain.Order) error {
+	subscription, err := s.subscriptionRepo.GetByIDForUpdate(ctx, *order.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if !subscription.PeriodPaid(order.OrderID, time.Now()) {
+		log.Printf("Order %s does not bill the pending period of subscription %s", order.OrderID, subscription.ID)
+		return nil
+	}
+
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
 	}
 
+	if subscription.Periods > 1 {
+		if err := s.eventPublisher.PublishSubscriptionRenewed(ctx, subscription, order); err != nil {
+			return fmt.Errorf("failed to publish subscription renewed event: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -118,17 +391,19 @@ func (s *OrderService) UpdateOrderPaymentID(ctx context.Context, orderID uuid.UU
 	// Update payment ID using the SetPaymentID method
 	order.SetPaymentID(paymentID)
 
-	// Save updated order
-	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return fmt.Errorf("failed to update order with payment ID: %w", err)
-	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Save updated order
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to update order with payment ID: %w", err)
+		}
 
-	// Publish order payment bound event
-	if err := s.eventPublisher.PublishOrderPaymentBound(ctx, order); err != nil {
-		return fmt.Errorf("failed to publish order payment bound event: %w", err)
-	}
+		// Publish order payment bound event
+		if err := s.eventPublisher.PublishOrderPaymentBound(ctx, order); err != nil {
+			return fmt.Errorf("failed to publish order payment bound event: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func (s *OrderService) MarkCustomerCompleted(ctx context.Context, orderID uuid.UUID) error {
@@ -143,21 +418,27 @@ func (s *OrderService) MarkCustomerCompleted(ctx context.Context, orderID uuid.U
 		return fmt.Errorf("order must be confirmed before marking as completed")
 	}
 
+	// Bundles wait here between sessions until the next one is booked
+	if order.Status == domain.StatusConfirmed && !order.HasSessionInProgress() {
+		return domain.ErrNoSessionBooked
+	}
+
 	// Mark as completed by customer
+	completedBefore := order.SessionsCompleted
 	order.MarkCustomerCompleted()
-	if( order.Status == domain.StatusCompleted) {
-		// Publish order completed event
-		if err := s.eventPublisher.PublishOrderCompleted(ctx, order); err != nil {
-			return fmt.Errorf("failed to publish order completed event: %w", err)
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Save updated order
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to mark order as completed by customer: %w", err)
 		}
-	}
 
-	// Save updated order
-	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return fmt.Errorf("failed to mark order as completed by customer: %w", err)
-	}
+		if order.SessionsCompleted > completedBefore {
+			return s.publishSessionCompleted(ctx, order)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func (s *OrderService) MarkProphetCompleted(ctx context.Context, orderID uuid.UUID) error {
@@ -172,18 +453,400 @@ func (s *OrderService) MarkProphetCompleted(ctx context.Context, orderID uuid.UU
 		return fmt.Errorf("order must be confirmed before marking as completed")
 	}
 
+	// Bundles wait here between sessions until the next one is booked
+	if order.Status == domain.StatusConfirmed && !order.HasSessionInProgress() {
+		return domain.ErrNoSessionBooked
+	}
+
 	// Mark as completed by prophet
-	order.MarkProphetCompleted()
-	if( order.Status == domain.StatusCompleted) {
-		// Publish order completed event
-		if err := s.eventPublisher.PublishOrderCompleted(ctx, order); err != nil {
-			return fmt.Errorf("failed to publish order completed event: %w", err)
+	completedBefore := order.SessionsCompleted
+	order.MarkProphetCompleted(s.disputeWindow)
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Save updated order
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to mark order as completed by prophet: %w", err)
+		}
+
+		if order.SessionsCompleted > completedBefore {
+			return s.publishSessionCompleted(ctx, order)
+		}
+
+		return nil
+	})
+}
+
+func (s *OrderService) CancelOrder(ctx context.Context, cmd inbound.CancelOrderCommand) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// Resolve which side of the order the caller is on
+	var by domain.CancelledBy
+	switch domain.CancelledBy(cmd.Role) {
+	case domain.CancelledByCustomer:
+		if order.CustomerID != cmd.UserID {
+			return nil, domain.ErrNotOrderParticipant
+		}
+		by = domain.CancelledByCustomer
+	case domain.CancelledByProphet:
+		prophetID, err := s.prophetOf(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		if prophetID != cmd.UserID {
+			return nil, domain.ErrNotOrderParticipant
+		}
+		by = domain.CancelledByProphet
+	default:
+		return nil, domain.ErrInvalidCanceller
+	}
+
+	previousStatus := order.Status
+	if err := order.Cancel(by, cmd.Reason); err != nil {
+		return nil, err
+	}
+
+	// Payment-service refunds or voids the payment when it sees the event
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to cancel order: %w", err)
+		}
+
+		if err := s.releasePromotion(ctx, order); err != nil {
+			return err
+		}
+
+		if err := s.eventPublisher.PublishOrderCancelled(ctx, order, previousStatus); err != nil {
+			return fmt.Errorf("failed to publish order cancelled event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The session will not happen, so let someone else book it
+	releaseSlot(ctx, s.bookingProvider, order)
+
+	return order, nil
+}
+
+// CancelRefundedOrder brings the order in line with a payment an admin
+// refunded: a reading not done yet is cancelled, freeing its slot and
+// promotion. Orders already cancelled were refunded because of that, and
+// readings that were done keep their order.
+func (s *OrderService) CancelRefundedOrder(ctx context.Context, orderID uuid.UUID) error {
+	var order *domain.Order
+	cancelled := false
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		previousStatus := order.Status
+		if err := order.Cancel(domain.CancelledByRefund, "payment was refunded"); err != nil {
+			if errors.Is(err, domain.ErrOrderAlreadyCancelled) || errors.Is(err, domain.ErrOrderNotCancellable) {
+				log.Printf("Payment for order %s refunded; order stays %s", orderID, previousStatus)
+				return nil
+			}
+			return err
+		}
+		cancelled = true
+
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to cancel order: %w", err)
+		}
+		if err := s.releasePromotion(ctx, order); err != nil {
+			return err
+		}
+		// Payment-service finds the payment refunded already
+		if err := s.eventPublisher.PublishOrderCancelled(ctx, order, previousStatus); err != nil {
+			return fmt.Errorf("failed to publish order cancelled event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if cancelled {
+		releaseSlot(ctx, s.bookingProvider, order)
+	}
+	return nil
+}
+
+// RescheduleOrder moves the customer's session to a new time before the
+// reading has started. The order stays locked while the booking moves, so a
+// concurrent cancellation waits and then releases the new booking.
+func (s *OrderService) RescheduleOrder(ctx context.Context, cmd inbound.RescheduleOrderCommand) (*domain.Order, error) {
+	var order *domain.Order
+	var previousStart *time.Time
+	moved := false
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if order.CustomerID != cmd.CustomerID {
+			return domain.ErrNotOrderParticipant
+		}
+		if !order.ReadingNotStarted() || !order.HasSessionInProgress() {
+			return domain.ErrOrderNotReschedulable
 		}
+		previousStart = order.SessionStartAt
+
+		// Course-service moves the order's booking and frees the old session
+		booking, err := s.bookingProvider.ReserveSlot(ctx, order, cmd.StartAt)
+		if err != nil {
+			return err
+		}
+		moved = true
+		order.AttachBooking(booking)
+
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to reschedule order: %w", err)
+		}
+		if err := s.eventPublisher.PublishOrderRescheduled(ctx, order, previousStart); err != nil {
+			return fmt.Errorf("failed to publish order rescheduled event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if moved && previousStart != nil {
+			// Put the booking back where the order still says it is
+			if _, restoreErr := s.bookingProvider.ReserveSlot(ctx, order, *previousStart); restoreErr != nil {
+				log.Printf("Failed to restore booking of order %s to %s: %v", order.OrderID, previousStart, restoreErr)
+			}
+		}
+		return nil, err
 	}
-	// Save updated order
+	return order, nil
+}
+
+// BookNextSession reserves the next session of a bundle once the previous one
+// is completed
+func (s *OrderService) BookNextSession(ctx context.Context, cmd inbound.BookSessionCommand) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.CustomerID != cmd.CustomerID {
+		return nil, domain.ErrNotOrderParticipant
+	}
+	if err := order.StartNextSession(); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.bookingProvider.ReserveSlot(ctx, order, cmd.StartAt)
+	if err != nil {
+		return nil, err
+	}
+	order.AttachBooking(booking)
+
 	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return fmt.Errorf("failed to mark order as completed by prophet: %w", err)
+		releaseSlot(ctx, s.bookingProvider, order)
+		return nil, fmt.Errorf("failed to book session: %w", err)
 	}
+	return order, nil
+}
 
+// OpenDispute holds a confirmed order so it is not auto-completed and the
+// prophet is not paid until the dispute is resolved
+func (s *OrderService) OpenDispute(ctx context.Context, cmd inbound.OpenDisputeCommand) (*domain.Order, error) {
+	var order *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the order so the auto-completer cannot complete it underneath us
+		var err error
+		order, err = s.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if order.CustomerID != cmd.CustomerID {
+			return domain.ErrNotOrderParticipant
+		}
+		if err := order.OpenDispute(cmd.Reason, time.Now()); err != nil {
+			return err
+		}
+
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to dispute order: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishOrderDisputed(ctx, order); err != nil {
+			return fmt.Errorf("failed to publish order disputed event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ResolveDispute closes a dispute. The customer may withdraw it, releasing the
+// payment to the prophet, and the prophet may accept it, refunding the customer.
+func (s *OrderService) ResolveDispute(ctx context.Context, cmd inbound.ResolveDisputeCommand) (*domain.Order, error) {
+	if cmd.Resolution != domain.DisputeResolutionReleased && cmd.Resolution != domain.DisputeResolutionRefunded {
+		return nil, domain.ErrInvalidResolution
+	}
+
+	var order *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		switch domain.CancelledBy(cmd.Role) {
+		case domain.CancelledByCustomer:
+			if order.CustomerID != cmd.UserID {
+				return domain.ErrNotOrderParticipant
+			}
+			if cmd.Resolution != domain.DisputeResolutionReleased {
+				return domain.ErrResolutionNotAllowed
+			}
+		case domain.CancelledByProphet:
+			prophetID, err := s.prophetOf(ctx, order)
+			if err != nil {
+				return err
+			}
+			if prophetID != cmd.UserID {
+				return domain.ErrNotOrderParticipant
+			}
+			if cmd.Resolution != domain.DisputeResolutionRefunded {
+				return domain.ErrResolutionNotAllowed
+			}
+		default:
+			return domain.ErrNotOrderParticipant
+		}
+
+		if err := order.ResolveDispute(cmd.Resolution, cmd.UserID, cmd.Note); err != nil {
+			return err
+		}
+
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to resolve dispute: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishOrderDisputeResolved(ctx, order); err != nil {
+			return fmt.Errorf("failed to publish order dispute resolved event: %w", err)
+		}
+
+		// Payment-service settles on session completion and refunds on order
+		// cancelled, so the outcome is published as the usual event as well
+		if order.Status != domain.StatusCancelled {
+			if err := s.publishSessionCompleted(ctx, order); err != nil {
+				return err
+			}
+		} else {
+			if err := s.releasePromotion(ctx, order); err != nil {
+				return err
+			}
+			if err := s.eventPublisher.PublishOrderCancelled(ctx, order, domain.StatusDisputed); err != nil {
+				return fmt.Errorf("failed to publish order cancelled event: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == domain.StatusCancelled {
+		releaseSlot(ctx, s.bookingProvider, order)
+	}
+
+	return order, nil
+}
+
+// AutoCompleteDueOrders completes sessions the customer neither confirmed nor
+// disputed before the dispute window ran out
+func (s *OrderService) AutoCompleteDueOrders(ctx context.Context, now time.Time, limit int) (int, error) {
+	// Sessions completed without a deadline would otherwise never come due
+	if backfilled, err := s.orderRepo.BackfillAutoCompleteAt(ctx, s.disputeWindow); err != nil {
+		return 0, fmt.Errorf("failed to backfill auto-completion deadlines: %w", err)
+	} else if backfilled > 0 {
+		log.Printf("Backfilled auto-completion deadline of %d orders", backfilled)
+	}
+
+	due, err := s.orderRepo.GetDueForAutoCompletion(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get orders due for auto-completion: %w", err)
+	}
+
+	completed := 0
+	for _, candidate := range due {
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Re-read under lock; the customer may have disputed or confirmed since
+			order, err := s.orderRepo.GetByIDForUpdate(ctx, candidate.OrderID)
+			if err != nil {
+				return fmt.Errorf("failed to get order: %w", err)
+			}
+			if !order.AutoComplete(now) {
+				return nil
+			}
+
+			if err := s.orderRepo.Update(ctx, order); err != nil {
+				return fmt.Errorf("failed to auto-complete order: %w", err)
+			}
+
+			if err := s.publishSessionCompleted(ctx, order); err != nil {
+				return err
+			}
+
+			completed++
+			return nil
+		})
+		if err != nil {
+			return completed, fmt.Errorf("order %s: %w", candidate.OrderID, err)
+		}
+	}
+
+	return completed, nil
+}
+
+// publishSessionCompleted reports the session the order just completed.
+// Payment-service settles every session; the last one completes the order.
+func (s *OrderService) publishSessionCompleted(ctx context.Context, order *domain.Order) error {
+	if order.Status == domain.StatusCompleted {
+		if err := s.eventPublisher.PublishOrderCompleted(ctx, order); err != nil {
+			return fmt.Errorf("failed to publish order completed event: %w", err)
+		}
+		return nil
+	}
+	if err := s.eventPublisher.PublishOrderSessionCompleted(ctx, order); err != nil {
+		return fmt.Errorf("failed to publish order session completed event: %w", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// prophetOf returns the prophet who owns the order's course. Orders placed
+// before price snapshots have no prophet recorded, so course-service is asked.
+func (s *OrderService) prophetOf(ctx context.Context, order *domain.Order) (string, error) {
+	if order.ProphetID != "" {
+		return order.ProphetID, nil
+	}
+	prophetID, err := s.courseProvider.GetProphetID(ctx, order.CourseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve prophet for order: %w", err)
+	}
+	return prophetID, nil
+}
+
+func releaseSlot(ctx context.Context, bookingProvider outbound.BookingProvider, order *domain.Order) {
+	if order.BookingID == "" {
+		return
+	}
+	if err := bookingProvider.ReleaseSlot(ctx, order.BookingID); err != nil {
+		log.Printf("Failed to release booking %s for order %s: %v", order.BookingID, order.OrderID, err)
+	}
+}
@@ -0,0 +1,269 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+)
+
+func TestOrderServiceCancelOrder(t *testing.T) {
+	started := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		order   func() *domain.Order
+		userID  string
+		role    string
+		wantErr error
+	}{
+		{name: "customer", order: func() *domain.Order { return confirmedOrder("customer-1", "prophet-1") }, userID: "customer-1", role: "customer"},
+		{name: "prophet", order: func() *domain.Order { return confirmedOrder("customer-1", "prophet-1") }, userID: "prophet-1", role: "prophet"},
+		{
+			name: "prophet once the session started",
+			order: func() *domain.Order {
+				order := confirmedOrder("customer-1", "prophet-1")
+				order.SessionStartAt = &started
+				return order
+			},
+			userID: "prophet-1",
+			role:   "prophet",
+		},
+		{
+			name: "customer once the session started",
+			order: func() *domain.Order {
+				order := confirmedOrder("customer-1", "prophet-1")
+				order.SessionStartAt = &started
+				return order
+			},
+			userID:  "customer-1",
+			role:    "customer",
+			wantErr: domain.ErrOrderNotCancellable,
+		},
+		{name: "another customer", order: func() *domain.Order { return confirmedOrder("customer-1", "prophet-1") }, userID: "customer-2", role: "customer", wantErr: domain.ErrNotOrderParticipant},
+		{name: "another prophet", order: func() *domain.Order { return confirmedOrder("customer-1", "prophet-1") }, userID: "prophet-2", role: "prophet", wantErr: domain.ErrNotOrderParticipant},
+		{name: "admin role", order: func() *domain.Order { return confirmedOrder("customer-1", "prophet-1") }, userID: "admin-1", role: "admin", wantErr: domain.ErrInvalidCanceller},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order()
+			promotion := &domain.Promotion{ID: order.OrderID}
+			order.PromotionID = &promotion.ID
+			f := newOrderFixture(order)
+			f.promotions.AddRedemption(context.Background(), &domain.PromotionRedemption{PromotionID: promotion.ID, OrderID: order.OrderID, CustomerID: order.CustomerID})
+
+			_, err := f.service.CancelOrder(context.Background(), inbound.CancelOrderCommand{OrderID: order.OrderID, UserID: tt.userID, Role: tt.role, Reason: "cannot make it"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CancelOrder() error = %v, want %v", err, tt.wantErr)
+			}
+
+			saved := f.orders.saved(order.OrderID)
+			if tt.wantErr != nil {
+				if saved.Status != domain.StatusConfirmed || len(f.publisher.events) != 0 || len(f.bookings.released) != 0 || len(f.promotions.redemptions) != 1 {
+					t.Errorf("failed cancel left status %s, events %v, released %v, %d redemptions", saved.Status, f.publisher.events, f.bookings.released, len(f.promotions.redemptions))
+				}
+				return
+			}
+			if saved.Status != domain.StatusCancelled || saved.CancelledBy != domain.CancelledBy(tt.role) {
+				t.Errorf("saved order is %s, cancelled by %q", saved.Status, saved.CancelledBy)
+			}
+			if got := fmt.Sprint(f.publisher.events); got != "[cancelled from CONFIRMED]" {
+				t.Errorf("published %s, want the cancellation", got)
+			}
+			if got := fmt.Sprint(f.bookings.released); got != "[booking-1]" {
+				t.Errorf("released %s, want the order's booking", got)
+			}
+			if len(f.promotions.redemptions) != 0 {
+				t.Errorf("promotion redemption was not released")
+			}
+		})
+	}
+}
+
+func TestOrderServiceCancelRefundedOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       domain.OrderStatus
+		prophetDone  bool
+		wantStatus   domain.OrderStatus
+		wantEvents   string
+		wantReleased string
+	}{
+		{name: "confirmed reading", status: domain.StatusConfirmed, wantStatus: domain.StatusCancelled, wantEvents: "[cancelled from CONFIRMED]", wantReleased: "[booking-1]"},
+		{name: "pending payment", status: domain.StatusPending, wantStatus: domain.StatusCancelled, wantEvents: "[cancelled from PENDING]", wantReleased: "[booking-1]"},
+		{name: "reading marked done", status: domain.StatusConfirmed, prophetDone: true, wantStatus: domain.StatusConfirmed, wantEvents: "[]", wantReleased: "[]"},
+		{name: "completed", status: domain.StatusCompleted, wantStatus: domain.StatusCompleted, wantEvents: "[]", wantReleased: "[]"},
+		{name: "already cancelled", status: domain.StatusCancelled, wantStatus: domain.StatusCancelled, wantEvents: "[]", wantReleased: "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := confirmedOrder("customer-1", "prophet-1")
+			order.Status = tt.status
+			order.IsProphetCompleted = tt.prophetDone
+			f := newOrderFixture(order)
+
+			if err := f.service.CancelRefundedOrder(context.Background(), order.OrderID); err != nil {
+				t.Fatalf("CancelRefundedOrder() error = %v", err)
+			}
+
+			saved := f.orders.saved(order.OrderID)
+			if saved.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", saved.Status, tt.wantStatus)
+			}
+			if tt.wantStatus == domain.StatusCancelled && tt.status != domain.StatusCancelled && saved.CancelledBy != domain.CancelledByRefund {
+				t.Errorf("cancelled by %q, want %q", saved.CancelledBy, domain.CancelledByRefund)
+			}
+			if got := fmt.Sprint(f.publisher.events); got != tt.wantEvents {
+				t.Errorf("published %s, want %s", got, tt.wantEvents)
+			}
+			if got := fmt.Sprint(f.bookings.released); got != tt.wantReleased {
+				t.Errorf("released %s, want %s", got, tt.wantReleased)
+			}
+		})
+	}
+}
+
+func TestOrderServiceCancelRefundedOrderUnknown(t *testing.T) {
+	f := newOrderFixture()
+	order := confirmedOrder("customer-1", "prophet-1")
+	if err := f.service.CancelRefundedOrder(context.Background(), order.OrderID); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Errorf("CancelRefundedOrder() error = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+}
+
+func TestOrderServiceOpenDispute(t *testing.T) {
+	tests := []struct {
+		name       string
+		customerID string
+		wantErr    error
+	}{
+		{name: "customer", customerID: "customer-1"},
+		{name: "someone else", customerID: "customer-2", wantErr: domain.ErrNotOrderParticipant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := confirmedOrder("customer-1", "prophet-1")
+			order.MarkProphetCompleted(time.Hour)
+			f := newOrderFixture(order)
+
+			_, err := f.service.OpenDispute(context.Background(), inbound.OpenDisputeCommand{OrderID: order.OrderID, CustomerID: tt.customerID, Reason: "no show"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenDispute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			saved := f.orders.saved(order.OrderID)
+			wantStatus, wantEvents := domain.StatusDisputed, "[disputed]"
+			if tt.wantErr != nil {
+				wantStatus, wantEvents = domain.StatusConfirmed, "[]"
+			}
+			if saved.Status != wantStatus {
+				t.Errorf("status = %s, want %s", saved.Status, wantStatus)
+			}
+			if got := fmt.Sprint(f.publisher.events); got != wantEvents {
+				t.Errorf("published %s, want %s", got, wantEvents)
+			}
+		})
+	}
+}
+
+func TestOrderServiceResolveDispute(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		role         string
+		resolution   domain.DisputeResolution
+		wantErr      error
+		wantStatus   domain.OrderStatus
+		wantEvents   string
+		wantReleased string
+	}{
+		{
+			name: "customer withdraws", userID: "customer-1", role: "customer", resolution: domain.DisputeResolutionReleased,
+			wantStatus: domain.StatusCompleted, wantEvents: "[dispute_resolved completed]", wantReleased: "[]",
+		},
+		{
+			name: "prophet accepts", userID: "prophet-1", role: "prophet", resolution: domain.DisputeResolutionRefunded,
+			wantStatus: domain.StatusCancelled, wantEvents: "[dispute_resolved cancelled from DISPUTED]", wantReleased: "[booking-1]",
+		},
+		{name: "customer cannot refund themselves", userID: "customer-1", role: "customer", resolution: domain.DisputeResolutionRefunded, wantErr: domain.ErrResolutionNotAllowed},
+		{name: "prophet cannot release the payment", userID: "prophet-1", role: "prophet", resolution: domain.DisputeResolutionReleased, wantErr: domain.ErrResolutionNotAllowed},
+		{name: "another prophet", userID: "prophet-2", role: "prophet", resolution: domain.DisputeResolutionRefunded, wantErr: domain.ErrNotOrderParticipant},
+		{name: "unknown role", userID: "admin-1", role: "admin", resolution: domain.DisputeResolutionReleased, wantErr: domain.ErrNotOrderParticipant},
+		{name: "unknown resolution", userID: "customer-1", role: "customer", resolution: domain.DisputeResolution("SPLIT"), wantErr: domain.ErrInvalidResolution},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := confirmedOrder("customer-1", "prophet-1")
+			order.MarkProphetCompleted(time.Hour)
+			if err := order.OpenDispute("no show", time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			f := newOrderFixture(order)
+
+			_, err := f.service.ResolveDispute(context.Background(), inbound.ResolveDisputeCommand{OrderID: order.OrderID, UserID: tt.userID, Role: tt.role, Resolution: tt.resolution})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveDispute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			saved := f.orders.saved(order.OrderID)
+			if tt.wantErr != nil {
+				if saved.Status != domain.StatusDisputed || len(f.publisher.events) != 0 {
+					t.Errorf("failed resolution left status %s and published %v", saved.Status, f.publisher.events)
+				}
+				return
+			}
+			if saved.Status != tt.wantStatus || saved.DisputeResolution != tt.resolution {
+				t.Errorf("order is %s resolved %q, want %s resolved %q", saved.Status, saved.DisputeResolution, tt.wantStatus, tt.resolution)
+			}
+			if got := fmt.Sprint(f.publisher.events); got != tt.wantEvents {
+				t.Errorf("published %s, want %s", got, tt.wantEvents)
+			}
+			if got := fmt.Sprint(f.bookings.released); got != tt.wantReleased {
+				t.Errorf("released %s, want %s", got, tt.wantReleased)
+			}
+		})
+	}
+}
+
+func TestOrderServiceAutoCompleteDueOrders(t *testing.T) {
+	due := confirmedOrder("customer-1", "prophet-1")
+	due.MarkProphetCompleted(-time.Minute)
+	waiting := confirmedOrder("customer-2", "prophet-1")
+	waiting.MarkProphetCompleted(time.Hour)
+	disputed := confirmedOrder("customer-3", "prophet-1")
+	disputed.MarkProphetCompleted(time.Hour)
+	if err := disputed.OpenDispute("no show", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	disputed.AutoCompleteAt = at(time.Now().Add(-time.Minute))
+	f := newOrderFixture(due, waiting, disputed)
+
+	completed, err := f.service.AutoCompleteDueOrders(context.Background(), time.Now(), 10)
+	if err != nil {
+		t.Fatalf("AutoCompleteDueOrders() error = %v", err)
+	}
+	if completed != 1 {
+		t.Errorf("completed %d orders, want 1", completed)
+	}
+
+	if saved := f.orders.saved(due.OrderID); saved.Status != domain.StatusCompleted || !saved.AutoCompleted {
+		t.Errorf("due order is %s, auto-completed %v", saved.Status, saved.AutoCompleted)
+	}
+	if saved := f.orders.saved(waiting.OrderID); saved.Status != domain.StatusConfirmed {
+		t.Errorf("order inside its dispute window is %s", saved.Status)
+	}
+	if saved := f.orders.saved(disputed.OrderID); saved.Status != domain.StatusDisputed {
+		t.Errorf("disputed order is %s", saved.Status)
+	}
+	if got := fmt.Sprint(f.publisher.events); got != "[completed]" || f.publisher.orders[0].OrderID != due.OrderID {
+		t.Errorf("published %s, want the due order completed", got)
+	}
+}
+
+func at(t time.Time) *time.Time {
+	return &t
+}
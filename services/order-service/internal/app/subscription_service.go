@@ -0,0 +1,364 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/services/order-service/internal/ports/inbound"
+	"github.com/wnmay/horo/services/order-service/internal/ports/outbound"
+)
+
+type SubscriptionService struct {
+	subscriptionRepo outbound.SubscriptionRepository
+	orderRepo        outbound.OrderRepository
+	txManager        outbound.TransactionManager
+	eventPublisher   outbound.EventPublisher
+	paymentService   outbound.PaymentService
+	courseProvider   outbound.CourseProvider
+	bookingProvider  outbound.BookingProvider
+	rateProvider     outbound.ExchangeRateProvider
+	// grace is how long the customer has to pay for a period before the
+	// subscription lapses
+	grace time.Duration
+}
+
+func NewSubscriptionService(
+	subscriptionRepo outbound.SubscriptionRepository,
+	orderRepo outbound.OrderRepository,
+	txManager outbound.TransactionManager,
+	eventPublisher outbound.EventPublisher,
+	paymentService outbound.PaymentService,
+	courseProvider outbound.CourseProvider,
+	bookingProvider outbound.BookingProvider,
+	rateProvider outbound.ExchangeRateProvider,
+	grace time.Duration,
+) inbound.SubscriptionService {
+	return &SubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		orderRepo:        orderRepo,
+		txManager:        txManager,
+		eventPublisher:   eventPublisher,
+		paymentService:   paymentService,
+		courseProvider:   courseProvider,
+		bookingProvider:  bookingProvider,
+		rateProvider:     rateProvider,
+		grace:            grace,
+	}
+}
+
+// Subscribe subscribes the customer to the course and places the order for
+// the first period. It is paid like any other order; the period starts once
+// the payment completes.
+func (s *SubscriptionService) Subscribe(ctx context.Context, cmd inbound.SubscribeCommand) (*domain.Subscription, *domain.Order, error) {
+	course, err := s.courseProvider.GetCourseSnapshot(ctx, cmd.CourseID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCourseUnavailable) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if err := course.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	rate, err := chargeRate(ctx, s.rateProvider, course, cmd.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription := domain.NewSubscription(cmd.CustomerID, course, cmd.RoomID, rate.To, s.grace)
+	order := domain.NewOrder(cmd.CustomerID, subscription.Course(), cmd.RoomID, rate)
+	order.AttachSubscription(subscription, 1)
+	subscription.AwaitPayment(order.OrderID)
+
+	booking, err := s.bookingProvider.ReserveSlot(ctx, order, cmd.StartAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	order.AttachBooking(booking)
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+			return fmt.Errorf("failed to create subscription: %w", err)
+		}
+
+		if err := s.orderRepo.Create(ctx, order); err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		// Payment-service creates the first period's payment from this event
+		if err := s.eventPublisher.PublishOrderCreated(ctx, order); err != nil {
+			return fmt.Errorf("failed to publish order created event: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		releaseSlot(ctx, s.bookingProvider, order)
+		return nil, nil, err
+	}
+
+	return subscription, order, nil
+}
+
+// GetSubscription returns the subscription and the orders for its periods to
+// the subscriber, the prophet and admins
+func (s *SubscriptionService) GetSubscription(ctx context.Context, subscriptionID uuid.UUID, userID string, role string) (*domain.Subscription, []*domain.Order, error) {
+	subscription, err := s.subscriptionRepo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSubscriptionNotFound) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if role != roleAdmin && subscription.CustomerID != userID && subscription.ProphetID != userID {
+		return nil, nil, domain.ErrNotSubscriber
+	}
+
+	orders, err := s.orderRepo.GetBySubscriptionID(ctx, subscription.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get orders for subscription: %w", err)
+	}
+	return subscription, orders, nil
+}
+
+// ListSubscriptions returns a prophet the subscriptions to their courses and
+// anyone else their own subscriptions
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context, userID string, role string) ([]*domain.Subscription, error) {
+	var (
+		subscriptions []*domain.Subscription
+		err           error
+	)
+	if role == roleProphet {
+		subscriptions, err = s.subscriptionRepo.ListByProphet(ctx, userID)
+	} else {
+		subscriptions, err = s.subscriptionRepo.ListByCustomer(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// CancelSubscription stops the subscription from renewing. A paid period runs
+// out first; an unpaid one is cancelled with its order straight away.
+func (s *SubscriptionService) CancelSubscription(ctx context.Context, subscriptionID uuid.UUID, customerID string) (*domain.Subscription, error) {
+	var (
+		subscription *domain.Subscription
+		cancelled    *domain.Order
+	)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		subscription, err = s.subscriptionRepo.GetByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			if errors.Is(err, domain.ErrSubscriptionNotFound) {
+				return err
+			}
+			return fmt.Errorf("failed to get subscription: %w", err)
+		}
+		if subscription.CustomerID != customerID {
+			return domain.ErrNotSubscriber
+		}
+
+		pending, err := subscription.Cancel(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return fmt.Errorf("failed to cancel subscription: %w", err)
+		}
+
+		if !subscription.Ended() {
+			return nil
+		}
+
+		cancelled, err = s.cancelPendingOrder(ctx, pending, "subscription cancelled")
+		if err != nil {
+			return err
+		}
+
+		if err := s.eventPublisher.PublishSubscriptionLapsed(ctx, subscription, domain.LapseReasonCancelled); err != nil {
+			return fmt.Errorf("failed to publish subscription lapsed event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cancelled != nil {
+		releaseSlot(ctx, s.bookingProvider, cancelled)
+	}
+	return subscription, nil
+}
+
+// RenewDueSubscriptions moves due subscriptions on: a paid period that ran
+// out is renewed with a new order, or ended if the customer cancelled, and a
+// period left unpaid past its grace lapses the subscription. Renewal payments
+// are issued through payment-service once the renewal is recorded; one that
+// fails is retried on the next run.
+func (s *SubscriptionService) RenewDueSubscriptions(ctx context.Context, now time.Time, limit int) (int, error) {
+	due, err := s.subscriptionRepo.GetDue(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get subscriptions due for renewal: %w", err)
+	}
+
+	processed := 0
+	for _, candidate := range due {
+		var (
+			subscription *domain.Subscription
+			cancelled    *domain.Order
+		)
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Re-read under lock; a payment or cancellation may have landed since
+			var err error
+			subscription, err = s.subscriptionRepo.GetByIDForUpdate(ctx, candidate.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get subscription: %w", err)
+			}
+
+			switch {
+			case subscription.RenewalDue(now) && subscription.CancelAtPeriodEnd:
+				subscription.Expire(now)
+				if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+					return fmt.Errorf("failed to end subscription: %w", err)
+				}
+				if err := s.eventPublisher.PublishSubscriptionLapsed(ctx, subscription, domain.LapseReasonCancelled); err != nil {
+					return fmt.Errorf("failed to publish subscription lapsed event: %w", err)
+				}
+
+			case subscription.RenewalDue(now):
+				if err := s.startRenewal(ctx, subscription); err != nil {
+					return err
+				}
+
+			case subscription.LapseDue(now):
+				pending := subscription.Lapse(now)
+				if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+					return fmt.Errorf("failed to lapse subscription: %w", err)
+				}
+				cancelled, err = s.cancelPendingOrder(ctx, pending, "subscription period not paid")
+				if err != nil {
+					return err
+				}
+				if err := s.eventPublisher.PublishSubscriptionLapsed(ctx, subscription, domain.LapseReasonUnpaid); err != nil {
+					return fmt.Errorf("failed to publish subscription lapsed event: %w", err)
+				}
+
+			case subscription.Status == domain.SubscriptionPastDue && subscription.PendingPaymentID == "":
+				// Renewed on an earlier run whose payment was not issued
+
+			default:
+				return nil
+			}
+
+			processed++
+			return nil
+		})
+		if err != nil {
+			return processed, fmt.Errorf("subscription %s: %w", candidate.ID, err)
+		}
+
+		if cancelled != nil {
+			releaseSlot(ctx, s.bookingProvider, cancelled)
+		}
+
+		if subscription.Status == domain.SubscriptionPastDue && subscription.PendingPaymentID == "" {
+			if err := s.issueRenewalPayment(ctx, subscription); err != nil {
+				log.Printf("Failed to issue renewal payment for subscription %s: %v", subscription.ID, err)
+			}
+		}
+	}
+
+	return processed, nil
+}
+
+// startRenewal places the order for the subscription's next period at the
+// subscribed price, converted at today's rate
+func (s *SubscriptionService) startRenewal(ctx context.Context, subscription *domain.Subscription) error {
+	course := subscription.Course()
+	rate, err := chargeRate(ctx, s.rateProvider, course, subscription.ChargeCurrency)
+	if err != nil {
+		return err
+	}
+
+	order := domain.NewOrder(subscription.CustomerID, course, subscription.RoomID, rate)
+	order.AttachSubscription(subscription, subscription.Periods+1)
+	subscription.StartRenewal(order.OrderID, s.grace)
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return fmt.Errorf("failed to create renewal order: %w", err)
+	}
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return fmt.Errorf("failed to renew subscription: %w", err)
+	}
+	return nil
+}
+
+// issueRenewalPayment asks payment-service for the pending period's payment
+// and records it on the subscription. Payment-service reuses the payment if
+// an earlier attempt created it.
+func (s *SubscriptionService) issueRenewalPayment(ctx context.Context, subscription *domain.Subscription) error {
+	order, err := s.orderRepo.GetByID(ctx, *subscription.PendingOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get renewal order: %w", err)
+	}
+
+	payment, err := s.paymentService.CreatePayment(ctx, order.OrderID, order.ChargeAmount, order.ChargeCurrency)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.subscriptionRepo.GetByIDForUpdate(ctx, subscription.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		}
+		if current.PendingOrderID == nil || *current.PendingOrderID != order.OrderID {
+			return nil
+		}
+
+		current.PendingPaymentID = payment.PaymentID
+		if err := s.subscriptionRepo.Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to record renewal payment: %w", err)
+		}
+		return nil
+	})
+}
+
+// cancelPendingOrder cancels the unpaid order of a subscription that ended.
+// It returns the cancelled order, or nil when there was nothing to cancel.
+func (s *SubscriptionService) cancelPendingOrder(ctx context.Context, orderID *uuid.UUID, reason string) (*domain.Order, error) {
+	if orderID == nil {
+		return nil, nil
+	}
+
+	order, err := s.orderRepo.GetByIDForUpdate(ctx, *orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// A failed payment may have cancelled the order already
+	previousStatus := order.Status
+	if err := order.Cancel(domain.CancelledBySubscription, reason); err != nil {
+		if errors.Is(err, domain.ErrOrderAlreadyCancelled) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	if err := s.eventPublisher.PublishOrderCancelled(ctx, order, previousStatus); err != nil {
+		return nil, fmt.Errorf("failed to publish order cancelled event: %w", err)
+	}
+	return order, nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidOrderStatus   = errors.New("status must be PENDING, CONFIRMED, CANCELLED, COMPLETED or DISPUTED")
+	ErrTransitionNotAllowed = errors.New("order cannot be moved to that status")
+	ErrAuditReasonRequired  = errors.New("a reason is required")
+)
+
+// ParseOrderStatus checks status is one an order can have, ignoring case
+func ParseOrderStatus(status string) (OrderStatus, error) {
+	switch s := OrderStatus(strings.ToUpper(status)); s {
+	case StatusPending, StatusConfirmed, StatusCancelled, StatusCompleted, StatusDisputed:
+		return s, nil
+	}
+	return "", ErrInvalidOrderStatus
+}
+
+// OrderAudit records an admin forcing an order from one status to another,
+// and why
+type OrderAudit struct {
+	ID         uuid.UUID   `json:"id"`
+	OrderID    uuid.UUID   `json:"order_id"`
+	AdminID    string      `json:"admin_id"`
+	FromStatus OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus `json:"to_status"`
+	Reason     string      `json:"reason"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// NewOrderAudit records the order's move out of from, which must already
+// have been made
+func NewOrderAudit(order *Order, from OrderStatus, adminID, reason string) *OrderAudit {
+	return &OrderAudit{
+		ID:         uuid.New(),
+		OrderID:    order.OrderID,
+		AdminID:    adminID,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+}
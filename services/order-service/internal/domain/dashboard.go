@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+// DefaultTopProphets is how many prophets the dashboard ranks by default
+const DefaultTopProphets = 5
+
+// Dashboard summarises the orders placed between From and To for admins
+type Dashboard struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// GMV is the value charged for orders that were paid for, whether
+	// completed, still running or disputed, in each charge currency
+	GMV            []*CurrencySales    `json:"gmv"`
+	OrdersByStatus map[OrderStatus]int `json:"orders_by_status"`
+	// TopProphets ranks prophets by GMV within each charge currency, since
+	// amounts in different currencies do not compare
+	TopProphets []*ProphetSales `json:"top_prophets"`
+}
+
+// CurrencySales is the value of paid orders in one currency
+type CurrencySales struct {
+	Currency string        `json:"currency"`
+	Amount   money.Decimal `json:"amount"`
+	Orders   int           `json:"orders"`
+}
+
+// ProphetSales is the value of a prophet's paid orders in one currency
+type ProphetSales struct {
+	ProphetID string        `json:"prophet_id"`
+	Currency  string        `json:"currency"`
+	Amount    money.Decimal `json:"amount"`
+	Orders    int           `json:"orders"`
+}
+
+// PaidStatuses are the statuses of orders counted towards GMV
+var PaidStatuses = []OrderStatus{StatusConfirmed, StatusCompleted, StatusDisputed}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+// IdentityRateSource marks orders charged in the course's own currency
+const IdentityRateSource = "identity"
+
+var ErrRateUnavailable = errors.New("no exchange rate for the requested currency")
+
+// ExchangeRate converts an amount in From into To: 1 From = Rate To
+type ExchangeRate struct {
+	From   string
+	To     string
+	Rate   money.Decimal
+	Source string
+	AsOf   time.Time
+}
+
+// IdentityRate charges an amount in the currency it is priced in
+func IdentityRate(currency string) *ExchangeRate {
+	return &ExchangeRate{
+		From:   currency,
+		To:     currency,
+		Rate:   money.NewFromInt(1),
+		Source: IdentityRateSource,
+		AsOf:   time.Now(),
+	}
+}
+
+// Convert converts amount and rounds it to the minor unit of To
+func (r *ExchangeRate) Convert(amount money.Decimal) money.Decimal {
+	return money.RoundTo(amount.Mul(r.Rate), r.To)
+}
@@ -1,9 +1,11 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type OrderStatus string
@@ -12,6 +14,56 @@ const (
 	StatusConfirmed OrderStatus = "CONFIRMED"
 	StatusCancelled OrderStatus = "CANCELLED"
 	StatusCompleted  OrderStatus = "COMPLETED"
+	StatusDisputed  OrderStatus = "DISPUTED"
+)
+
+// CancelledBy identifies which party cancelled an order
+type CancelledBy string
+const (
+	CancelledByCustomer CancelledBy = "customer"
+	CancelledByProphet  CancelledBy = "prophet"
+	// CancelledByDispute marks orders cancelled by a refunded dispute
+	CancelledByDispute  CancelledBy = "dispute"
+	// CancelledBySubscription marks unpaid periods of an ended subscription
+	CancelledBySubscription CancelledBy = "subscription"
+	// CancelledByAdmin marks orders an admin forced into CANCELLED
+	CancelledByAdmin CancelledBy = "admin"
+	// CancelledByRefund marks orders whose payment an admin refunded before
+	// the reading took place
+	CancelledByRefund CancelledBy = "refund"
+)
+
+// DisputeResolution is the outcome of a dispute
+type DisputeResolution string
+const (
+	// DisputeResolutionReleased lets the session stand and pays the prophet
+	DisputeResolutionReleased DisputeResolution = "RELEASED"
+	// DisputeResolutionRefunded cancels the order and refunds the customer
+	DisputeResolutionRefunded DisputeResolution = "REFUNDED"
+)
+
+// DefaultCurrency is the currency of courses that declare none
+const DefaultCurrency = money.DefaultCurrency
+
+var (
+	ErrOrderNotFound         = errors.New("order not found")
+	ErrCourseUnavailable     = errors.New("course is not available for ordering")
+	ErrSlotUnavailable       = errors.New("requested session time is not available")
+	ErrSlotTaken             = errors.New("requested session time is already booked")
+	ErrOrderAlreadyCancelled = errors.New("order is already cancelled")
+	ErrOrderNotCancellable   = errors.New("order can no longer be cancelled by the customer")
+	ErrInvalidCanceller      = errors.New("only the customer or the prophet can cancel an order")
+	ErrNotOrderParticipant   = errors.New("user is not a participant of this order")
+	ErrOrderNotReschedulable = errors.New("order can no longer be rescheduled")
+	ErrOrderNotDisputable    = errors.New("order can no longer be disputed")
+	ErrDisputeWindowClosed   = errors.New("dispute window has closed")
+	ErrOrderNotDisputed      = errors.New("order is not disputed")
+	ErrInvalidResolution     = errors.New("resolution must be RELEASED or REFUNDED")
+	ErrResolutionNotAllowed  = errors.New("user cannot resolve the dispute this way")
+	ErrNoSessionBooked       = errors.New("order has no session booked")
+	ErrOrderNotBookable      = errors.New("order is not open for booking sessions")
+	ErrSessionInProgress     = errors.New("current session must be completed before booking the next one")
+	ErrNoSessionsLeft        = errors.New("order has no sessions left to book")
 )
 
 type Order struct {
@@ -26,14 +78,110 @@ type Order struct {
 	ProphetCompletedAt   *time.Time  `json:"prophet_completed_at,omitempty"`
 	OrderDate            time.Time   `json:"order_date"`
 	RoomID				 string   `json:"room_id"`
+	CancelledBy          CancelledBy `json:"cancelled_by,omitempty"`
+	CancelReason         string      `json:"cancel_reason,omitempty"`
+	CancelledAt          *time.Time  `json:"cancelled_at,omitempty"`
+	// Course details as the customer saw them when ordering. Events carry
+	// these values so the charged amount never drifts from the quoted price.
+	CourseName           string      `json:"course_name"`
+	CourseType           string      `json:"course_type,omitempty"`
+	ProphetID            string      `json:"prophet_id"`
+	Price                money.Decimal `json:"price"`
+	Currency             string      `json:"currency"`
+	// Promotion applied at order time. DiscountAmount is in Currency and is
+	// taken off Price before conversion.
+	PromotionID          *uuid.UUID  `json:"promotion_id,omitempty"`
+	CouponCode           string      `json:"coupon_code,omitempty"`
+	DiscountAmount       money.Decimal `json:"discount_amount"`
+	// What the customer is charged, converted from Price at order time.
+	// ExchangeRate is units of ChargeCurrency per unit of Currency.
+	ChargeAmount         money.Decimal `json:"charge_amount"`
+	ChargeCurrency       string      `json:"charge_currency"`
+	ExchangeRate         money.Decimal `json:"exchange_rate"`
+	RateSource           string      `json:"rate_source"`
+	RateAsOf             *time.Time  `json:"rate_as_of,omitempty"`
+	DurationMinutes      int         `json:"duration_minutes"`
+	// Set on orders billing a period of a subscription
+	SubscriptionID       *uuid.UUID  `json:"subscription_id,omitempty"`
+	SubscriptionPeriod   int         `json:"subscription_period,omitempty"`
+	// Bundles buy several sessions in one order. SessionsBooked counts the
+	// sessions reserved so far, the current one included; each session is
+	// completed, and settled, on its own.
+	Sessions             int         `json:"sessions"`
+	SessionsBooked       int         `json:"sessions_booked"`
+	SessionsCompleted    int         `json:"sessions_completed"`
+	// Current session reserved with course-service. Times are UTC;
+	// SessionTimezone is the prophet's timezone for rendering.
+	BookingID            string      `json:"booking_id,omitempty"`
+	SessionStartAt       *time.Time  `json:"session_start_at,omitempty"`
+	SessionEndAt         *time.Time  `json:"session_end_at,omitempty"`
+	SessionTimezone      string      `json:"session_timezone,omitempty"`
+	// Set when the prophet marks the session done. Unless the customer
+	// confirms or disputes first, the order completes automatically then.
+	AutoCompleteAt       *time.Time  `json:"auto_complete_at,omitempty"`
+	AutoCompleted        bool        `json:"auto_completed"`
+	DisputeReason        string      `json:"dispute_reason,omitempty"`
+	DisputedAt           *time.Time  `json:"disputed_at,omitempty"`
+	DisputeResolution    DisputeResolution `json:"dispute_resolution,omitempty"`
+	DisputeResolvedBy    string      `json:"dispute_resolved_by,omitempty"`
+	DisputeNote          string      `json:"dispute_note,omitempty"`
+	DisputeResolvedAt    *time.Time  `json:"dispute_resolved_at,omitempty"`
+}
+
+// SessionBooking is a session slot reserved for an order
+type SessionBooking struct {
+	BookingID string
+	StartAt   time.Time
+	EndAt     time.Time
+	Timezone  string
 }
 
-func NewOrder(customerID string, courseID string, roomID string) *Order {
+// CourseSnapshot holds the course details copied onto an order
+type CourseSnapshot struct {
+	CourseID        string
+	CourseName      string
+	CourseType      string
+	ProphetID       string
+	Price           money.Decimal
+	Currency        string
+	DurationMinutes int
+	Sessions        int
+}
+
+// Validate checks that the course can be ordered at its current price
+func (c *CourseSnapshot) Validate() error {
+	if c.CourseID == "" || c.ProphetID == "" || !c.Price.IsPositive() {
+		return ErrCourseUnavailable
+	}
+	return nil
+}
+
+// NewOrder places an order for the course, charging its price converted at
+// rate. The rate must convert from the course's currency.
+func NewOrder(customerID string, course *CourseSnapshot, roomID string, rate *ExchangeRate) *Order {
+	currency := course.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	asOf := rate.AsOf
 	return &Order{
 		OrderID:             uuid.New(),
 		CustomerID:          customerID,
-		CourseID:            courseID,
+		CourseID:            course.CourseID,
 		RoomID:              roomID,
+		CourseName:          course.CourseName,
+		CourseType:          course.CourseType,
+		ProphetID:           course.ProphetID,
+		Price:               course.Price,
+		Currency:            currency,
+		ChargeAmount:        rate.Convert(course.Price),
+		ChargeCurrency:      rate.To,
+		ExchangeRate:        rate.Rate,
+		RateSource:          rate.Source,
+		RateAsOf:            &asOf,
+		DurationMinutes:     course.DurationMinutes,
+		Sessions:            max(course.Sessions, 1),
+		SessionsBooked:      1,
 		Status:              StatusPending,
 		IsCustomerCompleted: false,
 		IsProphetCompleted:  false,
@@ -41,12 +189,143 @@ func NewOrder(customerID string, courseID string, roomID string) *Order {
 	}
 }
 
+// AttachSubscription makes the order bill the given period of a subscription.
+// Renewals are ordered without a session; the customer books it with
+// StartNextSession once the period is paid.
+func (o *Order) AttachSubscription(subscription *Subscription, period int) {
+	id := subscription.ID
+	o.SubscriptionID = &id
+	o.SubscriptionPeriod = period
+	if period > 1 {
+		o.SessionsBooked = 0
+	}
+}
+
+// ApplyPromotion takes the promotion's discount off the price and converts
+// what is left at the order's exchange rate. The charge is at least one minor
+// unit: an order is only confirmed once its payment is captured, and a free
+// one has nothing to capture.
+func (o *Order) ApplyPromotion(promotion *Promotion, discount money.Decimal, rate *ExchangeRate) {
+	id := promotion.ID
+	o.PromotionID = &id
+	o.CouponCode = promotion.Code
+	o.DiscountAmount = discount
+	o.ChargeAmount = money.Max(rate.Convert(o.Price.Sub(discount)), money.MinorUnit(o.ChargeCurrency))
+}
+
+// ChargeDiscount is the promotion discount in the charge currency, i.e. how
+// much less the customer pays than the converted list price
+func (o *Order) ChargeDiscount() money.Decimal {
+	if !o.DiscountAmount.IsPositive() {
+		return money.Zero
+	}
+	return money.RoundTo(o.Price.Mul(o.ExchangeRate), o.ChargeCurrency).Sub(o.ChargeAmount)
+}
+
+// SessionCount is how many sessions the order bought
+func (o *Order) SessionCount() int {
+	return max(o.Sessions, 1)
+}
+
+// CurrentSession is the number, starting at 1, of the session being booked
+// or held
+func (o *Order) CurrentSession() int {
+	return max(o.SessionsBooked, 1)
+}
+
+// HasSessionInProgress reports whether a booked session still awaits
+// completion
+func (o *Order) HasSessionInProgress() bool {
+	return o.SessionsBooked > o.SessionsCompleted
+}
+
+// StartNextSession moves a confirmed bundle on to its next session once the
+// previous one is completed. The slot is attached with AttachBooking.
+func (o *Order) StartNextSession() error {
+	if o.Status != StatusConfirmed {
+		return ErrOrderNotBookable
+	}
+	if o.HasSessionInProgress() {
+		return ErrSessionInProgress
+	}
+	if o.SessionsCompleted >= o.SessionCount() {
+		return ErrNoSessionsLeft
+	}
+	o.SessionsBooked = o.SessionsCompleted + 1
+	return nil
+}
+
+// AttachBooking records the reserved session on the order
+func (o *Order) AttachBooking(booking *SessionBooking) {
+	start, end := booking.StartAt.UTC(), booking.EndAt.UTC()
+	o.BookingID = booking.BookingID
+	o.SessionStartAt = &start
+	o.SessionEndAt = &end
+	o.SessionTimezone = booking.Timezone
+}
+
 func (o *Order) Confirm() {
 	o.Status = StatusConfirmed
 }
 
-func (o *Order) Cancel() {
+// Cancel cancels the order on behalf of the customer or the prophet. A customer
+// may only cancel before the reading has started, i.e. while the order is
+// pending or confirmed, its session start time has not come and neither side
+// has marked the session done. The prophet may cancel at any time.
+func (o *Order) Cancel(by CancelledBy, reason string) error {
+	if o.Status == StatusCancelled {
+		return ErrOrderAlreadyCancelled
+	}
+
+	switch by {
+	case CancelledByCustomer:
+		if o.SubscriptionID != nil {
+			return ErrSubscriptionOrder
+		}
+		if !o.ReadingNotStarted() {
+			return ErrOrderNotCancellable
+		}
+	case CancelledBySubscription:
+		// An ended subscription drops its unpaid period even once the
+		// session's start time has passed
+		if !o.sessionUnmarked() {
+			return ErrOrderNotCancellable
+		}
+	case CancelledByRefund:
+		// The payment is gone, so a reading not yet done will not be; one
+		// that was done keeps its order
+		if !o.sessionUnmarked() {
+			return ErrOrderNotCancellable
+		}
+	case CancelledByProphet:
+	default:
+		return ErrInvalidCanceller
+	}
+
+	now := time.Now()
 	o.Status = StatusCancelled
+	o.CancelledBy = by
+	o.CancelReason = reason
+	o.CancelledAt = &now
+	return nil
+}
+
+// ReadingNotStarted reports whether the session has not begun yet: neither
+// side has marked it done and its start time, if booked, is still ahead
+func (o *Order) ReadingNotStarted() bool {
+	if o.SessionStartAt != nil && !time.Now().Before(*o.SessionStartAt) {
+		return false
+	}
+	return o.sessionUnmarked()
+}
+
+// sessionUnmarked reports whether the order is pending or confirmed and
+// neither side has marked the session done
+func (o *Order) sessionUnmarked() bool {
+	if o.Status != StatusPending && o.Status != StatusConfirmed {
+		return false
+	}
+	return !o.IsCustomerCompleted && !o.IsProphetCompleted
 }
 
 func (o *Order) MarkCustomerCompleted() {
@@ -56,20 +335,152 @@ func (o *Order) MarkCustomerCompleted() {
 	o.checkAndMarkComplete()
 }
 
-func (o *Order) MarkProphetCompleted() {
+// MarkProphetCompleted records that the prophet delivered the session and
+// starts the customer's dispute window
+func (o *Order) MarkProphetCompleted(disputeWindow time.Duration) {
 	now := time.Now()
 	o.IsProphetCompleted = true
 	o.ProphetCompletedAt = &now
+	if o.AutoCompleteAt == nil {
+		deadline := now.Add(disputeWindow)
+		o.AutoCompleteAt = &deadline
+	}
 	o.checkAndMarkComplete()
 }
 
 func (o *Order) checkAndMarkComplete() {
 	// Only mark as completed if both prophet and customer have completed
-	if o.IsCustomerCompleted && o.IsProphetCompleted {
-		o.Status = StatusCompleted
+	if o.Status != StatusCompleted && o.IsCustomerCompleted && o.IsProphetCompleted {
+		o.completeSession()
 	}	
 }
 
+// completeSession finishes the current session. The last session completes
+// the order; earlier ones return it to CONFIRMED with the completion flags
+// and booking cleared, ready for the next session to be booked.
+func (o *Order) completeSession() {
+	o.SessionsCompleted = o.CurrentSession()
+	if o.SessionsCompleted >= o.SessionCount() {
+		o.Status = StatusCompleted
+		return
+	}
+
+	o.Status = StatusConfirmed
+	o.IsCustomerCompleted = false
+	o.IsProphetCompleted = false
+	o.CustomerCompletedAt = nil
+	o.ProphetCompletedAt = nil
+	o.AutoCompleteAt = nil
+	o.BookingID = ""
+	o.SessionStartAt = nil
+	o.SessionEndAt = nil
+}
+
+// AutoCompleteDue reports whether the dispute window has run out on an order
+// still waiting for the customer
+func (o *Order) AutoCompleteDue(now time.Time) bool {
+	return o.Status == StatusConfirmed && o.IsProphetCompleted &&
+		o.AutoCompleteAt != nil && !now.Before(*o.AutoCompleteAt)
+}
+
+// AutoComplete completes the current session of an order whose dispute
+// window has run out
+func (o *Order) AutoComplete(now time.Time) bool {
+	if !o.AutoCompleteDue(now) {
+		return false
+	}
+	o.AutoCompleted = true
+	o.completeSession()
+	return true
+}
+
+// OpenDispute puts a confirmed order on hold before the customer has accepted
+// the session and before the dispute window closes
+func (o *Order) OpenDispute(reason string, now time.Time) error {
+	if o.Status != StatusConfirmed || o.IsCustomerCompleted {
+		return ErrOrderNotDisputable
+	}
+	if o.AutoCompleteAt != nil && !now.Before(*o.AutoCompleteAt) {
+		return ErrDisputeWindowClosed
+	}
+	o.Status = StatusDisputed
+	o.DisputeReason = reason
+	o.DisputedAt = &now
+	return nil
+}
+
+// ResolveDispute closes a dispute. A released dispute completes the disputed
+// session so the prophet is settled for it; a refunded one cancels the order.
+func (o *Order) ResolveDispute(resolution DisputeResolution, resolvedBy, note string) error {
+	if o.Status != StatusDisputed {
+		return ErrOrderNotDisputed
+	}
+
+	now := time.Now()
+	switch resolution {
+	case DisputeResolutionReleased:
+		o.completeSession()
+	case DisputeResolutionRefunded:
+		o.Status = StatusCancelled
+		o.CancelledBy = CancelledByDispute
+		o.CancelReason = o.DisputeReason
+		o.CancelledAt = &now
+	default:
+		return ErrInvalidResolution
+	}
+	o.DisputeResolution = resolution
+	o.DisputeResolvedBy = resolvedBy
+	o.DisputeNote = note
+	o.DisputeResolvedAt = &now
+	return nil
+}
+
+// ForceStatus moves the order into status on an admin's say-so, bypassing the
+// checks the customer and prophet are held to. Orders still only move
+// forward: pending orders may be confirmed, unfinished orders cancelled and
+// confirmed or disputed orders completed. Cancelled orders stay cancelled.
+// Disputed orders are settled as a dispute resolved by the admin, completing
+// them releases the payment and cancelling them refunds it.
+func (o *Order) ForceStatus(status OrderStatus, adminID, reason string) error {
+	if o.Status == StatusDisputed {
+		return o.forceResolveDispute(status, adminID, reason)
+	}
+
+	switch {
+	case status == StatusConfirmed && o.Status == StatusPending:
+		o.Status = StatusConfirmed
+	case status == StatusCancelled && (o.Status == StatusPending || o.Status == StatusConfirmed):
+		now := time.Now()
+		o.Status = StatusCancelled
+		o.CancelledBy = CancelledByAdmin
+		o.CancelReason = reason
+		o.CancelledAt = &now
+	case status == StatusCompleted && o.Status == StatusConfirmed:
+		o.Status = StatusCompleted
+		o.SessionsCompleted = o.SessionCount()
+	default:
+		return ErrTransitionNotAllowed
+	}
+	return nil
+}
+
+func (o *Order) forceResolveDispute(status OrderStatus, adminID, reason string) error {
+	switch status {
+	case StatusCompleted:
+		if err := o.ResolveDispute(DisputeResolutionReleased, adminID, reason); err != nil {
+			return err
+		}
+		// Sessions after the disputed one are completed along with it
+		o.Status = StatusCompleted
+		o.SessionsCompleted = o.SessionCount()
+		return nil
+	case StatusCancelled:
+		return o.ResolveDispute(DisputeResolutionRefunded, adminID, reason)
+	default:
+		return ErrTransitionNotAllowed
+	}
+}
+
 func (o *Order) Complete() {
 	o.Status = StatusCompleted
 }
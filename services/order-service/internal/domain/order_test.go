@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func at(t time.Time) *time.Time {
+	return &t
+}
+
+func TestOrderReadingNotStarted(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		order Order
+		want  bool
+	}{
+		{name: "pending without a session", order: Order{Status: StatusPending}, want: true},
+		{name: "confirmed before the session", order: Order{Status: StatusConfirmed, SessionStartAt: at(now.Add(time.Hour))}, want: true},
+		{name: "confirmed once the session started", order: Order{Status: StatusConfirmed, SessionStartAt: at(now.Add(-time.Minute))}, want: false},
+		{name: "pending once the session started", order: Order{Status: StatusPending, SessionStartAt: at(now.Add(-time.Minute))}, want: false},
+		{name: "marked done by the prophet", order: Order{Status: StatusConfirmed, SessionStartAt: at(now.Add(time.Hour)), IsProphetCompleted: true}, want: false},
+		{name: "marked done by the customer", order: Order{Status: StatusConfirmed, IsCustomerCompleted: true}, want: false},
+		{name: "disputed", order: Order{Status: StatusDisputed}, want: false},
+		{name: "cancelled", order: Order{Status: StatusCancelled, SessionStartAt: at(now.Add(time.Hour))}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.order.ReadingNotStarted(); got != tt.want {
+				t.Errorf("ReadingNotStarted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderCancel(t *testing.T) {
+	now := time.Now()
+	started := at(now.Add(-time.Minute))
+	upcoming := at(now.Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		order   Order
+		by      CancelledBy
+		wantErr error
+	}{
+		{name: "customer before the session", order: Order{Status: StatusConfirmed, SessionStartAt: upcoming}, by: CancelledByCustomer},
+		{name: "customer once the session started", order: Order{Status: StatusConfirmed, SessionStartAt: started}, by: CancelledByCustomer, wantErr: ErrOrderNotCancellable},
+		{name: "prophet once the session started", order: Order{Status: StatusConfirmed, SessionStartAt: started}, by: CancelledByProphet},
+		{name: "subscription drops an unpaid period that started", order: Order{Status: StatusPending, SessionStartAt: started}, by: CancelledBySubscription},
+		{name: "subscription keeps a delivered period", order: Order{Status: StatusConfirmed, IsProphetCompleted: true}, by: CancelledBySubscription, wantErr: ErrOrderNotCancellable},
+		{name: "refund cancels a reading not done", order: Order{Status: StatusConfirmed, SessionStartAt: started}, by: CancelledByRefund},
+		{name: "refund keeps a completed order", order: Order{Status: StatusCompleted}, by: CancelledByRefund, wantErr: ErrOrderNotCancellable},
+		{name: "already cancelled", order: Order{Status: StatusCancelled}, by: CancelledByProphet, wantErr: ErrOrderAlreadyCancelled},
+		{name: "someone else", order: Order{Status: StatusConfirmed}, by: CancelledBy("stranger"), wantErr: ErrInvalidCanceller},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			err := order.Cancel(tt.by, "reason")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Cancel() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if order.Status != tt.order.Status {
+					t.Errorf("Cancel() changed the status to %s on error", order.Status)
+				}
+				return
+			}
+			if order.Status != StatusCancelled || order.CancelledBy != tt.by || order.CancelledAt == nil {
+				t.Errorf("Cancel() = status %s, by %q, at %v", order.Status, order.CancelledBy, order.CancelledAt)
+			}
+		})
+	}
+}
+
+func TestOrderOpenDispute(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		order   Order
+		wantErr error
+	}{
+		{name: "confirmed before the session is marked done", order: Order{Status: StatusConfirmed}},
+		{name: "inside the dispute window", order: Order{Status: StatusConfirmed, IsProphetCompleted: true, AutoCompleteAt: at(now.Add(time.Hour))}},
+		{name: "dispute window closed", order: Order{Status: StatusConfirmed, IsProphetCompleted: true, AutoCompleteAt: at(now)}, wantErr: ErrDisputeWindowClosed},
+		{name: "customer accepted the session", order: Order{Status: StatusConfirmed, IsCustomerCompleted: true}, wantErr: ErrOrderNotDisputable},
+		{name: "pending", order: Order{Status: StatusPending}, wantErr: ErrOrderNotDisputable},
+		{name: "already disputed", order: Order{Status: StatusDisputed}, wantErr: ErrOrderNotDisputable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			err := order.OpenDispute("no show", now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenDispute() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if order.Status != tt.order.Status {
+					t.Errorf("OpenDispute() changed the status to %s on error", order.Status)
+				}
+				return
+			}
+			if order.Status != StatusDisputed || order.DisputeReason != "no show" || order.DisputedAt == nil {
+				t.Errorf("OpenDispute() = status %s, reason %q, at %v", order.Status, order.DisputeReason, order.DisputedAt)
+			}
+			if order.AutoComplete(now.Add(2 * time.Hour)) {
+				t.Error("disputed order was auto-completed")
+			}
+		})
+	}
+}
+
+func TestOrderResolveDispute(t *testing.T) {
+	disputed := func(sessions, completed int) Order {
+		return Order{Status: StatusDisputed, DisputeReason: "no show", Sessions: sessions, SessionsBooked: completed + 1, SessionsCompleted: completed, BookingID: "booking-1", IsProphetCompleted: true}
+	}
+
+	tests := []struct {
+		name          string
+		order         Order
+		resolution    DisputeResolution
+		wantStatus    OrderStatus
+		wantCompleted int
+		wantErr       error
+	}{
+		{name: "released", order: disputed(1, 0), resolution: DisputeResolutionReleased, wantStatus: StatusCompleted, wantCompleted: 1},
+		{name: "released bundle session", order: disputed(3, 0), resolution: DisputeResolutionReleased, wantStatus: StatusConfirmed, wantCompleted: 1},
+		{name: "refunded", order: disputed(1, 0), resolution: DisputeResolutionRefunded, wantStatus: StatusCancelled},
+		{name: "unknown resolution", order: disputed(1, 0), resolution: DisputeResolution("SPLIT"), wantStatus: StatusDisputed, wantErr: ErrInvalidResolution},
+		{name: "not disputed", order: Order{Status: StatusConfirmed}, resolution: DisputeResolutionReleased, wantStatus: StatusConfirmed, wantErr: ErrOrderNotDisputed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			err := order.ResolveDispute(tt.resolution, "user-1", "note")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveDispute() error = %v, want %v", err, tt.wantErr)
+			}
+			if order.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", order.Status, tt.wantStatus)
+			}
+			if err != nil {
+				return
+			}
+			if order.SessionsCompleted != tt.wantCompleted {
+				t.Errorf("SessionsCompleted = %d, want %d", order.SessionsCompleted, tt.wantCompleted)
+			}
+			if order.DisputeResolution != tt.resolution || order.DisputeResolvedBy != "user-1" || order.DisputeResolvedAt == nil {
+				t.Errorf("resolution recorded as %q by %q at %v", order.DisputeResolution, order.DisputeResolvedBy, order.DisputeResolvedAt)
+			}
+			if tt.resolution == DisputeResolutionRefunded && (order.CancelledBy != CancelledByDispute || order.CancelReason != "no show") {
+				t.Errorf("cancelled by %q for %q, want the dispute", order.CancelledBy, order.CancelReason)
+			}
+		})
+	}
+}
+
+func TestOrderAutoComplete(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		order      Order
+		want       bool
+		wantStatus OrderStatus
+	}{
+		{name: "window ran out", order: Order{Status: StatusConfirmed, Sessions: 1, SessionsBooked: 1, IsProphetCompleted: true, AutoCompleteAt: at(now)}, want: true, wantStatus: StatusCompleted},
+		{name: "window still open", order: Order{Status: StatusConfirmed, Sessions: 1, SessionsBooked: 1, IsProphetCompleted: true, AutoCompleteAt: at(now.Add(time.Minute))}, wantStatus: StatusConfirmed},
+		{name: "prophet has not marked it done", order: Order{Status: StatusConfirmed, Sessions: 1, SessionsBooked: 1, AutoCompleteAt: at(now)}, wantStatus: StatusConfirmed},
+		{name: "disputed", order: Order{Status: StatusDisputed, Sessions: 1, SessionsBooked: 1, IsProphetCompleted: true, AutoCompleteAt: at(now)}, wantStatus: StatusDisputed},
+		{name: "bundle session", order: Order{Status: StatusConfirmed, Sessions: 2, SessionsBooked: 1, IsProphetCompleted: true, AutoCompleteAt: at(now)}, want: true, wantStatus: StatusConfirmed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			if got := order.AutoComplete(now); got != tt.want {
+				t.Errorf("AutoComplete() = %v, want %v", got, tt.want)
+			}
+			if order.Status != tt.wantStatus || order.AutoCompleted != tt.want {
+				t.Errorf("status %s, auto-completed %v, want %s, %v", order.Status, order.AutoCompleted, tt.wantStatus, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrOutboxMessageRejected marks a relay failure caused by the message itself,
+// such as the broker refusing it. Only these count towards parking a message;
+// anything else, like the broker being down, is retried for as long as it takes.
+var ErrOutboxMessageRejected = errors.New("outbox message was rejected")
+
+// OutboxMessage is a domain event recorded alongside the order change that
+// produced it and relayed to the message broker afterwards
+type OutboxMessage struct {
+	ID         uuid.UUID
+	RoutingKey string
+	OwnerID    string
+	Payload    []byte
+	// Attempts counts the times the message was rejected; failures to reach
+	// the broker only update LastError
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+	// FailedAt is set when the relay parks the message after it was rejected
+	// too many times; parked messages stay in the outbox until an admin
+	// replays them
+	FailedAt *time.Time
+}
+
+func NewOutboxMessage(routingKey string, ownerID string, payload []byte) *OutboxMessage {
+	return &OutboxMessage{
+		ID:         uuid.New(),
+		RoutingKey: routingKey,
+		OwnerID:    ownerID,
+		Payload:    payload,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// RecordFailure notes a failed attempt to relay the message. Only rejections
+// count towards maxAttempts, zero meaning no limit; it reports whether the
+// message was parked.
+func (m *OutboxMessage) RecordFailure(err error, maxAttempts int, now time.Time) bool {
+	m.LastError = err.Error()
+	if !errors.Is(err, ErrOutboxMessageRejected) {
+		return false
+	}
+
+	m.Attempts++
+	if maxAttempts <= 0 || m.Attempts < maxAttempts {
+		return false
+	}
+	m.FailedAt = &now
+	return true
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestOutboxMessageRecordFailure(t *testing.T) {
+	rejected := fmt.Errorf("%w: nacked", ErrOutboxMessageRejected)
+	unreachable := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		attempts     int
+		err          error
+		maxAttempts  int
+		wantAttempts int
+		wantParked   bool
+	}{
+		{name: "first rejection", attempts: 0, err: rejected, maxAttempts: 3, wantAttempts: 1},
+		{name: "last allowed rejection", attempts: 2, err: rejected, maxAttempts: 3, wantAttempts: 3, wantParked: true},
+		{name: "broker unreachable does not count", attempts: 2, err: unreachable, maxAttempts: 3, wantAttempts: 2},
+		{name: "no limit", attempts: 100, err: rejected, maxAttempts: 0, wantAttempts: 101},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := NewOutboxMessage("order.created", "order-1", []byte(`{}`))
+			message.Attempts = tt.attempts
+
+			parked := message.RecordFailure(tt.err, tt.maxAttempts, time.Now())
+			if parked != tt.wantParked {
+				t.Errorf("RecordFailure() = %v, want %v", parked, tt.wantParked)
+			}
+			if message.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", message.Attempts, tt.wantAttempts)
+			}
+			if (message.FailedAt != nil) != tt.wantParked {
+				t.Errorf("FailedAt = %v, want parked %v", message.FailedAt, tt.wantParked)
+			}
+			if message.LastError != tt.err.Error() {
+				t.Errorf("LastError = %q, want %q", message.LastError, tt.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+var ErrPaymentNotFound = errors.New("payment not found for order")
+
+// PaymentInfo is the payment-service view of the payment backing an order
+type PaymentInfo struct {
+	PaymentID   string    `json:"payment_id"`
+	OrderID     string    `json:"order_id"`
+	ProphetID   string    `json:"prophet_id,omitempty"`
+	Amount      money.Decimal `json:"amount"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	Provider    string    `json:"provider,omitempty"`
+	ProviderRef string    `json:"provider_ref,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
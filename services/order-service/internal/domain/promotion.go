@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// PromotionKind is how a promotion computes its discount
+type PromotionKind string
+
+const (
+	// PromotionPercentage takes Value percent off the course price
+	PromotionPercentage PromotionKind = "PERCENTAGE"
+	// PromotionFixed takes Value off the course price, in Currency
+	PromotionFixed PromotionKind = "FIXED"
+)
+
+var (
+	ErrPromotionNotFound   = errors.New("promotion not found")
+	ErrInvalidPromotion    = errors.New("invalid promotion")
+	ErrCouponNotFound      = errors.New("coupon code is not valid")
+	ErrCouponCodeTaken     = errors.New("coupon code is already in use")
+	ErrCouponExpired       = errors.New("coupon is not active")
+	ErrCouponNotApplicable = errors.New("coupon does not apply to this order")
+	ErrPromotionExhausted  = errors.New("promotion has reached its usage limit")
+	ErrNotPromotionOwner   = errors.New("user cannot manage this promotion")
+)
+
+// Promotion is a discount on the course price. Promotions with a Code are
+// coupons the customer enters; the rest apply automatically to every order
+// they match.
+type Promotion struct {
+	ID          uuid.UUID     `json:"id"`
+	Code        string        `json:"code,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Kind        PromotionKind `json:"kind"`
+	// Value is a percentage for PERCENTAGE promotions and an amount in
+	// Currency for FIXED ones
+	Value    money.Decimal `json:"value"`
+	Currency string        `json:"currency,omitempty"`
+	// ProphetID limits the promotion to one prophet's courses; empty means
+	// every course
+	ProphetID string `json:"prophet_id,omitempty"`
+	// FirstOrderOnly limits the promotion to customers without earlier orders
+	FirstOrderOnly bool `json:"first_order_only"`
+	// Usage limits; zero means unlimited
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerCustomer int        `json:"max_per_customer"`
+	Redemptions    int        `json:"redemptions"`
+	StartsAt       time.Time  `json:"starts_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PromotionTarget is the order a promotion is evaluated against
+type PromotionTarget struct {
+	ProphetID string
+	Currency  string
+	// FirstOrder is true when the customer has no other live orders
+	FirstOrder bool
+	// CustomerRedemptions is how often the customer already used the promotion
+	CustomerRedemptions int
+}
+
+// PromotionRedemption records an order that used a promotion. Redemptions
+// of cancelled orders are removed so they do not count against the limits.
+type PromotionRedemption struct {
+	PromotionID uuid.UUID
+	OrderID     uuid.UUID
+	CustomerID  string
+	Discount    money.Decimal
+	Currency    string
+	RedeemedAt  time.Time
+}
+
+// NewPromotion validates and creates an active promotion. A FIXED promotion
+// needs a currency; a PERCENTAGE one must be above 0 and at most 100.
+func NewPromotion(code, description string, kind PromotionKind, value money.Decimal, currency, prophetID string, firstOrderOnly bool, maxRedemptions, maxPerCustomer int, startsAt time.Time, expiresAt *time.Time, createdBy string) (*Promotion, error) {
+	if !value.IsPositive() || maxRedemptions < 0 || maxPerCustomer < 0 {
+		return nil, ErrInvalidPromotion
+	}
+	switch kind {
+	case PromotionPercentage:
+		if value.GreaterThan(money.NewFromInt(100)) {
+			return nil, ErrInvalidPromotion
+		}
+		currency = ""
+	case PromotionFixed:
+		normalized, err := money.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = normalized
+	default:
+		return nil, ErrInvalidPromotion
+	}
+
+	now := time.Now()
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+	if expiresAt != nil && !expiresAt.After(startsAt) {
+		return nil, ErrInvalidPromotion
+	}
+
+	return &Promotion{
+		ID:             uuid.New(),
+		Code:           NormalizeCouponCode(code),
+		Description:    description,
+		Kind:           kind,
+		Value:          value,
+		Currency:       currency,
+		ProphetID:      prophetID,
+		FirstOrderOnly: firstOrderOnly,
+		MaxRedemptions: maxRedemptions,
+		MaxPerCustomer: maxPerCustomer,
+		StartsAt:       startsAt,
+		ExpiresAt:      expiresAt,
+		Active:         true,
+		CreatedBy:      createdBy,
+		CreatedAt:      now,
+	}, nil
+}
+
+// NormalizeCouponCode makes coupon codes case-insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsCoupon reports whether the customer has to enter a code to use the promotion
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != ""
+}
+
+// Deactivate stops the promotion from applying to new orders
+func (p *Promotion) Deactivate() {
+	p.Active = false
+}
+
+// LiveAt reports whether the promotion is active and within its validity period
+func (p *Promotion) LiveAt(t time.Time) bool {
+	if !p.Active || t.Before(p.StartsAt) {
+		return false
+	}
+	return p.ExpiresAt == nil || t.Before(*p.ExpiresAt)
+}
+
+// CheckEligible reports why the promotion cannot be applied to target at t,
+// or nil if it can
+func (p *Promotion) CheckEligible(target PromotionTarget, t time.Time) error {
+	if !p.LiveAt(t) {
+		return ErrCouponExpired
+	}
+	if p.ProphetID != "" && p.ProphetID != target.ProphetID {
+		return ErrCouponNotApplicable
+	}
+	if p.Kind == PromotionFixed && p.Currency != target.Currency {
+		return ErrCouponNotApplicable
+	}
+	if p.FirstOrderOnly && !target.FirstOrder {
+		return ErrCouponNotApplicable
+	}
+	return p.CheckUsage(p.Redemptions, target.CustomerRedemptions)
+}
+
+// CheckUsage reports whether one more redemption stays within the limits
+func (p *Promotion) CheckUsage(redemptions, customerRedemptions int) error {
+	if p.MaxRedemptions > 0 && redemptions >= p.MaxRedemptions {
+		return ErrPromotionExhausted
+	}
+	if p.MaxPerCustomer > 0 && customerRedemptions >= p.MaxPerCustomer {
+		return ErrPromotionExhausted
+	}
+	return nil
+}
+
+// Discount is the amount taken off price, rounded to the currency's minor
+// unit. It never exceeds the price.
+func (p *Promotion) Discount(price money.Decimal, currency string) money.Decimal {
+	var discount money.Decimal
+	switch p.Kind {
+	case PromotionPercentage:
+		discount = price.Mul(p.Value).Div(money.NewFromInt(100))
+	case PromotionFixed:
+		if p.Currency != currency {
+			return money.Zero
+		}
+		discount = p.Value
+	}
+	return money.Min(money.RoundTo(discount, currency), price)
+}
+
+// BestPromotion picks the promotion giving the largest discount on price.
+// Ties go to the earlier promotion in the slice.
+func BestPromotion(promotions []*Promotion, price money.Decimal, currency string) (*Promotion, money.Decimal) {
+	var best *Promotion
+	bestDiscount := money.Zero
+	for _, p := range promotions {
+		if discount := p.Discount(price, currency); discount.GreaterThan(bestDiscount) {
+			best, bestDiscount = p, discount
+		}
+	}
+	return best, bestDiscount
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+func TestOrderApplyPromotion(t *testing.T) {
+	course := &CourseSnapshot{CourseID: "course-1", ProphetID: "prophet-1", Price: money.MustParse("500"), Currency: "THB"}
+	usd := &ExchangeRate{From: "THB", To: "USD", Rate: money.MustParse("0.028"), AsOf: time.Now()}
+
+	tests := []struct {
+		name       string
+		rate       *ExchangeRate
+		promotion  *Promotion
+		wantCharge string
+	}{
+		{
+			name:       "percentage",
+			rate:       IdentityRate("THB"),
+			promotion:  &Promotion{Kind: PromotionPercentage, Value: money.MustParse("20")},
+			wantCharge: "400",
+		},
+		{
+			name:       "converted after the discount",
+			rate:       usd,
+			promotion:  &Promotion{Kind: PromotionFixed, Value: money.MustParse("100"), Currency: "THB"},
+			wantCharge: "11.2",
+		},
+		{
+			name:       "full discount still charges one minor unit",
+			rate:       IdentityRate("THB"),
+			promotion:  &Promotion{Kind: PromotionPercentage, Value: money.MustParse("100")},
+			wantCharge: "0.01",
+		},
+		{
+			name:       "fixed discount above the price",
+			rate:       usd,
+			promotion:  &Promotion{Kind: PromotionFixed, Value: money.MustParse("1000"), Currency: "THB"},
+			wantCharge: "0.01",
+		},
+		{
+			name:       "remainder that converts to nothing",
+			rate:       usd,
+			promotion:  &Promotion{Kind: PromotionFixed, Value: money.MustParse("499.9"), Currency: "THB"},
+			wantCharge: "0.01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := NewOrder("customer-1", course, "room-1", tt.rate)
+			order.ApplyPromotion(tt.promotion, tt.promotion.Discount(order.Price, order.Currency), tt.rate)
+			if want := money.MustParse(tt.wantCharge); !order.ChargeAmount.Equal(want) {
+				t.Errorf("ChargeAmount = %s, want %s", order.ChargeAmount, want)
+			}
+			if !order.ChargeAmount.IsPositive() {
+				t.Errorf("ChargeAmount = %s, want above zero", order.ChargeAmount)
+			}
+		})
+	}
+}
+
+func TestNewPromotion(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		kind      PromotionKind
+		value     string
+		currency  string
+		expiresAt *time.Time
+		maxUses   int
+		wantErr   error
+	}{
+		{name: "percentage", kind: PromotionPercentage, value: "20"},
+		{name: "full percentage", kind: PromotionPercentage, value: "100"},
+		{name: "fixed", kind: PromotionFixed, value: "100", currency: "thb"},
+		{name: "percentage above 100", kind: PromotionPercentage, value: "100.5", wantErr: ErrInvalidPromotion},
+		{name: "zero value", kind: PromotionPercentage, value: "0", wantErr: ErrInvalidPromotion},
+		{name: "negative limit", kind: PromotionPercentage, value: "20", maxUses: -1, wantErr: ErrInvalidPromotion},
+		{name: "unknown kind", kind: PromotionKind("BOGO"), value: "20", wantErr: ErrInvalidPromotion},
+		{name: "fixed with a malformed currency", kind: PromotionFixed, value: "100", currency: "BAHT", wantErr: money.ErrInvalidCurrency},
+		{name: "expires before it starts", kind: PromotionPercentage, value: "20", expiresAt: &earlier, wantErr: ErrInvalidPromotion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion, err := NewPromotion(" spring ", "", tt.kind, money.MustParse(tt.value), tt.currency, "", false, tt.maxUses, 0, now, tt.expiresAt, "admin-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPromotion() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if promotion.Code != "SPRING" || !promotion.Active {
+				t.Errorf("promotion has code %q, active %v", promotion.Code, promotion.Active)
+			}
+			if tt.kind == PromotionFixed && promotion.Currency != "THB" {
+				t.Errorf("Currency = %q, want THB", promotion.Currency)
+			}
+			if tt.kind == PromotionPercentage && promotion.Currency != "" {
+				t.Errorf("percentage promotion kept currency %q", promotion.Currency)
+			}
+		})
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion Promotion
+		price     string
+		currency  string
+		want      string
+	}{
+		{name: "percentage", promotion: Promotion{Kind: PromotionPercentage, Value: money.MustParse("15")}, price: "500", currency: "THB", want: "75"},
+		{name: "percentage rounded", promotion: Promotion{Kind: PromotionPercentage, Value: money.MustParse("33")}, price: "9.99", currency: "USD", want: "3.3"},
+		{name: "percentage of a yen price", promotion: Promotion{Kind: PromotionPercentage, Value: money.MustParse("15")}, price: "999", currency: "JPY", want: "150"},
+		{name: "fixed", promotion: Promotion{Kind: PromotionFixed, Value: money.MustParse("100"), Currency: "THB"}, price: "500", currency: "THB", want: "100"},
+		{name: "fixed above the price", promotion: Promotion{Kind: PromotionFixed, Value: money.MustParse("600"), Currency: "THB"}, price: "500", currency: "THB", want: "500"},
+		{name: "fixed in another currency", promotion: Promotion{Kind: PromotionFixed, Value: money.MustParse("10"), Currency: "USD"}, price: "500", currency: "THB", want: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.Discount(money.MustParse(tt.price), tt.currency); !got.Equal(money.MustParse(tt.want)) {
+				t.Errorf("Discount() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionCheckEligible(t *testing.T) {
+	now := time.Now()
+	live := func(edit func(p *Promotion)) Promotion {
+		p := Promotion{Kind: PromotionPercentage, Value: money.MustParse("10"), Active: true, StartsAt: now.Add(-time.Hour)}
+		edit(&p)
+		return p
+	}
+	target := PromotionTarget{ProphetID: "prophet-1", Currency: "THB", FirstOrder: true}
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		target    PromotionTarget
+		wantErr   error
+	}{
+		{name: "eligible", promotion: live(func(p *Promotion) {}), target: target},
+		{name: "inactive", promotion: live(func(p *Promotion) { p.Active = false }), target: target, wantErr: ErrCouponExpired},
+		{name: "not started", promotion: live(func(p *Promotion) { p.StartsAt = now.Add(time.Hour) }), target: target, wantErr: ErrCouponExpired},
+		{name: "expired", promotion: live(func(p *Promotion) { p.ExpiresAt = &now }), target: target, wantErr: ErrCouponExpired},
+		{name: "own prophet", promotion: live(func(p *Promotion) { p.ProphetID = "prophet-1" }), target: target},
+		{name: "another prophet", promotion: live(func(p *Promotion) { p.ProphetID = "prophet-2" }), target: target, wantErr: ErrCouponNotApplicable},
+		{
+			name:      "fixed in another currency",
+			promotion: live(func(p *Promotion) { p.Kind, p.Currency = PromotionFixed, "USD" }),
+			target:    target,
+			wantErr:   ErrCouponNotApplicable,
+		},
+		{
+			name:      "first order only on a repeat customer",
+			promotion: live(func(p *Promotion) { p.FirstOrderOnly = true }),
+			target:    PromotionTarget{ProphetID: "prophet-1", Currency: "THB"},
+			wantErr:   ErrCouponNotApplicable,
+		},
+		{name: "used up", promotion: live(func(p *Promotion) { p.MaxRedemptions, p.Redemptions = 10, 10 }), target: target, wantErr: ErrPromotionExhausted},
+		{
+			name:      "used up by the customer",
+			promotion: live(func(p *Promotion) { p.MaxPerCustomer = 1 }),
+			target:    PromotionTarget{ProphetID: "prophet-1", Currency: "THB", CustomerRedemptions: 1},
+			wantErr:   ErrPromotionExhausted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.promotion.CheckEligible(tt.target, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckEligible() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBestPromotion(t *testing.T) {
+	tenPercent := &Promotion{Code: "TEN", Kind: PromotionPercentage, Value: money.MustParse("10")}
+	fiftyOff := &Promotion{Code: "FIFTY", Kind: PromotionFixed, Value: money.MustParse("50"), Currency: "THB"}
+	otherFiftyOff := &Promotion{Code: "FIFTY2", Kind: PromotionFixed, Value: money.MustParse("50"), Currency: "THB"}
+	dollarsOff := &Promotion{Code: "USD", Kind: PromotionFixed, Value: money.MustParse("50"), Currency: "USD"}
+
+	tests := []struct {
+		name         string
+		promotions   []*Promotion
+		price        string
+		want         *Promotion
+		wantDiscount string
+	}{
+		{name: "percentage wins on a large price", promotions: []*Promotion{fiftyOff, tenPercent}, price: "1000", want: tenPercent, wantDiscount: "100"},
+		{name: "fixed wins on a small price", promotions: []*Promotion{tenPercent, fiftyOff}, price: "300", want: fiftyOff, wantDiscount: "50"},
+		{name: "tie goes to the first", promotions: []*Promotion{fiftyOff, otherFiftyOff}, price: "300", want: fiftyOff, wantDiscount: "50"},
+		{name: "no discount in the currency", promotions: []*Promotion{dollarsOff}, price: "300", wantDiscount: "0"},
+		{name: "none", price: "300", wantDiscount: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, discount := BestPromotion(tt.promotions, money.MustParse(tt.price), "THB")
+			if got != tt.want {
+				t.Errorf("BestPromotion() picked %v, want %v", got, tt.want)
+			}
+			if !discount.Equal(money.MustParse(tt.wantDiscount)) {
+				t.Errorf("discount = %s, want %s", discount, tt.wantDiscount)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// SubscriptionStatus is where a subscription is in its billing cycle
+type SubscriptionStatus string
+
+const (
+	// SubscriptionPending waits for the first period to be paid
+	SubscriptionPending SubscriptionStatus = "PENDING"
+	// SubscriptionActive is paid up to CurrentPeriodEnd
+	SubscriptionActive SubscriptionStatus = "ACTIVE"
+	// SubscriptionPastDue has a renewal issued and not paid yet
+	SubscriptionPastDue SubscriptionStatus = "PAST_DUE"
+	// SubscriptionCancelled was ended by the customer
+	SubscriptionCancelled SubscriptionStatus = "CANCELLED"
+	// SubscriptionLapsed ended because a period was not paid in time
+	SubscriptionLapsed SubscriptionStatus = "LAPSED"
+)
+
+// Reasons a subscription ends, carried on the lapsed event
+const (
+	LapseReasonCancelled = "cancelled"
+	LapseReasonUnpaid    = "unpaid"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionEnded    = errors.New("subscription has already ended")
+	ErrNotSubscriber        = errors.New("user is not the subscriber")
+	ErrSubscriptionOrder    = errors.New("subscription orders are cancelled through their subscription")
+)
+
+// Subscription bills a customer for a course every month. Each billing
+// period is an order of its own, charged at the price the customer subscribed
+// at, and every period shares the subscription's chat room.
+type Subscription struct {
+	ID         uuid.UUID          `json:"id"`
+	CustomerID string             `json:"customer_id"`
+	CourseID   string             `json:"course_id"`
+	CourseName string             `json:"course_name"`
+	CourseType string             `json:"course_type,omitempty"`
+	ProphetID  string             `json:"prophet_id"`
+	RoomID     string             `json:"room_id"`
+	Status     SubscriptionStatus `json:"status"`
+	// Price per period in Currency. Renewals are converted to ChargeCurrency
+	// at the rate of the day.
+	Price          money.Decimal `json:"price"`
+	Currency       string        `json:"currency"`
+	ChargeCurrency string        `json:"charge_currency"`
+	// Periods counts the paid periods; the latest runs from
+	// CurrentPeriodStart to CurrentPeriodEnd
+	Periods            int        `json:"periods"`
+	CurrentPeriodStart *time.Time `json:"current_period_start,omitempty"`
+	CurrentPeriodEnd   *time.Time `json:"current_period_end,omitempty"`
+	// PendingOrderID is the unpaid order for the next period. The payment
+	// for it is issued once PendingPaymentID is set; it must be paid before
+	// GraceEndsAt or the subscription lapses.
+	PendingOrderID   *uuid.UUID `json:"pending_order_id,omitempty"`
+	PendingPaymentID string     `json:"pending_payment_id,omitempty"`
+	GraceEndsAt      *time.Time `json:"grace_ends_at,omitempty"`
+	// CancelAtPeriodEnd stops renewal; the paid period still runs out
+	CancelAtPeriodEnd bool       `json:"cancel_at_period_end"`
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`
+	EndedAt           *time.Time `json:"ended_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// NewSubscription subscribes the customer to the course at its current price.
+// The first period's order must be paid within grace.
+func NewSubscription(customerID string, course *CourseSnapshot, roomID, chargeCurrency string, grace time.Duration) *Subscription {
+	currency := course.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	now := time.Now()
+	graceEndsAt := now.Add(grace)
+	return &Subscription{
+		ID:             uuid.New(),
+		CustomerID:     customerID,
+		CourseID:       course.CourseID,
+		CourseName:     course.CourseName,
+		CourseType:     course.CourseType,
+		ProphetID:      course.ProphetID,
+		RoomID:         roomID,
+		Status:         SubscriptionPending,
+		Price:          course.Price,
+		Currency:       currency,
+		ChargeCurrency: chargeCurrency,
+		GraceEndsAt:    &graceEndsAt,
+		CreatedAt:      now,
+	}
+}
+
+// Course is the course as subscribed to, which every period is ordered at
+func (s *Subscription) Course() *CourseSnapshot {
+	return &CourseSnapshot{
+		CourseID:   s.CourseID,
+		CourseName: s.CourseName,
+		CourseType: s.CourseType,
+		ProphetID:  s.ProphetID,
+		Price:      s.Price,
+		Currency:   s.Currency,
+		Sessions:   1,
+	}
+}
+
+// AwaitPayment records the order billing the next period
+func (s *Subscription) AwaitPayment(orderID uuid.UUID) {
+	s.PendingOrderID = &orderID
+	s.PendingPaymentID = ""
+}
+
+// Ended reports whether the subscription will not bill again
+func (s *Subscription) Ended() bool {
+	return s.Status == SubscriptionCancelled || s.Status == SubscriptionLapsed
+}
+
+// RenewalDue reports whether the paid period has run out
+func (s *Subscription) RenewalDue(now time.Time) bool {
+	return s.Status == SubscriptionActive && s.CurrentPeriodEnd != nil && !now.Before(*s.CurrentPeriodEnd)
+}
+
+// LapseDue reports whether an unpaid period has run past its grace
+func (s *Subscription) LapseDue(now time.Time) bool {
+	return (s.Status == SubscriptionPending || s.Status == SubscriptionPastDue) &&
+		s.GraceEndsAt != nil && !now.Before(*s.GraceEndsAt)
+}
+
+// StartRenewal bills the next period with orderID. The customer has until
+// grace after the period ended to pay.
+func (s *Subscription) StartRenewal(orderID uuid.UUID, grace time.Duration) {
+	graceEndsAt := s.CurrentPeriodEnd.Add(grace)
+	s.Status = SubscriptionPastDue
+	s.GraceEndsAt = &graceEndsAt
+	s.AwaitPayment(orderID)
+}
+
+// PeriodPaid starts the period billed by orderID. The first period starts
+// when it is paid; later ones follow on from the previous period. It reports
+// false when orderID is not the order awaiting payment.
+func (s *Subscription) PeriodPaid(orderID uuid.UUID, paidAt time.Time) bool {
+	if s.PendingOrderID == nil || *s.PendingOrderID != orderID || s.Ended() {
+		return false
+	}
+
+	start := paidAt
+	if s.CurrentPeriodEnd != nil {
+		start = *s.CurrentPeriodEnd
+	}
+	end := start.AddDate(0, 1, 0)
+	s.Periods++
+	s.CurrentPeriodStart = &start
+	s.CurrentPeriodEnd = &end
+	s.Status = SubscriptionActive
+	s.PendingOrderID = nil
+	s.PendingPaymentID = ""
+	s.GraceEndsAt = nil
+	return true
+}
+
+// Cancel stops the subscription from renewing. A paid period runs out
+// first; an unpaid one ends the subscription now, and its pending order is
+// returned so the caller can cancel it.
+func (s *Subscription) Cancel(now time.Time) (*uuid.UUID, error) {
+	if s.Ended() {
+		return nil, ErrSubscriptionEnded
+	}
+	s.CancelledAt = &now
+	if s.Status == SubscriptionActive {
+		s.CancelAtPeriodEnd = true
+		return nil, nil
+	}
+	return s.end(SubscriptionCancelled, now), nil
+}
+
+// Expire ends a subscription that was cancelled once its paid period is over
+func (s *Subscription) Expire(now time.Time) {
+	s.end(SubscriptionCancelled, now)
+}
+
+// Lapse ends a subscription whose period was not paid in time and returns
+// the unpaid order so the caller can cancel it
+func (s *Subscription) Lapse(now time.Time) *uuid.UUID {
+	return s.end(SubscriptionLapsed, now)
+}
+
+func (s *Subscription) end(status SubscriptionStatus, now time.Time) *uuid.UUID {
+	pending := s.PendingOrderID
+	s.Status = status
+	s.EndedAt = &now
+	s.PendingOrderID = nil
+	s.PendingPaymentID = ""
+	s.GraceEndsAt = nil
+	return pending
+}
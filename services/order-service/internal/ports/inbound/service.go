@@ -2,15 +2,19 @@ package inbound
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 )
 
 // OrderService defines the interface for order business logic
 type OrderService interface {
 	CreateOrder(ctx context.Context, cmd CreateOrderCommand) (*domain.Order, error)
-	GetOrders(ctx context.Context) ([]*domain.Order, error)
+	// GetOrders pages through orders, only those in status unless it is empty
+	GetOrders(ctx context.Context, status domain.OrderStatus, page contract.PageRequest) (contract.Page[*domain.Order], error)
 	GetOrderByID(ctx context.Context, orderID uuid.UUID) (*domain.Order, error)
 	GetOrdersByCustomer(ctx context.Context, customerID string) ([]*domain.Order, error)
 	GetOrdersByRoom(ctx context.Context, roomID string) ([]*domain.Order, error)
@@ -18,11 +22,141 @@ type OrderService interface {
 	UpdateOrderPaymentID(ctx context.Context, orderID uuid.UUID, paymentID uuid.UUID) error
 	MarkCustomerCompleted(ctx context.Context, orderID uuid.UUID) error
 	MarkProphetCompleted(ctx context.Context, orderID uuid.UUID) error
+	CancelOrder(ctx context.Context, cmd CancelOrderCommand) (*domain.Order, error)
+	// CancelRefundedOrder cancels an order whose payment was refunded outside
+	// the order's own flow, unless its reading was already done
+	CancelRefundedOrder(ctx context.Context, orderID uuid.UUID) error
+	GetOrderPayment(ctx context.Context, orderID uuid.UUID) (*domain.PaymentInfo, error)
+	RescheduleOrder(ctx context.Context, cmd RescheduleOrderCommand) (*domain.Order, error)
+	BookNextSession(ctx context.Context, cmd BookSessionCommand) (*domain.Order, error)
+	OpenDispute(ctx context.Context, cmd OpenDisputeCommand) (*domain.Order, error)
+	ResolveDispute(ctx context.Context, cmd ResolveDisputeCommand) (*domain.Order, error)
+	// AutoCompleteDueOrders completes up to limit orders whose dispute window
+	// has run out and returns how many were completed
+	AutoCompleteDueOrders(ctx context.Context, now time.Time, limit int) (int, error)
+	// ForceOrderStatus moves an order into another status on an admin's
+	// say-so and records why
+	ForceOrderStatus(ctx context.Context, cmd ForceOrderStatusCommand) (*domain.Order, error)
+	GetOrderAudit(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderAudit, error)
+	// GetDashboard summarises the orders placed in [from, to), ranking the
+	// top prophets in each currency
+	GetDashboard(ctx context.Context, from, to time.Time, top int) (*domain.Dashboard, error)
+}
+
+// PromotionService defines the interface for managing promotions. Admins
+// manage every promotion; prophets only those scoped to their own courses.
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, cmd CreatePromotionCommand) (*domain.Promotion, error)
+	ListPromotions(ctx context.Context, userID string, role string) ([]*domain.Promotion, error)
+	DeactivatePromotion(ctx context.Context, promotionID uuid.UUID, userID string, role string) (*domain.Promotion, error)
+}
+
+// SubscriptionService defines the interface for subscriptions. Customers
+// manage their own subscriptions; prophets see those to their courses.
+type SubscriptionService interface {
+	Subscribe(ctx context.Context, cmd SubscribeCommand) (*domain.Subscription, *domain.Order, error)
+	GetSubscription(ctx context.Context, subscriptionID uuid.UUID, userID string, role string) (*domain.Subscription, []*domain.Order, error)
+	ListSubscriptions(ctx context.Context, userID string, role string) ([]*domain.Subscription, error)
+	CancelSubscription(ctx context.Context, subscriptionID uuid.UUID, customerID string) (*domain.Subscription, error)
+	// RenewDueSubscriptions renews, expires or lapses up to limit
+	// subscriptions that are due and returns how many were processed
+	RenewDueSubscriptions(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// OutboxService lets admins inspect and replay the events the outbox relay
+// parked after the broker kept rejecting them
+type OutboxService interface {
+	ListFailedMessages(ctx context.Context, limit int) ([]*domain.OutboxMessage, error)
+	// ReplayFailedMessages queues the parked messages with the given IDs, or
+	// all of them when ids is empty, again and returns how many it queued
+	ReplayFailedMessages(ctx context.Context, ids []uuid.UUID) (int, error)
 }
 
 // CreateOrderCommand represents the command to create an order
 type CreateOrderCommand struct {
-	CustomerID string `json:"customer_id" validate:"required"`
-	CourseID   string `json:"course_id" validate:"required"`
-	RoomID     string `json:"room_id" validate:"required"`
-}
\ No newline at end of file
+	CustomerID string    `json:"customer_id" validate:"required"`
+	CourseID   string    `json:"course_id" validate:"required"`
+	RoomID     string    `json:"room_id" validate:"required"`
+	StartAt    time.Time `json:"start_at" validate:"required"`
+	// Currency to charge in; empty means the course's own currency
+	Currency string `json:"currency"`
+	// CouponCode is an optional coupon to apply to the course price
+	CouponCode string `json:"coupon_code"`
+}
+
+// SubscribeCommand represents the command to subscribe to a course. StartAt
+// books the first period's session.
+type SubscribeCommand struct {
+	CustomerID string    `json:"customer_id" validate:"required"`
+	CourseID   string    `json:"course_id" validate:"required"`
+	RoomID     string    `json:"room_id" validate:"required"`
+	StartAt    time.Time `json:"start_at" validate:"required"`
+	// Currency to charge in; empty means the course's own currency
+	Currency string `json:"currency"`
+}
+
+// RescheduleOrderCommand represents the command to move an order's session
+type RescheduleOrderCommand struct {
+	OrderID    uuid.UUID `json:"order_id" validate:"required"`
+	CustomerID string    `json:"customer_id" validate:"required"`
+	StartAt    time.Time `json:"start_at" validate:"required"`
+}
+
+// BookSessionCommand represents the command to book the next session of a
+// bundle
+type BookSessionCommand struct {
+	OrderID    uuid.UUID `json:"order_id" validate:"required"`
+	CustomerID string    `json:"customer_id" validate:"required"`
+	StartAt    time.Time `json:"start_at" validate:"required"`
+}
+
+// CancelOrderCommand represents the command to cancel an order
+type CancelOrderCommand struct {
+	OrderID uuid.UUID `json:"order_id" validate:"required"`
+	UserID  string    `json:"user_id" validate:"required"`
+	Role    string    `json:"role" validate:"required"`
+	Reason  string    `json:"reason"`
+}
+
+// OpenDisputeCommand represents the command to dispute a delivered session
+type OpenDisputeCommand struct {
+	OrderID    uuid.UUID `json:"order_id" validate:"required"`
+	CustomerID string    `json:"customer_id" validate:"required"`
+	Reason     string    `json:"reason" validate:"required"`
+}
+
+// ResolveDisputeCommand represents the command to close a dispute
+type ResolveDisputeCommand struct {
+	OrderID    uuid.UUID                `json:"order_id" validate:"required"`
+	UserID     string                   `json:"user_id" validate:"required"`
+	Role       string                   `json:"role" validate:"required"`
+	Resolution domain.DisputeResolution `json:"resolution" validate:"required"`
+	Note       string                   `json:"note"`
+}
+
+// ForceOrderStatusCommand represents an admin's command to move an order into
+// another status
+type ForceOrderStatusCommand struct {
+	OrderID uuid.UUID          `json:"order_id" validate:"required"`
+	AdminID string             `json:"admin_id" validate:"required"`
+	Status  domain.OrderStatus `json:"status" validate:"required"`
+	Reason  string             `json:"reason" validate:"required"`
+}
+
+// CreatePromotionCommand represents the command to create a promotion. An
+// empty Code creates a promotion that applies automatically.
+type CreatePromotionCommand struct {
+	UserID         string               `json:"user_id" validate:"required"`
+	Role           string               `json:"role" validate:"required"`
+	Code           string               `json:"code"`
+	Description    string               `json:"description"`
+	Kind           domain.PromotionKind `json:"kind" validate:"required"`
+	Value          money.Decimal        `json:"value" validate:"required"`
+	Currency       string               `json:"currency"`
+	ProphetID      string               `json:"prophet_id"`
+	FirstOrderOnly bool                 `json:"first_order_only"`
+	MaxRedemptions int                  `json:"max_redemptions"`
+	MaxPerCustomer int                  `json:"max_per_customer"`
+	StartsAt       time.Time            `json:"starts_at"`
+	ExpiresAt      *time.Time           `json:"expires_at"`
+}
@@ -2,31 +2,139 @@ package outbound
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/wnmay/horo/services/order-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+	"github.com/wnmay/horo/shared/money"
 )
 
 // OrderRepository defines the interface for order data persistence
 type OrderRepository interface {
 	Create(ctx context.Context, order *domain.Order) error
-	GetAll(ctx context.Context) ([]*domain.Order, error)
+	// GetAll pages through orders, only those in status unless it is empty
+	GetAll(ctx context.Context, status domain.OrderStatus, page contract.PageRequest) (contract.Page[*domain.Order], error)
 	GetByID(ctx context.Context, orderID uuid.UUID) (*domain.Order, error)
+	// GetByIDForUpdate locks the order for the rest of the transaction in ctx
+	GetByIDForUpdate(ctx context.Context, orderID uuid.UUID) (*domain.Order, error)
+	BackfillAutoCompleteAt(ctx context.Context, disputeWindow time.Duration) (int64, error)
+	GetDueForAutoCompletion(ctx context.Context, now time.Time, limit int) ([]*domain.Order, error)
 	GetByCustomerID(ctx context.Context, customerID string) ([]*domain.Order, error)
+	GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*domain.Order, error)
 	GetByRoomID(ctx context.Context, roomID string) ([]*domain.Order, error)
 	Update(ctx context.Context, order *domain.Order) error
 	Delete(ctx context.Context, orderID uuid.UUID) error
+	// CountByStatus counts the orders placed in [from, to) in each status
+	CountByStatus(ctx context.Context, from, to time.Time) (map[domain.OrderStatus]int, error)
+	// SalesByProphet totals the charges of the orders placed in [from, to)
+	// that were paid for, per prophet and charge currency, largest first
+	SalesByProphet(ctx context.Context, from, to time.Time) ([]*domain.ProphetSales, error)
+}
+
+// AuditRepository stores the record of admins forcing orders between statuses
+type AuditRepository interface {
+	Create(ctx context.Context, audit *domain.OrderAudit) error
+	// ListByOrder returns an order's audit records, oldest first
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderAudit, error)
+}
+
+// PromotionRepository defines the interface for promotion persistence.
+// Promotions are returned with their current redemption count.
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *domain.Promotion) error
+	GetByID(ctx context.Context, promotionID uuid.UUID) (*domain.Promotion, error)
+	// GetByIDForUpdate locks the promotion for the rest of the transaction in
+	// ctx, serialising redemptions against its usage limits
+	GetByIDForUpdate(ctx context.Context, promotionID uuid.UUID) (*domain.Promotion, error)
+	// GetByCode returns domain.ErrPromotionNotFound for unknown codes
+	GetByCode(ctx context.Context, code string) (*domain.Promotion, error)
+	// List returns promotions scoped to prophetID, or all when it is empty
+	List(ctx context.Context, prophetID string) ([]*domain.Promotion, error)
+	// ListAutomatic returns active promotions without a code that are live at t
+	ListAutomatic(ctx context.Context, t time.Time) ([]*domain.Promotion, error)
+	Update(ctx context.Context, promotion *domain.Promotion) error
+	CountCustomerRedemptions(ctx context.Context, promotionID uuid.UUID, customerID string) (int, error)
+	AddRedemption(ctx context.Context, redemption *domain.PromotionRedemption) error
+	// RemoveRedemption frees the redemption made by an order, if any
+	RemoveRedemption(ctx context.Context, orderID uuid.UUID) error
+}
+
+// SubscriptionRepository defines the interface for subscription persistence
+type SubscriptionRepository interface {
+	Create(ctx context.Context, subscription *domain.Subscription) error
+	// GetByID returns domain.ErrSubscriptionNotFound for unknown subscriptions
+	GetByID(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error)
+	// GetByIDForUpdate locks the subscription for the rest of the transaction in ctx
+	GetByIDForUpdate(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error)
+	ListByCustomer(ctx context.Context, customerID string) ([]*domain.Subscription, error)
+	ListByProphet(ctx context.Context, prophetID string) ([]*domain.Subscription, error)
+	// GetDue returns subscriptions the renewal scheduler has work for at now:
+	// periods that ran out, unpaid periods past their grace and renewals whose
+	// payment was not issued yet
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error)
+	Update(ctx context.Context, subscription *domain.Subscription) error
+}
+
+// TransactionManager runs a unit of work atomically. Repositories called with
+// the context passed to fn take part in the same transaction.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxRepository stores domain events until the relay hands them to the broker
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, message *domain.OutboxMessage) error
+	// ProcessPending locks up to limit unpublished messages in creation order and
+	// passes each to fn. Messages are marked published when fn succeeds; the
+	// first failure records the error and stops the batch to preserve ordering.
+	// Only failures wrapping domain.ErrOutboxMessageRejected count as attempts:
+	// a message rejected maxAttempts times is parked and skipped from then on,
+	// and zero retries it forever. Other failures are retried without limit.
+	ProcessPending(ctx context.Context, limit int, maxAttempts int, fn func(ctx context.Context, message *domain.OutboxMessage) error) (int, error)
+	// ListFailed returns up to limit parked messages, oldest first
+	ListFailed(ctx context.Context, limit int) ([]*domain.OutboxMessage, error)
+	// ReplayFailed returns the parked messages with the given IDs, or all of
+	// them when ids is empty, to the queue and reports how many it returned
+	ReplayFailed(ctx context.Context, ids []uuid.UUID) (int, error)
 }
 
 // EventPublisher defines the interface for publishing domain events
 type EventPublisher interface {
 	PublishOrderCreated(ctx context.Context, order *domain.Order) error
 	PublishOrderCompleted(ctx context.Context, order *domain.Order) error
+	PublishOrderSessionCompleted(ctx context.Context, order *domain.Order) error
 	PublishOrderPaid(ctx context.Context, order *domain.Order) error
 	PublishOrderPaymentBound(ctx context.Context, order *domain.Order) error
+	PublishOrderCancelled(ctx context.Context, order *domain.Order, previousStatus domain.OrderStatus) error
+	PublishOrderDisputed(ctx context.Context, order *domain.Order) error
+	PublishOrderDisputeResolved(ctx context.Context, order *domain.Order) error
+	PublishOrderRescheduled(ctx context.Context, order *domain.Order, previousStart *time.Time) error
+	PublishSubscriptionRenewed(ctx context.Context, subscription *domain.Subscription, order *domain.Order) error
+	PublishSubscriptionLapsed(ctx context.Context, subscription *domain.Subscription, reason string) error
+}
+
+// CourseProvider defines the interface for course lookups
+type CourseProvider interface {
+	GetProphetID(ctx context.Context, courseID string) (string, error)
+	GetCourseSnapshot(ctx context.Context, courseID string) (*domain.CourseSnapshot, error)
+}
+
+// BookingProvider reserves and releases session slots with course-service
+type BookingProvider interface {
+	ReserveSlot(ctx context.Context, order *domain.Order, startAt time.Time) (*domain.SessionBooking, error)
+	ReleaseSlot(ctx context.Context, bookingID string) error
+}
+
+// ExchangeRateProvider quotes the rate for converting between currencies
+type ExchangeRateProvider interface {
+	// GetRate returns how many units of to one unit of from buys, or
+	// domain.ErrRateUnavailable when the pair is not quoted
+	GetRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error)
 }
 
 // PaymentService defines the interface for payment operations
 type PaymentService interface {
-	CreatePayment(ctx context.Context, orderID uuid.UUID, amount float64) error
+	CreatePayment(ctx context.Context, orderID uuid.UUID, amount money.Decimal, currency string) (*domain.PaymentInfo, error)
+	GetPaymentByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.PaymentInfo, error)
 }
\ No newline at end of file
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,19 +11,29 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 
+	grpcin "github.com/wnmay/horo/services/payment-service/internal/adapters/inbound/grpc"
 	"github.com/wnmay/horo/services/payment-service/internal/adapters/inbound/http"
 	inboundMessage "github.com/wnmay/horo/services/payment-service/internal/adapters/inbound/message"
 	"github.com/wnmay/horo/services/payment-service/internal/adapters/outbound/db"
 	"github.com/wnmay/horo/services/payment-service/internal/adapters/outbound/message"
+	"github.com/wnmay/horo/services/payment-service/internal/adapters/outbound/provider"
 	"github.com/wnmay/horo/services/payment-service/internal/app"
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
 	sharedDB "github.com/wnmay/horo/shared/db"
 	"github.com/wnmay/horo/shared/env"
+	"github.com/wnmay/horo/shared/jwt"
 	sharedMessage "github.com/wnmay/horo/shared/message"
+	"github.com/wnmay/horo/shared/money"
+	pb "github.com/wnmay/horo/shared/proto/payment"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
 	_ = env.LoadEnv("payment-service")
+	jwt.InitFromEnv(jwt.ServicePayment)
 	port := env.GetString("REST_PORT", "3001")
+	grpcPort := env.GetString("GRPC_PORT", "50054")
 
 	log.Println("Starting payment service...")
 
@@ -45,11 +57,74 @@ func main() {
 	// Initialize publisher
 	eventPublisher := message.NewPublisher(rabbit)
 	
+	// Initialize payment provider. The bundled fake gateway runs in-process so
+	// checkout and webhooks work locally without an external account.
+	webhookRepo := db.NewGormWebhookRepository(gormDB)
+	providerName := env.GetString("PAYMENT_PROVIDER", provider.FakeProviderName)
+	if providerName != provider.FakeProviderName {
+		log.Fatalf("Unsupported payment provider: %s", providerName)
+	}
+	fakeProviderURL := env.GetString("FAKE_PROVIDER_URL", "http://localhost:4242")
+	fakeProviderSecret := env.GetString("FAKE_PROVIDER_SIGNING_SECRET", "whsec_local_dev")
+	paymentProvider := provider.NewFakeProvider(fakeProviderURL, fakeProviderSecret)
+
+	var fakeGatewayApp *fiber.App
+	if env.GetBool("FAKE_PROVIDER_EMBEDDED", true) {
+		fakeGatewayPort := env.GetString("FAKE_PROVIDER_PORT", "4242")
+		webhookURL := env.GetString("FAKE_PROVIDER_WEBHOOK_URL", "http://localhost:"+port+"/api/payments/webhooks/"+provider.FakeProviderName)
+		fakeGateway := provider.NewFakeGateway(fakeProviderURL, webhookURL, fakeProviderSecret)
+		fakeGatewayApp = fiber.New(fiber.Config{AppName: "Fake Payment Provider"})
+		fakeGateway.Register(fakeGatewayApp)
+		go func() {
+			log.Printf("Fake payment provider listening on port :%s", fakeGatewayPort)
+			if err := fakeGatewayApp.Listen(":" + fakeGatewayPort); err != nil {
+				log.Println("Fake payment provider stopped:", err)
+			}
+		}()
+	}
+
 	// Initialize application service
-	paymentService := app.NewPaymentService(paymentRepo, eventPublisher)
+	ledgerRepo := db.NewGormLedgerRepository(gormDB)
+	payoutRepo := db.NewGormPayoutRepository(gormDB)
+	feeRuleRepo := db.NewGormFeeRuleRepository(gormDB)
+	auditRepo := db.NewGormAuditRepository(gormDB)
+	// Applies when no stored fee rule matches a settlement. Its flat fee is
+	// only charged on settlements in PLATFORM_FEE_CURRENCY.
+	feePercent, err := money.Parse(env.GetString("PLATFORM_FEE_PERCENT", "0"))
+	if err != nil {
+		log.Fatalf("Invalid PLATFORM_FEE_PERCENT: %v", err)
+	}
+	feeFlat, err := money.Parse(env.GetString("PLATFORM_FEE_FLAT", "0"))
+	if err != nil {
+		log.Fatalf("Invalid PLATFORM_FEE_FLAT: %v", err)
+	}
+	feeCurrency, err := money.NormalizeCurrency(env.GetString("PLATFORM_FEE_CURRENCY", money.DefaultCurrency))
+	if err != nil {
+		log.Fatalf("Invalid PLATFORM_FEE_CURRENCY: %v", err)
+	}
+	defaultFeeRule := &domain.FeeRule{
+		ID:         domain.DefaultFeeRuleID,
+		Currency:   feeCurrency,
+		Percentage: feePercent,
+		FlatFee:    money.RoundTo(feeFlat, feeCurrency),
+	}
+	paymentService := app.NewPaymentService(paymentRepo, eventPublisher, paymentProvider, webhookRepo, ledgerRepo, payoutRepo, feeRuleRepo, auditRepo, paymentRepo, defaultFeeRule)
+
+	// Payments settled before the ledger existed still count towards balances
+	if posted, err := paymentService.BackfillSettlementLedger(context.Background()); err != nil {
+		log.Printf("Failed to backfill settlement ledger: %v", err)
+	} else if posted > 0 {
+		log.Printf("Backfilled %d settlements into the ledger", posted)
+	}
 	
+	// Initialize processed message ledger for idempotent consumers
+	processedStore, err := sharedMessage.NewPostgresProcessedMessageStore(gormDB, paymentRepo)
+	if err != nil {
+		log.Fatal("Failed to initialize processed message store:", err)
+	}
+
 	// Initialize consumer
-	consumer := inboundMessage.NewConsumer(paymentService, rabbit)
+	consumer := inboundMessage.NewConsumer(paymentService, rabbit, processedStore)
 	go func() {
 		log.Println("Starting order created consumer...")
 		if err := consumer.StartListening(); err != nil {
@@ -58,7 +133,7 @@ func main() {
 	}()
 	
 	// Initialize HTTP server
-	httpHandler := http.NewHandler(paymentService)
+	httpHandler := http.NewHandler(paymentService, paymentProvider.SignatureHeader())
 	
 	// Initialize fiber app
 	appFiber := fiber.New(fiber.Config{
@@ -67,6 +142,7 @@ func main() {
 	
 	// Add middleware
 	appFiber.Use(cors.New())
+	appFiber.Use(jwt.IdentityFromToken())
 	
 	// Health check
 	appFiber.Get("/health", func(c *fiber.Ctx) error {
@@ -87,14 +163,35 @@ func main() {
 		}
 	}()
 	
+	// Start gRPC server
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(jwt.UnaryServerIdentity()))
+	pb.RegisterPaymentServiceServer(grpcServer, grpcin.NewPaymentGRPCServer(paymentService))
+	reflection.Register(grpcServer)
+	go func() {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("Failed to listen on %s: %v", grpcPort, err)
+		}
+		log.Printf("Payment gRPC service listening on port :%s", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Println("gRPC server stopped:", err)
+		}
+	}()
+
 	// Wait for shutdown signal
 	waitForSignal()
 	
 	// Graceful shutdown
 	log.Println("Shutting down payment service...")
+	grpcServer.GracefulStop()
 	if err := appFiber.Shutdown(); err != nil {
 		log.Printf("Error during shutdown: %v", err)
 	}
+	if fakeGatewayApp != nil {
+		if err := fakeGatewayApp.Shutdown(); err != nil {
+			log.Printf("Error shutting down fake payment provider: %v", err)
+		}
+	}
 }
 
 func waitForSignal() {
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	pb "github.com/wnmay/horo/shared/proto/payment"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toPbPaymentStatus(s domain.PaymentStatus) pb.PaymentStatus {
+	switch s {
+	case domain.PaymentStatusPending:
+		return pb.PaymentStatus_PAYMENT_STATUS_PENDING
+	case domain.PaymentStatusCompleted:
+		return pb.PaymentStatus_PAYMENT_STATUS_COMPLETED
+	case domain.PaymentStatusSettled:
+		return pb.PaymentStatus_PAYMENT_STATUS_SETTLED
+	case domain.PaymentStatusFailed:
+		return pb.PaymentStatus_PAYMENT_STATUS_FAILED
+	case domain.PaymentStatusRefunded:
+		return pb.PaymentStatus_PAYMENT_STATUS_REFUNDED
+	default:
+		return pb.PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
+	}
+}
+
+func toPbPayment(p *domain.Payment) *pb.Payment {
+	if p == nil {
+		return nil
+	}
+	return &pb.Payment{
+		PaymentId:   p.PaymentID,
+		OrderId:     p.OrderID,
+		ProphetId:   p.ProphetID,
+		Amount:      p.Amount.Float64(),
+		Status:      toPbPaymentStatus(p.Status),
+		Provider:    p.Provider,
+		ProviderRef: p.ProviderRef,
+		CreatedTime: timestamppb.New(p.CreatedAt),
+		UpdatedTime: timestamppb.New(p.UpdatedAt),
+		CourseType:  p.CourseType,
+		GrossAmount: p.GrossAmount.Float64(),
+		FeeAmount:   p.FeeAmount.Float64(),
+		NetAmount:   p.NetAmount.Float64(),
+		FeeRuleId:   p.FeeRuleID,
+		Currency:    p.Currency,
+	}
+}
+
+func toPbCurrencyBalance(b domain.CurrencyBalance) *pb.CurrencyBalance {
+	return &pb.CurrencyBalance{
+		Currency:         b.Currency,
+		Balance:          b.Balance.Float64(),
+		AvailableBalance: b.AvailableBalance.Float64(),
+		GrossSettled:     b.GrossSettled.Float64(),
+		PlatformFees:     b.PlatformFees.Float64(),
+		NetSettled:       b.NetSettled.Float64(),
+	}
+}
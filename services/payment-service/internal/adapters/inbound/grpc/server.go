@@ -1 +1,132 @@
-package grpc
\ No newline at end of file
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/jwt"
+	"github.com/wnmay/horo/shared/money"
+	pb "github.com/wnmay/horo/shared/proto/payment"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PaymentGRPCServer struct {
+	pb.UnimplementedPaymentServiceServer
+	svc inbound.PaymentService
+}
+
+func NewPaymentGRPCServer(s inbound.PaymentService) *PaymentGRPCServer {
+	return &PaymentGRPCServer{svc: s}
+}
+
+// CreatePayment, GetPayment and GetPaymentByOrder are only for services:
+// payments do not record their customer, so a user's access to them is
+// checked on their order instead. Order-service creates and finds the
+// payments of its orders, and the gateway looks payments up to find their
+// order.
+func (s *PaymentGRPCServer) CreatePayment(ctx context.Context, req *pb.CreatePaymentRequest) (*pb.CreatePaymentResponse, error) {
+	if err := jwt.RequireService(ctx, jwt.ServiceOrder); err != nil {
+		return nil, err
+	}
+	if req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	amount, err := money.NewFromFloat(req.GetAmount())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	p, err := s.svc.CreatePaymentFromOrder(ctx, inbound.CreatePaymentCommand{
+		OrderID:  req.GetOrderId(),
+		Amount:   amount,
+		Currency: req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CreatePaymentResponse{Payment: toPbPayment(p)}, nil
+}
+
+func (s *PaymentGRPCServer) GetPayment(ctx context.Context, req *pb.GetPaymentRequest) (*pb.GetPaymentResponse, error) {
+	if err := jwt.RequireService(ctx, jwt.ServiceAPIGateway, jwt.ServiceOrder); err != nil {
+		return nil, err
+	}
+	if req.GetPaymentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_id is required")
+	}
+	p, err := s.svc.GetPayment(ctx, req.GetPaymentId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetPaymentResponse{Payment: toPbPayment(p)}, nil
+}
+
+func (s *PaymentGRPCServer) GetPaymentByOrder(ctx context.Context, req *pb.GetPaymentByOrderRequest) (*pb.GetPaymentByOrderResponse, error) {
+	if err := jwt.RequireService(ctx, jwt.ServiceOrder); err != nil {
+		return nil, err
+	}
+	if req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	p, err := s.svc.GetPaymentByOrderID(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetPaymentByOrderResponse{Payment: toPbPayment(p)}, nil
+}
+
+func (s *PaymentGRPCServer) GetProphetBalance(ctx context.Context, req *pb.GetProphetBalanceRequest) (*pb.GetProphetBalanceResponse, error) {
+	if req.GetProphetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "prophet_id is required")
+	}
+	if err := jwt.AuthorizeUser(ctx, req.GetProphetId()); err != nil {
+		return nil, err
+	}
+	balance, err := s.svc.GetProphetBalanceSummary(ctx, req.GetProphetId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	balances := make([]*pb.CurrencyBalance, 0, len(balance.Balances))
+	for _, b := range balance.Balances {
+		balances = append(balances, toPbCurrencyBalance(b))
+	}
+	return &pb.GetProphetBalanceResponse{
+		ProphetId: balance.ProphetID,
+		Balance:   balance.In(money.DefaultCurrency).Balance.Float64(),
+		Balances:  balances,
+	}, nil
+}
+
+func (s *PaymentGRPCServer) ListPaymentsByProphet(ctx context.Context, req *pb.ListPaymentsByProphetRequest) (*pb.ListPaymentsByProphetResponse, error) {
+	if req.GetProphetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "prophet_id is required")
+	}
+	if err := jwt.AuthorizeUser(ctx, req.GetProphetId()); err != nil {
+		return nil, err
+	}
+	payments, err := s.svc.ListPaymentsByProphet(ctx, req.GetProphetId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	out := make([]*pb.Payment, 0, len(payments))
+	for _, p := range payments {
+		out = append(out, toPbPayment(p))
+	}
+	return &pb.ListPaymentsByProphetResponse{Payments: out}, nil
+}
+
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, money.ErrInvalidCurrency):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
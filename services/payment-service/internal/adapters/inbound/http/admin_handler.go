@@ -0,0 +1,73 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+)
+
+// ListPayments returns the payments in ?status=, which is required
+func (h *Handler) ListPayments(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can list payments"})
+	}
+
+	status, err := domain.ParsePaymentStatus(c.Query("status"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	payments, err := h.paymentSvc.ListPaymentsByStatus(c.Context(), status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(payments)
+}
+
+// ForcePaymentStatus moves a payment to the requested status, recording the
+// admin's reason in its audit trail
+func (h *Handler) ForcePaymentStatus(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can change payment status"})
+	}
+
+	var cmd inbound.ForcePaymentStatusCommand
+	if err := c.BodyParser(&cmd); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	cmd.PaymentID = c.Params("id")
+	cmd.AdminID = c.Get("X-User-Id")
+
+	payment, err := h.paymentSvc.ForcePaymentStatus(c.Context(), cmd)
+	if err != nil {
+		return adminPaymentError(c, err)
+	}
+	return c.JSON(payment)
+}
+
+func (h *Handler) GetPaymentAudit(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can view payment audits"})
+	}
+
+	audits, err := h.paymentSvc.GetPaymentAudit(c.Context(), c.Params("id"))
+	if err != nil {
+		return adminPaymentError(c, err)
+	}
+	return c.JSON(audits)
+}
+
+func adminPaymentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAuditReasonRequired), errors.Is(err, domain.ErrInvalidPaymentStatus):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
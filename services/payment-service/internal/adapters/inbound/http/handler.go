@@ -1,17 +1,28 @@
 package http
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
 	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type Handler struct {
-	paymentSvc inbound.PaymentService
+	paymentSvc      inbound.PaymentService
+	signatureHeader string
 }
 
-func NewHandler(paymentSvc inbound.PaymentService) *Handler {
+// signatureHeader names the header the configured provider signs webhooks with
+func NewHandler(paymentSvc inbound.PaymentService, signatureHeader string) *Handler {
 	return &Handler{
-		paymentSvc: paymentSvc,
+		paymentSvc:      paymentSvc,
+		signatureHeader: signatureHeader,
 	}
 }
 
@@ -20,9 +31,40 @@ func (h *Handler) Register(app *fiber.App) {
 	payments := api.Group("/payments")
 
 	payments.Get("/balance", h.GetProphetBalance)
+	payments.Get("/ledger", h.GetLedgerStatement)
+	payments.Get("/payouts", h.ListPayouts)
+	payments.Post("/payouts", h.RequestPayout)
+	payments.Post("/payouts/:id/approve", h.ApprovePayout)
+	payments.Post("/payouts/:id/reject", h.RejectPayout)
+	payments.Post("/payouts/:id/paid", h.MarkPayoutPaid)
+	payments.Post("/payouts/:id/cancel", h.CancelPayout)
+	payments.Get("/fee-rules", h.ListFeeRules)
+	payments.Post("/fee-rules", h.CreateFeeRule)
+	payments.Post("/fee-rules/:id/end", h.EndFeeRule)
 	payments.Get("/order/:orderID", h.GetPaymentByOrder)
+	payments.Post("/webhooks/:provider", h.ProviderWebhook)
+	payments.Get("/", h.ListPayments)
 	payments.Get("/:id", h.GetPayment)
-	payments.Put("/:id/complete", h.CompletePayment)
+	payments.Put("/:id/complete", h.AuthMiddleware, h.CompletePayment)
+	payments.Post("/:id/checkout", h.CreateCheckoutSession)
+	payments.Get("/:id/webhooks", h.GetWebhookHistory)
+	payments.Post("/:id/force-status", h.ForcePaymentStatus)
+	payments.Get("/:id/audit", h.GetPaymentAudit)
+}
+
+// AuthMiddleware refuses requests without a user, as verified from the
+// gateway's identity assertion
+func (h *Handler) AuthMiddleware(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "user not authenticated",
+		})
+	}
+
+	c.Locals("userID", userID)
+	c.Locals("role", c.Get("X-User-Role"))
+	return c.Next()
 }
 
 func (h *Handler) GetPayment(c *fiber.Ctx) error {
@@ -61,7 +103,13 @@ func (h *Handler) GetPaymentByOrder(c *fiber.Ctx) error {
 	return c.JSON(payment)
 }
 
+// CompletePayment marks a payment captured on an admin's say-so. Captures
+// from the provider arrive through ProviderWebhook instead.
 func (h *Handler) CompletePayment(c *fiber.Ctx) error {
+	if c.Locals("role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can complete payments"})
+	}
+
 	paymentID := c.Params("id")
 	if paymentID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -95,10 +143,373 @@ func (h *Handler) GetProphetBalance(c *fiber.Ctx) error {
 	}
 
 
-    amount, err := h.paymentSvc.GetProphetBalance(c.Context(), userID)
+    balance, err := h.paymentSvc.GetProphetBalanceSummary(c.Context(), userID)
     if err != nil {
         return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
     }
 
-    return c.JSON(amount)
+    return c.JSON(balance)
+}
+
+func (h *Handler) CreateCheckoutSession(c *fiber.Ctx) error {
+	paymentID := c.Params("id")
+	if paymentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Payment ID is required",
+		})
+	}
+
+	session, err := h.paymentSvc.CreateCheckoutSession(c.Context(), paymentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(session)
+}
+
+// ProviderWebhook receives signed notifications from the payment provider.
+// It is called by the provider, not by users, so it carries no user headers.
+func (h *Handler) ProviderWebhook(c *fiber.Ctx) error {
+	provider := c.Params("provider")
+	signature := c.Get(h.signatureHeader)
+
+	err := h.paymentSvc.HandleProviderWebhook(c.Context(), provider, c.Body(), signature)
+	switch {
+	case err == nil:
+		return c.JSON(fiber.Map{"received": true})
+	case errors.Is(err, domain.ErrUnknownProvider):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidWebhookSignature):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	default:
+		// A non-2xx response makes the provider retry delivery
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
+
+func (h *Handler) GetWebhookHistory(c *fiber.Ctx) error {
+	paymentID := c.Params("id")
+	if paymentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Payment ID is required",
+		})
+	}
+
+	records, err := h.paymentSvc.GetWebhookHistory(c.Context(), paymentID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(records)
+}
+
+const (
+	roleProphet = "prophet"
+	roleAdmin   = "admin"
+)
+
+const statementDateLayout = "2006-01-02"
+
+type RequestPayoutRequest struct {
+	Amount   money.Decimal `json:"amount" validate:"required"`
+	Currency string        `json:"currency"`
+}
+
+type RejectPayoutRequest struct {
+	Reason string `json:"reason"`
+}
+
+type MarkPayoutPaidRequest struct {
+	Reference string `json:"reference" validate:"required"`
+}
+
+type EndFeeRuleRequest struct {
+	EffectiveTo *time.Time `json:"effective_to"`
+}
+
+// GetLedgerStatement returns the prophet's ledger movements in one currency
+// (THB by default) between from and to (YYYY-MM-DD, both inclusive; the last
+// 30 days by default). Pass format=csv to download the statement as CSV.
+func (h *Handler) GetLedgerStatement(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "userID is required"})
+	}
+	if c.Get("X-User-Role") != roleProphet {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only prophets can access their ledger"})
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, err := parseStatementDate(c.Query("from"), today.AddDate(0, 0, -30))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from must be YYYY-MM-DD"})
+	}
+	to, err := parseStatementDate(c.Query("to"), today)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to must be YYYY-MM-DD"})
+	}
+
+	statement, err := h.paymentSvc.GetProphetStatement(c.Context(), userID, c.Query("currency"), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidStatementRange) || errors.Is(err, money.ErrInvalidCurrency) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if strings.EqualFold(c.Query("format"), "csv") {
+		return writeStatementCSV(c, statement, from, to)
+	}
+	return c.JSON(statement)
+}
+
+func parseStatementDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse(statementDateLayout, value)
+}
+
+func writeStatementCSV(c *fiber.Ctx, statement *domain.Statement, from, to time.Time) error {
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="ledger_%s_%s_%s.csv"`,
+		statement.Currency, from.Format(statementDateLayout), to.Format(statementDateLayout)))
+
+	places := money.MinorUnits(statement.Currency)
+	amount := func(d money.Decimal) string { return d.StringFixed(places) }
+
+	w := csv.NewWriter(c.Response().BodyWriter())
+	_ = w.Write([]string{"date", "kind", "description", "reference", "payment_id", "payout_id", "currency", "debit", "credit", "balance"})
+	_ = w.Write([]string{from.Format(time.RFC3339), "OPENING_BALANCE", "", "", "", "", statement.Currency, "", "", amount(statement.OpeningBalance)})
+	for _, line := range statement.Lines {
+		_ = w.Write([]string{
+			line.CreatedAt.UTC().Format(time.RFC3339),
+			string(line.Kind),
+			line.Description,
+			line.Reference,
+			line.PaymentID,
+			line.PayoutID,
+			statement.Currency,
+			amount(line.Debit),
+			amount(line.Credit),
+			amount(line.Balance),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// ListPayouts returns the calling prophet's payouts with their available
+// balance in the currency query parameter, THB by default. Admins list payouts
+// in a given status instead, REQUESTED by default.
+func (h *Handler) ListPayouts(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "userID is required"})
+	}
+
+	switch c.Get("X-User-Role") {
+	case roleAdmin:
+		status := domain.PayoutStatus(strings.ToUpper(c.Query("status", string(domain.PayoutStatusRequested))))
+		payouts, err := h.paymentSvc.ListPayoutsByStatus(c.Context(), status)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(payouts)
+	case roleProphet:
+		payouts, err := h.paymentSvc.ListProphetPayouts(c.Context(), userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		currency, err := money.NormalizeCurrency(c.Query("currency"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		available, err := h.paymentSvc.GetAvailableBalance(c.Context(), userID, currency)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{
+			"currency":          currency,
+			"available_balance": available,
+			"payouts":           payouts,
+		})
+	default:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only prophets or admins can access payouts"})
+	}
+}
+
+func (h *Handler) RequestPayout(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "userID is required"})
+	}
+	if c.Get("X-User-Role") != roleProphet {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only prophets can request payouts"})
+	}
+
+	var req RequestPayoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	payout, err := h.paymentSvc.RequestPayout(c.Context(), userID, req.Amount, req.Currency)
+	if err != nil {
+		return payoutError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(payout)
+}
+
+func (h *Handler) CancelPayout(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "userID is required"})
+	}
+	if c.Get("X-User-Role") != roleProphet {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only prophets can cancel their payouts"})
+	}
+
+	payout, err := h.paymentSvc.CancelPayout(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return payoutError(c, err)
+	}
+	return c.JSON(payout)
+}
+
+func (h *Handler) ApprovePayout(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can review payouts"})
+	}
+
+	payout, err := h.paymentSvc.ApprovePayout(c.Context(), c.Params("id"), c.Get("X-User-Id"))
+	if err != nil {
+		return payoutError(c, err)
+	}
+	return c.JSON(payout)
+}
+
+func (h *Handler) RejectPayout(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can review payouts"})
+	}
+
+	var req RejectPayoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	payout, err := h.paymentSvc.RejectPayout(c.Context(), c.Params("id"), c.Get("X-User-Id"), req.Reason)
+	if err != nil {
+		return payoutError(c, err)
+	}
+	return c.JSON(payout)
+}
+
+func (h *Handler) MarkPayoutPaid(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can mark payouts as paid"})
+	}
+
+	var req MarkPayoutPaidRequest
+	if err := c.BodyParser(&req); err != nil || req.Reference == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Transfer reference is required"})
+	}
+
+	payout, err := h.paymentSvc.MarkPayoutPaid(c.Context(), c.Params("id"), req.Reference)
+	if err != nil {
+		return payoutError(c, err)
+	}
+	return c.JSON(payout)
+}
+
+func payoutError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPayoutNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidPayoutAmount), errors.Is(err, money.ErrInvalidCurrency):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInsufficientBalance), errors.Is(err, domain.ErrInvalidPayoutState):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
+
+func (h *Handler) ListFeeRules(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can manage fee rules"})
+	}
+
+	rules, err := h.paymentSvc.ListFeeRules(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(rules)
+}
+
+// CreateFeeRule adds a platform fee rule. Rules for a prophet override rules
+// for a course type, which override platform-wide rules.
+func (h *Handler) CreateFeeRule(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can manage fee rules"})
+	}
+
+	var cmd inbound.CreateFeeRuleCommand
+	if err := c.BodyParser(&cmd); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	cmd.CreatedBy = c.Get("X-User-Id")
+
+	rule, err := h.paymentSvc.CreateFeeRule(c.Context(), cmd)
+	if err != nil {
+		return feeRuleError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// EndFeeRule stops a rule from applying to settlements from effective_to
+// onwards, or from now when it is omitted
+func (h *Handler) EndFeeRule(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != roleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can manage fee rules"})
+	}
+
+	var req EndFeeRuleRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	at := time.Now()
+	if req.EffectiveTo != nil {
+		at = *req.EffectiveTo
+	}
+
+	rule, err := h.paymentSvc.EndFeeRule(c.Context(), c.Params("id"), at)
+	if err != nil {
+		return feeRuleError(c, err)
+	}
+	return c.JSON(rule)
+}
+
+func feeRuleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrFeeRuleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidFeeRule):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
 }
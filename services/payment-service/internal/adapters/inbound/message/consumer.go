@@ -14,12 +14,14 @@ import (
 type Consumer struct {
 	paymentService inbound.PaymentService
 	rabbit         *message.RabbitMQ
+	processed      message.ProcessedMessageStore
 }
 
-func NewConsumer(paymentService inbound.PaymentService, rabbit *message.RabbitMQ) *Consumer {
+func NewConsumer(paymentService inbound.PaymentService, rabbit *message.RabbitMQ, processed message.ProcessedMessageStore) *Consumer {
 	return &Consumer{
 		paymentService: paymentService,
 		rabbit:         rabbit,
+		processed:      processed,
 	}
 }
 
@@ -31,19 +33,32 @@ func (c *Consumer) StartListening() error {
 	if err := c.rabbit.DeclareQueue(createPaymentQueue, OrderCreateRoutingKey); err != nil {
 		return err
 	}
-    if err := c.rabbit.ConsumeMessages(createPaymentQueue, c.handleOrderCreated); err != nil {
+    if err := c.rabbit.ConsumeMessages(createPaymentQueue, message.Idempotent(c.processed, createPaymentQueue, c.handleOrderCreated)); err != nil {
         return err
     }
 
 	settlePaymentQueue := message.SettlePaymentQueue
-	OrderCompleteRoutingKey := contract.OrderCompletedEvent
 
-	if err := c.rabbit.DeclareQueue(settlePaymentQueue, OrderCompleteRoutingKey); err != nil {
+	// Bundles settle each session as it completes; the last one completes the order
+	if err := c.rabbit.DeclareQueueAndBindEvents(settlePaymentQueue, []string{
+		contract.OrderCompletedEvent,
+		contract.OrderSessionCompletedEvent,
+	}); err != nil {
 		return err
 	}
-    if err := c.rabbit.ConsumeMessages(settlePaymentQueue, c.handleOrderCompleted); err != nil {
+    if err := c.rabbit.ConsumeMessages(settlePaymentQueue, message.Idempotent(c.processed, settlePaymentQueue, c.handleOrderCompleted)); err != nil {
         return err
     }
+
+	refundPaymentQueue := message.RefundPaymentQueue
+	OrderCancelRoutingKey := contract.OrderCancelledEvent
+
+	if err := c.rabbit.DeclareQueue(refundPaymentQueue, OrderCancelRoutingKey); err != nil {
+		return err
+	}
+	if err := c.rabbit.ConsumeMessages(refundPaymentQueue, message.Idempotent(c.processed, refundPaymentQueue, c.handleOrderCancelled)); err != nil {
+		return err
+	}
  
 	return nil
 
@@ -64,13 +79,14 @@ func (c *Consumer) handleOrderCreated(ctx context.Context, delivery amqp.Deliver
 		return err
 	}
 
-	log.Printf("Processing order created event for order: %s, amount: %.2f", 
-		orderData.OrderID, orderData.Amount)
+	log.Printf("Processing order created event for order: %s, amount: %s %s", 
+		orderData.OrderID, orderData.Amount, orderData.Currency)
 
-	// Create payment command
+	// Charge what the customer was quoted, in the order's charge currency
 	cmd := inbound.CreatePaymentCommand{
-		OrderID: orderData.OrderID,
-		Amount:  orderData.Amount,
+		OrderID:  orderData.OrderID,
+		Amount:   orderData.Amount,
+		Currency: orderData.Currency,
 	}
 
 	// Call payment service to create payment
@@ -102,7 +118,14 @@ func (c *Consumer) handleOrderCompleted(ctx context.Context, delivery amqp.Deliv
 
     log.Printf("Processing order completed event for order: %s", orderCompletedData.OrderID)
 
-    if err := c.paymentService.SettlePayment(ctx, orderCompletedData.OrderID, orderCompletedData.ProphetID); err != nil {
+    cmd := inbound.SettlePaymentCommand{
+        OrderID:    orderCompletedData.OrderID,
+        ProphetID:  orderCompletedData.ProphetID,
+        CourseType: orderCompletedData.CourseType,
+        Session:    orderCompletedData.Session,
+        Sessions:   orderCompletedData.Sessions,
+    }
+    if err := c.paymentService.SettlePayment(ctx, cmd); err != nil {
         log.Printf("Failed to complete payment %s for order %s: %v",
             orderCompletedData.OrderID, orderCompletedData.OrderID, err)
         return err
@@ -110,4 +133,33 @@ func (c *Consumer) handleOrderCompleted(ctx context.Context, delivery amqp.Deliv
 
     log.Printf("Successfully completed payment for order %s", orderCompletedData.OrderID)
     return nil
-}
\ No newline at end of file
+}
+
+func (c *Consumer) handleOrderCancelled(ctx context.Context, delivery amqp.Delivery) error {
+	log.Printf("Received order cancelled event: %s", delivery.Body)
+
+	var amqpMessage contract.AmqpMessage
+	if err := json.Unmarshal(delivery.Body, &amqpMessage); err != nil {
+		log.Printf("Failed to unmarshal AMQP message: %v", err)
+		return err
+	}
+
+	var orderCancelledData message.OrderCancelledData
+	if err := json.Unmarshal(amqpMessage.Data, &orderCancelledData); err != nil {
+		log.Printf("Failed to unmarshal order cancelled data: %v", err)
+		return err
+	}
+
+	log.Printf("Processing order cancelled event for order: %s, cancelled by: %s",
+		orderCancelledData.OrderID, orderCancelledData.CancelledBy)
+
+	// A payment not created yet is recorded as voided, so order.created
+	// arriving afterwards cannot make it payable
+	if err := c.paymentService.RefundPayment(ctx, orderCancelledData.OrderID); err != nil {
+		log.Printf("Failed to refund payment for order %s: %v", orderCancelledData.OrderID, err)
+		return err
+	}
+
+	log.Printf("Successfully compensated payment for order %s", orderCancelledData.OrderID)
+	return nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"gorm.io/gorm"
+)
+
+type auditModel struct {
+	ID         string    `gorm:"primaryKey;type:uuid"`
+	PaymentID  string    `gorm:"index;not null;type:varchar(255)"`
+	AdminID    string    `gorm:"not null;type:varchar(255)"`
+	FromStatus string    `gorm:"not null;type:varchar(20)"`
+	ToStatus   string    `gorm:"not null;type:varchar(20)"`
+	Reason     string    `gorm:"not null;type:text"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (auditModel) TableName() string { return "payment_audits" }
+
+type GormAuditRepository struct{ db *gorm.DB }
+
+var _ outbound.AuditRepository = (*GormAuditRepository)(nil)
+
+func NewGormAuditRepository(db *gorm.DB) *GormAuditRepository {
+	if err := db.AutoMigrate(&auditModel{}); err != nil {
+		log.Printf("Payment audits migration failed: %v", err)
+	} else {
+		log.Printf("Payment audits table migrated successfully")
+	}
+
+	return &GormAuditRepository{db: db}
+}
+
+func (r *GormAuditRepository) Create(ctx context.Context, audit *domain.PaymentAudit) error {
+	if audit.ID == "" {
+		audit.ID = uuid.New().String()
+	}
+	return conn(ctx, r.db).Create(&auditModel{
+		ID:         audit.ID,
+		PaymentID:  audit.PaymentID,
+		AdminID:    audit.AdminID,
+		FromStatus: string(audit.FromStatus),
+		ToStatus:   string(audit.ToStatus),
+		Reason:     audit.Reason,
+		CreatedAt:  audit.CreatedAt,
+	}).Error
+}
+
+func (r *GormAuditRepository) ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.PaymentAudit, error) {
+	var models []auditModel
+	if err := conn(ctx, r.db).
+		Where("payment_id = ?", paymentID).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	audits := make([]*domain.PaymentAudit, 0, len(models))
+	for _, m := range models {
+		audits = append(audits, &domain.PaymentAudit{
+			ID:         m.ID,
+			PaymentID:  m.PaymentID,
+			AdminID:    m.AdminID,
+			FromStatus: domain.PaymentStatus(m.FromStatus),
+			ToStatus:   domain.PaymentStatus(m.ToStatus),
+			Reason:     m.Reason,
+			CreatedAt:  m.CreatedAt,
+		})
+	}
+	return audits, nil
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+	"gorm.io/gorm"
+)
+
+type feeRuleModel struct {
+	ID            string        `gorm:"primaryKey;type:uuid"`
+	ProphetID     string        `gorm:"index;type:varchar(255)"`
+	CourseType    string        `gorm:"type:varchar(50)"`
+	Currency      string        `gorm:"type:varchar(3)"`
+	Percentage    money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	FlatFee       money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	EffectiveFrom time.Time     `gorm:"not null;index"`
+	EffectiveTo   *time.Time    `gorm:"default:null"`
+	CreatedBy     string        `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time     `gorm:"not null"`
+}
+
+func (feeRuleModel) TableName() string { return "fee_rules" }
+
+type GormFeeRuleRepository struct{ db *gorm.DB }
+
+var _ outbound.FeeRuleRepository = (*GormFeeRuleRepository)(nil)
+
+func NewGormFeeRuleRepository(db *gorm.DB) *GormFeeRuleRepository {
+	// Auto-migrate fee rules table
+	if err := db.AutoMigrate(&feeRuleModel{}); err != nil {
+		log.Printf("Fee rules migration failed: %v", err)
+	} else {
+		log.Printf("Fee rules table migrated successfully")
+	}
+
+	return &GormFeeRuleRepository{db: db}
+}
+
+func (r *GormFeeRuleRepository) Create(ctx context.Context, rule *domain.FeeRule) error {
+	return r.db.WithContext(ctx).Create(toFeeRuleModel(rule)).Error
+}
+
+func (r *GormFeeRuleRepository) GetByID(ctx context.Context, ruleID string) (*domain.FeeRule, error) {
+	var model feeRuleModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", ruleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrFeeRuleNotFound
+		}
+		return nil, err
+	}
+	return toFeeRuleEntity(&model), nil
+}
+
+func (r *GormFeeRuleRepository) Update(ctx context.Context, rule *domain.FeeRule) error {
+	return r.db.WithContext(ctx).Save(toFeeRuleModel(rule)).Error
+}
+
+func (r *GormFeeRuleRepository) List(ctx context.Context) ([]*domain.FeeRule, error) {
+	return r.list(r.db.WithContext(ctx))
+}
+
+func (r *GormFeeRuleRepository) ListEffectiveAt(ctx context.Context, t time.Time) ([]*domain.FeeRule, error) {
+	return r.list(r.db.WithContext(ctx).
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", t, t))
+}
+
+func (r *GormFeeRuleRepository) list(query *gorm.DB) ([]*domain.FeeRule, error) {
+	var models []feeRuleModel
+	if err := query.Order("effective_from DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make([]*domain.FeeRule, len(models))
+	for i := range models {
+		rules[i] = toFeeRuleEntity(&models[i])
+	}
+	return rules, nil
+}
+
+func toFeeRuleModel(rule *domain.FeeRule) *feeRuleModel {
+	return &feeRuleModel{
+		ID:            rule.ID,
+		ProphetID:     rule.ProphetID,
+		CourseType:    rule.CourseType,
+		Currency:      rule.Currency,
+		Percentage:    rule.Percentage,
+		FlatFee:       rule.FlatFee,
+		EffectiveFrom: rule.EffectiveFrom,
+		EffectiveTo:   rule.EffectiveTo,
+		CreatedBy:     rule.CreatedBy,
+		CreatedAt:     rule.CreatedAt,
+	}
+}
+
+func toFeeRuleEntity(model *feeRuleModel) *domain.FeeRule {
+	currency := model.Currency
+	// Flat fees on rules created before rules had a currency were in THB
+	if currency == "" && model.FlatFee.IsPositive() {
+		currency = money.DefaultCurrency
+	}
+	return &domain.FeeRule{
+		ID:            model.ID,
+		ProphetID:     model.ProphetID,
+		CourseType:    model.CourseType,
+		Currency:      currency,
+		Percentage:    model.Percentage,
+		FlatFee:       model.FlatFee,
+		EffectiveFrom: model.EffectiveFrom,
+		EffectiveTo:   model.EffectiveTo,
+		CreatedBy:     model.CreatedBy,
+		CreatedAt:     model.CreatedAt,
+	}
+}
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ledgerTransactionModel struct {
+	ID          string `gorm:"primaryKey;type:uuid"`
+	Kind        string `gorm:"not null;type:varchar(30)"`
+	Reference   string `gorm:"not null;type:varchar(255);uniqueIndex"`
+	ProphetID   string `gorm:"index;type:varchar(255)"`
+	PaymentID   string `gorm:"index;type:varchar(255)"`
+	PayoutID    string `gorm:"index;type:varchar(255)"`
+	Description string `gorm:"type:text"`
+	// Entries posted before the ledger was multi-currency were in THB
+	Currency  string    `gorm:"type:varchar(3);not null;default:'THB'"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (ledgerTransactionModel) TableName() string { return "ledger_transactions" }
+
+type ledgerEntryModel struct {
+	ID            string        `gorm:"primaryKey;type:uuid"`
+	TransactionID string        `gorm:"not null;type:uuid;index"`
+	Account       string        `gorm:"not null;type:varchar(255);index:idx_ledger_entries_account_created"`
+	Currency      string        `gorm:"type:varchar(3);not null;default:'THB';index:idx_ledger_entries_account_created"`
+	Debit         money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	Credit        money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	CreatedAt     time.Time     `gorm:"not null;index:idx_ledger_entries_account_created"`
+}
+
+func (ledgerEntryModel) TableName() string { return "ledger_entries" }
+
+type GormLedgerRepository struct{ db *gorm.DB }
+
+var _ outbound.LedgerRepository = (*GormLedgerRepository)(nil)
+
+func NewGormLedgerRepository(db *gorm.DB) *GormLedgerRepository {
+	// Auto-migrate ledger tables
+	if err := db.AutoMigrate(&ledgerTransactionModel{}, &ledgerEntryModel{}); err != nil {
+		log.Printf("Ledger migration failed: %v", err)
+	} else {
+		log.Printf("Ledger tables migrated successfully")
+	}
+
+	return &GormLedgerRepository{db: db}
+}
+
+func (r *GormLedgerRepository) PostTransaction(ctx context.Context, tx *domain.LedgerTransaction) error {
+	if err := tx.Validate(); err != nil {
+		return err
+	}
+
+	return conn(ctx, r.db).Transaction(func(db *gorm.DB) error {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(toLedgerTransactionModel(tx))
+		if result.Error != nil {
+			return result.Error
+		}
+		// The reference was already posted by an earlier attempt
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		entries := make([]ledgerEntryModel, len(tx.Entries))
+		for i, entry := range tx.Entries {
+			entries[i] = toLedgerEntryModel(tx.ID, &entry)
+		}
+		return db.Create(&entries).Error
+	})
+}
+
+func (r *GormLedgerRepository) FindTransactionByReference(ctx context.Context, reference string) (*domain.LedgerTransaction, error) {
+	var model ledgerTransactionModel
+	if err := conn(ctx, r.db).First(&model, "reference = ?", reference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLedgerTransactionNotFound
+		}
+		return nil, err
+	}
+
+	var entries []ledgerEntryModel
+	if err := conn(ctx, r.db).
+		Where("transaction_id = ?", model.ID).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return toLedgerTransactionEntity(&model, entries), nil
+}
+
+func (r *GormLedgerRepository) AccountBalance(ctx context.Context, account, currency string) (money.Decimal, error) {
+	return accountBalance(conn(ctx, r.db), account, currency, nil)
+}
+
+func (r *GormLedgerRepository) AccountBalances(ctx context.Context, account string) (map[string]money.Decimal, error) {
+	return accountBalances(conn(ctx, r.db), account)
+}
+
+func (r *GormLedgerRepository) AccountBalanceBefore(ctx context.Context, account, currency string, t time.Time) (money.Decimal, error) {
+	return accountBalance(conn(ctx, r.db), account, currency, &t)
+}
+
+func (r *GormLedgerRepository) ListAccountLines(ctx context.Context, account, currency string, from, to time.Time) ([]domain.StatementLine, error) {
+	type row struct {
+		TransactionID string
+		Kind          string
+		Reference     string
+		PaymentID     string
+		PayoutID      string
+		Description   string
+		Debit         money.Decimal
+		Credit        money.Decimal
+		CreatedAt     time.Time
+	}
+
+	var rows []row
+	err := conn(ctx, r.db).
+		Table("ledger_entries AS e").
+		Select("e.transaction_id, t.kind, t.reference, t.payment_id, t.payout_id, t.description, e.debit, e.credit, e.created_at").
+		Joins("JOIN ledger_transactions AS t ON t.id = e.transaction_id").
+		Where("e.account = ? AND e.currency = ? AND e.created_at >= ? AND e.created_at < ?", account, currency, from, to).
+		Order("e.created_at, e.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]domain.StatementLine, len(rows))
+	for i, row := range rows {
+		lines[i] = domain.StatementLine{
+			TransactionID: row.TransactionID,
+			Kind:          domain.LedgerTransactionKind(row.Kind),
+			Reference:     row.Reference,
+			PaymentID:     row.PaymentID,
+			PayoutID:      row.PayoutID,
+			Description:   row.Description,
+			Debit:         row.Debit,
+			Credit:        row.Credit,
+			CreatedAt:     row.CreatedAt,
+		}
+	}
+	return lines, nil
+}
+
+// accountBalance sums credits minus debits in currency, optionally only
+// before a time
+func accountBalance(db *gorm.DB, account, currency string, before *time.Time) (money.Decimal, error) {
+	type row struct{ Sum money.Decimal }
+	var out row
+	query := db.Model(&ledgerEntryModel{}).
+		Select("COALESCE(SUM(credit - debit), 0) AS sum").
+		Where("account = ? AND currency = ?", account, currency)
+	if before != nil {
+		query = query.Where("created_at < ?", *before)
+	}
+	if err := query.Take(&out).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return money.Zero, err
+	}
+	return out.Sum, nil
+}
+
+// accountBalances sums credits minus debits per currency
+func accountBalances(db *gorm.DB, account string) (map[string]money.Decimal, error) {
+	type row struct {
+		Currency string
+		Sum      money.Decimal
+	}
+	var rows []row
+	err := db.Model(&ledgerEntryModel{}).
+		Select("currency, COALESCE(SUM(credit - debit), 0) AS sum").
+		Where("account = ?", account).
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]money.Decimal, len(rows))
+	for _, row := range rows {
+		balances[row.Currency] = row.Sum
+	}
+	return balances, nil
+}
+
+func toLedgerTransactionModel(tx *domain.LedgerTransaction) *ledgerTransactionModel {
+	return &ledgerTransactionModel{
+		ID:          tx.ID,
+		Kind:        string(tx.Kind),
+		Reference:   tx.Reference,
+		ProphetID:   tx.ProphetID,
+		PaymentID:   tx.PaymentID,
+		PayoutID:    tx.PayoutID,
+		Description: tx.Description,
+		Currency:    tx.Currency,
+		CreatedAt:   tx.CreatedAt,
+	}
+}
+
+func toLedgerEntryModel(transactionID string, entry *domain.LedgerEntry) ledgerEntryModel {
+	return ledgerEntryModel{
+		ID:            entry.ID,
+		TransactionID: transactionID,
+		Account:       entry.Account,
+		Currency:      entry.Currency,
+		Debit:         entry.Debit,
+		Credit:        entry.Credit,
+		CreatedAt:     entry.CreatedAt,
+	}
+}
+
+func toLedgerTransactionEntity(model *ledgerTransactionModel, entries []ledgerEntryModel) *domain.LedgerTransaction {
+	tx := &domain.LedgerTransaction{
+		ID:          model.ID,
+		Kind:        domain.LedgerTransactionKind(model.Kind),
+		Reference:   model.Reference,
+		ProphetID:   model.ProphetID,
+		PaymentID:   model.PaymentID,
+		PayoutID:    model.PayoutID,
+		Description: model.Description,
+		Currency:    model.Currency,
+		CreatedAt:   model.CreatedAt,
+		Entries:     make([]domain.LedgerEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		tx.Entries[i] = domain.LedgerEntry{
+			ID:            entry.ID,
+			TransactionID: entry.TransactionID,
+			Account:       entry.Account,
+			Currency:      entry.Currency,
+			Debit:         entry.Debit,
+			Credit:        entry.Credit,
+			CreatedAt:     entry.CreatedAt,
+		}
+	}
+	return tx
+}
@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+	"gorm.io/gorm"
+)
+
+type payoutModel struct {
+	ID            string        `gorm:"primaryKey;type:uuid"`
+	ProphetID     string        `gorm:"not null;index;type:varchar(255)"`
+	Amount        money.Decimal `gorm:"type:numeric(20,8);not null"`
+	Currency      string        `gorm:"type:varchar(3);not null;default:'THB'"`
+	Status        string        `gorm:"not null;index;type:varchar(20)"`
+	RequestedAt   time.Time     `gorm:"not null"`
+	ReviewedBy    string        `gorm:"type:varchar(255)"`
+	ReviewedAt    *time.Time    `gorm:"default:null"`
+	RejectReason  string        `gorm:"type:text"`
+	PaidReference string        `gorm:"type:varchar(255)"`
+	PaidAt        *time.Time    `gorm:"default:null"`
+	UpdatedAt     time.Time     `gorm:"not null"`
+}
+
+func (payoutModel) TableName() string { return "payouts" }
+
+var openPayoutStatuses = []string{string(domain.PayoutStatusRequested), string(domain.PayoutStatusApproved)}
+
+type GormPayoutRepository struct{ db *gorm.DB }
+
+var _ outbound.PayoutRepository = (*GormPayoutRepository)(nil)
+
+func NewGormPayoutRepository(db *gorm.DB) *GormPayoutRepository {
+	// Auto-migrate payouts table
+	if err := db.AutoMigrate(&payoutModel{}); err != nil {
+		log.Printf("Payouts migration failed: %v", err)
+	} else {
+		log.Printf("Payouts table migrated successfully")
+	}
+
+	return &GormPayoutRepository{db: db}
+}
+
+func (r *GormPayoutRepository) CreateWithinBalance(ctx context.Context, payout *domain.Payout) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialise requests per prophet until the transaction ends
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "payout:"+payout.ProphetID).Error; err != nil {
+			return err
+		}
+
+		balances, err := accountBalances(tx, domain.ProphetAccount(payout.ProphetID))
+		if err != nil {
+			return err
+		}
+		open, err := openTotals(tx, payout.ProphetID)
+		if err != nil {
+			return err
+		}
+		if payout.Amount.GreaterThan(balances[payout.Currency].Sub(open[payout.Currency])) {
+			return domain.ErrInsufficientBalance
+		}
+
+		return tx.Create(toPayoutModel(payout)).Error
+	})
+}
+
+func (r *GormPayoutRepository) GetByID(ctx context.Context, payoutID string) (*domain.Payout, error) {
+	var model payoutModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", payoutID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPayoutNotFound
+		}
+		return nil, err
+	}
+	return toPayoutEntity(&model), nil
+}
+
+func (r *GormPayoutRepository) Update(ctx context.Context, payout *domain.Payout) error {
+	return r.db.WithContext(ctx).Save(toPayoutModel(payout)).Error
+}
+
+func (r *GormPayoutRepository) ListByProphetID(ctx context.Context, prophetID string) ([]*domain.Payout, error) {
+	return r.list(ctx, "prophet_id = ?", prophetID)
+}
+
+func (r *GormPayoutRepository) ListByStatus(ctx context.Context, status domain.PayoutStatus) ([]*domain.Payout, error) {
+	return r.list(ctx, "status = ?", string(status))
+}
+
+func (r *GormPayoutRepository) OpenTotals(ctx context.Context, prophetID string) (map[string]money.Decimal, error) {
+	return openTotals(r.db.WithContext(ctx), prophetID)
+}
+
+func (r *GormPayoutRepository) list(ctx context.Context, query string, arg interface{}) ([]*domain.Payout, error) {
+	var models []payoutModel
+	if err := r.db.WithContext(ctx).
+		Where(query, arg).
+		Order("requested_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	payouts := make([]*domain.Payout, len(models))
+	for i := range models {
+		payouts[i] = toPayoutEntity(&models[i])
+	}
+	return payouts, nil
+}
+
+func openTotals(db *gorm.DB, prophetID string) (map[string]money.Decimal, error) {
+	type row struct {
+		Currency string
+		Sum      money.Decimal
+	}
+	var rows []row
+	err := db.Model(&payoutModel{}).
+		Select("currency, COALESCE(SUM(amount), 0) AS sum").
+		Where("prophet_id = ? AND status IN ?", prophetID, openPayoutStatuses).
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]money.Decimal, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = row.Sum
+	}
+	return totals, nil
+}
+
+func toPayoutModel(p *domain.Payout) *payoutModel {
+	return &payoutModel{
+		ID:            p.ID,
+		ProphetID:     p.ProphetID,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        string(p.Status),
+		RequestedAt:   p.RequestedAt,
+		ReviewedBy:    p.ReviewedBy,
+		ReviewedAt:    p.ReviewedAt,
+		RejectReason:  p.RejectReason,
+		PaidReference: p.PaidReference,
+		PaidAt:        p.PaidAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+func toPayoutEntity(model *payoutModel) *domain.Payout {
+	return &domain.Payout{
+		ID:            model.ID,
+		ProphetID:     model.ProphetID,
+		Amount:        model.Amount,
+		Currency:      model.Currency,
+		Status:        domain.PayoutStatus(model.Status),
+		RequestedAt:   model.RequestedAt,
+		ReviewedBy:    model.ReviewedBy,
+		ReviewedAt:    model.ReviewedAt,
+		RejectReason:  model.RejectReason,
+		PaidReference: model.PaidReference,
+		PaidAt:        model.PaidAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
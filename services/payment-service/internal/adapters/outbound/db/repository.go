@@ -8,17 +8,33 @@ import (
 
 	"github.com/wnmay/horo/services/payment-service/internal/domain"
 	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type paymentModel struct {
 	PaymentID string    `gorm:"primaryKey;type:uuid;column:payment_id"`
-	OrderID   string    `gorm:"not null;index;type:uuid"`
+	// One payment per order, so concurrent creations cannot charge it twice
+	OrderID   string    `gorm:"not null;uniqueIndex:idx_payments_order_id_unique;type:uuid"`
 	ProphetID string    `gorm:"index;type:string"`
-	Amount    float64   `gorm:"not null"`
+	Amount    money.Decimal `gorm:"type:numeric(20,8);not null"`
+	// Payments created before prices carried a currency were in THB
+	Currency  string    `gorm:"type:varchar(3);not null;default:'THB'"`
 	Status    domain.PaymentStatus `gorm:"not null;default:PENDING"`
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
+	Provider    string `gorm:"type:varchar(50)"`
+	ProviderRef string `gorm:"index;type:varchar(255)"`
+	CourseType  string  `gorm:"type:varchar(50)"`
+	GrossAmount money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	FeeAmount   money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	NetAmount   money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	FeeRuleID   string  `gorm:"type:varchar(255)"`
+	Sessions        int           `gorm:"not null;default:0"`
+	SessionsSettled int           `gorm:"not null;default:0"`
+	RefundedAmount  money.Decimal `gorm:"type:numeric(20,8);not null;default:0"`
+	RefundRef       string        `gorm:"type:varchar(255)"`
 }
 
 func (paymentModel) TableName() string { return "payments" }
@@ -28,6 +44,13 @@ type GormPaymentRepository struct{ db *gorm.DB }
 var _ outbound.PaymentRepository = (*GormPaymentRepository)(nil)
 
 func NewGormPaymentRepository(db *gorm.DB) *GormPaymentRepository {
+	// The order ID index was not unique before; replace it
+	if db.Migrator().HasIndex(&paymentModel{}, "idx_payments_order_id") {
+		if err := db.Migrator().DropIndex(&paymentModel{}, "idx_payments_order_id"); err != nil {
+			log.Printf("Failed to drop payments order index: %v", err)
+		}
+	}
+
 	// Auto-migrate payment table
 	if err := db.AutoMigrate(&paymentModel{}); err != nil {
 		log.Printf("Payment migration failed: %v", err)
@@ -38,77 +61,194 @@ func NewGormPaymentRepository(db *gorm.DB) *GormPaymentRepository {
 	return &GormPaymentRepository{db: db}
 }
 
+// WithinTransaction runs fn inside a database transaction. Nested calls reuse
+// the transaction already carried by ctx.
+func (r *GormPaymentRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, or the plain connection, for
+// stores outside this package that take part in the same transactions
+func (r *GormPaymentRepository) Conn(ctx context.Context) *gorm.DB {
+	return conn(ctx, r.db)
+}
+
+// Create stores a new payment, or returns ErrPaymentExists if the order
+// already has one
 func (r *GormPaymentRepository) Create(ctx context.Context, p *domain.Payment) error {
-	model := paymentModel{
-		PaymentID: p.PaymentID,
-		OrderID:   p.OrderID,
-		Amount:    p.Amount,
-		Status:    p.Status,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
-	}
-	return r.db.WithContext(ctx).Create(&model).Error
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "order_id"}}, DoNothing: true}).
+		Create(toPaymentModel(p))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPaymentExists
+	}
+	return nil
 }
 
 func (r *GormPaymentRepository) GetByID(ctx context.Context, id string) (*domain.Payment, error) {
 	var model paymentModel
-	if err := r.db.WithContext(ctx).First(&model, "payment_id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&model, "payment_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPaymentNotFound
+		}
 		return nil, err
 	}
-	return &domain.Payment{
-		PaymentID: model.PaymentID,
-		OrderID:   model.OrderID,
-		ProphetID: model.ProphetID,
-		Amount:    model.Amount,
-		Status:    model.Status,
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
-	}, nil
+	return toPaymentEntity(&model), nil
+}
+
+// GetByIDForUpdate retrieves a payment and locks its row until the
+// transaction carried by ctx ends
+func (r *GormPaymentRepository) GetByIDForUpdate(ctx context.Context, id string) (*domain.Payment, error) {
+	var model paymentModel
+	if err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&model, "payment_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return toPaymentEntity(&model), nil
 }
 
 func (r *GormPaymentRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
 	var model paymentModel
-	if err := r.db.WithContext(ctx).First(&model, "order_id = ?", orderID).Error; err != nil {
+	if err := conn(ctx, r.db).First(&model, "order_id = ?", orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPaymentNotFound
+		}
 		return nil, err
 	}
-	return &domain.Payment{
-		PaymentID: model.PaymentID,
-		OrderID:   model.OrderID,
-		ProphetID: model.ProphetID,
-		Amount:    model.Amount,
-		Status:    model.Status,
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
-	}, nil
+	return toPaymentEntity(&model), nil
 }
 
 func (r *GormPaymentRepository) Update(ctx context.Context, p *domain.Payment) error {
-	model := paymentModel{
-		PaymentID: p.PaymentID,
-		OrderID:   p.OrderID,
-		ProphetID: p.ProphetID,
-		Amount:    p.Amount,
-		Status:    p.Status,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
-	}
-	return r.db.WithContext(ctx).Save(&model).Error
+	return conn(ctx, r.db).Save(toPaymentModel(p)).Error
 }
 
 func (r *GormPaymentRepository) Delete(ctx context.Context, paymentID string) error {
-	return r.db.WithContext(ctx).Delete(&paymentModel{}, "payment_id = ?", paymentID).Error
+	return conn(ctx, r.db).Delete(&paymentModel{}, "payment_id = ?", paymentID).Error
+}
+
+func (r *GormPaymentRepository) ListByProphetID(ctx context.Context, prophetID string) ([]*domain.Payment, error) {
+	var models []paymentModel
+	if err := conn(ctx, r.db).
+		Where("prophet_id = ?", prophetID).
+		Order("created_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*domain.Payment, 0, len(models))
+	for i := range models {
+		payments = append(payments, toPaymentEntity(&models[i]))
+	}
+	return payments, nil
 }
 
-func (r *GormPaymentRepository) GetProphetSettledBalance(ctx context.Context, prophetID string) (float64, error) {
-    type row struct{ Sum float64 }
-    var out row
-    err := r.db.WithContext(ctx).
-        Model(&paymentModel{}).
-        Select("COALESCE(SUM(amount), 0) AS sum").
-        Where("prophet_id = ? AND status = ?", prophetID, domain.PaymentStatusSettled).
-        Take(&out).Error
-    if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-        return 0, err
-    }
-    return out.Sum, nil
-}
\ No newline at end of file
+func (r *GormPaymentRepository) ListByStatus(ctx context.Context, status domain.PaymentStatus) ([]*domain.Payment, error) {
+	var models []paymentModel
+	if err := conn(ctx, r.db).
+		Where("status = ?", status).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*domain.Payment, 0, len(models))
+	for i := range models {
+		payments = append(payments, toPaymentEntity(&models[i]))
+	}
+	return payments, nil
+}
+
+func (r *GormPaymentRepository) SettledTotals(ctx context.Context, prophetID string) (map[string]domain.SettledTotal, error) {
+	type row struct {
+		Currency string
+		Gross    money.Decimal
+		Fee      money.Decimal
+	}
+	var rows []row
+	// Payments settled before fees were recorded only carry their amount.
+	// Bundles count the sessions settled so far, including those kept when
+	// the rest of the bundle was refunded.
+	err := conn(ctx, r.db).
+		Model(&paymentModel{}).
+		Select("currency, COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE gross_amount END), 0) AS gross, COALESCE(SUM(fee_amount), 0) AS fee", domain.PaymentStatusSettled).
+		Where("prophet_id = ? AND (status = ? OR (status IN ? AND sessions_settled > 0 AND sessions_settled < sessions))",
+			prophetID, domain.PaymentStatusSettled, []domain.PaymentStatus{domain.PaymentStatusCompleted, domain.PaymentStatusRefunded}).
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]domain.SettledTotal, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = domain.SettledTotal{Gross: row.Gross, Fee: row.Fee}
+	}
+	return totals, nil
+}
+
+func toPaymentModel(p *domain.Payment) *paymentModel {
+	return &paymentModel{
+		PaymentID:   p.PaymentID,
+		OrderID:     p.OrderID,
+		ProphetID:   p.ProphetID,
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Status:      p.Status,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Provider:    p.Provider,
+		ProviderRef: p.ProviderRef,
+		CourseType:  p.CourseType,
+		GrossAmount: p.GrossAmount,
+		FeeAmount:   p.FeeAmount,
+		NetAmount:   p.NetAmount,
+		FeeRuleID:   p.FeeRuleID,
+		Sessions:        p.Sessions,
+		SessionsSettled: p.SessionsSettled,
+		RefundedAmount:  p.RefundedAmount,
+		RefundRef:       p.RefundRef,
+	}
+}
+
+func toPaymentEntity(model *paymentModel) *domain.Payment {
+	payment := &domain.Payment{
+		PaymentID:   model.PaymentID,
+		OrderID:     model.OrderID,
+		ProphetID:   model.ProphetID,
+		Amount:      model.Amount,
+		Currency:    model.Currency,
+		Status:      model.Status,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+		Provider:    model.Provider,
+		ProviderRef: model.ProviderRef,
+		CourseType:  model.CourseType,
+		GrossAmount: model.GrossAmount,
+		FeeAmount:   model.FeeAmount,
+		NetAmount:   model.NetAmount,
+		FeeRuleID:   model.FeeRuleID,
+		Sessions:        model.Sessions,
+		SessionsSettled: model.SessionsSettled,
+		RefundedAmount:  model.RefundedAmount,
+		RefundRef:       model.RefundRef,
+	}
+
+	// Refunds before bundles always returned the whole amount
+	if payment.Status == domain.PaymentStatusRefunded && payment.RefundedAmount.IsZero() {
+		payment.RefundedAmount = payment.Amount
+	}
+	return payment
+}
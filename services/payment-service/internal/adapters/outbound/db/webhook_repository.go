@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookModel struct {
+	ID              string     `gorm:"primaryKey;type:uuid"`
+	Provider        string     `gorm:"not null;type:varchar(50);uniqueIndex:idx_webhook_provider_event"`
+	EventID         string     `gorm:"not null;type:varchar(255);uniqueIndex:idx_webhook_provider_event"`
+	EventType       string     `gorm:"type:varchar(100)"`
+	PaymentID       string     `gorm:"index;type:varchar(255)"`
+	ProviderRef     string     `gorm:"type:varchar(255)"`
+	Payload         string     `gorm:"type:text;not null"`
+	Signature       string     `gorm:"type:text"`
+	ReceivedAt      time.Time  `gorm:"not null"`
+	ProcessedAt     *time.Time `gorm:"default:null"`
+	ProcessingError string     `gorm:"type:text"`
+	RefundRef       string     `gorm:"type:varchar(255)"`
+}
+
+func (webhookModel) TableName() string { return "payment_webhook_events" }
+
+type GormWebhookRepository struct{ db *gorm.DB }
+
+var _ outbound.WebhookRepository = (*GormWebhookRepository)(nil)
+
+func NewGormWebhookRepository(db *gorm.DB) *GormWebhookRepository {
+	// Auto-migrate webhook history table
+	if err := db.AutoMigrate(&webhookModel{}); err != nil {
+		log.Printf("Webhook events migration failed: %v", err)
+	} else {
+		log.Printf("Webhook events table migrated successfully")
+	}
+
+	return &GormWebhookRepository{db: db}
+}
+
+func (r *GormWebhookRepository) Record(ctx context.Context, record *domain.WebhookRecord) (*domain.WebhookRecord, error) {
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+
+	model := toWebhookModel(record)
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(model).Error; err != nil {
+		return nil, err
+	}
+
+	// Read back so a redelivered event returns the original record
+	var stored webhookModel
+	if err := r.db.WithContext(ctx).
+		First(&stored, "provider = ? AND event_id = ?", record.Provider, record.EventID).Error; err != nil {
+		return nil, err
+	}
+	return toWebhookEntity(&stored), nil
+}
+
+func (r *GormWebhookRepository) MarkProcessed(ctx context.Context, id string, processingErr error) error {
+	updates := map[string]interface{}{"processing_error": ""}
+	if processingErr != nil {
+		updates["processing_error"] = processingErr.Error()
+	} else {
+		updates["processed_at"] = time.Now()
+	}
+	return r.db.WithContext(ctx).Model(&webhookModel{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *GormWebhookRepository) RecordRefund(ctx context.Context, id string, refundRef string) error {
+	return r.db.WithContext(ctx).Model(&webhookModel{}).Where("id = ?", id).Update("refund_ref", refundRef).Error
+}
+
+func (r *GormWebhookRepository) ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.WebhookRecord, error) {
+	var models []webhookModel
+	if err := r.db.WithContext(ctx).
+		Where("payment_id = ?", paymentID).
+		Order("received_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]*domain.WebhookRecord, len(models))
+	for i := range models {
+		records[i] = toWebhookEntity(&models[i])
+	}
+	return records, nil
+}
+
+func toWebhookModel(record *domain.WebhookRecord) *webhookModel {
+	return &webhookModel{
+		ID:              record.ID,
+		Provider:        record.Provider,
+		EventID:         record.EventID,
+		EventType:       record.EventType,
+		PaymentID:       record.PaymentID,
+		ProviderRef:     record.ProviderRef,
+		Payload:         record.Payload,
+		Signature:       record.Signature,
+		ReceivedAt:      record.ReceivedAt,
+		ProcessedAt:     record.ProcessedAt,
+		ProcessingError: record.ProcessingError,
+		RefundRef:       record.RefundRef,
+	}
+}
+
+func toWebhookEntity(model *webhookModel) *domain.WebhookRecord {
+	return &domain.WebhookRecord{
+		ID:              model.ID,
+		Provider:        model.Provider,
+		EventID:         model.EventID,
+		EventType:       model.EventType,
+		PaymentID:       model.PaymentID,
+		ProviderRef:     model.ProviderRef,
+		Payload:         model.Payload,
+		Signature:       model.Signature,
+		ReceivedAt:      model.ReceivedAt,
+		ProcessedAt:     model.ProcessedAt,
+		ProcessingError: model.ProcessingError,
+		RefundRef:       model.RefundRef,
+	}
+}
@@ -28,6 +28,7 @@ func (p *Publisher) PublishPaymentCompleted(ctx context.Context, payment *domain
 		OrderID:    payment.OrderID,
 		Status:     string(payment.Status),
 		Amount:     payment.Amount,
+		Currency:   payment.Currency,
 	}
 
 	// Marshal the payment data
@@ -72,7 +73,7 @@ func (p *Publisher) PublishPaymentFailed(ctx context.Context, payment *domain.Pa
 	}
 
 	// Publish the message with routing key for payment failure
-	if err := p.rabbit.PublishMessage(ctx, "payment.failed", amqpMessage); err != nil {
+	if err := p.rabbit.PublishMessage(ctx, contract.PaymentFailedEvent, amqpMessage); err != nil {
 		return fmt.Errorf("failed to publish payment failed event: %w", err)
 	}
 
@@ -86,6 +87,7 @@ func (p *Publisher) PublishPaymentCreated(ctx context.Context, payment *domain.P
 		OrderID:    payment.OrderID,
 		Status:     string(payment.Status),
 		Amount:     payment.Amount,
+		Currency:   payment.Currency,
 	}
 	data, err := json.Marshal(paymentData)
 	if err != nil {
@@ -105,11 +107,18 @@ func (p *Publisher) PublishPaymentCreated(ctx context.Context, payment *domain.P
 }
 
 func (p *Publisher) PublishPaymentSettled(ctx context.Context, payment *domain.Payment) error {
-	payload := message.PaymentPublishedData{
-		PaymentID:  payment.PaymentID,
-		OrderID:    payment.OrderID,
-		Status:     string(payment.Status),
-		Amount:     payment.Amount,
+	payload := message.PaymentSettledData{
+		PaymentID:   payment.PaymentID,
+		OrderID:     payment.OrderID,
+		ProphetID:   payment.ProphetID,
+		CourseType:  payment.CourseType,
+		Status:      string(payment.Status),
+		Amount:      payment.Amount,
+		GrossAmount: payment.GrossAmount,
+		FeeAmount:   payment.FeeAmount,
+		NetAmount:   payment.NetAmount,
+		Currency:    payment.Currency,
+		FeeRuleID:   payment.FeeRuleID,
 	}
 
 	data, err := json.Marshal(payload)
@@ -130,3 +139,33 @@ func (p *Publisher) PublishPaymentSettled(ctx context.Context, payment *domain.P
 		payment.OrderID, payment.PaymentID)
 	return nil
 }
+
+func (p *Publisher) PublishPaymentRefunded(ctx context.Context, payment *domain.Payment, settlementReversed bool) error {
+	payload := message.PaymentRefundedData{
+		PaymentID:          payment.PaymentID,
+		OrderID:            payment.OrderID,
+		ProphetID:          payment.ProphetID,
+		Status:             string(payment.Status),
+		Amount:             payment.RefundedAmount,
+		Currency:           payment.Currency,
+		SettlementReversed: settlementReversed,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payment refunded payload: %w", err)
+	}
+
+	msg := contract.AmqpMessage{
+		OwnerID: payment.OrderID,
+		Data:    data,
+	}
+
+	if err := p.rabbit.PublishMessage(ctx, contract.PaymentRefundedEvent, msg); err != nil {
+		return fmt.Errorf("failed to publish payment refunded event: %w", err)
+	}
+
+	fmt.Printf("Published payment refunded event for order: %s, payment: %s\n",
+		payment.OrderID, payment.PaymentID)
+	return nil
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+)
+
+const fakeSessionTTL = 30 * time.Minute
+
+// FakeGateway is a tiny in-memory stand-in for a hosted payment provider so
+// the checkout and webhook flow can run on a dev laptop. Opening the checkout
+// URL shows a page to approve or decline; either choice sends a signed webhook
+// to the payment service.
+type FakeGateway struct {
+	publicURL     string
+	webhookURL    string
+	signingSecret string
+	client        *http.Client
+
+	mu       sync.Mutex
+	sessions map[string]*fakeSession
+	// refunds maps session IDs to their refund
+	refunds map[string]*fakeRefund
+}
+
+func NewFakeGateway(publicURL, webhookURL, signingSecret string) *FakeGateway {
+	return &FakeGateway{
+		publicURL:     publicURL,
+		webhookURL:    webhookURL,
+		signingSecret: signingSecret,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		sessions:      make(map[string]*fakeSession),
+		refunds:       make(map[string]*fakeRefund),
+	}
+}
+
+func (g *FakeGateway) Register(app *fiber.App) {
+	app.Post("/v1/checkout/sessions", g.createSession)
+	app.Get("/checkout/:id", g.checkoutPage)
+	app.Post("/checkout/:id/complete", g.completeCheckout)
+	app.Post("/v1/refunds", g.createRefund)
+}
+
+func (g *FakeGateway) createSession(c *fiber.Ctx) error {
+	var req fakeSessionRequest
+	if err := c.BodyParser(&req); err != nil || req.PaymentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payment_id is required"})
+	}
+
+	id := "cs_" + uuid.New().String()
+	session := &fakeSession{
+		ID:          id,
+		PaymentID:   req.PaymentID,
+		OrderID:     req.OrderID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		CheckoutURL: fmt.Sprintf("%s/checkout/%s", g.publicURL, id),
+		ExpiresAt:   time.Now().Add(fakeSessionTTL),
+	}
+
+	g.mu.Lock()
+	g.sessions[id] = session
+	g.mu.Unlock()
+
+	return c.Status(fiber.StatusCreated).JSON(session)
+}
+
+func (g *FakeGateway) checkoutPage(c *fiber.Ctx) error {
+	session, ok := g.session(c.Params("id"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("checkout session not found")
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(fmt.Sprintf(`<html><body>
+<h3>Fake checkout</h3>
+<p>Payment %s for order %s, amount %s %s</p>
+<form method="post" action="/checkout/%s/complete?outcome=succeeded"><button>Pay</button></form>
+<form method="post" action="/checkout/%s/complete?outcome=failed"><button>Decline</button></form>
+</body></html>`, session.PaymentID, session.OrderID, session.Amount, session.Currency, session.ID, session.ID))
+}
+
+func (g *FakeGateway) completeCheckout(c *fiber.Ctx) error {
+	session, ok := g.session(c.Params("id"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "checkout session not found"})
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": "checkout session expired"})
+	}
+
+	eventType := domain.ProviderEventPaymentSucceeded
+	if c.Query("outcome") == "failed" {
+		eventType = domain.ProviderEventPaymentFailed
+	}
+
+	event := fakeWebhookEvent{
+		ID:        "evt_" + uuid.New().String(),
+		Type:      string(eventType),
+		CreatedAt: time.Now().Unix(),
+	}
+	event.Data.SessionID = session.ID
+	event.Data.PaymentID = session.PaymentID
+
+	if err := g.sendWebhook(event); err != nil {
+		log.Printf("Fake gateway failed to deliver webhook %s: %v", event.ID, err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"session_id": session.ID,
+		"event_id":   event.ID,
+		"outcome":    event.Type,
+	})
+}
+
+// createRefund refunds a session's capture, returning the existing refund if
+// it was refunded before
+func (g *FakeGateway) createRefund(c *fiber.Ctx) error {
+	var req fakeRefundRequest
+	if err := c.BodyParser(&req); err != nil || req.SessionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "session_id is required"})
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.sessions[req.SessionID]; !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "checkout session not found"})
+	}
+	if refund, ok := g.refunds[req.SessionID]; ok {
+		return c.JSON(refund)
+	}
+
+	refund := &fakeRefund{ID: "re_" + uuid.New().String(), SessionID: req.SessionID, Amount: req.Amount}
+	g.refunds[req.SessionID] = refund
+	return c.Status(fiber.StatusCreated).JSON(refund)
+}
+
+func (g *FakeGateway) sendWebhook(event fakeWebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, g.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FakeSignatureHeader, signPayload(g.signingSecret, time.Now().Unix(), payload))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (g *FakeGateway) session(id string) (*fakeSession, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	session, ok := g.sessions[id]
+	return session, ok
+}
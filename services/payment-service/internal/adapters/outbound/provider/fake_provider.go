@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+const FakeProviderName = "fake"
+
+// FakeProvider talks to the local FakeGateway the same way a real adapter
+// would talk to a hosted payment provider
+type FakeProvider struct {
+	baseURL       string
+	signingSecret string
+	client        *http.Client
+}
+
+var _ outbound.PaymentProvider = (*FakeProvider)(nil)
+
+func NewFakeProvider(baseURL, signingSecret string) *FakeProvider {
+	return &FakeProvider{
+		baseURL:       baseURL,
+		signingSecret: signingSecret,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *FakeProvider) Name() string { return FakeProviderName }
+
+func (p *FakeProvider) SignatureHeader() string { return FakeSignatureHeader }
+
+type fakeSessionRequest struct {
+	PaymentID string        `json:"payment_id"`
+	OrderID   string        `json:"order_id"`
+	Amount    money.Decimal `json:"amount"`
+	Currency  string        `json:"currency"`
+}
+
+type fakeSession struct {
+	ID          string        `json:"id"`
+	PaymentID   string        `json:"payment_id"`
+	OrderID     string        `json:"order_id"`
+	Amount      money.Decimal `json:"amount"`
+	Currency    string        `json:"currency"`
+	CheckoutURL string        `json:"checkout_url"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+}
+
+func (p *FakeProvider) CreateCheckoutSession(ctx context.Context, payment *domain.Payment) (*domain.CheckoutSession, error) {
+	body, err := json.Marshal(fakeSessionRequest{
+		PaymentID: payment.PaymentID,
+		OrderID:   payment.OrderID,
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkout request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/checkout/sessions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach payment provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("payment provider returned status %d", resp.StatusCode)
+	}
+
+	var session fakeSession
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return nil, fmt.Errorf("failed to decode checkout session: %w", err)
+	}
+
+	return &domain.CheckoutSession{
+		PaymentID:   payment.PaymentID,
+		Provider:    FakeProviderName,
+		ProviderRef: session.ID,
+		CheckoutURL: session.CheckoutURL,
+		ExpiresAt:   session.ExpiresAt,
+	}, nil
+}
+
+type fakeWebhookEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	CreatedAt int64  `json:"created_at"`
+	Data      struct {
+		SessionID string `json:"session_id"`
+		PaymentID string `json:"payment_id"`
+	} `json:"data"`
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (*domain.ProviderEvent, error) {
+	if err := verifySignature(p.signingSecret, signature, payload, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event fakeWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+
+	return &domain.ProviderEvent{
+		EventID:     event.ID,
+		Type:        domain.ProviderEventType(event.Type),
+		ProviderRef: event.Data.SessionID,
+		PaymentID:   event.Data.PaymentID,
+		OccurredAt:  time.Unix(event.CreatedAt, 0),
+	}, nil
+}
+
+type fakeRefundRequest struct {
+	SessionID string        `json:"session_id"`
+	PaymentID string        `json:"payment_id"`
+	Amount    money.Decimal `json:"amount"`
+}
+
+type fakeRefund struct {
+	ID        string        `json:"id"`
+	SessionID string        `json:"session_id"`
+	Amount    money.Decimal `json:"amount"`
+}
+
+func (p *FakeProvider) RefundCapture(ctx context.Context, payment *domain.Payment, providerRef string, amount money.Decimal) (string, error) {
+	body, err := json.Marshal(fakeRefundRequest{SessionID: providerRef, PaymentID: payment.PaymentID, Amount: amount})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal refund request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/refunds", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create refund request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach payment provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("payment provider returned status %d", resp.StatusCode)
+	}
+
+	var refund fakeRefund
+	if err := json.NewDecoder(resp.Body).Decode(&refund); err != nil {
+		return "", fmt.Errorf("failed to decode refund: %w", err)
+	}
+	return refund.ID, nil
+}
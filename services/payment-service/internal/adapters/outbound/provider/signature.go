@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+)
+
+// FakeSignatureHeader carries "t=<unix>,v1=<hex hmac>" where the HMAC-SHA256
+// is computed over "<unix>.<raw body>" with the shared signing secret
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// webhookTolerance bounds how old a signed webhook may be, to limit replays
+const webhookTolerance = 5 * time.Minute
+
+func signPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func verifySignature(secret string, header string, payload []byte, now time.Time) error {
+	var timestamp int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return domain.ErrInvalidWebhookSignature
+			}
+			timestamp = ts
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == 0 || signature == "" {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	expected := signPayload(secret, timestamp, payload)
+	if !hmac.Equal([]byte(expected), []byte(fmt.Sprintf("t=%d,v1=%s", timestamp, signature))) {
+		return domain.ErrInvalidWebhookSignature
+	}
+	return nil
+}
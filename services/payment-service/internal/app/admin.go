@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+)
+
+// ListPaymentsByStatus returns the payments in status, oldest first
+func (s *Service) ListPaymentsByStatus(ctx context.Context, status domain.PaymentStatus) ([]*domain.Payment, error) {
+	payments, err := s.paymentRepo.ListByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+	return payments, nil
+}
+
+// ForcePaymentStatus moves a payment through the same transitions the
+// provider and order events drive, on an admin's say-so: a pending payment
+// can be completed or failed, and a captured or settled one refunded. The
+// change is recorded in the payment's audit trail with the admin's reason, in
+// the same transaction as the change itself; the transition's events are
+// published once that transaction commits.
+func (s *Service) ForcePaymentStatus(ctx context.Context, cmd inbound.ForcePaymentStatusCommand) (*domain.Payment, error) {
+	reason := strings.TrimSpace(cmd.Reason)
+	if reason == "" {
+		return nil, domain.ErrAuditReasonRequired
+	}
+	status, err := domain.ParsePaymentStatus(string(cmd.Status))
+	if err != nil {
+		return nil, err
+	}
+
+	var payment *domain.Payment
+	var from domain.PaymentStatus
+	err = s.withinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = s.paymentRepo.GetByIDForUpdate(ctx, cmd.PaymentID)
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		from = payment.Status
+		if from == status {
+			return fmt.Errorf("payment is already %s: %w", from, domain.ErrInvalidTransition)
+		}
+
+		var transition func(ctx context.Context) error
+		switch {
+		case status == domain.PaymentStatusCompleted:
+			transition = func(ctx context.Context) error { return s.CompletePayment(ctx, payment.PaymentID) }
+		case status == domain.PaymentStatusFailed:
+			transition = func(ctx context.Context) error { return s.FailPayment(ctx, payment.PaymentID) }
+		case status == domain.PaymentStatusRefunded &&
+			(from == domain.PaymentStatusCompleted || from == domain.PaymentStatusSettled):
+			transition = func(ctx context.Context) error { return s.RefundPayment(ctx, payment.OrderID) }
+		default:
+			return fmt.Errorf("payment is %s, cannot move to %s: %w", from, status, domain.ErrInvalidTransition)
+		}
+
+		// The audit is written first, so a failed insert rolls back before
+		// the transition calls the provider
+		audit := &domain.PaymentAudit{
+			PaymentID:  payment.PaymentID,
+			AdminID:    cmd.AdminID,
+			FromStatus: from,
+			ToStatus:   status,
+			Reason:     reason,
+			CreatedAt:  time.Now(),
+		}
+		if err := s.auditRepo.Create(ctx, audit); err != nil {
+			return fmt.Errorf("failed to record payment audit: %w", err)
+		}
+
+		if err := transition(ctx); err != nil {
+			return err
+		}
+
+		payment, err = s.paymentRepo.GetByID(ctx, cmd.PaymentID)
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Payment %s forced from %s to %s by admin %s: %s", payment.PaymentID, from, payment.Status, cmd.AdminID, reason)
+	return payment, nil
+}
+
+// GetPaymentAudit returns the admin status changes made to a payment, oldest
+// first
+func (s *Service) GetPaymentAudit(ctx context.Context, paymentID string) ([]*domain.PaymentAudit, error) {
+	if _, err := s.paymentRepo.GetByID(ctx, paymentID); err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	audits, err := s.auditRepo.ListByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment audit: %w", err)
+	}
+	return audits, nil
+}
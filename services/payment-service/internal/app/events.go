@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"log"
+)
+
+// pendingEventsKey carries the events raised inside a transaction until it
+// commits
+type pendingEventsKey struct{}
+
+type pendingEvents []func(ctx context.Context)
+
+// withinTransaction runs fn in a transaction and publishes the events fn
+// raises through publish only once the transaction has committed, so a
+// rollback never announces a change that was not saved
+func (s *Service) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(pendingEventsKey{}).(*pendingEvents); ok {
+		// The outer transaction publishes when it commits
+		return s.txManager.WithinTransaction(ctx, fn)
+	}
+
+	events := &pendingEvents{}
+	if err := s.txManager.WithinTransaction(context.WithValue(ctx, pendingEventsKey{}, events), fn); err != nil {
+		return err
+	}
+	for _, send := range *events {
+		send(ctx)
+	}
+	return nil
+}
+
+// publish sends an event announcing what happened, at once or, inside
+// withinTransaction, after the transaction commits. The change is saved by
+// then, so a failure to publish is only logged.
+func publish(ctx context.Context, what string, event func(ctx context.Context) error) {
+	send := func(ctx context.Context) {
+		if err := event(ctx); err != nil {
+			log.Printf("%s but failed to publish event: %v", what, err)
+		}
+	}
+
+	if events, ok := ctx.Value(pendingEventsKey{}).(*pendingEvents); ok {
+		*events = append(*events, send)
+		return
+	}
+	send(ctx)
+}
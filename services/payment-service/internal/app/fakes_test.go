@@ -0,0 +1,395 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// The fakes below keep the outbound ports in memory. Repositories hand out
+// copies, as the database would, so a change only sticks once it is saved.
+
+type memoryPayments struct {
+	payments map[string]domain.Payment
+}
+
+func newMemoryPayments(payments ...*domain.Payment) *memoryPayments {
+	repo := &memoryPayments{payments: map[string]domain.Payment{}}
+	for _, payment := range payments {
+		repo.payments[payment.PaymentID] = *payment
+	}
+	return repo
+}
+
+func (r *memoryPayments) saved(paymentID string) *domain.Payment {
+	payment := r.payments[paymentID]
+	return &payment
+}
+
+func (r *memoryPayments) Create(ctx context.Context, payment *domain.Payment) error {
+	for _, existing := range r.payments {
+		if existing.OrderID == payment.OrderID {
+			return domain.ErrPaymentExists
+		}
+	}
+	r.payments[payment.PaymentID] = *payment
+	return nil
+}
+
+func (r *memoryPayments) GetByID(ctx context.Context, paymentID string) (*domain.Payment, error) {
+	payment, ok := r.payments[paymentID]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	return &payment, nil
+}
+
+func (r *memoryPayments) GetByIDForUpdate(ctx context.Context, paymentID string) (*domain.Payment, error) {
+	return r.GetByID(ctx, paymentID)
+}
+
+func (r *memoryPayments) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			return &payment, nil
+		}
+	}
+	return nil, domain.ErrPaymentNotFound
+}
+
+func (r *memoryPayments) Update(ctx context.Context, payment *domain.Payment) error {
+	if _, ok := r.payments[payment.PaymentID]; !ok {
+		return domain.ErrPaymentNotFound
+	}
+	r.payments[payment.PaymentID] = *payment
+	return nil
+}
+
+func (r *memoryPayments) Delete(ctx context.Context, paymentID string) error {
+	delete(r.payments, paymentID)
+	return nil
+}
+
+func (r *memoryPayments) ListByProphetID(ctx context.Context, prophetID string) ([]*domain.Payment, error) {
+	var payments []*domain.Payment
+	for _, payment := range r.payments {
+		if payment.ProphetID == prophetID {
+			payments = append(payments, &payment)
+		}
+	}
+	return payments, nil
+}
+
+func (r *memoryPayments) ListByStatus(ctx context.Context, status domain.PaymentStatus) ([]*domain.Payment, error) {
+	var payments []*domain.Payment
+	for _, payment := range r.payments {
+		if payment.Status == status {
+			payments = append(payments, &payment)
+		}
+	}
+	return payments, nil
+}
+
+func (r *memoryPayments) SettledTotals(ctx context.Context, prophetID string) (map[string]domain.SettledTotal, error) {
+	totals := map[string]domain.SettledTotal{}
+	for _, payment := range r.payments {
+		if payment.ProphetID != prophetID || payment.Status != domain.PaymentStatusSettled {
+			continue
+		}
+		total := totals[payment.Currency]
+		total.Gross = total.Gross.Add(payment.GrossAmount)
+		total.Fee = total.Fee.Add(payment.FeeAmount)
+		totals[payment.Currency] = total
+	}
+	return totals, nil
+}
+
+// memoryLedger posts each reference once and sums balances from the entries
+type memoryLedger struct {
+	transactions []*domain.LedgerTransaction
+}
+
+func (l *memoryLedger) PostTransaction(ctx context.Context, tx *domain.LedgerTransaction) error {
+	if err := tx.Validate(); err != nil {
+		return err
+	}
+	if _, err := l.FindTransactionByReference(ctx, tx.Reference); err == nil {
+		return nil
+	}
+	l.transactions = append(l.transactions, tx)
+	return nil
+}
+
+func (l *memoryLedger) FindTransactionByReference(ctx context.Context, reference string) (*domain.LedgerTransaction, error) {
+	for _, tx := range l.transactions {
+		if tx.Reference == reference {
+			return tx, nil
+		}
+	}
+	return nil, domain.ErrLedgerTransactionNotFound
+}
+
+func (l *memoryLedger) AccountBalance(ctx context.Context, account, currency string) (money.Decimal, error) {
+	return l.AccountBalanceBefore(ctx, account, currency, time.Now().Add(time.Hour))
+}
+
+func (l *memoryLedger) AccountBalances(ctx context.Context, account string) (map[string]money.Decimal, error) {
+	balances := map[string]money.Decimal{}
+	for _, tx := range l.transactions {
+		for _, entry := range tx.Entries {
+			if entry.Account == account {
+				balances[entry.Currency] = balances[entry.Currency].Add(entry.Credit).Sub(entry.Debit)
+			}
+		}
+	}
+	return balances, nil
+}
+
+func (l *memoryLedger) AccountBalanceBefore(ctx context.Context, account, currency string, t time.Time) (money.Decimal, error) {
+	balance := money.Zero
+	for _, tx := range l.transactions {
+		for _, entry := range tx.Entries {
+			if entry.Account == account && entry.Currency == currency && entry.CreatedAt.Before(t) {
+				balance = balance.Add(entry.Credit).Sub(entry.Debit)
+			}
+		}
+	}
+	return balance, nil
+}
+
+func (l *memoryLedger) ListAccountLines(ctx context.Context, account, currency string, from, to time.Time) ([]domain.StatementLine, error) {
+	return nil, nil
+}
+
+// kinds lists the kind and reference of every posted transaction in order
+func (l *memoryLedger) kinds() string {
+	kinds := make([]string, 0, len(l.transactions))
+	for _, tx := range l.transactions {
+		kinds = append(kinds, fmt.Sprintf("%s %s", tx.Kind, tx.Reference))
+	}
+	return fmt.Sprint(kinds)
+}
+
+// memoryPayouts checks new payouts against the ledger balance, as the
+// repository does under its lock
+type memoryPayouts struct {
+	ledger  *memoryLedger
+	payouts map[string]domain.Payout
+}
+
+func (r *memoryPayouts) CreateWithinBalance(ctx context.Context, payout *domain.Payout) error {
+	balance, err := r.ledger.AccountBalance(ctx, domain.ProphetAccount(payout.ProphetID), payout.Currency)
+	if err != nil {
+		return err
+	}
+	open, err := r.OpenTotals(ctx, payout.ProphetID)
+	if err != nil {
+		return err
+	}
+	if payout.Amount.GreaterThan(balance.Sub(open[payout.Currency])) {
+		return domain.ErrInsufficientBalance
+	}
+	r.payouts[payout.ID] = *payout
+	return nil
+}
+
+func (r *memoryPayouts) GetByID(ctx context.Context, payoutID string) (*domain.Payout, error) {
+	payout, ok := r.payouts[payoutID]
+	if !ok {
+		return nil, domain.ErrPayoutNotFound
+	}
+	return &payout, nil
+}
+
+func (r *memoryPayouts) Update(ctx context.Context, payout *domain.Payout) error {
+	r.payouts[payout.ID] = *payout
+	return nil
+}
+
+func (r *memoryPayouts) ListByProphetID(ctx context.Context, prophetID string) ([]*domain.Payout, error) {
+	var payouts []*domain.Payout
+	for _, payout := range r.payouts {
+		if payout.ProphetID == prophetID {
+			payouts = append(payouts, &payout)
+		}
+	}
+	return payouts, nil
+}
+
+func (r *memoryPayouts) ListByStatus(ctx context.Context, status domain.PayoutStatus) ([]*domain.Payout, error) {
+	var payouts []*domain.Payout
+	for _, payout := range r.payouts {
+		if payout.Status == status {
+			payouts = append(payouts, &payout)
+		}
+	}
+	return payouts, nil
+}
+
+func (r *memoryPayouts) OpenTotals(ctx context.Context, prophetID string) (map[string]money.Decimal, error) {
+	totals := map[string]money.Decimal{}
+	for _, payout := range r.payouts {
+		if payout.ProphetID == prophetID && payout.Open() {
+			totals[payout.Currency] = totals[payout.Currency].Add(payout.Amount)
+		}
+	}
+	return totals, nil
+}
+
+type memoryFeeRules struct {
+	rules map[string]domain.FeeRule
+}
+
+func (r *memoryFeeRules) Create(ctx context.Context, rule *domain.FeeRule) error {
+	r.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *memoryFeeRules) GetByID(ctx context.Context, ruleID string) (*domain.FeeRule, error) {
+	rule, ok := r.rules[ruleID]
+	if !ok {
+		return nil, domain.ErrFeeRuleNotFound
+	}
+	return &rule, nil
+}
+
+func (r *memoryFeeRules) Update(ctx context.Context, rule *domain.FeeRule) error {
+	r.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *memoryFeeRules) List(ctx context.Context) ([]*domain.FeeRule, error) {
+	rules := make([]*domain.FeeRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+func (r *memoryFeeRules) ListEffectiveAt(ctx context.Context, t time.Time) ([]*domain.FeeRule, error) {
+	var rules []*domain.FeeRule
+	for _, rule := range r.rules {
+		if rule.EffectiveAt(t) {
+			rules = append(rules, &rule)
+		}
+	}
+	return rules, nil
+}
+
+// fakeTx runs the unit of work in place; the fakes have nothing to roll back
+type fakeTx struct{}
+
+func (fakeTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// recordingPublisher records the name of every event published
+type recordingPublisher struct {
+	events []string
+}
+
+func (p *recordingPublisher) PublishPaymentCompleted(ctx context.Context, payment *domain.Payment) error {
+	p.events = append(p.events, "completed")
+	return nil
+}
+
+func (p *recordingPublisher) PublishPaymentFailed(ctx context.Context, payment *domain.Payment) error {
+	p.events = append(p.events, "failed")
+	return nil
+}
+
+func (p *recordingPublisher) PublishPaymentCreated(ctx context.Context, payment *domain.Payment) error {
+	p.events = append(p.events, "created")
+	return nil
+}
+
+func (p *recordingPublisher) PublishPaymentSettled(ctx context.Context, payment *domain.Payment) error {
+	p.events = append(p.events, "settled")
+	return nil
+}
+
+func (p *recordingPublisher) PublishPaymentRefunded(ctx context.Context, payment *domain.Payment, settlementReversed bool) error {
+	p.events = append(p.events, fmt.Sprintf("refunded reversed=%t", settlementReversed))
+	return nil
+}
+
+// fakeProvider refunds captures, failing them with refundErr when it is set,
+// and records each refund as the session and amount refunded
+type fakeProvider struct {
+	refundErr error
+	refunds   []string
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) CreateCheckoutSession(ctx context.Context, payment *domain.Payment) (*domain.CheckoutSession, error) {
+	return &domain.CheckoutSession{PaymentID: payment.PaymentID, Provider: p.Name(), ProviderRef: "cs_" + payment.PaymentID}, nil
+}
+
+func (p *fakeProvider) SignatureHeader() string {
+	return "X-Fake-Signature"
+}
+
+func (p *fakeProvider) VerifyWebhook(payload []byte, signature string) (*domain.ProviderEvent, error) {
+	return nil, domain.ErrInvalidWebhookSignature
+}
+
+func (p *fakeProvider) RefundCapture(ctx context.Context, payment *domain.Payment, providerRef string, amount money.Decimal) (string, error) {
+	if p.refundErr != nil {
+		return "", p.refundErr
+	}
+	p.refunds = append(p.refunds, providerRef+" "+amount.String())
+	return "re_" + providerRef, nil
+}
+
+// paymentFixture wires a Service to in-memory ports, charging a 10% fee when
+// no rule matches
+type paymentFixture struct {
+	service   *Service
+	payments  *memoryPayments
+	publisher *recordingPublisher
+	provider  *fakeProvider
+	ledger    *memoryLedger
+	payouts   *memoryPayouts
+	feeRules  *memoryFeeRules
+}
+
+func newPaymentFixture(payments ...*domain.Payment) *paymentFixture {
+	ledger := &memoryLedger{}
+	f := &paymentFixture{
+		payments:  newMemoryPayments(payments...),
+		publisher: &recordingPublisher{},
+		provider:  &fakeProvider{},
+		ledger:    ledger,
+		payouts:   &memoryPayouts{ledger: ledger, payouts: map[string]domain.Payout{}},
+		feeRules:  &memoryFeeRules{rules: map[string]domain.FeeRule{}},
+	}
+	defaultRule := &domain.FeeRule{ID: domain.DefaultFeeRuleID, Percentage: money.NewFromInt(10)}
+	f.service = NewPaymentService(f.payments, f.publisher, f.provider, nil, f.ledger, f.payouts, f.feeRules, nil, fakeTx{}, defaultRule)
+	return f
+}
+
+// paymentIn returns a payment of amount for a new order in status
+func paymentIn(status domain.PaymentStatus, amount string) *domain.Payment {
+	payment := domain.NewPayment("order-"+amount+"-"+string(status), money.MustParse(amount), "THB")
+	payment.Status = status
+	return payment
+}
+
+// settle settles the payment and posts it to the ledger as SettlePayment would
+func (f *paymentFixture) settle(payment *domain.Payment, prophetID string) {
+	payment.Status = domain.PaymentStatusCompleted
+	fee := (&domain.FeeRule{ID: domain.DefaultFeeRuleID, Percentage: money.NewFromInt(10)}).Apply(payment.Amount, payment.Currency)
+	if err := payment.Settle(prophetID, "", fee); err != nil {
+		panic(err)
+	}
+	f.payments.payments[payment.PaymentID] = *payment
+	if err := f.ledger.PostTransaction(context.Background(), domain.NewSettlementTransaction(payment)); err != nil {
+		panic(err)
+	}
+}
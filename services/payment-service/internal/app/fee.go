@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// quoteFee splits gross with the most specific fee rule in force at t,
+// falling back to the configured default rule
+func (s *Service) quoteFee(ctx context.Context, prophetID, courseType string, gross money.Decimal, currency string, t time.Time) (domain.FeeBreakdown, error) {
+	rules, err := s.feeRuleRepo.ListEffectiveAt(ctx, t)
+	if err != nil {
+		return domain.FeeBreakdown{}, fmt.Errorf("failed to list fee rules: %w", err)
+	}
+
+	rule := domain.SelectFeeRule(rules, prophetID, courseType, currency, t)
+	if rule == nil {
+		rule = s.defaultFeeRule
+	}
+	return rule.Apply(gross, currency), nil
+}
+
+func (s *Service) CreateFeeRule(ctx context.Context, cmd inbound.CreateFeeRuleCommand) (*domain.FeeRule, error) {
+	effectiveFrom := time.Now()
+	if cmd.EffectiveFrom != nil {
+		effectiveFrom = *cmd.EffectiveFrom
+	}
+
+	rule, err := domain.NewFeeRule(cmd.ProphetID, cmd.CourseType, cmd.Currency, cmd.Percentage, cmd.FlatFee, effectiveFrom, cmd.EffectiveTo, cmd.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRuleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create fee rule: %w", err)
+	}
+
+	log.Printf("Fee rule %s created by %s: %s%% + %s %s (prophet %q, course type %q) from %s",
+		rule.ID, rule.CreatedBy, rule.Percentage, rule.FlatFee, rule.Currency, rule.ProphetID, rule.CourseType, rule.EffectiveFrom.Format(time.RFC3339))
+	return rule, nil
+}
+
+func (s *Service) ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error) {
+	rules, err := s.feeRuleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee rules: %w", err)
+	}
+	return rules, nil
+}
+
+// EndFeeRule stops a rule from applying to settlements from at onwards.
+// Rules are never deleted so past settlements stay explainable.
+func (s *Service) EndFeeRule(ctx context.Context, ruleID string, at time.Time) (*domain.FeeRule, error) {
+	rule, err := s.feeRuleRepo.GetByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFeeRuleNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get fee rule: %w", err)
+	}
+
+	if err := rule.End(at); err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRuleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update fee rule: %w", err)
+	}
+	return rule, nil
+}
+
+// GetProphetBalanceSummary reports, per currency, the prophet's ledger and
+// available balances together with the gross, fee and net of their settled
+// payments
+func (s *Service) GetProphetBalanceSummary(ctx context.Context, prophetID string) (*domain.ProphetBalance, error) {
+	if prophetID == "" {
+		return nil, fmt.Errorf("prophet id is required")
+	}
+	balances, err := s.ledgerRepo.AccountBalances(ctx, domain.ProphetAccount(prophetID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prophet balance: %w", err)
+	}
+	open, err := s.payoutRepo.OpenTotals(ctx, prophetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open payouts: %w", err)
+	}
+	settled, err := s.paymentRepo.SettledTotals(ctx, prophetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settled totals: %w", err)
+	}
+
+	currencies := map[string]struct{}{}
+	for currency := range balances {
+		currencies[currency] = struct{}{}
+	}
+	for currency := range open {
+		currencies[currency] = struct{}{}
+	}
+	for currency := range settled {
+		currencies[currency] = struct{}{}
+	}
+
+	summary := &domain.ProphetBalance{ProphetID: prophetID, Balances: []domain.CurrencyBalance{}}
+	for currency := range currencies {
+		totals := settled[currency]
+		summary.Balances = append(summary.Balances, domain.CurrencyBalance{
+			Currency:         currency,
+			Balance:          balances[currency],
+			AvailableBalance: balances[currency].Sub(open[currency]),
+			GrossSettled:     totals.Gross,
+			PlatformFees:     totals.Fee,
+			NetSettled:       totals.Gross.Sub(totals.Fee),
+		})
+	}
+	sort.Slice(summary.Balances, func(i, j int) bool {
+		return summary.Balances[i].Currency < summary.Balances[j].Currency
+	})
+	return summary, nil
+}
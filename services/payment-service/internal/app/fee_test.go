@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+func TestServiceSettlePaymentQuotesFee(t *testing.T) {
+	lastYear := time.Now().AddDate(-1, 0, 0)
+	lastWeek := time.Now().AddDate(0, 0, -7)
+
+	tests := []struct {
+		name     string
+		rules    []*domain.FeeRule
+		wantFee  string
+		wantRule string
+	}{
+		{name: "default rule", wantFee: "50", wantRule: domain.DefaultFeeRuleID},
+		{
+			name:     "prophet override",
+			rules:    []*domain.FeeRule{{ID: "platform", Percentage: money.NewFromInt(15), EffectiveFrom: lastYear}, {ID: "vip", ProphetID: "prophet-1", Percentage: money.NewFromInt(5), EffectiveFrom: lastYear}},
+			wantFee:  "25",
+			wantRule: "vip",
+		},
+		{
+			name:     "course type rule with a flat fee",
+			rules:    []*domain.FeeRule{{ID: "tarot", CourseType: "tarot", Currency: "THB", Percentage: money.NewFromInt(10), FlatFee: money.NewFromInt(20), EffectiveFrom: lastYear}},
+			wantFee:  "70",
+			wantRule: "tarot",
+		},
+		{
+			name:     "another prophet's override",
+			rules:    []*domain.FeeRule{{ID: "vip", ProphetID: "prophet-2", Percentage: money.NewFromInt(5), EffectiveFrom: lastYear}},
+			wantFee:  "50",
+			wantRule: domain.DefaultFeeRuleID,
+		},
+		{
+			name:     "ended rule",
+			rules:    []*domain.FeeRule{{ID: "promo", Percentage: money.Zero, EffectiveFrom: lastYear, EffectiveTo: &lastWeek}},
+			wantFee:  "50",
+			wantRule: domain.DefaultFeeRuleID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := paymentIn(domain.PaymentStatusCompleted, "500")
+			f := newPaymentFixture(payment)
+			for _, rule := range tt.rules {
+				f.feeRules.Create(context.Background(), rule)
+			}
+
+			if err := f.service.SettlePayment(context.Background(), inbound.SettlePaymentCommand{OrderID: payment.OrderID, ProphetID: "prophet-1", CourseType: "tarot"}); err != nil {
+				t.Fatalf("SettlePayment() error = %v", err)
+			}
+
+			saved := f.payments.saved(payment.PaymentID)
+			wantFee := money.MustParse(tt.wantFee)
+			if saved.Status != domain.PaymentStatusSettled || saved.FeeRuleID != tt.wantRule || !saved.FeeAmount.Equal(wantFee) {
+				t.Errorf("payment is %s with fee %s under %q, want SETTLED with %s under %q", saved.Status, saved.FeeAmount, saved.FeeRuleID, wantFee, tt.wantRule)
+			}
+
+			prophet, _ := f.ledger.AccountBalance(context.Background(), domain.ProphetAccount("prophet-1"), "THB")
+			revenue, _ := f.ledger.AccountBalance(context.Background(), domain.AccountPlatformRevenue, "THB")
+			if !revenue.Equal(wantFee) || !prophet.Equal(payment.Amount.Sub(wantFee)) {
+				t.Errorf("ledger credits prophet %s and revenue %s, want %s and %s", prophet, revenue, payment.Amount.Sub(wantFee), wantFee)
+			}
+		})
+	}
+}
+
+func TestServiceSettlePaymentKeepsFirstQuote(t *testing.T) {
+	payment := paymentIn(domain.PaymentStatusCompleted, "500")
+	f := newPaymentFixture(payment)
+	cmd := inbound.SettlePaymentCommand{OrderID: payment.OrderID, ProphetID: "prophet-1"}
+
+	if err := f.service.SettlePayment(context.Background(), cmd); err != nil {
+		t.Fatalf("SettlePayment() error = %v", err)
+	}
+	// A rule added before the redelivery must not change the settlement
+	f.feeRules.Create(context.Background(), &domain.FeeRule{ID: "free", Percentage: money.Zero, EffectiveFrom: time.Now().Add(-time.Minute)})
+	if err := f.service.SettlePayment(context.Background(), cmd); err != nil {
+		t.Fatalf("redelivered SettlePayment() error = %v", err)
+	}
+
+	if saved := f.payments.saved(payment.PaymentID); saved.FeeRuleID != domain.DefaultFeeRuleID {
+		t.Errorf("fee rule = %q after redelivery, want %q", saved.FeeRuleID, domain.DefaultFeeRuleID)
+	}
+	if want := fmt.Sprintf("[SETTLEMENT settlement:%s]", payment.PaymentID); f.ledger.kinds() != want {
+		t.Errorf("ledger = %s, want %s", f.ledger.kinds(), want)
+	}
+	if got := fmt.Sprint(f.publisher.events); got != "[settled]" {
+		t.Errorf("published %s, want one settlement", got)
+	}
+}
+
+func TestServiceSettleBundleSessions(t *testing.T) {
+	payment := paymentIn(domain.PaymentStatusCompleted, "1000")
+	f := newPaymentFixture(payment)
+
+	for session := 1; session <= 3; session++ {
+		cmd := inbound.SettlePaymentCommand{OrderID: payment.OrderID, ProphetID: "prophet-1", Session: session, Sessions: 3}
+		if err := f.service.SettlePayment(context.Background(), cmd); err != nil {
+			t.Fatalf("SettlePayment() session %d error = %v", session, err)
+		}
+	}
+
+	saved := f.payments.saved(payment.PaymentID)
+	if saved.Status != domain.PaymentStatusSettled || !saved.GrossAmount.Equal(payment.Amount) || !saved.FeeAmount.Equal(money.NewFromInt(100)) {
+		t.Errorf("payment is %s with gross %s and fee %s, want SETTLED with 1000 and 100", saved.Status, saved.GrossAmount, saved.FeeAmount)
+	}
+	prophet, _ := f.ledger.AccountBalance(context.Background(), domain.ProphetAccount("prophet-1"), "THB")
+	if !prophet.Equal(money.NewFromInt(900)) {
+		t.Errorf("prophet balance = %s, want 900", prophet)
+	}
+	if got := fmt.Sprint(f.publisher.events); got != "[settled]" {
+		t.Errorf("published %s, want the settlement once the last session settles", got)
+	}
+}
+
+func TestServiceEndFeeRule(t *testing.T) {
+	f := newPaymentFixture()
+	from := time.Now().Add(-time.Hour)
+	rule, err := f.service.CreateFeeRule(context.Background(), inbound.CreateFeeRuleCommand{Percentage: money.NewFromInt(20), EffectiveFrom: &from, CreatedBy: "admin-1"})
+	if err != nil {
+		t.Fatalf("CreateFeeRule() error = %v", err)
+	}
+
+	if _, err := f.service.EndFeeRule(context.Background(), rule.ID, from); err == nil {
+		t.Error("EndFeeRule() at its start succeeded, want an error")
+	}
+	if _, err := f.service.EndFeeRule(context.Background(), rule.ID, time.Now()); err != nil {
+		t.Fatalf("EndFeeRule() error = %v", err)
+	}
+
+	payment := paymentIn(domain.PaymentStatusCompleted, "500")
+	f.payments.payments[payment.PaymentID] = *payment
+	if err := f.service.SettlePayment(context.Background(), inbound.SettlePaymentCommand{OrderID: payment.OrderID, ProphetID: "prophet-1"}); err != nil {
+		t.Fatalf("SettlePayment() error = %v", err)
+	}
+	if saved := f.payments.saved(payment.PaymentID); saved.FeeRuleID != domain.DefaultFeeRuleID {
+		t.Errorf("settled under %q after the rule ended, want %q", saved.FeeRuleID, domain.DefaultFeeRuleID)
+	}
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// maxStatementRange bounds a single statement request
+const maxStatementRange = 366 * 24 * time.Hour
+
+// postSettlement credits the prophet for a settled payment
+func (s *Service) postSettlement(ctx context.Context, payment *domain.Payment) error {
+	tx := domain.NewSettlementTransaction(payment)
+	if err := s.ledgerRepo.PostTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to post settlement to ledger: %w", err)
+	}
+	return nil
+}
+
+// reverseSettlement takes a refunded payment's settlements back out of the
+// prophet's balance. Payments that were never settled have nothing to
+// reverse, and a bundle refunded part way keeps its settled sessions.
+func (s *Service) reverseSettlement(ctx context.Context, payment *domain.Payment) error {
+	if payment.PartiallySettled() {
+		return nil
+	}
+
+	for _, reference := range payment.SettlementReferences() {
+		settlement, err := s.ledgerRepo.FindTransactionByReference(ctx, reference)
+		if errors.Is(err, domain.ErrLedgerTransactionNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find settlement in ledger: %w", err)
+		}
+
+		if err := s.ledgerRepo.PostTransaction(ctx, domain.NewRefundReversalTransaction(settlement)); err != nil {
+			return fmt.Errorf("failed to post refund reversal to ledger: %w", err)
+		}
+	}
+	return nil
+}
+
+// BackfillSettlementLedger posts settlements for payments settled before the
+// ledger existed. Already posted payments are skipped by their reference.
+func (s *Service) BackfillSettlementLedger(ctx context.Context) (int, error) {
+	payments, err := s.paymentRepo.ListByStatus(ctx, domain.PaymentStatusSettled)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list settled payments: %w", err)
+	}
+
+	posted := 0
+	for _, payment := range payments {
+		// Bundles were posted session by session as they settled
+		if payment.IsBundle() {
+			continue
+		}
+		if _, err := s.ledgerRepo.FindTransactionByReference(ctx, domain.SettlementReference(payment.PaymentID)); err == nil {
+			continue
+		} else if !errors.Is(err, domain.ErrLedgerTransactionNotFound) {
+			return posted, fmt.Errorf("failed to look up settlement: %w", err)
+		}
+
+		if err := s.postSettlement(ctx, payment); err != nil {
+			return posted, err
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+// GetProphetStatement lists the movements in currency on a prophet's account
+// in [from, to) with running balances
+func (s *Service) GetProphetStatement(ctx context.Context, prophetID string, currency string, from, to time.Time) (*domain.Statement, error) {
+	if prophetID == "" {
+		return nil, fmt.Errorf("prophet id is required")
+	}
+	if !to.After(from) || to.Sub(from) > maxStatementRange {
+		return nil, domain.ErrInvalidStatementRange
+	}
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	account := domain.ProphetAccount(prophetID)
+	opening, err := s.ledgerRepo.AccountBalanceBefore(ctx, account, currency, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening balance: %w", err)
+	}
+	lines, err := s.ledgerRepo.ListAccountLines(ctx, account, currency, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+
+	statement := &domain.Statement{
+		ProphetID:      prophetID,
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		Lines:          lines,
+	}
+	statement.ApplyRunningBalance()
+	return statement, nil
+}
+
+// RequestPayout asks to withdraw part of the prophet's available balance in
+// currency
+func (s *Service) RequestPayout(ctx context.Context, prophetID string, amount money.Decimal, currency string) (*domain.Payout, error) {
+	if prophetID == "" {
+		return nil, fmt.Errorf("prophet id is required")
+	}
+	payout, err := domain.NewPayout(prophetID, amount, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.payoutRepo.CreateWithinBalance(ctx, payout); err != nil {
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create payout: %w", err)
+	}
+
+	log.Printf("Payout %s of %s %s requested by prophet %s", payout.ID, payout.Amount, payout.Currency, prophetID)
+	return payout, nil
+}
+
+// GetAvailableBalance is the balance in currency the prophet may still
+// request a payout for
+func (s *Service) GetAvailableBalance(ctx context.Context, prophetID string, currency string) (money.Decimal, error) {
+	summary, err := s.GetProphetBalanceSummary(ctx, prophetID)
+	if err != nil {
+		return money.Zero, err
+	}
+	currency, err = money.NormalizeCurrency(currency)
+	if err != nil {
+		return money.Zero, err
+	}
+	return summary.In(currency).AvailableBalance, nil
+}
+
+func (s *Service) ListProphetPayouts(ctx context.Context, prophetID string) ([]*domain.Payout, error) {
+	payouts, err := s.payoutRepo.ListByProphetID(ctx, prophetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+func (s *Service) ListPayoutsByStatus(ctx context.Context, status domain.PayoutStatus) ([]*domain.Payout, error) {
+	payouts, err := s.payoutRepo.ListByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+func (s *Service) ApprovePayout(ctx context.Context, payoutID string, reviewer string) (*domain.Payout, error) {
+	return s.transitionPayout(ctx, payoutID, func(payout *domain.Payout) error {
+		return payout.Approve(reviewer)
+	})
+}
+
+func (s *Service) RejectPayout(ctx context.Context, payoutID string, reviewer string, reason string) (*domain.Payout, error) {
+	return s.transitionPayout(ctx, payoutID, func(payout *domain.Payout) error {
+		return payout.Reject(reviewer, reason)
+	})
+}
+
+// CancelPayout lets the prophet withdraw a request that was not reviewed yet
+func (s *Service) CancelPayout(ctx context.Context, payoutID string, prophetID string) (*domain.Payout, error) {
+	return s.transitionPayout(ctx, payoutID, func(payout *domain.Payout) error {
+		if payout.ProphetID != prophetID {
+			return domain.ErrPayoutNotFound
+		}
+		return payout.Cancel()
+	})
+}
+
+// MarkPayoutPaid records the bank transfer for an approved payout and debits
+// the prophet's balance in the ledger
+func (s *Service) MarkPayoutPaid(ctx context.Context, payoutID string, reference string) (*domain.Payout, error) {
+	payout, err := s.transitionPayout(ctx, payoutID, func(payout *domain.Payout) error {
+		return payout.MarkPaid(reference)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Posting is idempotent, so marking the payout paid again repairs a failed post
+	if err := s.ledgerRepo.PostTransaction(ctx, domain.NewWithdrawalTransaction(payout)); err != nil {
+		return nil, fmt.Errorf("failed to post withdrawal to ledger: %w", err)
+	}
+
+	log.Printf("Payout %s of %s %s paid to prophet %s", payout.ID, payout.Amount, payout.Currency, payout.ProphetID)
+	return payout, nil
+}
+
+func (s *Service) transitionPayout(ctx context.Context, payoutID string, transition func(*domain.Payout) error) (*domain.Payout, error) {
+	payout, err := s.payoutRepo.GetByID(ctx, payoutID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPayoutNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get payout: %w", err)
+	}
+
+	if err := transition(payout); err != nil {
+		return nil, err
+	}
+
+	if err := s.payoutRepo.Update(ctx, payout); err != nil {
+		return nil, fmt.Errorf("failed to update payout: %w", err)
+	}
+	return payout, nil
+}
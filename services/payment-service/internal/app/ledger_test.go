@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// earningFixture has prophet-1 earn 450 THB from a settled 500 THB payment
+func earningFixture() *paymentFixture {
+	f := newPaymentFixture()
+	f.settle(paymentIn(domain.PaymentStatusCompleted, "500"), "prophet-1")
+	return f
+}
+
+func TestServiceRequestPayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		open     string
+		amount   string
+		currency string
+		wantErr  error
+	}{
+		{name: "whole balance", amount: "450", currency: "THB"},
+		{name: "part of the balance", amount: "100", currency: "thb"},
+		{name: "more than the balance", amount: "450.01", currency: "THB", wantErr: domain.ErrInsufficientBalance},
+		{name: "balance held by an open payout", open: "400", amount: "100", currency: "THB", wantErr: domain.ErrInsufficientBalance},
+		{name: "other currency has no balance", amount: "1", currency: "USD", wantErr: domain.ErrInsufficientBalance},
+		{name: "zero", amount: "0", currency: "THB", wantErr: domain.ErrInvalidPayoutAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := earningFixture()
+			if tt.open != "" {
+				if _, err := f.service.RequestPayout(context.Background(), "prophet-1", money.MustParse(tt.open), "THB"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			payout, err := f.service.RequestPayout(context.Background(), "prophet-1", money.MustParse(tt.amount), tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestPayout() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if payout.Status != domain.PayoutStatusRequested || payout.Currency != "THB" {
+				t.Errorf("payout is %s in %s, want REQUESTED in THB", payout.Status, payout.Currency)
+			}
+			if len(f.ledger.transactions) != 1 {
+				t.Errorf("requesting a payout posted to the ledger")
+			}
+		})
+	}
+}
+
+func TestServicePayoutPaid(t *testing.T) {
+	f := earningFixture()
+	ctx := context.Background()
+
+	payout, err := f.service.RequestPayout(ctx, "prophet-1", money.MustParse("300"), "THB")
+	if err != nil {
+		t.Fatalf("RequestPayout() error = %v", err)
+	}
+	available, _ := f.service.GetAvailableBalance(ctx, "prophet-1", "THB")
+	if !available.Equal(money.NewFromInt(150)) {
+		t.Errorf("available balance with the payout open = %s, want 150", available)
+	}
+
+	if _, err := f.service.MarkPayoutPaid(ctx, payout.ID, "transfer-1"); !errors.Is(err, domain.ErrInvalidPayoutState) {
+		t.Fatalf("MarkPayoutPaid() before approval error = %v, want %v", err, domain.ErrInvalidPayoutState)
+	}
+	if _, err := f.service.ApprovePayout(ctx, payout.ID, "admin-1"); err != nil {
+		t.Fatalf("ApprovePayout() error = %v", err)
+	}
+	// Marking it paid again repairs a failed post without debiting twice
+	for i := 0; i < 2; i++ {
+		if _, err := f.service.MarkPayoutPaid(ctx, payout.ID, "transfer-1"); err != nil {
+			t.Fatalf("MarkPayoutPaid() error = %v", err)
+		}
+	}
+
+	balance, _ := f.service.GetProphetBalance(ctx, "prophet-1", "THB")
+	if !balance.Equal(money.NewFromInt(150)) {
+		t.Errorf("balance after the payout = %s, want 150", balance)
+	}
+	available, _ = f.service.GetAvailableBalance(ctx, "prophet-1", "THB")
+	if !available.Equal(money.NewFromInt(150)) {
+		t.Errorf("available balance after the payout = %s, want 150", available)
+	}
+	want := fmt.Sprintf("[SETTLEMENT settlement:%s WITHDRAWAL withdrawal:%s]", f.ledger.transactions[0].PaymentID, payout.ID)
+	if got := f.ledger.kinds(); got != want {
+		t.Errorf("ledger = %s, want %s", got, want)
+	}
+}
+
+func TestServicePayoutClosedWithoutPaying(t *testing.T) {
+	tests := []struct {
+		name    string
+		close   func(s *Service, payoutID string) (*domain.Payout, error)
+		want    domain.PayoutStatus
+		wantErr error
+	}{
+		{
+			name: "rejected",
+			close: func(s *Service, id string) (*domain.Payout, error) {
+				return s.RejectPayout(context.Background(), id, "admin-1", "bank details missing")
+			},
+			want: domain.PayoutStatusRejected,
+		},
+		{
+			name: "cancelled by the prophet",
+			close: func(s *Service, id string) (*domain.Payout, error) {
+				return s.CancelPayout(context.Background(), id, "prophet-1")
+			},
+			want: domain.PayoutStatusCancelled,
+		},
+		{
+			name: "cancelled by another prophet",
+			close: func(s *Service, id string) (*domain.Payout, error) {
+				return s.CancelPayout(context.Background(), id, "prophet-2")
+			},
+			want:    domain.PayoutStatusRequested,
+			wantErr: domain.ErrPayoutNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := earningFixture()
+			payout, err := f.service.RequestPayout(context.Background(), "prophet-1", money.MustParse("450"), "THB")
+			if err != nil {
+				t.Fatalf("RequestPayout() error = %v", err)
+			}
+
+			if _, err := tt.close(f.service, payout.ID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("closing the payout error = %v, want %v", err, tt.wantErr)
+			}
+			saved, _ := f.payouts.GetByID(context.Background(), payout.ID)
+			if saved.Status != tt.want {
+				t.Errorf("status = %s, want %s", saved.Status, tt.want)
+			}
+
+			// A closed payout frees its amount for a new request
+			_, err = f.service.RequestPayout(context.Background(), "prophet-1", money.MustParse("450"), "THB")
+			if freed := tt.wantErr == nil; (err == nil) != freed {
+				t.Errorf("new request error = %v, want balance freed %v", err, freed)
+			}
+			if len(f.ledger.transactions) != 1 {
+				t.Errorf("closing a payout posted to the ledger")
+			}
+		})
+	}
+}
+
+func TestServiceRefundAfterPayoutRequest(t *testing.T) {
+	f := newPaymentFixture()
+	payment := paymentIn(domain.PaymentStatusCompleted, "500")
+	f.settle(payment, "prophet-1")
+	ctx := context.Background()
+
+	if _, err := f.service.RequestPayout(ctx, "prophet-1", money.MustParse("450"), "THB"); err != nil {
+		t.Fatalf("RequestPayout() error = %v", err)
+	}
+	if err := f.service.RefundPayment(ctx, payment.OrderID); err != nil {
+		t.Fatalf("RefundPayment() error = %v", err)
+	}
+
+	// The reversal leaves nothing to pay the open request from
+	balance, _ := f.service.GetProphetBalance(ctx, "prophet-1", "THB")
+	available, _ := f.service.GetAvailableBalance(ctx, "prophet-1", "THB")
+	if !balance.IsZero() || !available.Equal(money.NewFromInt(-450)) {
+		t.Errorf("balance %s, available %s after the refund, want 0 and -450", balance, available)
+	}
+	if _, err := f.service.RequestPayout(ctx, "prophet-1", money.MustParse("1"), "THB"); !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("RequestPayout() after the refund error = %v, want %v", err, domain.ErrInsufficientBalance)
+	}
+}
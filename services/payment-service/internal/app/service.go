@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -9,30 +10,90 @@ import (
 	"github.com/wnmay/horo/services/payment-service/internal/domain"
 	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
 	"github.com/wnmay/horo/services/payment-service/internal/ports/outbound"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type Service struct {
 	paymentRepo    outbound.PaymentRepository
 	eventPublisher outbound.PaymentEventPublisher
+	provider       outbound.PaymentProvider
+	webhookRepo    outbound.WebhookRepository
+	ledgerRepo     outbound.LedgerRepository
+	payoutRepo     outbound.PayoutRepository
+	feeRuleRepo    outbound.FeeRuleRepository
+	auditRepo      outbound.AuditRepository
+	txManager      outbound.TransactionManager
+	defaultFeeRule *domain.FeeRule
 }
 
-func NewPaymentService(paymentRepo outbound.PaymentRepository, eventPublisher outbound.PaymentEventPublisher) *Service {
+func NewPaymentService(
+	paymentRepo outbound.PaymentRepository,
+	eventPublisher outbound.PaymentEventPublisher,
+	provider outbound.PaymentProvider,
+	webhookRepo outbound.WebhookRepository,
+	ledgerRepo outbound.LedgerRepository,
+	payoutRepo outbound.PayoutRepository,
+	feeRuleRepo outbound.FeeRuleRepository,
+	auditRepo outbound.AuditRepository,
+	txManager outbound.TransactionManager,
+	defaultFeeRule *domain.FeeRule,
+) *Service {
 	return &Service{
 		paymentRepo:    paymentRepo,
 		eventPublisher: eventPublisher,
+		provider:       provider,
+		webhookRepo:    webhookRepo,
+		ledgerRepo:     ledgerRepo,
+		payoutRepo:     payoutRepo,
+		feeRuleRepo:    feeRuleRepo,
+		auditRepo:      auditRepo,
+		txManager:      txManager,
+		defaultFeeRule: defaultFeeRule,
 	}
 }
 
 // Payment Service Implementation
 func (s *Service) CreatePaymentFromOrder(ctx context.Context, cmd inbound.CreatePaymentCommand) (*domain.Payment, error) {
-	log.Printf("Creating payment for order: %s, amount: %.2f", cmd.OrderID, cmd.Amount)
+	currency, err := money.NormalizeCurrency(cmd.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment currency for order %s: %w", cmd.OrderID, err)
+	}
+	log.Printf("Creating payment for order: %s, amount: %s %s", cmd.OrderID, cmd.Amount, currency)
 
-	// Create new payment entity
-	payment := domain.NewPayment(cmd.OrderID, cmd.Amount)
+	// A retried delivery may find the payment from an earlier attempt; reuse it
+	// and only re-announce it instead of charging the order twice
+	payment, err := s.paymentRepo.GetByOrderID(ctx, cmd.OrderID)
+	switch {
+	case err == nil && payment.Status == domain.PaymentStatusFailed && payment.Amount.IsZero():
+		// The order was cancelled first and its payment voided; record what it
+		// would have charged, but leave it voided
+		payment.Amount = money.RoundTo(cmd.Amount, currency)
+		payment.Currency = currency
+		payment.UpdatedAt = time.Now()
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return nil, fmt.Errorf("failed to update voided payment: %w", err)
+		}
+		log.Printf("Order %s was cancelled before payment; payment %s stays voided", cmd.OrderID, payment.PaymentID)
+	case err == nil:
+		log.Printf("Payment %s already exists for order %s", payment.PaymentID, cmd.OrderID)
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		// Create new payment entity
+		payment = domain.NewPayment(cmd.OrderID, money.RoundTo(cmd.Amount, currency), currency)
 
-	// Save payment to repository
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
-		return nil, fmt.Errorf("failed to create payment: %w", err)
+		// Save payment to repository. A concurrent delivery may have created
+		// the order's payment since the lookup; use that one.
+		switch err := s.paymentRepo.Create(ctx, payment); {
+		case errors.Is(err, domain.ErrPaymentExists):
+			payment, err = s.paymentRepo.GetByOrderID(ctx, cmd.OrderID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up payment for order: %w", err)
+			}
+			log.Printf("Payment %s was created concurrently for order %s", payment.PaymentID, cmd.OrderID)
+		case err != nil:
+			return nil, fmt.Errorf("failed to create payment: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to look up payment for order: %w", err)
 	}
 
 	if err := s.eventPublisher.PublishPaymentCreated(ctx, payment); err != nil {
@@ -82,7 +143,9 @@ func (s *Service) CompletePayment(ctx context.Context, paymentID string) error {
 	}
 
 	// Complete the payment
-	payment.Complete()
+	if err := payment.Complete(); err != nil {
+		return fmt.Errorf("failed to complete payment: %w", err)
+	}
 
 	// Update payment in repository
 	if err := s.paymentRepo.Update(ctx, payment); err != nil {
@@ -90,44 +153,358 @@ func (s *Service) CompletePayment(ctx context.Context, paymentID string) error {
 	}
 
 	// Publish payment completed event
-	if err := s.eventPublisher.PublishPaymentCompleted(ctx, payment); err != nil {
-		log.Printf("Failed to publish payment completed event: %v", err)
-		// Don't return error as payment was successfully completed
-	}
+	publish(ctx, "Payment completed", func(ctx context.Context) error {
+		return s.eventPublisher.PublishPaymentCompleted(ctx, payment)
+	})
 
 	log.Printf("Payment %s completed successfully", payment.PaymentID)
 	return nil
 }
 
-func (s *Service) SettlePayment(ctx context.Context, orderID string, prophetID string) error {
-	payment, err := s.paymentRepo.GetByOrderID(ctx, orderID)
+// SettlePayment attributes the order's captured payment to the prophet,
+// taking the platform fee in force now. Like settleSession, the ledger is
+// posted before the payment is saved as SETTLED, so a redelivery after a
+// failed post or save still completes the settlement and publishes it.
+func (s *Service) SettlePayment(ctx context.Context, cmd inbound.SettlePaymentCommand) error {
+	payment, err := s.paymentRepo.GetByOrderID(ctx, cmd.OrderID)
 	if err != nil {
 		return fmt.Errorf("failed to get payment: %w", err)
 	}
+	if cmd.Sessions > 1 {
+		return s.settleSession(ctx, payment, cmd)
+	}
 
 	prev := payment.Status
-	if err := payment.Settle(prophetID); err != nil {
-		return fmt.Errorf("failed to settle payment: %w", err)
+	if prev != domain.PaymentStatusSettled {
+		fee, err := s.quoteFee(ctx, cmd.ProphetID, cmd.CourseType, payment.Amount, payment.Currency, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := payment.Settle(cmd.ProphetID, cmd.CourseType, fee); err != nil {
+			return fmt.Errorf("failed to settle payment: %w", err)
+		}
+	}
+
+	// Posting is idempotent, so a redelivery after a failed save posts nothing twice
+	if err := s.postSettlement(ctx, payment); err != nil {
+		return err
+	}
+
+	if prev == domain.PaymentStatusSettled {
+		log.Printf("Payment %s is already settled", payment.PaymentID)
+		return nil
 	}
 
 	if err := s.paymentRepo.Update(ctx, payment); err != nil {
 		return fmt.Errorf("failed to update payment: %w", err)
 	}
 
-	if prev != domain.PaymentStatusSettled && payment.Status == domain.PaymentStatusSettled {
+	if payment.Status == domain.PaymentStatusSettled {
 		if err := s.eventPublisher.PublishPaymentSettled(ctx, payment); err != nil {
 			log.Printf("Payment settled but failed to publish event: %v", err)
 		}
 	}
 
-	log.Printf("Payment %s settled successfully", payment.PaymentID)
+	log.Printf("Payment %s settled: gross %s, fee %s, net %s %s", payment.PaymentID, payment.GrossAmount, payment.FeeAmount, payment.NetAmount, payment.Currency)
 	return nil
 }
 
+// settleSession settles one session's share of a bundle payment. The ledger
+// is posted before the payment is saved; posting is idempotent, so a
+// redelivery after a failed save completes the settlement.
+func (s *Service) settleSession(ctx context.Context, payment *domain.Payment, cmd inbound.SettlePaymentCommand) error {
+	fee, err := s.quoteFee(ctx, cmd.ProphetID, cmd.CourseType, payment.Amount, payment.Currency, time.Now())
+	if err != nil {
+		return err
+	}
+	settlement, err := payment.SettleSession(cmd.ProphetID, cmd.CourseType, cmd.Session, cmd.Sessions, fee)
+	if err != nil {
+		return fmt.Errorf("failed to settle session %d of payment: %w", cmd.Session, err)
+	}
+	if settlement == nil {
+		log.Printf("Session %d of payment %s is already settled", cmd.Session, payment.PaymentID)
+		return nil
+	}
+
+	if err := s.ledgerRepo.PostTransaction(ctx, domain.NewSessionSettlementTransaction(payment, settlement)); err != nil {
+		return fmt.Errorf("failed to post settlement to ledger: %w", err)
+	}
+
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	if payment.Status == domain.PaymentStatusSettled {
+		if err := s.eventPublisher.PublishPaymentSettled(ctx, payment); err != nil {
+			log.Printf("Payment settled but failed to publish event: %v", err)
+		}
+	}
+
+	log.Printf("Payment %s settled session %d/%d: gross %s, fee %s %s", payment.PaymentID, settlement.Session, cmd.Sessions, settlement.Gross, settlement.Fee, payment.Currency)
+	return nil
+}
 
-func (s *Service) GetProphetBalance(ctx context.Context, prophetID string) (float64, error) {
-    if prophetID == "" {
-        return 0, fmt.Errorf("prophet id is required")
-    }
-    return s.paymentRepo.GetProphetSettledBalance(ctx, prophetID)
-}
\ No newline at end of file
+// GetProphetBalance returns what the platform owes the prophet in currency
+// according to the ledger: settlements less refund reversals and paid-out
+// withdrawals
+func (s *Service) GetProphetBalance(ctx context.Context, prophetID string, currency string) (money.Decimal, error) {
+	if prophetID == "" {
+		return money.Zero, fmt.Errorf("prophet id is required")
+	}
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return money.Zero, err
+	}
+	balance, err := s.ledgerRepo.AccountBalance(ctx, domain.ProphetAccount(prophetID), currency)
+	if err != nil {
+		return money.Zero, fmt.Errorf("failed to get prophet balance: %w", err)
+	}
+	return balance, nil
+}
+
+// ListPaymentsByProphet returns the payments attributed to a prophet, newest
+// first. Payments only carry a prophet once they have been settled.
+func (s *Service) ListPaymentsByProphet(ctx context.Context, prophetID string) ([]*domain.Payment, error) {
+	if prophetID == "" {
+		return nil, fmt.Errorf("prophet id is required")
+	}
+	payments, err := s.paymentRepo.ListByProphetID(ctx, prophetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments for prophet: %w", err)
+	}
+	return payments, nil
+}
+
+// RefundPayment compensates for a cancelled order. A payment that was never
+// captured is failed, and one not created yet is recorded as failed; a
+// captured or settled one is refunded, reversing the prophet's settlement
+// when needed. Bundles cancelled part way refund only the sessions that were
+// not settled.
+func (s *Service) RefundPayment(ctx context.Context, orderID string) error {
+	payment, err := s.paymentRepo.GetByOrderID(ctx, orderID)
+	if errors.Is(err, domain.ErrPaymentNotFound) {
+		// The order was cancelled before its payment was created; void the
+		// payment up front so the one created afterwards cannot be paid
+		voided := domain.NewVoidedPayment(orderID)
+		err = s.paymentRepo.Create(ctx, voided)
+		if err == nil {
+			log.Printf("Order %s cancelled before its payment was created; payment %s recorded as voided", orderID, voided.PaymentID)
+			return nil
+		}
+		if !errors.Is(err, domain.ErrPaymentExists) {
+			return fmt.Errorf("failed to record voided payment: %w", err)
+		}
+		// Created concurrently after all; compensate it like any other
+		payment, err = s.paymentRepo.GetByOrderID(ctx, orderID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	switch payment.Status {
+	case domain.PaymentStatusRefunded:
+		log.Printf("Payment %s for order %s is already %s", payment.PaymentID, orderID, payment.Status)
+		// An earlier attempt may have refunded without reversing the ledger
+		return s.reverseSettlement(ctx, payment)
+	case domain.PaymentStatusFailed:
+		log.Printf("Payment %s for order %s is already %s", payment.PaymentID, orderID, payment.Status)
+		return nil
+	case domain.PaymentStatusPending:
+		payment.Fail()
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		publish(ctx, "Payment voided", func(ctx context.Context) error {
+			return s.eventPublisher.PublishPaymentFailed(ctx, payment)
+		})
+		log.Printf("Payment %s voided for cancelled order %s", payment.PaymentID, orderID)
+		return nil
+	}
+
+	amount, err := payment.RefundAmount()
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	// The money goes back at the provider before the payment is marked
+	// refunded, so a failed refund leaves the payment as it was and the
+	// redelivery retries it. Payments completed without the provider, such as
+	// by an admin, have nothing there to refund.
+	refundRef := ""
+	if payment.ProviderRef != "" && amount.IsPositive() {
+		refundRef, err = s.provider.RefundCapture(ctx, payment, payment.ProviderRef, amount)
+		if err != nil {
+			return fmt.Errorf("failed to refund payment %s at the provider: %w", payment.PaymentID, err)
+		}
+	}
+
+	settlementReversed, err := payment.Refund(refundRef)
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	if settlementReversed {
+		if err := s.reverseSettlement(ctx, payment); err != nil {
+			return err
+		}
+	}
+
+	publish(ctx, "Payment refunded", func(ctx context.Context) error {
+		return s.eventPublisher.PublishPaymentRefunded(ctx, payment, settlementReversed)
+	})
+
+	log.Printf("Payment %s refunded %s %s for order %s (provider refund: %q, settlement reversed: %t)", payment.PaymentID, amount, payment.Currency, orderID, refundRef, settlementReversed)
+	return nil
+}
+
+// CreateCheckoutSession opens a hosted checkout at the payment provider for a
+// pending payment and remembers the provider reference on the payment
+func (s *Service) CreateCheckoutSession(ctx context.Context, paymentID string) (*domain.CheckoutSession, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	if payment.Status != domain.PaymentStatusPending {
+		return nil, fmt.Errorf("payment is %s: %w", payment.Status, domain.ErrInvalidTransition)
+	}
+
+	session, err := s.provider.CreateCheckoutSession(ctx, payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	if err := payment.AttachCheckoutSession(session); err != nil {
+		return nil, err
+	}
+
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	log.Printf("Checkout session %s created for payment %s", session.ProviderRef, payment.PaymentID)
+	return session, nil
+}
+
+// FailPayment marks a pending payment as failed
+func (s *Service) FailPayment(ctx context.Context, paymentID string) error {
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	if payment.Status == domain.PaymentStatusFailed {
+		return nil
+	}
+	if payment.Status != domain.PaymentStatusPending {
+		return fmt.Errorf("payment is %s: %w", payment.Status, domain.ErrInvalidTransition)
+	}
+
+	payment.Fail()
+
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	publish(ctx, "Payment failed", func(ctx context.Context) error {
+		return s.eventPublisher.PublishPaymentFailed(ctx, payment)
+	})
+
+	log.Printf("Payment %s failed", payment.PaymentID)
+	return nil
+}
+
+// HandleProviderWebhook verifies a webhook from the payment provider, keeps
+// its raw body and drives the payment to COMPLETED or FAILED. A capture that
+// arrives after the payment was voided or refunded is refunded in turn.
+// Redelivered events that were already processed are acknowledged without
+// side effects.
+func (s *Service) HandleProviderWebhook(ctx context.Context, providerName string, payload []byte, signature string) error {
+	if providerName != s.provider.Name() {
+		return domain.ErrUnknownProvider
+	}
+
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	record, err := s.webhookRepo.Record(ctx, &domain.WebhookRecord{
+		Provider:    providerName,
+		EventID:     event.EventID,
+		EventType:   string(event.Type),
+		PaymentID:   event.PaymentID,
+		ProviderRef: event.ProviderRef,
+		Payload:     string(payload),
+		Signature:   signature,
+		ReceivedAt:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook: %w", err)
+	}
+	if record.ProcessedAt != nil {
+		log.Printf("Webhook %s already processed, skipping", event.EventID)
+		return nil
+	}
+
+	processingErr := s.applyProviderEvent(ctx, record, event)
+	if err := s.webhookRepo.MarkProcessed(ctx, record.ID, processingErr); err != nil {
+		log.Printf("Failed to mark webhook %s as processed: %v", record.ID, err)
+	}
+	return processingErr
+}
+
+func (s *Service) applyProviderEvent(ctx context.Context, record *domain.WebhookRecord, event *domain.ProviderEvent) error {
+	payment, err := s.paymentRepo.GetByID(ctx, event.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment.ProviderRef != event.ProviderRef {
+		return fmt.Errorf("webhook session %s does not match payment %s", event.ProviderRef, payment.PaymentID)
+	}
+
+	switch event.Type {
+	case domain.ProviderEventPaymentSucceeded:
+		switch payment.Status {
+		case domain.PaymentStatusCompleted, domain.PaymentStatusSettled:
+			return nil
+		case domain.PaymentStatusFailed, domain.PaymentStatusRefunded:
+			return s.refundLateCapture(ctx, record, payment, event)
+		}
+		return s.CompletePayment(ctx, payment.PaymentID)
+	case domain.ProviderEventPaymentFailed:
+		return s.FailPayment(ctx, payment.PaymentID)
+	default:
+		log.Printf("Ignoring provider event %s of type %s", event.EventID, event.Type)
+		return nil
+	}
+}
+
+// refundLateCapture gives back money the customer paid after their payment
+// was voided or refunded, e.g. in a checkout left open while the order was
+// cancelled. The payment keeps its status.
+func (s *Service) refundLateCapture(ctx context.Context, record *domain.WebhookRecord, payment *domain.Payment, event *domain.ProviderEvent) error {
+	refundRef, err := s.provider.RefundCapture(ctx, payment, event.ProviderRef, payment.Amount)
+	if err != nil {
+		// Failing the webhook makes the provider redeliver it, retrying the refund
+		return fmt.Errorf("failed to refund late capture of %s payment %s: %w", payment.Status, payment.PaymentID, err)
+	}
+	if err := s.webhookRepo.RecordRefund(ctx, record.ID, refundRef); err != nil {
+		log.Printf("Refunded late capture %s but failed to record it: %v", refundRef, err)
+	}
+
+	log.Printf("Refunded late capture of %s payment %s: %s", payment.Status, payment.PaymentID, refundRef)
+	return nil
+}
+
+func (s *Service) GetWebhookHistory(ctx context.Context, paymentID string) ([]*domain.WebhookRecord, error) {
+	records, err := s.webhookRepo.ListByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook history: %w", err)
+	}
+	return records, nil
+}
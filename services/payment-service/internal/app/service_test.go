@@ -0,0 +1,179 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/services/payment-service/internal/ports/inbound"
+	"github.com/wnmay/horo/shared/money"
+)
+
+func TestServiceRefundPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      domain.PaymentStatus
+		settled     bool
+		wantStatus  domain.PaymentStatus
+		wantEvents  string
+		wantBalance string
+	}{
+		{name: "pending payment is voided", status: domain.PaymentStatusPending, wantStatus: domain.PaymentStatusFailed, wantEvents: "[failed]", wantBalance: "0"},
+		{name: "captured payment is refunded", status: domain.PaymentStatusCompleted, wantStatus: domain.PaymentStatusRefunded, wantEvents: "[refunded reversed=false]", wantBalance: "0"},
+		{name: "settled payment is refunded and reversed", settled: true, wantStatus: domain.PaymentStatusRefunded, wantEvents: "[refunded reversed=true]", wantBalance: "0"},
+		{name: "failed payment stays failed", status: domain.PaymentStatusFailed, wantStatus: domain.PaymentStatusFailed, wantEvents: "[]", wantBalance: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := paymentIn(tt.status, "500")
+			f := newPaymentFixture(payment)
+			if tt.settled {
+				f.settle(payment, "prophet-1")
+			}
+
+			if err := f.service.RefundPayment(context.Background(), payment.OrderID); err != nil {
+				t.Fatalf("RefundPayment() error = %v", err)
+			}
+
+			saved := f.payments.saved(payment.PaymentID)
+			if saved.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", saved.Status, tt.wantStatus)
+			}
+			if got := fmt.Sprint(f.publisher.events); got != tt.wantEvents {
+				t.Errorf("published %s, want %s", got, tt.wantEvents)
+			}
+			balance, _ := f.ledger.AccountBalance(context.Background(), domain.ProphetAccount("prophet-1"), "THB")
+			if !balance.Equal(money.MustParse(tt.wantBalance)) {
+				t.Errorf("prophet balance = %s, want %s", balance, tt.wantBalance)
+			}
+			if tt.wantStatus == domain.PaymentStatusRefunded && !saved.RefundedAmount.Equal(payment.Amount) {
+				t.Errorf("RefundedAmount = %s, want %s", saved.RefundedAmount, payment.Amount)
+			}
+		})
+	}
+}
+
+func TestServiceRefundPaymentRedelivered(t *testing.T) {
+	payment := paymentIn(domain.PaymentStatusCompleted, "500")
+	f := newPaymentFixture(payment)
+	f.settle(payment, "prophet-1")
+
+	for i := 0; i < 2; i++ {
+		if err := f.service.RefundPayment(context.Background(), payment.OrderID); err != nil {
+			t.Fatalf("RefundPayment() attempt %d error = %v", i+1, err)
+		}
+	}
+
+	want := fmt.Sprintf("[SETTLEMENT settlement:%[1]s REFUND_REVERSAL refund_reversal:%[1]s]", payment.PaymentID)
+	if got := f.ledger.kinds(); got != want {
+		t.Errorf("ledger = %s, want %s", got, want)
+	}
+	if got := fmt.Sprint(f.publisher.events); got != "[refunded reversed=true]" {
+		t.Errorf("published %s, want one refund", got)
+	}
+}
+
+func TestServiceRefundPaymentBeforeCreation(t *testing.T) {
+	f := newPaymentFixture()
+
+	// The order is cancelled before payment-service sees it created
+	if err := f.service.RefundPayment(context.Background(), "order-1"); err != nil {
+		t.Fatalf("RefundPayment() error = %v", err)
+	}
+	payment, err := f.service.CreatePaymentFromOrder(context.Background(), inbound.CreatePaymentCommand{OrderID: "order-1", Amount: money.MustParse("500"), Currency: "THB"})
+	if err != nil {
+		t.Fatalf("CreatePaymentFromOrder() error = %v", err)
+	}
+
+	if payment.Status != domain.PaymentStatusFailed {
+		t.Errorf("payment created after cancellation is %s, want %s", payment.Status, domain.PaymentStatusFailed)
+	}
+	if !payment.Amount.Equal(money.MustParse("500")) || payment.Currency != "THB" {
+		t.Errorf("voided payment records %s %s, want 500 THB", payment.Amount, payment.Currency)
+	}
+	if len(f.payments.payments) != 1 {
+		t.Errorf("order has %d payments, want 1", len(f.payments.payments))
+	}
+}
+
+func TestServiceRefundPaymentAtProvider(t *testing.T) {
+	tenPercent := (&domain.FeeRule{Percentage: money.NewFromInt(10)}).Apply(money.MustParse("1000"), "THB")
+
+	tests := []struct {
+		name        string
+		payment     func() *domain.Payment
+		refundErr   error
+		wantRefunds string
+		wantRef     string
+		wantErr     bool
+	}{
+		{
+			name: "captured by the provider",
+			payment: func() *domain.Payment {
+				payment := paymentIn(domain.PaymentStatusCompleted, "1000")
+				payment.ProviderRef = "cs_1"
+				return payment
+			},
+			wantRefunds: "[cs_1 1000]",
+			wantRef:     "re_cs_1",
+		},
+		{
+			name: "bundle refunds the sessions not settled",
+			payment: func() *domain.Payment {
+				payment := paymentIn(domain.PaymentStatusCompleted, "1000")
+				payment.ProviderRef = "cs_1"
+				if _, err := payment.SettleSession("prophet-1", "", 1, 4, tenPercent); err != nil {
+					t.Fatal(err)
+				}
+				return payment
+			},
+			wantRefunds: "[cs_1 750]",
+			wantRef:     "re_cs_1",
+		},
+		{
+			name:        "completed without the provider",
+			payment:     func() *domain.Payment { return paymentIn(domain.PaymentStatusCompleted, "1000") },
+			wantRefunds: "[]",
+		},
+		{
+			name: "provider refund fails",
+			payment: func() *domain.Payment {
+				payment := paymentIn(domain.PaymentStatusCompleted, "1000")
+				payment.ProviderRef = "cs_1"
+				return payment
+			},
+			refundErr:   errors.New("provider unavailable"),
+			wantRefunds: "[]",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := tt.payment()
+			f := newPaymentFixture(payment)
+			f.provider.refundErr = tt.refundErr
+
+			err := f.service.RefundPayment(context.Background(), payment.OrderID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RefundPayment() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := fmt.Sprint(f.provider.refunds); got != tt.wantRefunds {
+				t.Errorf("provider refunds = %s, want %s", got, tt.wantRefunds)
+			}
+
+			saved := f.payments.saved(payment.PaymentID)
+			if tt.wantErr {
+				// The redelivery retries a payment left as it was
+				if saved.Status != domain.PaymentStatusCompleted || len(f.publisher.events) != 0 {
+					t.Errorf("failed refund left the payment %s and published %v", saved.Status, f.publisher.events)
+				}
+				return
+			}
+			if saved.Status != domain.PaymentStatusRefunded || saved.RefundRef != tt.wantRef {
+				t.Errorf("payment is %s with refund ref %q, want REFUNDED with %q", saved.Status, saved.RefundRef, tt.wantRef)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPaymentStatus = errors.New("status must be PENDING, COMPLETED, SETTLED, FAILED or REFUNDED")
+	ErrAuditReasonRequired  = errors.New("a reason is required")
+)
+
+// ParsePaymentStatus checks status is one a payment can have, ignoring case
+func ParsePaymentStatus(status string) (PaymentStatus, error) {
+	switch s := PaymentStatus(strings.ToUpper(status)); s {
+	case PaymentStatusPending, PaymentStatusCompleted, PaymentStatusSettled, PaymentStatusFailed, PaymentStatusRefunded:
+		return s, nil
+	}
+	return "", ErrInvalidPaymentStatus
+}
+
+// PaymentAudit records an admin forcing a payment from one status to
+// another, and why
+type PaymentAudit struct {
+	ID         string        `json:"id"`
+	PaymentID  string        `json:"payment_id"`
+	AdminID    string        `json:"admin_id"`
+	FromStatus PaymentStatus `json:"from_status"`
+	ToStatus   PaymentStatus `json:"to_status"`
+	Reason     string        `json:"reason"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
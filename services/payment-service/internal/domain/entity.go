@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type PaymentStatus string
@@ -14,35 +15,80 @@ const (
 	PaymentStatusCompleted PaymentStatus = "COMPLETED"
 	PaymentStatusSettled PaymentStatus = "SETTLED"
 	PaymentStatusFailed    PaymentStatus = "FAILED"
+	PaymentStatusRefunded  PaymentStatus = "REFUNDED"
 )
 
 type Payment struct {
 	PaymentID  string        `json:"payment_id"`
 	OrderID    string        `json:"order_id"`
-	Amount     float64       `json:"amount"`
+	Amount     money.Decimal `json:"amount"`
+	// Currency is the ISO 4217 code every amount on the payment is in
+	Currency   string        `json:"currency"`
 	Status     PaymentStatus `json:"status"`
 	CreatedAt  time.Time     `json:"created_at"`
 	UpdatedAt  time.Time     `json:"updated_at"`
 	ProphetID  string        `json:"prophet_id"`
+	// Provider and ProviderRef identify the checkout session at the external
+	// payment provider that will capture this payment
+	Provider    string `json:"provider,omitempty"`
+	ProviderRef string `json:"provider_ref,omitempty"`
+	// Set on settlement: the platform fee taken from the gross amount under
+	// FeeRuleID and the net credited to the prophet
+	CourseType  string        `json:"course_type,omitempty"`
+	GrossAmount money.Decimal `json:"gross_amount"`
+	FeeAmount   money.Decimal `json:"fee_amount"`
+	NetAmount   money.Decimal `json:"net_amount"`
+	FeeRuleID   string        `json:"fee_rule_id,omitempty"`
+	// Bundle payments settle one share per session as each completes. The
+	// amounts above then add up the sessions settled so far, and the payment
+	// stays COMPLETED until the last one.
+	Sessions        int `json:"sessions"`
+	SessionsSettled int `json:"sessions_settled"`
+	// RefundedAmount is what went back to the customer: the whole amount, or
+	// the unsettled sessions' share when a bundle is cancelled part way
+	RefundedAmount money.Decimal `json:"refunded_amount"`
+	// RefundRef is the provider's reference for the refund, if the payment
+	// was captured by the provider
+	RefundRef string `json:"refund_ref,omitempty"`
+}
+
+// SessionSettlement is the share of a bundle payment settled for one session
+type SessionSettlement struct {
+	Session int
+	Gross   money.Decimal
+	Fee     money.Decimal
 }
 
 
 var (
 	ErrInvalidTransition = errors.New("invalid payment status transition")
+	ErrPaymentNotFound   = errors.New("payment not found")
+	// ErrPaymentExists is returned when creating a second payment for an order
+	ErrPaymentExists = errors.New("order already has a payment")
 )
 
-func NewPayment(orderID string, amount float64) *Payment {
+func NewPayment(orderID string, amount money.Decimal, currency string) *Payment {
 	now := time.Now()
 	return &Payment{
 		PaymentID: uuid.New().String(),
 		OrderID:   orderID,
 		Amount:    amount,
+		Currency:  currency,
 		Status:    PaymentStatusPending,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
 
+// NewVoidedPayment stands in for the payment of an order cancelled before
+// its payment was created. It is already FAILED, so the payment the order's
+// creation asks for later is never payable; its amount is filled in then.
+func NewVoidedPayment(orderID string) *Payment {
+	payment := NewPayment(orderID, money.Zero, money.DefaultCurrency)
+	payment.Status = PaymentStatusFailed
+	return payment
+}
+
 func (p *Payment) Complete() error {
 	if p.Status == PaymentStatusCompleted {
 		return nil
@@ -55,7 +101,9 @@ func (p *Payment) Complete() error {
 	return nil
 }
 
-func (p *Payment) Settle(prophetID string) error {
+// Settle attributes a captured payment to the prophet and records the fee
+// split. A payment that is already settled keeps its original split.
+func (p *Payment) Settle(prophetID, courseType string, fee FeeBreakdown) error {
 	if p.Status == PaymentStatusSettled {
 		return nil
 	}
@@ -65,11 +113,129 @@ func (p *Payment) Settle(prophetID string) error {
 	p.Status = PaymentStatusSettled
 	p.UpdatedAt = time.Now()
 	p.ProphetID = prophetID
+	p.CourseType = courseType
+	p.GrossAmount = fee.Gross
+	p.FeeAmount = fee.Fee
+	p.NetAmount = fee.Net
+	p.FeeRuleID = fee.FeeRuleID
 	return nil
 }
 
+// SettleSession settles session out of sessions of a bundle payment. fee is
+// quoted on the whole amount; each session takes an even share of the
+// amount and of the fee. Sessions settle in order, and settling one again
+// returns nil without changes.
+func (p *Payment) SettleSession(prophetID, courseType string, session, sessions int, fee FeeBreakdown) (*SessionSettlement, error) {
+	if session < 1 || session > sessions {
+		return nil, ErrInvalidTransition
+	}
+	if session <= p.SessionsSettled {
+		return nil, nil
+	}
+	if p.Status != PaymentStatusCompleted || session != p.SessionsSettled+1 {
+		return nil, ErrInvalidTransition
+	}
+
+	settlement := &SessionSettlement{
+		Session: session,
+		Gross:   sessionShare(p.Amount, session, sessions, p.Currency),
+		Fee:     sessionShare(fee.Fee, session, sessions, p.Currency),
+	}
+	p.UpdatedAt = time.Now()
+	p.ProphetID = prophetID
+	p.CourseType = courseType
+	p.Sessions = sessions
+	p.SessionsSettled = session
+	p.GrossAmount = p.GrossAmount.Add(settlement.Gross)
+	p.FeeAmount = p.FeeAmount.Add(settlement.Fee)
+	p.NetAmount = p.GrossAmount.Sub(p.FeeAmount)
+	p.FeeRuleID = fee.FeeRuleID
+	if session == sessions {
+		p.Status = PaymentStatusSettled
+	}
+	return settlement, nil
+}
+
+// sessionShare is session's part of amount split evenly over sessions,
+// taken as the difference of rounded running totals so the shares always
+// add up to amount
+func sessionShare(amount money.Decimal, session, sessions int, currency string) money.Decimal {
+	total := func(n int) money.Decimal {
+		return money.RoundTo(amount.Mul(money.NewFromInt(int64(n))).Div(money.NewFromInt(int64(sessions))), currency)
+	}
+	return total(session).Sub(total(session - 1))
+}
+
+// IsBundle reports whether the payment is settled per session
+func (p *Payment) IsBundle() bool {
+	return p.Sessions > 1
+}
+
+// PartiallySettled reports whether some, but not all, sessions of a bundle
+// have been settled
+func (p *Payment) PartiallySettled() bool {
+	return p.IsBundle() && p.SessionsSettled > 0 && p.SessionsSettled < p.Sessions
+}
+
+// SettlementReferences lists the ledger references of the payment's
+// settlements
+func (p *Payment) SettlementReferences() []string {
+	if !p.IsBundle() {
+		return []string{SettlementReference(p.PaymentID)}
+	}
+	references := make([]string, 0, p.SessionsSettled)
+	for session := 1; session <= p.SessionsSettled; session++ {
+		references = append(references, SessionSettlementReference(p.PaymentID, session))
+	}
+	return references
+}
+
 func (p *Payment) Fail() {
 	p.Status = PaymentStatusFailed
 	p.UpdatedAt = time.Now()
 }
 
+// RefundAmount is what refunding the payment returns to the customer. A
+// bundle cancelled part way only refunds the sessions not settled yet; the
+// prophet keeps what was delivered.
+func (p *Payment) RefundAmount() (money.Decimal, error) {
+	switch p.Status {
+	case PaymentStatusCompleted:
+		return p.Amount.Sub(p.GrossAmount), nil
+	case PaymentStatusSettled:
+		return p.Amount, nil
+	default:
+		return money.Zero, ErrInvalidTransition
+	}
+}
+
+// Refund records that RefundAmount went back to the customer under the
+// provider's refundRef. Refunding a settled payment reverses the prophet's
+// settlement, which the caller is told about through settlementReversed so it
+// can compensate downstream.
+func (p *Payment) Refund(refundRef string) (settlementReversed bool, err error) {
+	if p.Status == PaymentStatusRefunded {
+		return false, nil
+	}
+	amount, err := p.RefundAmount()
+	if err != nil {
+		return false, err
+	}
+	settlementReversed = p.Status == PaymentStatusSettled
+	p.RefundedAmount = amount
+	p.RefundRef = refundRef
+	p.Status = PaymentStatusRefunded
+	p.UpdatedAt = time.Now()
+	return settlementReversed, nil
+}
+
+// AttachCheckoutSession links the payment to a provider checkout session
+func (p *Payment) AttachCheckoutSession(session *CheckoutSession) error {
+	if p.Status != PaymentStatusPending {
+		return ErrInvalidTransition
+	}
+	p.Provider = session.Provider
+	p.ProviderRef = session.ProviderRef
+	p.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+// bundlePayment is a captured payment for a bundle with its first settled
+// sessions settled under a 10% fee
+func bundlePayment(amount string, sessions, settled int) *Payment {
+	payment := NewPayment("order-1", money.MustParse(amount), "THB")
+	payment.Status = PaymentStatusCompleted
+	fee := (&FeeRule{Percentage: money.NewFromInt(10)}).Apply(payment.Amount, payment.Currency)
+	for session := 1; session <= settled; session++ {
+		if _, err := payment.SettleSession("prophet-1", "", session, sessions, fee); err != nil {
+			panic(err)
+		}
+	}
+	return payment
+}
+
+func TestPaymentRefundAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment *Payment
+		want    string
+		wantErr error
+	}{
+		{name: "captured", payment: bundlePayment("500", 1, 0), want: "500"},
+		{name: "settled", payment: &Payment{Amount: money.MustParse("500"), Status: PaymentStatusSettled, GrossAmount: money.MustParse("500")}, want: "500"},
+		{name: "bundle with one of three sessions settled", payment: bundlePayment("1000", 3, 1), want: "666.67"},
+		{name: "bundle with two of three sessions settled", payment: bundlePayment("1000", 3, 2), want: "333.33"},
+		{name: "pending", payment: &Payment{Amount: money.MustParse("500"), Status: PaymentStatusPending}, wantErr: ErrInvalidTransition},
+		{name: "refunded", payment: &Payment{Amount: money.MustParse("500"), Status: PaymentStatusRefunded}, wantErr: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.payment.RefundAmount()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefundAmount() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(money.MustParse(tt.want)) {
+				t.Errorf("RefundAmount() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentRefund(t *testing.T) {
+	tests := []struct {
+		name         string
+		payment      *Payment
+		wantReversed bool
+		wantRefunded string
+		wantErr      error
+	}{
+		{name: "captured", payment: bundlePayment("500", 1, 0), wantRefunded: "500"},
+		{name: "settled", payment: &Payment{Amount: money.MustParse("500"), Status: PaymentStatusSettled}, wantReversed: true, wantRefunded: "500"},
+		{name: "bundle part way", payment: bundlePayment("1000", 4, 1), wantRefunded: "750"},
+		{name: "failed", payment: &Payment{Amount: money.MustParse("500"), Status: PaymentStatusFailed}, wantErr: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reversed, err := tt.payment.Refund("re_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refund() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if tt.payment.Status == PaymentStatusRefunded || tt.payment.RefundRef != "" {
+					t.Errorf("failed Refund() changed the payment to %s, ref %q", tt.payment.Status, tt.payment.RefundRef)
+				}
+				return
+			}
+			if reversed != tt.wantReversed {
+				t.Errorf("Refund() settlementReversed = %v, want %v", reversed, tt.wantReversed)
+			}
+			if tt.payment.Status != PaymentStatusRefunded || tt.payment.RefundRef != "re_1" {
+				t.Errorf("payment is %s with ref %q, want REFUNDED with re_1", tt.payment.Status, tt.payment.RefundRef)
+			}
+			if !tt.payment.RefundedAmount.Equal(money.MustParse(tt.wantRefunded)) {
+				t.Errorf("RefundedAmount = %s, want %s", tt.payment.RefundedAmount, tt.wantRefunded)
+			}
+
+			// Refunding again keeps the first refund
+			if reversed, err := tt.payment.Refund("re_2"); err != nil || reversed || tt.payment.RefundRef != "re_1" {
+				t.Errorf("second Refund() = %v, %v, ref %q", reversed, err, tt.payment.RefundRef)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// DefaultFeeRuleID identifies the configured fallback rule, which applies when
+// no stored rule matches a settlement
+const DefaultFeeRuleID = "default"
+
+var (
+	ErrFeeRuleNotFound = errors.New("fee rule not found")
+	ErrInvalidFeeRule  = errors.New("invalid fee rule")
+)
+
+// FeeRule is a platform commission of Percentage of the gross plus FlatFee.
+// A rule is scoped to a prophet, a course type and a currency, any of which
+// may be left open, and applies to settlements in [EffectiveFrom, EffectiveTo).
+// FlatFee is in Currency and is only charged on settlements in that currency.
+type FeeRule struct {
+	ID            string        `json:"id"`
+	ProphetID     string        `json:"prophet_id,omitempty"`
+	CourseType    string        `json:"course_type,omitempty"`
+	Currency      string        `json:"currency,omitempty"`
+	Percentage    money.Decimal `json:"percentage"`
+	FlatFee       money.Decimal `json:"flat_fee"`
+	EffectiveFrom time.Time     `json:"effective_from"`
+	EffectiveTo   *time.Time    `json:"effective_to,omitempty"`
+	CreatedBy     string        `json:"created_by,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+func NewFeeRule(prophetID, courseType, currency string, percentage, flatFee money.Decimal, effectiveFrom time.Time, effectiveTo *time.Time, createdBy string) (*FeeRule, error) {
+	if percentage.IsNegative() || percentage.GreaterThan(money.NewFromInt(100)) {
+		return nil, fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidFeeRule)
+	}
+	if flatFee.IsNegative() {
+		return nil, fmt.Errorf("%w: flat fee must not be negative", ErrInvalidFeeRule)
+	}
+	if currency != "" {
+		normalized, err := money.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFeeRule, err)
+		}
+		currency = normalized
+	}
+	if flatFee.IsPositive() && currency == "" {
+		return nil, fmt.Errorf("%w: a flat fee needs a currency", ErrInvalidFeeRule)
+	}
+	if effectiveTo != nil && !effectiveTo.After(effectiveFrom) {
+		return nil, fmt.Errorf("%w: effective_to must be after effective_from", ErrInvalidFeeRule)
+	}
+	return &FeeRule{
+		ID:            uuid.New().String(),
+		ProphetID:     prophetID,
+		CourseType:    courseType,
+		Currency:      currency,
+		Percentage:    percentage,
+		FlatFee:       money.RoundTo(flatFee, currency),
+		EffectiveFrom: effectiveFrom,
+		EffectiveTo:   effectiveTo,
+		CreatedBy:     createdBy,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// EffectiveAt reports whether the rule is in force at t
+func (r *FeeRule) EffectiveAt(t time.Time) bool {
+	return !t.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || t.Before(*r.EffectiveTo))
+}
+
+// Matches reports whether the rule's scope covers the prophet, course type
+// and currency
+func (r *FeeRule) Matches(prophetID, courseType, currency string) bool {
+	return (r.ProphetID == "" || r.ProphetID == prophetID) &&
+		(r.CourseType == "" || r.CourseType == courseType) &&
+		(r.Currency == "" || r.Currency == currency)
+}
+
+// End stops the rule from applying to settlements from at onwards
+func (r *FeeRule) End(at time.Time) error {
+	if !at.After(r.EffectiveFrom) {
+		return fmt.Errorf("%w: a rule can only end after it takes effect", ErrInvalidFeeRule)
+	}
+	if r.EffectiveTo != nil && !at.Before(*r.EffectiveTo) {
+		return nil
+	}
+	r.EffectiveTo = &at
+	return nil
+}
+
+// specificity ranks scoped rules above broader ones: a prophet override wins
+// over a course type rule, which wins over a currency rule, which wins over a
+// platform-wide rule
+func (r *FeeRule) specificity() int {
+	rank := 0
+	if r.ProphetID != "" {
+		rank += 4
+	}
+	if r.CourseType != "" {
+		rank += 2
+	}
+	if r.Currency != "" {
+		rank++
+	}
+	return rank
+}
+
+// SelectFeeRule picks the most specific rule in force at t for the prophet,
+// course type and currency. Among equally specific rules the one that took
+// effect last wins. It returns nil when no rule applies.
+func SelectFeeRule(rules []*FeeRule, prophetID, courseType, currency string, t time.Time) *FeeRule {
+	var selected *FeeRule
+	for _, rule := range rules {
+		if !rule.EffectiveAt(t) || !rule.Matches(prophetID, courseType, currency) {
+			continue
+		}
+		if selected == nil ||
+			rule.specificity() > selected.specificity() ||
+			(rule.specificity() == selected.specificity() && rule.EffectiveFrom.After(selected.EffectiveFrom)) {
+			selected = rule
+		}
+	}
+	return selected
+}
+
+// FeeBreakdown splits a gross amount into the platform fee and the prophet's net
+type FeeBreakdown struct {
+	Gross     money.Decimal `json:"gross"`
+	Fee       money.Decimal `json:"fee"`
+	Net       money.Decimal `json:"net"`
+	Currency  string        `json:"currency"`
+	FeeRuleID string        `json:"fee_rule_id,omitempty"`
+}
+
+// Apply computes the rule's fee on gross, rounded to the currency's minor
+// unit. The flat fee is only added when gross is in the rule's currency, and
+// the fee never exceeds the gross amount. A nil rule charges no fee.
+func (r *FeeRule) Apply(gross money.Decimal, currency string) FeeBreakdown {
+	if r == nil {
+		return FeeBreakdown{Gross: gross, Net: gross, Currency: currency}
+	}
+	fee := gross.Mul(r.Percentage).Div(money.NewFromInt(100))
+	if r.Currency == currency {
+		fee = fee.Add(r.FlatFee)
+	}
+	fee = money.Min(money.Max(money.RoundTo(fee, currency), money.Zero), gross)
+	return FeeBreakdown{
+		Gross:     gross,
+		Fee:       fee,
+		Net:       gross.Sub(fee),
+		Currency:  currency,
+		FeeRuleID: r.ID,
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+func TestNewFeeRule(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := from.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		currency   string
+		percentage string
+		flatFee    string
+		to         *time.Time
+		wantErr    error
+	}{
+		{name: "percentage only", percentage: "15", flatFee: "0"},
+		{name: "flat fee in a currency", currency: "thb", percentage: "0", flatFee: "20"},
+		{name: "whole gross", percentage: "100", flatFee: "0"},
+		{name: "negative percentage", percentage: "-1", flatFee: "0", wantErr: ErrInvalidFeeRule},
+		{name: "percentage above 100", percentage: "100.01", flatFee: "0", wantErr: ErrInvalidFeeRule},
+		{name: "negative flat fee", currency: "THB", percentage: "10", flatFee: "-5", wantErr: ErrInvalidFeeRule},
+		{name: "flat fee without a currency", percentage: "10", flatFee: "5", wantErr: ErrInvalidFeeRule},
+		{name: "malformed currency", currency: "BAHT", percentage: "10", flatFee: "0", wantErr: ErrInvalidFeeRule},
+		{name: "ends before it starts", percentage: "10", flatFee: "0", to: &before, wantErr: ErrInvalidFeeRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewFeeRule("", "", tt.currency, money.MustParse(tt.percentage), money.MustParse(tt.flatFee), from, tt.to, "admin-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewFeeRule() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && tt.currency != "" && rule.Currency != "THB" {
+				t.Errorf("Currency = %q, want it normalized", rule.Currency)
+			}
+		})
+	}
+}
+
+func TestSelectFeeRule(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	ended := now.Add(-time.Hour)
+	rule := func(id, prophetID, courseType, currency string, from time.Time, to *time.Time) *FeeRule {
+		return &FeeRule{ID: id, ProphetID: prophetID, CourseType: courseType, Currency: currency, EffectiveFrom: from, EffectiveTo: to}
+	}
+	platform := rule("platform", "", "", "", now.AddDate(-1, 0, 0), nil)
+	newerPlatform := rule("newer-platform", "", "", "", now.AddDate(0, -1, 0), nil)
+	thb := rule("thb", "", "", "THB", now.AddDate(-1, 0, 0), nil)
+	tarot := rule("tarot", "", "tarot", "", now.AddDate(-1, 0, 0), nil)
+	prophet := rule("prophet", "prophet-1", "", "", now.AddDate(-1, 0, 0), nil)
+	endedProphet := rule("ended-prophet", "prophet-2", "", "", now.AddDate(-1, 0, 0), &ended)
+	future := rule("future", "prophet-3", "", "", now.Add(time.Hour), nil)
+	all := []*FeeRule{platform, newerPlatform, thb, tarot, prophet, endedProphet, future}
+
+	tests := []struct {
+		name       string
+		rules      []*FeeRule
+		prophetID  string
+		courseType string
+		currency   string
+		want       string
+	}{
+		{name: "prophet override wins", rules: all, prophetID: "prophet-1", courseType: "tarot", currency: "THB", want: "prophet"},
+		{name: "course type beats currency", rules: all, prophetID: "prophet-9", courseType: "tarot", currency: "THB", want: "tarot"},
+		{name: "currency beats platform", rules: all, prophetID: "prophet-9", courseType: "astrology", currency: "THB", want: "thb"},
+		{name: "latest platform rule", rules: all, prophetID: "prophet-9", courseType: "astrology", currency: "USD", want: "newer-platform"},
+		{name: "ended override no longer applies", rules: all, prophetID: "prophet-2", currency: "USD", want: "newer-platform"},
+		{name: "future override does not apply yet", rules: all, prophetID: "prophet-3", currency: "USD", want: "newer-platform"},
+		{name: "nothing matches", rules: []*FeeRule{prophet, thb}, prophetID: "prophet-9", currency: "USD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectFeeRule(tt.rules, tt.prophetID, tt.courseType, tt.currency, now)
+			gotID := ""
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.want {
+				t.Errorf("SelectFeeRule() = %q, want %q", gotID, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeeRuleApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     *FeeRule
+		gross    string
+		currency string
+		wantFee  string
+	}{
+		{name: "no rule", gross: "500", currency: "THB", wantFee: "0"},
+		{name: "percentage", rule: &FeeRule{Percentage: money.MustParse("15")}, gross: "500", currency: "THB", wantFee: "75"},
+		{name: "rounded to the minor unit", rule: &FeeRule{Percentage: money.MustParse("12.5")}, gross: "10.01", currency: "USD", wantFee: "1.25"},
+		{name: "flat fee in its currency", rule: &FeeRule{Percentage: money.MustParse("10"), FlatFee: money.MustParse("20"), Currency: "THB"}, gross: "500", currency: "THB", wantFee: "70"},
+		{name: "flat fee skipped in other currencies", rule: &FeeRule{Percentage: money.MustParse("10"), FlatFee: money.MustParse("20"), Currency: "THB"}, gross: "50", currency: "USD", wantFee: "5"},
+		{name: "capped at the gross", rule: &FeeRule{FlatFee: money.MustParse("20"), Currency: "THB"}, gross: "15", currency: "THB", wantFee: "15"},
+		{name: "yen has no minor unit", rule: &FeeRule{Percentage: money.MustParse("15")}, gross: "999", currency: "JPY", wantFee: "150"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Apply(money.MustParse(tt.gross), tt.currency)
+			if !got.Fee.Equal(money.MustParse(tt.wantFee)) {
+				t.Errorf("Fee = %s, want %s", got.Fee, tt.wantFee)
+			}
+			if !got.Net.Add(got.Fee).Equal(got.Gross) {
+				t.Errorf("Net %s and Fee %s do not add up to Gross %s", got.Net, got.Fee, got.Gross)
+			}
+		})
+	}
+}
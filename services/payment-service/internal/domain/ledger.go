@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// Ledger accounts. Every transaction debits and credits these accounts by the
+// same total, so money is never created or lost between them. Accounts hold a
+// separate balance per currency; a transaction never mixes currencies.
+const (
+	// AccountPlatformClearing holds customer money captured by the provider
+	// until it is settled to a prophet or refunded
+	AccountPlatformClearing = "platform:clearing"
+	// AccountPlatformRevenue collects the platform commission
+	AccountPlatformRevenue = "platform:revenue"
+	// AccountPlatformPayouts receives money paid out to prophets' banks
+	AccountPlatformPayouts = "platform:payouts"
+)
+
+// ProphetAccount is the account the platform owes a prophet from. Its credit
+// balance is what the prophet has earned and not yet withdrawn.
+func ProphetAccount(prophetID string) string {
+	return "prophet:" + prophetID
+}
+
+type LedgerTransactionKind string
+
+const (
+	LedgerKindSettlement     LedgerTransactionKind = "SETTLEMENT"
+	LedgerKindWithdrawal     LedgerTransactionKind = "WITHDRAWAL"
+	LedgerKindRefundReversal LedgerTransactionKind = "REFUND_REVERSAL"
+)
+
+var (
+	ErrUnbalancedTransaction     = errors.New("ledger transaction does not balance")
+	ErrLedgerTransactionNotFound = errors.New("ledger transaction not found")
+	ErrInvalidStatementRange     = errors.New("statement range must be positive and at most a year")
+)
+
+// LedgerEntry moves money in or out of one account. Exactly one of Debit and
+// Credit is set.
+type LedgerEntry struct {
+	ID            string        `json:"id"`
+	TransactionID string        `json:"transaction_id"`
+	Account       string        `json:"account"`
+	Currency      string        `json:"currency"`
+	Debit         money.Decimal `json:"debit"`
+	Credit        money.Decimal `json:"credit"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// LedgerTransaction groups the entries of one business event. Reference is
+// unique, which makes posting the same event twice a no-op.
+type LedgerTransaction struct {
+	ID          string                `json:"id"`
+	Kind        LedgerTransactionKind `json:"kind"`
+	Reference   string                `json:"reference"`
+	ProphetID   string                `json:"prophet_id"`
+	PaymentID   string                `json:"payment_id,omitempty"`
+	PayoutID    string                `json:"payout_id,omitempty"`
+	Description string                `json:"description"`
+	Currency    string                `json:"currency"`
+	Entries     []LedgerEntry         `json:"entries"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// Validate checks the transaction has entries in its currency and that
+// debits equal credits
+func (t *LedgerTransaction) Validate() error {
+	if len(t.Entries) < 2 {
+		return fmt.Errorf("%w: at least two entries are required", ErrUnbalancedTransaction)
+	}
+	debits, credits := money.Zero, money.Zero
+	for _, entry := range t.Entries {
+		if entry.Debit.IsNegative() || entry.Credit.IsNegative() || entry.Debit.IsZero() == entry.Credit.IsZero() {
+			return fmt.Errorf("%w: entry on %s must either debit or credit a positive amount", ErrUnbalancedTransaction, entry.Account)
+		}
+		if entry.Currency != t.Currency {
+			return fmt.Errorf("%w: entry on %s is in %s, not %s", ErrUnbalancedTransaction, entry.Account, entry.Currency, t.Currency)
+		}
+		debits = debits.Add(entry.Debit)
+		credits = credits.Add(entry.Credit)
+	}
+	if !debits.Equal(credits) {
+		return fmt.Errorf("%w: debits %s, credits %s", ErrUnbalancedTransaction, debits, credits)
+	}
+	return nil
+}
+
+func newLedgerTransaction(kind LedgerTransactionKind, reference, prophetID, currency, description string) *LedgerTransaction {
+	return &LedgerTransaction{
+		ID:          uuid.New().String(),
+		Kind:        kind,
+		Reference:   reference,
+		ProphetID:   prophetID,
+		Description: description,
+		Currency:    currency,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (t *LedgerTransaction) debit(account string, amount money.Decimal) {
+	if amount.IsPositive() {
+		t.Entries = append(t.Entries, LedgerEntry{ID: uuid.New().String(), TransactionID: t.ID, Account: account, Currency: t.Currency, Debit: amount, CreatedAt: t.CreatedAt})
+	}
+}
+
+func (t *LedgerTransaction) credit(account string, amount money.Decimal) {
+	if amount.IsPositive() {
+		t.Entries = append(t.Entries, LedgerEntry{ID: uuid.New().String(), TransactionID: t.ID, Account: account, Currency: t.Currency, Credit: amount, CreatedAt: t.CreatedAt})
+	}
+}
+
+// SettlementReference is the ledger reference of a payment's settlement
+func SettlementReference(paymentID string) string {
+	return "settlement:" + paymentID
+}
+
+// SessionSettlementReference is the ledger reference of one session's
+// settlement on a bundle payment
+func SessionSettlementReference(paymentID string, session int) string {
+	return fmt.Sprintf("%s:%d", SettlementReference(paymentID), session)
+}
+
+// NewSettlementTransaction moves a captured payment out of clearing: the
+// platform fee to revenue and the rest to the prophet
+func NewSettlementTransaction(payment *Payment) *LedgerTransaction {
+	tx := newLedgerTransaction(LedgerKindSettlement, SettlementReference(payment.PaymentID), payment.ProphetID,
+		payment.Currency, fmt.Sprintf("Settlement of order %s", payment.OrderID))
+	tx.PaymentID = payment.PaymentID
+	// Dated when the payment settled, which keeps backfilled settlements in
+	// the right statement period
+	tx.CreatedAt = payment.UpdatedAt
+	tx.debit(AccountPlatformClearing, payment.Amount)
+	tx.credit(ProphetAccount(payment.ProphetID), payment.Amount.Sub(payment.FeeAmount))
+	tx.credit(AccountPlatformRevenue, payment.FeeAmount)
+	return tx
+}
+
+// NewSessionSettlementTransaction moves one session's share of a bundle
+// payment out of clearing
+func NewSessionSettlementTransaction(payment *Payment, settlement *SessionSettlement) *LedgerTransaction {
+	tx := newLedgerTransaction(LedgerKindSettlement, SessionSettlementReference(payment.PaymentID, settlement.Session), payment.ProphetID,
+		payment.Currency, fmt.Sprintf("Settlement of session %d/%d of order %s", settlement.Session, payment.Sessions, payment.OrderID))
+	tx.PaymentID = payment.PaymentID
+	tx.CreatedAt = payment.UpdatedAt
+	tx.debit(AccountPlatformClearing, settlement.Gross)
+	tx.credit(ProphetAccount(payment.ProphetID), settlement.Gross.Sub(settlement.Fee))
+	tx.credit(AccountPlatformRevenue, settlement.Fee)
+	return tx
+}
+
+// NewRefundReversalTransaction undoes a settlement so the refunded money goes
+// back to clearing, taking it out of the prophet's balance
+func NewRefundReversalTransaction(settlement *LedgerTransaction) *LedgerTransaction {
+	reference := "refund_reversal:" + strings.TrimPrefix(settlement.Reference, SettlementReference(""))
+	tx := newLedgerTransaction(LedgerKindRefundReversal, reference, settlement.ProphetID,
+		settlement.Currency, "Refund reversal of "+settlement.Description)
+	tx.PaymentID = settlement.PaymentID
+	for _, entry := range settlement.Entries {
+		tx.debit(entry.Account, entry.Credit)
+		tx.credit(entry.Account, entry.Debit)
+	}
+	return tx
+}
+
+// NewWithdrawalTransaction pays a prophet's approved payout out of their balance
+func NewWithdrawalTransaction(payout *Payout) *LedgerTransaction {
+	tx := newLedgerTransaction(LedgerKindWithdrawal, "withdrawal:"+payout.ID, payout.ProphetID,
+		payout.Currency, "Payout "+payout.ID)
+	tx.PayoutID = payout.ID
+	tx.debit(ProphetAccount(payout.ProphetID), payout.Amount)
+	tx.credit(AccountPlatformPayouts, payout.Amount)
+	return tx
+}
+
+// SettledTotal sums the gross and platform fee of settled payments in one
+// currency
+type SettledTotal struct {
+	Gross money.Decimal
+	Fee   money.Decimal
+}
+
+// CurrencyBalance summarises a prophet's earnings in one currency. Balance is
+// the ledger balance and AvailableBalance excludes payouts still in progress;
+// the settled totals cover payments that are currently settled.
+type CurrencyBalance struct {
+	Currency         string        `json:"currency"`
+	Balance          money.Decimal `json:"balance"`
+	AvailableBalance money.Decimal `json:"available_balance"`
+	GrossSettled     money.Decimal `json:"gross_settled"`
+	PlatformFees     money.Decimal `json:"platform_fees"`
+	NetSettled       money.Decimal `json:"net_settled"`
+}
+
+// ProphetBalance lists a prophet's earnings per currency. Balances are never
+// converted, so each currency is paid out on its own.
+type ProphetBalance struct {
+	ProphetID string            `json:"prophet_id"`
+	Balances  []CurrencyBalance `json:"balances"`
+}
+
+// In returns the balance in currency, which is zero if the prophet has none
+func (b *ProphetBalance) In(currency string) CurrencyBalance {
+	for _, balance := range b.Balances {
+		if balance.Currency == currency {
+			return balance
+		}
+	}
+	return CurrencyBalance{Currency: currency}
+}
+
+// StatementLine is one movement on a prophet's account
+type StatementLine struct {
+	TransactionID string                `json:"transaction_id"`
+	Kind          LedgerTransactionKind `json:"kind"`
+	Reference     string                `json:"reference"`
+	PaymentID     string                `json:"payment_id,omitempty"`
+	PayoutID      string                `json:"payout_id,omitempty"`
+	Description   string                `json:"description"`
+	Debit         money.Decimal         `json:"debit"`
+	Credit        money.Decimal         `json:"credit"`
+	// Balance is the running balance after this line
+	Balance   money.Decimal `json:"balance"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// Statement lists a prophet's ledger movements in one currency in [From, To)
+type Statement struct {
+	ProphetID      string          `json:"prophet_id"`
+	Currency       string          `json:"currency"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance money.Decimal   `json:"opening_balance"`
+	ClosingBalance money.Decimal   `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+}
+
+// ApplyRunningBalance fills in each line's balance and the closing balance
+func (s *Statement) ApplyRunningBalance() {
+	balance := s.OpeningBalance
+	for i := range s.Lines {
+		balance = balance.Add(s.Lines[i].Credit).Sub(s.Lines[i].Debit)
+		s.Lines[i].Balance = balance
+	}
+	s.ClosingBalance = balance
+}
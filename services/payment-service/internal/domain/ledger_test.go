@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+// balances sums credits minus debits per account
+func balances(txs ...*LedgerTransaction) map[string]money.Decimal {
+	totals := map[string]money.Decimal{}
+	for _, tx := range txs {
+		for _, entry := range tx.Entries {
+			totals[entry.Account] = totals[entry.Account].Add(entry.Credit).Sub(entry.Debit)
+		}
+	}
+	return totals
+}
+
+func TestLedgerTransactions(t *testing.T) {
+	settled := &Payment{PaymentID: "payment-1", OrderID: "order-1", Amount: money.MustParse("500"), Currency: "THB", ProphetID: "prophet-1", FeeAmount: money.MustParse("75")}
+	feeFree := &Payment{PaymentID: "payment-2", OrderID: "order-2", Amount: money.MustParse("500"), Currency: "THB", ProphetID: "prophet-1"}
+	settlement := NewSettlementTransaction(settled)
+	payout := &Payout{ID: "payout-1", ProphetID: "prophet-1", Amount: money.MustParse("300"), Currency: "THB"}
+
+	tests := []struct {
+		name          string
+		tx            *LedgerTransaction
+		wantReference string
+		wantProphet   string
+		wantRevenue   string
+	}{
+		{name: "settlement", tx: settlement, wantReference: "settlement:payment-1", wantProphet: "425", wantRevenue: "75"},
+		{name: "settlement without a fee", tx: NewSettlementTransaction(feeFree), wantReference: "settlement:payment-2", wantProphet: "500", wantRevenue: "0"},
+		{name: "refund reversal", tx: NewRefundReversalTransaction(settlement), wantReference: "refund_reversal:payment-1", wantProphet: "-425", wantRevenue: "-75"},
+		{name: "withdrawal", tx: NewWithdrawalTransaction(payout), wantReference: "withdrawal:payout-1", wantProphet: "-300", wantRevenue: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tx.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if tt.tx.Reference != tt.wantReference {
+				t.Errorf("Reference = %q, want %q", tt.tx.Reference, tt.wantReference)
+			}
+			got := balances(tt.tx)
+			if !got[ProphetAccount("prophet-1")].Equal(money.MustParse(tt.wantProphet)) {
+				t.Errorf("prophet moves %s, want %s", got[ProphetAccount("prophet-1")], tt.wantProphet)
+			}
+			if !got[AccountPlatformRevenue].Equal(money.MustParse(tt.wantRevenue)) {
+				t.Errorf("revenue moves %s, want %s", got[AccountPlatformRevenue], tt.wantRevenue)
+			}
+		})
+	}
+
+	// A settlement and its reversal leave every account where it was
+	for account, balance := range balances(settlement, NewRefundReversalTransaction(settlement)) {
+		if !balance.IsZero() {
+			t.Errorf("%s holds %s after the reversal", account, balance)
+		}
+	}
+}
+
+func TestSessionSettlementTransactions(t *testing.T) {
+	payment := bundlePayment("1000", 3, 0)
+	fee := (&FeeRule{Percentage: money.NewFromInt(10)}).Apply(payment.Amount, payment.Currency)
+
+	var txs []*LedgerTransaction
+	for session := 1; session <= 3; session++ {
+		settlement, err := payment.SettleSession("prophet-1", "", session, 3, fee)
+		if err != nil {
+			t.Fatalf("SettleSession(%d) error = %v", session, err)
+		}
+		tx := NewSessionSettlementTransaction(payment, settlement)
+		if err := tx.Validate(); err != nil {
+			t.Fatalf("session %d: Validate() error = %v", session, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	got := balances(txs...)
+	if !got[ProphetAccount("prophet-1")].Equal(money.NewFromInt(900)) || !got[AccountPlatformRevenue].Equal(money.NewFromInt(100)) {
+		t.Errorf("sessions credit prophet %s and revenue %s, want 900 and 100", got[ProphetAccount("prophet-1")], got[AccountPlatformRevenue])
+	}
+	if !got[AccountPlatformClearing].Equal(money.NewFromInt(-1000)) {
+		t.Errorf("sessions take %s out of clearing, want 1000", got[AccountPlatformClearing].Neg())
+	}
+}
+
+func TestLedgerTransactionValidate(t *testing.T) {
+	entry := func(account, currency, debit, credit string) LedgerEntry {
+		return LedgerEntry{Account: account, Currency: currency, Debit: money.MustParse(debit), Credit: money.MustParse(credit)}
+	}
+
+	tests := []struct {
+		name    string
+		entries []LedgerEntry
+		wantErr error
+	}{
+		{name: "balanced", entries: []LedgerEntry{entry("a", "THB", "10", "0"), entry("b", "THB", "0", "10")}},
+		{name: "single entry", entries: []LedgerEntry{entry("a", "THB", "10", "0")}, wantErr: ErrUnbalancedTransaction},
+		{name: "debits exceed credits", entries: []LedgerEntry{entry("a", "THB", "10", "0"), entry("b", "THB", "0", "9")}, wantErr: ErrUnbalancedTransaction},
+		{name: "mixed currencies", entries: []LedgerEntry{entry("a", "THB", "10", "0"), entry("b", "USD", "0", "10")}, wantErr: ErrUnbalancedTransaction},
+		{name: "entry both debits and credits", entries: []LedgerEntry{entry("a", "THB", "10", "10"), entry("b", "THB", "0", "0")}, wantErr: ErrUnbalancedTransaction},
+		{name: "negative amount", entries: []LedgerEntry{entry("a", "THB", "-10", "0"), entry("b", "THB", "0", "-10")}, wantErr: ErrUnbalancedTransaction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &LedgerTransaction{Currency: "THB", Entries: tt.entries}
+			if err := tx.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatementApplyRunningBalance(t *testing.T) {
+	statement := &Statement{
+		OpeningBalance: money.MustParse("100"),
+		Lines: []StatementLine{
+			{Credit: money.MustParse("425")},
+			{Debit: money.MustParse("300")},
+			{Debit: money.MustParse("425")},
+		},
+	}
+	statement.ApplyRunningBalance()
+
+	for i, want := range []string{"525", "225", "-200"} {
+		if !statement.Lines[i].Balance.Equal(money.MustParse(want)) {
+			t.Errorf("line %d balance = %s, want %s", i, statement.Lines[i].Balance, want)
+		}
+	}
+	if !statement.ClosingBalance.Equal(money.MustParse("-200")) {
+		t.Errorf("ClosingBalance = %s, want -200", statement.ClosingBalance)
+	}
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/money"
+)
+
+type PayoutStatus string
+
+const (
+	PayoutStatusRequested PayoutStatus = "REQUESTED"
+	PayoutStatusApproved  PayoutStatus = "APPROVED"
+	PayoutStatusRejected  PayoutStatus = "REJECTED"
+	PayoutStatusPaid      PayoutStatus = "PAID"
+	PayoutStatusCancelled PayoutStatus = "CANCELLED"
+)
+
+var (
+	ErrPayoutNotFound      = errors.New("payout not found")
+	ErrInvalidPayoutAmount = errors.New("payout amount must be positive")
+	ErrInsufficientBalance = errors.New("payout exceeds available balance")
+	ErrInvalidPayoutState  = errors.New("invalid payout status transition")
+)
+
+// Payout is a prophet's request to withdraw earnings. Requested and approved
+// payouts hold their amount so it cannot be requested twice; the ledger is
+// only debited when the payout is paid. A payout draws on the balance in its
+// own currency.
+type Payout struct {
+	ID           string        `json:"id"`
+	ProphetID    string        `json:"prophet_id"`
+	Amount       money.Decimal `json:"amount"`
+	Currency     string        `json:"currency"`
+	Status       PayoutStatus  `json:"status"`
+	RequestedAt  time.Time     `json:"requested_at"`
+	ReviewedBy   string        `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time    `json:"reviewed_at,omitempty"`
+	RejectReason string        `json:"reject_reason,omitempty"`
+	// PaidReference identifies the bank transfer that paid the payout
+	PaidReference string     `json:"paid_reference,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func NewPayout(prophetID string, amount money.Decimal, currency string) (*Payout, error) {
+	if !amount.IsPositive() {
+		return nil, ErrInvalidPayoutAmount
+	}
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Payout{
+		ID:          uuid.New().String(),
+		ProphetID:   prophetID,
+		Amount:      money.RoundTo(amount, currency),
+		Currency:    currency,
+		Status:      PayoutStatusRequested,
+		RequestedAt: now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Open reports whether the payout still holds part of the prophet's balance
+func (p *Payout) Open() bool {
+	return p.Status == PayoutStatusRequested || p.Status == PayoutStatusApproved
+}
+
+func (p *Payout) Approve(reviewer string) error {
+	if p.Status != PayoutStatusRequested {
+		return ErrInvalidPayoutState
+	}
+	now := time.Now()
+	p.Status = PayoutStatusApproved
+	p.ReviewedBy = reviewer
+	p.ReviewedAt = &now
+	p.UpdatedAt = now
+	return nil
+}
+
+func (p *Payout) Reject(reviewer, reason string) error {
+	if !p.Open() {
+		return ErrInvalidPayoutState
+	}
+	now := time.Now()
+	p.Status = PayoutStatusRejected
+	p.ReviewedBy = reviewer
+	p.ReviewedAt = &now
+	p.RejectReason = reason
+	p.UpdatedAt = now
+	return nil
+}
+
+func (p *Payout) MarkPaid(reference string) error {
+	if p.Status == PayoutStatusPaid {
+		return nil
+	}
+	if p.Status != PayoutStatusApproved {
+		return ErrInvalidPayoutState
+	}
+	now := time.Now()
+	p.Status = PayoutStatusPaid
+	p.PaidReference = reference
+	p.PaidAt = &now
+	p.UpdatedAt = now
+	return nil
+}
+
+// Cancel withdraws a request the prophet no longer wants before it is reviewed
+func (p *Payout) Cancel() error {
+	if p.Status != PayoutStatusRequested {
+		return ErrInvalidPayoutState
+	}
+	p.Status = PayoutStatusCancelled
+	p.UpdatedAt = time.Now()
+	return nil
+}
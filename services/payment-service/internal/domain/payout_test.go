@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wnmay/horo/shared/money"
+)
+
+func TestNewPayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     string
+		currency   string
+		wantAmount string
+		wantErr    error
+	}{
+		{name: "rounded to the currency", amount: "100.005", currency: "thb", wantAmount: "100.01"},
+		{name: "yen", amount: "1500.4", currency: "JPY", wantAmount: "1500"},
+		{name: "zero", amount: "0", currency: "THB", wantErr: ErrInvalidPayoutAmount},
+		{name: "negative", amount: "-1", currency: "THB", wantErr: ErrInvalidPayoutAmount},
+		{name: "malformed currency", amount: "100", currency: "BAHT", wantErr: money.ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payout, err := NewPayout("prophet-1", money.MustParse(tt.amount), tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPayout() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !payout.Amount.Equal(money.MustParse(tt.wantAmount)) || payout.Status != PayoutStatusRequested {
+				t.Errorf("payout of %s is %s, want %s REQUESTED", payout.Amount, payout.Status, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestPayoutTransitions(t *testing.T) {
+	transitions := map[string]func(*Payout) error{
+		"approve": func(p *Payout) error { return p.Approve("admin-1") },
+		"reject":  func(p *Payout) error { return p.Reject("admin-1", "bank details missing") },
+		"pay":     func(p *Payout) error { return p.MarkPaid("transfer-1") },
+		"cancel":  func(p *Payout) error { return p.Cancel() },
+	}
+
+	tests := []struct {
+		from       PayoutStatus
+		transition string
+		want       PayoutStatus
+		wantErr    error
+	}{
+		{from: PayoutStatusRequested, transition: "approve", want: PayoutStatusApproved},
+		{from: PayoutStatusRequested, transition: "reject", want: PayoutStatusRejected},
+		{from: PayoutStatusRequested, transition: "cancel", want: PayoutStatusCancelled},
+		{from: PayoutStatusRequested, transition: "pay", wantErr: ErrInvalidPayoutState},
+		{from: PayoutStatusApproved, transition: "pay", want: PayoutStatusPaid},
+		{from: PayoutStatusApproved, transition: "reject", want: PayoutStatusRejected},
+		{from: PayoutStatusApproved, transition: "cancel", wantErr: ErrInvalidPayoutState},
+		{from: PayoutStatusApproved, transition: "approve", wantErr: ErrInvalidPayoutState},
+		{from: PayoutStatusPaid, transition: "pay", want: PayoutStatusPaid},
+		{from: PayoutStatusPaid, transition: "reject", wantErr: ErrInvalidPayoutState},
+		{from: PayoutStatusRejected, transition: "approve", wantErr: ErrInvalidPayoutState},
+		{from: PayoutStatusCancelled, transition: "pay", wantErr: ErrInvalidPayoutState},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" "+tt.transition, func(t *testing.T) {
+			payout := &Payout{ID: "payout-1", Status: tt.from}
+			err := transitions[tt.transition](payout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s error = %v, want %v", tt.transition, err, tt.wantErr)
+			}
+			want := tt.want
+			if err != nil {
+				want = tt.from
+			}
+			if payout.Status != want {
+				t.Errorf("status = %s, want %s", payout.Status, want)
+			}
+			if payout.Open() != (want == PayoutStatusRequested || want == PayoutStatusApproved) {
+				t.Errorf("Open() = %v for %s", payout.Open(), want)
+			}
+		})
+	}
+}
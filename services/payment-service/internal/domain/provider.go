@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrUnknownProvider         = errors.New("unknown payment provider")
+)
+
+// CheckoutSession is a hosted payment page opened at the provider
+type CheckoutSession struct {
+	PaymentID   string    `json:"payment_id"`
+	Provider    string    `json:"provider"`
+	ProviderRef string    `json:"provider_ref"`
+	CheckoutURL string    `json:"checkout_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type ProviderEventType string
+
+const (
+	ProviderEventPaymentSucceeded ProviderEventType = "payment.succeeded"
+	ProviderEventPaymentFailed    ProviderEventType = "payment.failed"
+)
+
+// ProviderEvent is a verified webhook notification from the provider
+type ProviderEvent struct {
+	EventID     string
+	Type        ProviderEventType
+	ProviderRef string
+	PaymentID   string
+	OccurredAt  time.Time
+}
+
+// WebhookRecord keeps the raw webhook body received for a payment
+type WebhookRecord struct {
+	ID              string     `json:"id"`
+	Provider        string     `json:"provider"`
+	EventID         string     `json:"event_id"`
+	EventType       string     `json:"event_type"`
+	PaymentID       string     `json:"payment_id"`
+	ProviderRef     string     `json:"provider_ref"`
+	Payload         string     `json:"payload"`
+	Signature       string     `json:"signature"`
+	ReceivedAt      time.Time  `json:"received_at"`
+	ProcessedAt     *time.Time `json:"processed_at,omitempty"`
+	ProcessingError string     `json:"processing_error,omitempty"`
+	// RefundRef is the provider's refund of a capture that arrived after the
+	// payment was voided or refunded
+	RefundRef string `json:"refund_ref,omitempty"`
+}
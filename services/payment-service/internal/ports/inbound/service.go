@@ -2,8 +2,10 @@ package inbound
 
 import (
 	"context"
+	"time"
 
 	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type PaymentService interface {
@@ -12,11 +14,68 @@ type PaymentService interface {
 	GetPaymentByOrderID(ctx context.Context, orderID string) (*domain.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, paymentID string, status domain.PaymentStatus) error
 	CompletePayment(ctx context.Context, paymentID string) error
-	SettlePayment(ctx context.Context, paymentID string, prophetID string) error
-	GetProphetBalance(ctx context.Context, prophetID string) (float64, error)
+	SettlePayment(ctx context.Context, cmd SettlePaymentCommand) error
+	GetProphetBalance(ctx context.Context, prophetID string, currency string) (money.Decimal, error)
+	GetProphetBalanceSummary(ctx context.Context, prophetID string) (*domain.ProphetBalance, error)
+	ListPaymentsByProphet(ctx context.Context, prophetID string) ([]*domain.Payment, error)
+	RefundPayment(ctx context.Context, orderID string) error
+	CreateCheckoutSession(ctx context.Context, paymentID string) (*domain.CheckoutSession, error)
+	FailPayment(ctx context.Context, paymentID string) error
+	HandleProviderWebhook(ctx context.Context, provider string, payload []byte, signature string) error
+	GetWebhookHistory(ctx context.Context, paymentID string) ([]*domain.WebhookRecord, error)
+	GetProphetStatement(ctx context.Context, prophetID string, currency string, from, to time.Time) (*domain.Statement, error)
+	GetAvailableBalance(ctx context.Context, prophetID string, currency string) (money.Decimal, error)
+	RequestPayout(ctx context.Context, prophetID string, amount money.Decimal, currency string) (*domain.Payout, error)
+	ListProphetPayouts(ctx context.Context, prophetID string) ([]*domain.Payout, error)
+	ListPayoutsByStatus(ctx context.Context, status domain.PayoutStatus) ([]*domain.Payout, error)
+	ApprovePayout(ctx context.Context, payoutID string, reviewer string) (*domain.Payout, error)
+	RejectPayout(ctx context.Context, payoutID string, reviewer string, reason string) (*domain.Payout, error)
+	CancelPayout(ctx context.Context, payoutID string, prophetID string) (*domain.Payout, error)
+	MarkPayoutPaid(ctx context.Context, payoutID string, reference string) (*domain.Payout, error)
+	BackfillSettlementLedger(ctx context.Context) (int, error)
+	CreateFeeRule(ctx context.Context, cmd CreateFeeRuleCommand) (*domain.FeeRule, error)
+	ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error)
+	EndFeeRule(ctx context.Context, ruleID string, at time.Time) (*domain.FeeRule, error)
+	ListPaymentsByStatus(ctx context.Context, status domain.PaymentStatus) ([]*domain.Payment, error)
+	ForcePaymentStatus(ctx context.Context, cmd ForcePaymentStatusCommand) (*domain.Payment, error)
+	GetPaymentAudit(ctx context.Context, paymentID string) ([]*domain.PaymentAudit, error)
 }
 
 type CreatePaymentCommand struct {
-	OrderID string  `json:"order_id"`
-	Amount  float64 `json:"amount"`
+	OrderID  string        `json:"order_id"`
+	Amount   money.Decimal `json:"amount"`
+	Currency string        `json:"currency"`
+}
+
+type SettlePaymentCommand struct {
+	OrderID    string `json:"order_id"`
+	ProphetID  string `json:"prophet_id"`
+	CourseType string `json:"course_type"`
+	// Session out of Sessions is the bundle session to settle. Orders of a
+	// single session leave them zero and settle the whole payment.
+	Session  int `json:"session"`
+	Sessions int `json:"sessions"`
+}
+
+// CreateFeeRuleCommand adds a fee rule. Leaving ProphetID, CourseType or
+// Currency empty makes the rule apply to every prophet, course type or
+// currency; a flat fee requires a currency. A nil EffectiveFrom means now.
+type CreateFeeRuleCommand struct {
+	ProphetID     string        `json:"prophet_id"`
+	CourseType    string        `json:"course_type"`
+	Currency      string        `json:"currency"`
+	Percentage    money.Decimal `json:"percentage"`
+	FlatFee       money.Decimal `json:"flat_fee"`
+	EffectiveFrom *time.Time    `json:"effective_from"`
+	EffectiveTo   *time.Time    `json:"effective_to"`
+	CreatedBy     string        `json:"-"`
+}
+
+// ForcePaymentStatus moves a payment to Status on an admin's say-so. Reason
+// is required and kept in the payment's audit trail.
+type ForcePaymentStatusCommand struct {
+	PaymentID string               `json:"-"`
+	AdminID   string               `json:"-"`
+	Status    domain.PaymentStatus `json:"status"`
+	Reason    string               `json:"reason"`
 }
@@ -0,0 +1,32 @@
+package outbound
+
+import (
+	"context"
+
+	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
+)
+
+// PaymentProvider is the external gateway that actually captures money
+type PaymentProvider interface {
+	Name() string
+	CreateCheckoutSession(ctx context.Context, payment *domain.Payment) (*domain.CheckoutSession, error)
+	// SignatureHeader is the HTTP header carrying the webhook signature
+	SignatureHeader() string
+	// VerifyWebhook checks the signature and decodes the event
+	VerifyWebhook(payload []byte, signature string) (*domain.ProviderEvent, error)
+	// RefundCapture returns amount of the money captured in the checkout
+	// session providerRef and returns the provider's refund reference.
+	// Refunding a session twice returns the first refund.
+	RefundCapture(ctx context.Context, payment *domain.Payment, providerRef string, amount money.Decimal) (string, error)
+}
+
+type WebhookRepository interface {
+	// Record stores the webhook unless an event with the same provider and
+	// event ID exists, and returns the stored record either way
+	Record(ctx context.Context, record *domain.WebhookRecord) (*domain.WebhookRecord, error)
+	MarkProcessed(ctx context.Context, id string, processingErr error) error
+	// RecordRefund notes the refund issued for the webhook's capture
+	RecordRefund(ctx context.Context, id string, refundRef string) error
+	ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.WebhookRecord, error)
+}
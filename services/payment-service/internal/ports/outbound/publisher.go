@@ -11,4 +11,5 @@ type PaymentEventPublisher interface {
 	PublishPaymentFailed(ctx context.Context, payment *domain.Payment) error
 	PublishPaymentCreated(ctx context.Context, payment *domain.Payment) error
 	PublishPaymentSettled(ctx context.Context, payment *domain.Payment) error
+	PublishPaymentRefunded(ctx context.Context, payment *domain.Payment, settlementReversed bool) error
 }
\ No newline at end of file
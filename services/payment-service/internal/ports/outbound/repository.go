@@ -2,15 +2,77 @@ package outbound
 
 import (
 	"context"
+	"time"
 
 	"github.com/wnmay/horo/services/payment-service/internal/domain"
+	"github.com/wnmay/horo/shared/money"
 )
 
 type PaymentRepository interface {
+	// Create returns ErrPaymentExists if the order already has a payment
 	Create(ctx context.Context, payment *domain.Payment) error
 	GetByID(ctx context.Context, paymentID string) (*domain.Payment, error)
+	// GetByIDForUpdate locks the payment's row until the transaction carried
+	// by ctx ends
+	GetByIDForUpdate(ctx context.Context, paymentID string) (*domain.Payment, error)
 	GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error)
 	Update(ctx context.Context, payment *domain.Payment) error
 	Delete(ctx context.Context, paymentID string) error
-	GetProphetSettledBalance(ctx context.Context, prophetID string) (float64, error)
-}
\ No newline at end of file
+	ListByProphetID(ctx context.Context, prophetID string) ([]*domain.Payment, error)
+	ListByStatus(ctx context.Context, status domain.PaymentStatus) ([]*domain.Payment, error)
+	// SettledTotals sums the gross amount and platform fee of the prophet's
+	// settled payments per currency
+	SettledTotals(ctx context.Context, prophetID string) (map[string]domain.SettledTotal, error)
+}
+
+// TransactionManager runs a unit of work atomically. Repositories called with
+// the context passed to fn take part in the same transaction.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// AuditRepository stores the admin status changes made to payments
+type AuditRepository interface {
+	Create(ctx context.Context, audit *domain.PaymentAudit) error
+	ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.PaymentAudit, error)
+}
+// LedgerRepository stores the double-entry ledger
+type LedgerRepository interface {
+	// PostTransaction stores the transaction and its entries atomically. A
+	// transaction whose reference was already posted is ignored.
+	PostTransaction(ctx context.Context, tx *domain.LedgerTransaction) error
+	FindTransactionByReference(ctx context.Context, reference string) (*domain.LedgerTransaction, error)
+	// AccountBalance returns credits minus debits on the account in currency
+	AccountBalance(ctx context.Context, account, currency string) (money.Decimal, error)
+	// AccountBalances returns the account's balance in every currency it holds
+	AccountBalances(ctx context.Context, account string) (map[string]money.Decimal, error)
+	// AccountBalanceBefore returns the balance from entries posted before t
+	AccountBalanceBefore(ctx context.Context, account, currency string, t time.Time) (money.Decimal, error)
+	// ListAccountLines returns the account's movements in currency in
+	// [from, to), oldest first
+	ListAccountLines(ctx context.Context, account, currency string, from, to time.Time) ([]domain.StatementLine, error)
+}
+
+// PayoutRepository stores prophets' withdrawal requests
+type PayoutRepository interface {
+	// CreateWithinBalance stores a requested payout only if it fits the
+	// prophet's ledger balance minus their open payouts in the payout's
+	// currency. Concurrent requests for the same prophet are serialised.
+	CreateWithinBalance(ctx context.Context, payout *domain.Payout) error
+	GetByID(ctx context.Context, payoutID string) (*domain.Payout, error)
+	Update(ctx context.Context, payout *domain.Payout) error
+	ListByProphetID(ctx context.Context, prophetID string) ([]*domain.Payout, error)
+	ListByStatus(ctx context.Context, status domain.PayoutStatus) ([]*domain.Payout, error)
+	// OpenTotals sums the prophet's requested and approved payouts per currency
+	OpenTotals(ctx context.Context, prophetID string) (map[string]money.Decimal, error)
+}
+
+// FeeRuleRepository stores the platform fee rules applied on settlement
+type FeeRuleRepository interface {
+	Create(ctx context.Context, rule *domain.FeeRule) error
+	GetByID(ctx context.Context, ruleID string) (*domain.FeeRule, error)
+	Update(ctx context.Context, rule *domain.FeeRule) error
+	List(ctx context.Context) ([]*domain.FeeRule, error)
+	// ListEffectiveAt returns the rules in force at t
+	ListEffectiveAt(ctx context.Context, t time.Time) ([]*domain.FeeRule, error)
+}
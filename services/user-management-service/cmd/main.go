@@ -14,6 +14,7 @@ import (
 	"github.com/wnmay/horo/services/user-management-service/internal/app"
 	"github.com/wnmay/horo/services/user-management-service/internal/config"
 	"github.com/wnmay/horo/shared/env"
+	"github.com/wnmay/horo/shared/jwt"
 	proto "github.com/wnmay/horo/shared/proto/user-management"
 	"google.golang.org/grpc"
 )
@@ -24,6 +25,7 @@ const (
 
 func main() {
 	_ = env.LoadEnv(service_name)
+	jwt.InitFromEnv(service_name)
 	cfg := config.LoadConfig()
 
 	// Init db adapter
@@ -32,19 +34,24 @@ func main() {
 		log.Fatalf("failed to connect: %v", err)
 	}
 
+	sessionRepo := db.NewMongoSessionRepository(userRepo.Database())
+	if err := sessionRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("failed to create session indexes: %v", err)
+	}
+
 	// Init firebase and adapter
 	ctx := context.Background()
 	firebaseClient := firebase.InitFirebase(ctx, cfg.FirebaseAccountKeyFile)
 	firebaseAdapter := firebase.NewFirebaseAuthAdapter(firebaseClient)
 
 	// Init core service
-	userApp := app.NewUserManagementService(firebaseAdapter, userRepo)
-	authApp := app.NewAuthService(firebaseAdapter)
+	userApp := app.NewUserManagementService(firebaseAdapter, userRepo, sessionRepo)
+	authApp := app.NewAuthService(firebaseAdapter, userRepo, sessionRepo)
 
 	// Create grpc server
 	userServiceServer := grpcadapter.NewUserServer(userApp)
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(jwt.UnaryServerIdentity()))
 
 	// Register auth service on gRPC (user registration is now HTTP)
 	proto.RegisterUserServiceServer(grpcServer, userServiceServer)
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const SessionCollectionName = "sessions"
+
+type SessionModel struct {
+	ID        string     `bson:"_id"`
+	UserID    string     `bson:"user_id"`
+	CreatedAt time.Time  `bson:"created_at"`
+	ExpiresAt time.Time  `bson:"expires_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty"`
+}
+
+type MongoSessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSessionRepository(database *mongo.Database) *MongoSessionRepository {
+	return &MongoSessionRepository{collection: database.Collection(SessionCollectionName)}
+}
+
+// EnsureIndexes lets Mongo delete sessions once their refresh token expires
+func (r *MongoSessionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	return err
+}
+
+func (r *MongoSessionRepository) Save(ctx context.Context, session domain.Session) error {
+	_, err := r.collection.InsertOne(ctx, SessionModel{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+		RevokedAt: session.RevokedAt,
+	})
+	return err
+}
+
+// Revoke only matches an active session, so of two requests refreshing with
+// the same token only one succeeds
+func (r *MongoSessionRepository) Revoke(ctx context.Context, sessionID string, at time.Time) (bool, error) {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": sessionID, "revoked_at": bson.M{"$exists": false}, "expires_at": bson.M{"$gt": at}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *MongoSessionRepository) RevokeAllForUser(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	return err
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"time"
 
 	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -36,6 +38,12 @@ func NewMongoUserRepository(uri, dbName, collectionName string) (*MongoUserRepos
 	}, nil
 }
 
+// Database is the database users are kept in, for repositories that share
+// its connection
+func (r *MongoUserRepository) Database() *mongo.Database {
+	return r.collection.Database()
+}
+
 // in your repository layer
 func (r *MongoUserRepository) Save(ctx context.Context, user domain.User) error {
 	model := UserModel{
@@ -64,14 +72,7 @@ func (r *MongoUserRepository) FindById(ctx context.Context, userId string) (*dom
 		return nil, err
 	}
 
-	user := &domain.User{
-		ID:       userModel.UserID,
-		Email:    userModel.Email,
-		FullName: userModel.FullName,
-		Role:     userModel.Role,
-	}
-
-	return user, nil
+	return userModel.toDomain(), nil
 }
 
 func (r *MongoUserRepository) Update(ctx context.Context, userID string, update map[string]interface{}) (*domain.User, error) {
@@ -180,3 +181,101 @@ func (r *MongoUserRepository) MapUserNames(ctx context.Context, userIDs []string
 
 	return userNames, nil
 }
+
+// FindProphetsByIDs loads the prophets among userIDs, with their profiles
+func (r *MongoUserRepository) FindProphetsByIDs(ctx context.Context, userIDs []string) ([]*domain.User, error) {
+	filter := bson.M{
+		"user_id": bson.M{"$in": userIDs},
+		"role":    string(domain.USER_ROLE_PROPHET),
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prophets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var prophets []*domain.User
+
+	for cursor.Next(ctx) {
+		var user UserModel
+		if err := cursor.Decode(&user); err != nil {
+			return nil, fmt.Errorf("failed to decode user: %w", err)
+		}
+		prophets = append(prophets, user.toDomain())
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return prophets, nil
+}
+
+// userCursor is the position of the last user of a page
+type userCursor struct {
+	UserID string `json:"id"`
+}
+
+// Search pages through the users matching search, ordered by ID
+func (r *MongoUserRepository) Search(ctx context.Context, search domain.UserSearch, page contract.PageRequest) (contract.Page[*domain.User], error) {
+	filter := bson.M{}
+	if search.Query != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(search.Query), "$options": "i"}
+		filter["$or"] = bson.A{bson.M{"fullname": pattern}, bson.M{"email": pattern}}
+	}
+	if search.Role != "" {
+		filter["role"] = string(search.Role)
+	}
+	if search.Suspended != nil {
+		filter["suspension"] = bson.M{"$exists": *search.Suspended}
+	}
+	if page.Cursor != "" {
+		var position userCursor
+		if err := contract.DecodeCursor(page.Cursor, &position); err != nil {
+			return contract.Page[*domain.User]{}, err
+		}
+		filter["user_id"] = bson.M{"$gt": position.UserID}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "user_id", Value: 1}}).
+		SetLimit(int64(page.Limit + 1))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return contract.Page[*domain.User]{}, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	for cursor.Next(ctx) {
+		var user UserModel
+		if err := cursor.Decode(&user); err != nil {
+			return contract.Page[*domain.User]{}, fmt.Errorf("failed to decode user: %w", err)
+		}
+		users = append(users, user.toDomain())
+	}
+	if err := cursor.Err(); err != nil {
+		return contract.Page[*domain.User]{}, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return contract.NewPage(users, page.Limit, func(user *domain.User) any {
+		return userCursor{UserID: user.ID}
+	}), nil
+}
+
+// SetSuspension suspends the user, or reinstates them when suspension is nil
+func (r *MongoUserRepository) SetSuspension(ctx context.Context, userID string, suspension *domain.Suspension) (*domain.User, error) {
+	update := bson.M{"$unset": bson.M{"suspension": ""}}
+	if suspension != nil {
+		update = bson.M{"$set": bson.M{"suspension": SuspensionModel{
+			Reason:      suspension.Reason,
+			SuspendedBy: suspension.SuspendedBy,
+			SuspendedAt: suspension.SuspendedAt,
+		}}}
+	}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID}, update); err != nil {
+		return nil, err
+	}
+	return r.FindById(ctx, userID)
+}
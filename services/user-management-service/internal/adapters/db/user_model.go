@@ -1,11 +1,73 @@
 package db
 
+import (
+	"time"
+
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+)
+
 // Model for saving into gorm
 type UserModel struct {
 	UserID   string `bson:"user_id,omitempty" gorm:"primaryKey"`
 	FullName string `bson:"fullname"`
 	Email    string `bson:"email"`
 	Role     string `bson:"role"` // "prophet" or "customer"
+	// Left out when saving a user so registering again keeps the profile
+	Profile *ProphetProfileModel `bson:"profile,omitempty"`
+	// Only written by SetSuspension, and removed on reinstatement
+	Suspension *SuspensionModel `bson:"suspension,omitempty"`
+}
+
+type SuspensionModel struct {
+	Reason      string    `bson:"reason"`
+	SuspendedBy string    `bson:"suspended_by"`
+	SuspendedAt time.Time `bson:"suspended_at"`
+}
+
+type ProphetProfileModel struct {
+	Bio               string    `bson:"bio"`
+	Specialties       []string  `bson:"specialties"`
+	Languages         []string  `bson:"languages"`
+	YearsOfExperience int       `bson:"years_of_experience"`
+	AvatarURL         string    `bson:"avatar_url"`
+	Verified          bool      `bson:"verified"`
+	Online            bool      `bson:"online"`
+	LastSeenAt        time.Time `bson:"last_seen_at"`
+	UpdatedAt         time.Time `bson:"updated_at"`
+}
+
+func (m *UserModel) toDomain() *domain.User {
+	user := &domain.User{
+		ID:       m.UserID,
+		Email:    m.Email,
+		FullName: m.FullName,
+		Role:     m.Role,
+	}
+	if p := m.Profile; p != nil {
+		specialties := make([]domain.Specialty, 0, len(p.Specialties))
+		for _, s := range p.Specialties {
+			specialties = append(specialties, domain.Specialty(s))
+		}
+		user.Profile = &domain.ProphetProfile{
+			Bio:               p.Bio,
+			Specialties:       specialties,
+			Languages:         p.Languages,
+			YearsOfExperience: p.YearsOfExperience,
+			AvatarURL:         p.AvatarURL,
+			Verified:          p.Verified,
+			Online:            p.Online,
+			LastSeenAt:        p.LastSeenAt,
+			UpdatedAt:         p.UpdatedAt,
+		}
+	}
+	if s := m.Suspension; s != nil {
+		user.Suspension = &domain.Suspension{
+			Reason:      s.Reason,
+			SuspendedBy: s.SuspendedBy,
+			SuspendedAt: s.SuspendedAt,
+		}
+	}
+	return user
 }
 
 type ProphetModel struct {
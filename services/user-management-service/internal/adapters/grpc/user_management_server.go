@@ -2,11 +2,15 @@ package grpcadapter
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	"github.com/wnmay/horo/services/user-management-service/internal/domain"
 	"github.com/wnmay/horo/services/user-management-service/internal/ports"
 	proto "github.com/wnmay/horo/shared/proto/user-management"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type UserServer struct {
@@ -72,6 +76,38 @@ func (s *UserServer) MapUserNames(ctx context.Context, req *proto.MapUserNamesRe
 	}, nil
 }
 
+func (s *UserServer) GetProphetProfile(ctx context.Context, req *proto.GetProphetProfileRequest) (*proto.GetProphetProfileResponse, error) {
+	prophet, err := s.userManagementService.GetProphetProfile(ctx, req.UserId)
+	if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrNotProphet) {
+		return nil, status.Errorf(codes.NotFound, "prophet %s not found", req.UserId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.GetProphetProfileResponse{
+		Summary: toProtoProphetSummary(domain.NewProphetSummary(prophet, time.Now())),
+	}
+	if prophet.Profile != nil {
+		resp.Bio = prophet.Profile.Bio
+	}
+	return resp, nil
+}
+
+func (s *UserServer) MapProphetSummaries(ctx context.Context, req *proto.MapProphetSummariesRequest) (*proto.MapProphetSummariesResponse, error) {
+	summaries, err := s.userManagementService.MapProphetSummaries(ctx, req.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	prophets := make(map[string]*proto.ProphetSummary, len(summaries))
+	for _, summary := range summaries {
+		prophets[summary.UserID] = toProtoProphetSummary(summary)
+	}
+	return &proto.MapProphetSummariesResponse{
+		Prophets: prophets,
+	}, nil
+}
+
 func toProtoProphetName(prophetName *domain.ProphetName) *proto.ProphetData {
 	return &proto.ProphetData{
 		UserId:      prophetName.UserID,
@@ -85,3 +121,20 @@ func toProtoUserName(userName *domain.UserName) *proto.UserData {
 		Role: proto.UserRole(proto.UserRole_value[string(userName.UserRole)]),
 	}
 }
+
+func toProtoProphetSummary(summary *domain.ProphetSummary) *proto.ProphetSummary {
+	specialties := make([]string, 0, len(summary.Specialties))
+	for _, s := range summary.Specialties {
+		specialties = append(specialties, string(s))
+	}
+	return &proto.ProphetSummary{
+		UserId:            summary.UserID,
+		Name:              summary.Name,
+		AvatarUrl:         summary.AvatarURL,
+		Verified:          summary.Verified,
+		Online:            summary.Online,
+		Specialties:       specialties,
+		Languages:         summary.Languages,
+		YearsOfExperience: int32(summary.YearsOfExperience),
+	}
+}
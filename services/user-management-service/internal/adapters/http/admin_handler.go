@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+)
+
+type AdminUserResponse struct {
+	UserID     string              `json:"user_id"`
+	FullName   string              `json:"fullname"`
+	Email      string              `json:"email"`
+	Role       string              `json:"role"`
+	Suspension *SuspensionResponse `json:"suspension,omitempty"`
+}
+
+type SuspensionResponse struct {
+	Reason      string    `json:"reason"`
+	SuspendedBy string    `json:"suspended_by"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+func toAdminUserResponse(user *domain.User) AdminUserResponse {
+	resp := AdminUserResponse{
+		UserID:   user.ID,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+	if s := user.Suspension; s != nil {
+		resp.Suspension = &SuspensionResponse{
+			Reason:      s.Reason,
+			SuspendedBy: s.SuspendedBy,
+			SuspendedAt: s.SuspendedAt,
+		}
+	}
+	return resp
+}
+
+// SearchUsers — GET /api/users?q=&role=&suspended=&cursor=&limit=; admins only
+func (h *HTTPHandler) SearchUsers(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != string(domain.USER_ROLE_ADMIN) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "only admins can search users",
+		})
+	}
+
+	page, err := contract.ParsePageRequest(c.Query("cursor"), c.Query("limit"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	search := domain.UserSearch{Query: c.Query("q")}
+	if v := c.Query("role"); v != "" {
+		role, err := domain.ParseUserRole(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		search.Role = role
+	}
+	if v := c.Query("suspended"); v != "" {
+		suspended, err := strconv.ParseBool(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "suspended must be true or false",
+			})
+		}
+		search.Suspended = &suspended
+	}
+
+	users, err := h.userService.SearchUsers(c.Context(), search, page)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, contract.ErrInvalidCursor) {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	data := make([]AdminUserResponse, 0, len(users.Data))
+	for _, user := range users.Data {
+		data = append(data, toAdminUserResponse(user))
+	}
+	return c.Status(fiber.StatusOK).JSON(contract.Page[AdminUserResponse]{
+		Data:       data,
+		NextCursor: users.NextCursor,
+	})
+}
+
+// SuspendProphet stops a prophet from selling courses until reinstated;
+// admins only
+func (h *HTTPHandler) SuspendProphet(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != string(domain.USER_ROLE_ADMIN) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "only admins can suspend prophets",
+		})
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid json body",
+		})
+	}
+
+	user, err := h.userService.SuspendProphet(c.Context(), c.Params("id"), c.Get("X-User-Id"), req.Reason)
+	if err != nil {
+		return suspensionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": toAdminUserResponse(user),
+	})
+}
+
+// ReinstateProphet lifts a prophet's suspension; admins only
+func (h *HTTPHandler) ReinstateProphet(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != string(domain.USER_ROLE_ADMIN) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "only admins can reinstate prophets",
+		})
+	}
+
+	user, err := h.userService.ReinstateProphet(c.Context(), c.Params("id"))
+	if err != nil {
+		return suspensionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": toAdminUserResponse(user),
+	})
+}
+
+func suspensionError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrSuspensionReasonRequired):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrNotProphet):
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 	"log"
 	"strings"
 
@@ -10,7 +11,9 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
 	"github.com/wnmay/horo/services/user-management-service/internal/ports"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 type HTTPHandler struct {
@@ -51,6 +54,7 @@ func (h *HTTPHandler) SetupRoutes(app *fiber.App) {
 		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
 		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
 	}))
+	app.Use(jwt.IdentityFromToken())
 
 	// Health check endpoint
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -66,8 +70,21 @@ func (h *HTTPHandler) SetupRoutes(app *fiber.App) {
 	users.Post("/register", h.Register)
 	users.Get("/:id", h.GetUserByID)
 	users.Patch("/:id/update-name", h.UpdateUsernameByID)
+	users.Put("/:id/role", h.SetUserRole)
+	// Prophet profiles
+	users.Get("/prophets/:id/profile", h.GetProphetProfile)
+	users.Patch("/:id/profile", h.UpdateProphetProfile)
+	users.Put("/:id/online", h.SetOnlineStatus)
+	users.Put("/prophets/:id/verified", h.SetProphetVerified)
+	// Admin back office
+	users.Get("/", h.SearchUsers)
+	users.Post("/prophets/:id/suspend", h.SuspendProphet)
+	users.Post("/prophets/:id/reinstate", h.ReinstateProphet)
 	auth := api.Group("/auth")
 	auth.Get("/verify-token", h.VerifyToken)
+	auth.Post("/token", h.IssueToken)
+	auth.Post("/refresh", h.RefreshToken)
+	auth.Post("/revoke", h.RevokeToken)
 }
 
 func (h *HTTPHandler) Register(c *fiber.Ctx) error {
@@ -91,6 +108,12 @@ func (h *HTTPHandler) Register(c *fiber.Ctx) error {
 
 	ctx := context.Background()
 	err := h.userService.Register(ctx, req.IdToken, req.FullName, req.Role)
+	if errors.Is(err, domain.ErrInvalidRegisterRole) {
+		return c.Status(fiber.StatusBadRequest).JSON(RegisterResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		log.Printf("Registration failed: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(RegisterResponse{
@@ -180,6 +203,47 @@ func (h *HTTPHandler) UpdateUsernameByID(c *fiber.Ctx) error {
 	})
 }
 
+// SetUserRole makes a user a customer, prophet or admin; admins only
+func (h *HTTPHandler) SetUserRole(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != string(domain.USER_ROLE_ADMIN) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "only admins can change roles",
+		})
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "role is required",
+		})
+	}
+
+	user, err := h.userService.SetUserRole(c.Context(), c.Params("id"), req.Role)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrInvalidRole):
+			status = fiber.StatusBadRequest
+		case errors.Is(err, domain.ErrUserNotFound):
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": fiber.Map{
+			"user_id":  user.ID,
+			"fullname": user.FullName,
+			"email":    user.Email,
+			"role":     user.Role,
+		},
+	})
+}
+
 func (h *HTTPHandler) VerifyToken(c *fiber.Ctx) error {
 	token := c.Query("token")
 	log.Println("token", token)
@@ -231,6 +295,78 @@ func (h *HTTPHandler) VerifyToken(c *fiber.Ctx) error {
 	})
 }
 
+// IssueToken exchanges a Firebase ID token for a platform token pair
+func (h *HTTPHandler) IssueToken(c *fiber.Ctx) error {
+	var req struct {
+		IdToken string `json:"idToken"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.IdToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "idToken is required",
+		})
+	}
+
+	pair, err := h.authService.IssueSession(c.Context(), req.IdToken)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": pair})
+}
+
+// RefreshToken rotates a refresh token into a new token pair
+func (h *HTTPHandler) RefreshToken(c *fiber.Ctx) error {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "refreshToken is required",
+		})
+	}
+
+	pair, err := h.authService.RefreshSession(c.Context(), req.RefreshToken)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": pair})
+}
+
+// RevokeToken signs out the refresh token's session, or every session of its
+// user when all is set
+func (h *HTTPHandler) RevokeToken(c *fiber.Ctx) error {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+		All          bool   `json:"all"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "refreshToken is required",
+		})
+	}
+
+	if err := h.authService.RevokeSession(c.Context(), req.RefreshToken, req.All); err != nil {
+		return sessionError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func sessionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidIDToken), errors.Is(err, domain.ErrInvalidSession):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "user not registered",
+		})
+	}
+	log.Printf("Session request failed: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "failed to process session",
+	})
+}
+
 func StartHTTPServer(handler *HTTPHandler, port string) error {
 	app := fiber.New(fiber.Config{
 		AppName: "User Management Service",
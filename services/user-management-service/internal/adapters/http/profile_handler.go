@@ -0,0 +1,164 @@
+package http
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+)
+
+type ProphetProfileResponse struct {
+	UserID            string     `json:"user_id"`
+	FullName          string     `json:"fullname"`
+	Bio               string     `json:"bio"`
+	Specialties       []string   `json:"specialties"`
+	Languages         []string   `json:"languages"`
+	YearsOfExperience int        `json:"years_of_experience"`
+	AvatarURL         string     `json:"avatar_url"`
+	Verified          bool       `json:"verified"`
+	Online            bool       `json:"online"`
+	LastSeenAt        *time.Time `json:"last_seen_at,omitempty"`
+}
+
+func toProphetProfileResponse(user *domain.User) ProphetProfileResponse {
+	resp := ProphetProfileResponse{
+		UserID:      user.ID,
+		FullName:    user.FullName,
+		Specialties: []string{},
+		Languages:   []string{},
+	}
+	if p := user.Profile; p != nil {
+		resp.Bio = p.Bio
+		for _, s := range p.Specialties {
+			resp.Specialties = append(resp.Specialties, string(s))
+		}
+		if p.Languages != nil {
+			resp.Languages = p.Languages
+		}
+		resp.YearsOfExperience = p.YearsOfExperience
+		resp.AvatarURL = p.AvatarURL
+		resp.Verified = p.Verified
+		resp.Online = p.IsOnline(time.Now())
+		if !p.LastSeenAt.IsZero() {
+			resp.LastSeenAt = &p.LastSeenAt
+		}
+	}
+	return resp
+}
+
+// GetProphetProfile is the public profile of a prophet
+func (h *HTTPHandler) GetProphetProfile(c *fiber.Ctx) error {
+	user, err := h.userService.GetProphetProfile(c.Context(), c.Params("id"))
+	if errors.Is(err, domain.ErrNotProphet) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "prophet not found",
+		})
+	}
+	if err != nil {
+		return profileError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": toProphetProfileResponse(user),
+	})
+}
+
+type updateProphetProfileReq struct {
+	Bio               *string   `json:"bio"`
+	Specialties       *[]string `json:"specialties"`
+	Languages         *[]string `json:"languages"`
+	YearsOfExperience *int      `json:"years_of_experience"`
+	AvatarURL         *string   `json:"avatar_url"`
+}
+
+// UpdateProphetProfile edits the prophet's own profile; fields left out of
+// the body are kept
+func (h *HTTPHandler) UpdateProphetProfile(c *fiber.Ctx) error {
+	var req updateProphetProfileReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	user, err := h.userService.UpdateProphetProfile(c.Context(), c.Params("id"), domain.UpdateProphetProfileInput{
+		Bio:               req.Bio,
+		Specialties:       req.Specialties,
+		Languages:         req.Languages,
+		YearsOfExperience: req.YearsOfExperience,
+		AvatarURL:         req.AvatarURL,
+	})
+	if err != nil {
+		return profileError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": toProphetProfileResponse(user),
+	})
+}
+
+// SetOnlineStatus lets a prophet go online or offline. Clients keep a
+// prophet online by sending it again within domain.OnlineTimeout.
+func (h *HTTPHandler) SetOnlineStatus(c *fiber.Ctx) error {
+	var req struct {
+		Online *bool `json:"online"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Online == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "online is required",
+		})
+	}
+
+	user, err := h.userService.SetProphetOnline(c.Context(), c.Params("id"), *req.Online)
+	if err != nil {
+		return profileError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": toProphetProfileResponse(user),
+	})
+}
+
+// SetProphetVerified gives or takes away a prophet's verified badge; admins only
+func (h *HTTPHandler) SetProphetVerified(c *fiber.Ctx) error {
+	if c.Get("X-User-Role") != string(domain.USER_ROLE_ADMIN) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "only admins can verify prophets",
+		})
+	}
+
+	var req struct {
+		Verified *bool `json:"verified"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Verified == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "verified is required",
+		})
+	}
+
+	user, err := h.userService.SetProphetVerified(c.Context(), c.Params("id"), *req.Verified)
+	if err != nil {
+		return profileError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": toProphetProfileResponse(user),
+	})
+}
+
+func profileError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrNotProphet),
+		errors.Is(err, domain.ErrProphetSuspended):
+		status = fiber.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidSpecialty),
+		errors.Is(err, domain.ErrBioTooLong),
+		errors.Is(err, domain.ErrTooManyLanguages),
+		errors.Is(err, domain.ErrInvalidExperience),
+		errors.Is(err, domain.ErrInvalidAvatarURL):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
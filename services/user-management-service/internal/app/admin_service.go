@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
+)
+
+// SearchUsers lists the users matching search, for admins
+func (s *UserManagementService) SearchUsers(ctx context.Context, search domain.UserSearch, page contract.PageRequest) (contract.Page[*domain.User], error) {
+	return s.repo.Search(ctx, search, page)
+}
+
+// SuspendProphet suspends a prophet and takes them offline. Suspending a
+// prophet who is already suspended keeps the original suspension.
+func (s *UserManagementService) SuspendProphet(ctx context.Context, userID string, adminID string, reason string) (*domain.User, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.ErrSuspensionReasonRequired
+	}
+	user, err := s.GetProphetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Suspension != nil {
+		return user, nil
+	}
+
+	if user.Profile != nil && user.Profile.Online {
+		_, err := s.repo.Update(ctx, userID, map[string]interface{}{
+			"profile.online":       false,
+			"profile.last_seen_at": time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.SetSuspension(ctx, userID, &domain.Suspension{
+		Reason:      reason,
+		SuspendedBy: adminID,
+		SuspendedAt: time.Now(),
+	})
+}
+
+// ReinstateProphet lifts a prophet's suspension, if they have one
+func (s *UserManagementService) ReinstateProphet(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.GetProphetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Suspension == nil {
+		return user, nil
+	}
+	return s.repo.SetSuspension(ctx, userID, nil)
+}
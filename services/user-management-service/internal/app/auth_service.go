@@ -3,16 +3,22 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
 	"github.com/wnmay/horo/services/user-management-service/internal/ports"
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 type AuthService struct {
 	authPort ports.AuthPort
+	users    ports.UserRepositoryPort
+	sessions ports.SessionRepositoryPort
 }
 
-func NewAuthService(authPort ports.AuthPort) *AuthService {
-	return &AuthService{authPort: authPort}
+func NewAuthService(authPort ports.AuthPort, users ports.UserRepositoryPort, sessions ports.SessionRepositoryPort) *AuthService {
+	return &AuthService{authPort: authPort, users: users, sessions: sessions}
 }
 
 func (s *AuthService) GetClaims(ctx context.Context, token string) (*ports.Claims, error) {
@@ -26,3 +32,75 @@ func (s *AuthService) GetClaims(ctx context.Context, token string) (*ports.Claim
 func (s *AuthService) SetCustomClaims(ctx context.Context, uid string, customClaims map[string]interface{}) error {
 	return s.authPort.SetCustomUserClaims(ctx, uid, customClaims)
 }
+
+// IssueSession exchanges a Firebase ID token for a platform token pair. The
+// role comes from the user's record, not the Firebase claims.
+func (s *AuthService) IssueSession(ctx context.Context, idToken string) (*jwt.TokenPair, error) {
+	claims, err := s.authPort.VerifyIDToken(ctx, idToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidIDToken, err)
+	}
+	return s.startSession(ctx, claims.UserID)
+}
+
+// RefreshSession exchanges a refresh token for a new pair. Each refresh token
+// works once; using one again revokes all of the user's sessions, since it
+// has likely been stolen.
+func (s *AuthService) RefreshSession(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
+	claims, err := jwt.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, domain.ErrInvalidSession
+	}
+
+	now := time.Now()
+	active, err := s.sessions.Revoke(ctx, claims.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		if err := s.sessions.RevokeAllForUser(ctx, claims.UserID, now); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidSession
+	}
+	return s.startSession(ctx, claims.UserID)
+}
+
+// RevokeSession signs a refresh token out, or with all, every session of its
+// user. Revoking a token that was already revoked is not an error.
+func (s *AuthService) RevokeSession(ctx context.Context, refreshToken string, all bool) error {
+	claims, err := jwt.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return domain.ErrInvalidSession
+	}
+	if all {
+		return s.sessions.RevokeAllForUser(ctx, claims.UserID, time.Now())
+	}
+	_, err = s.sessions.Revoke(ctx, claims.ID, time.Now())
+	return err
+}
+
+func (s *AuthService) startSession(ctx context.Context, userID string) (*jwt.TokenPair, error) {
+	user, err := s.users.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	pair, err := jwt.IssueTokenPair(user.ID, user.Email, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessions.Save(ctx, domain.Session{
+		ID:        pair.RefreshTokenID,
+		UserID:    user.ID,
+		CreatedAt: time.Now(),
+		ExpiresAt: pair.RefreshTokenExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+)
+
+// GetProphetProfile loads a prophet with their profile
+func (s *UserManagementService) GetProphetProfile(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.repo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.Role != string(domain.USER_ROLE_PROPHET) {
+		return nil, domain.ErrNotProphet
+	}
+	return user, nil
+}
+
+func (s *UserManagementService) UpdateProphetProfile(ctx context.Context, userID string, input domain.UpdateProphetProfileInput) (*domain.User, error) {
+	if _, err := s.GetProphetProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	update := map[string]interface{}{"profile.updated_at": time.Now()}
+	if input.Bio != nil {
+		bio := strings.TrimSpace(*input.Bio)
+		if err := domain.ValidateBio(bio); err != nil {
+			return nil, err
+		}
+		update["profile.bio"] = bio
+	}
+	if input.Specialties != nil {
+		specialties, err := domain.ParseSpecialties(*input.Specialties)
+		if err != nil {
+			return nil, err
+		}
+		update["profile.specialties"] = specialties
+	}
+	if input.Languages != nil {
+		languages, err := domain.ParseLanguages(*input.Languages)
+		if err != nil {
+			return nil, err
+		}
+		update["profile.languages"] = languages
+	}
+	if input.YearsOfExperience != nil {
+		if err := domain.ValidateYearsOfExperience(*input.YearsOfExperience); err != nil {
+			return nil, err
+		}
+		update["profile.years_of_experience"] = *input.YearsOfExperience
+	}
+	if input.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*input.AvatarURL)
+		if err := domain.ValidateAvatarURL(avatarURL); err != nil {
+			return nil, err
+		}
+		update["profile.avatar_url"] = avatarURL
+	}
+
+	return s.repo.Update(ctx, userID, update)
+}
+
+// SetProphetOnline marks a prophet online or offline. Going online again
+// before OnlineTimeout keeps them shown as online. Suspended prophets cannot
+// go online.
+func (s *UserManagementService) SetProphetOnline(ctx context.Context, userID string, online bool) (*domain.User, error) {
+	user, err := s.GetProphetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if online && user.Suspension != nil {
+		return nil, domain.ErrProphetSuspended
+	}
+	return s.repo.Update(ctx, userID, map[string]interface{}{
+		"profile.online":       online,
+		"profile.last_seen_at": time.Now(),
+	})
+}
+
+// SetProphetVerified gives or takes away a prophet's verified badge
+func (s *UserManagementService) SetProphetVerified(ctx context.Context, userID string, verified bool) (*domain.User, error) {
+	if _, err := s.GetProphetProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, userID, map[string]interface{}{
+		"profile.verified":   verified,
+		"profile.updated_at": time.Now(),
+	})
+}
+
+// MapProphetSummaries summarises the prophets among userIDs for listing
+// alongside their courses; other IDs are left out
+func (s *UserManagementService) MapProphetSummaries(ctx context.Context, userIDs []string) ([]*domain.ProphetSummary, error) {
+	prophets, err := s.repo.FindProphetsByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	summaries := make([]*domain.ProphetSummary, 0, len(prophets))
+	for _, prophet := range prophets {
+		summaries = append(summaries, domain.NewProphetSummary(prophet, now))
+	}
+	return summaries, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/wnmay/horo/services/user-management-service/internal/domain"
 	"github.com/wnmay/horo/services/user-management-service/internal/ports"
@@ -15,13 +16,18 @@ import (
 type UserManagementService struct {
 	authClient ports.AuthPort
 	repo       ports.UserRepositoryPort
+	sessions   ports.SessionRepositoryPort
 }
 
-func NewUserManagementService(authCleint ports.AuthPort, repo ports.UserRepositoryPort) *UserManagementService {
-	return &UserManagementService{authClient: authCleint, repo: repo}
+func NewUserManagementService(authCleint ports.AuthPort, repo ports.UserRepositoryPort, sessions ports.SessionRepositoryPort) *UserManagementService {
+	return &UserManagementService{authClient: authCleint, repo: repo, sessions: sessions}
 }
 
 func (s *UserManagementService) Register(ctx context.Context, idToken, fullName, role string) error {
+	if _, err := domain.ParseRegisterRole(role); err != nil {
+		return err
+	}
+
 	claims, err := s.authClient.VerifyIDToken(ctx, idToken)
 	if err != nil {
 		return fmt.Errorf("invalid firebase token: %w", err)
@@ -61,6 +67,38 @@ func (s *UserManagementService) UpdateFullName(ctx context.Context, userID strin
 	return s.repo.Update(ctx, userID, update)
 }
 
+// SetUserRole changes a user's role both in their token claims and in the
+// database. Their platform sessions are revoked, so they sign in again to get
+// tokens with the new role; Firebase tokens only get it once refreshed.
+func (s *UserManagementService) SetUserRole(ctx context.Context, userID string, role string) (*domain.User, error) {
+	parsed, err := domain.ParseUserRole(role)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	customClaims := map[string]interface{}{
+		"role": string(parsed),
+	}
+	if err := s.authClient.SetCustomUserClaims(ctx, userID, customClaims); err != nil {
+		return nil, fmt.Errorf("failed to set custom claims: %w", err)
+	}
+	updated, err := s.repo.Update(ctx, userID, map[string]interface{}{"role": string(parsed)})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return updated, nil
+}
+
 func (s *UserManagementService) GetProphetNames(ctx context.Context, userIDs []string) ([]*domain.ProphetName, error) {
 	prophetNames, err := s.repo.FindProphetNames(ctx, userIDs)
 	if err != nil {
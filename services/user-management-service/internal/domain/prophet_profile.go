@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Specialty is a kind of reading a prophet gives, matching course-service's
+// course types
+type Specialty string
+
+const (
+	SPECIALTY_LOVE            Specialty = "love"
+	SPECIALTY_WORK            Specialty = "work"
+	SPECIALTY_MONEY           Specialty = "money"
+	SPECIALTY_LUCK            Specialty = "luck"
+	SPECIALTY_STUDY           Specialty = "study"
+	SPECIALTY_PERSONAL_GROWTH Specialty = "personal_growth"
+)
+
+var specialties = map[Specialty]bool{
+	SPECIALTY_LOVE:            true,
+	SPECIALTY_WORK:            true,
+	SPECIALTY_MONEY:           true,
+	SPECIALTY_LUCK:            true,
+	SPECIALTY_STUDY:           true,
+	SPECIALTY_PERSONAL_GROWTH: true,
+}
+
+const (
+	MaxBioLength         = 2000
+	MaxLanguages         = 10
+	MaxYearsOfExperience = 80
+	// OnlineTimeout is how long a prophet who went online stays shown as
+	// online without refreshing their status
+	OnlineTimeout = 15 * time.Minute
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrNotProphet        = errors.New("user is not a prophet")
+	ErrInvalidSpecialty  = errors.New("specialties must be course types: love, work, money, luck, study or personal_growth")
+	ErrBioTooLong        = errors.New("bio must be at most 2000 characters")
+	ErrTooManyLanguages  = errors.New("at most 10 languages")
+	ErrInvalidExperience = errors.New("years of experience must be between 0 and 80")
+	ErrInvalidAvatarURL  = errors.New("avatar URL must be an http or https URL")
+)
+
+// ProphetProfile is what customers see about a prophet besides their name.
+// Verified is set by admins; Online by the prophet.
+type ProphetProfile struct {
+	Bio               string
+	Specialties       []Specialty
+	Languages         []string
+	YearsOfExperience int
+	AvatarURL         string
+	Verified          bool
+	Online            bool
+	LastSeenAt        time.Time
+	UpdatedAt         time.Time
+}
+
+// IsOnline reports whether the prophet went online and has refreshed their
+// status within OnlineTimeout
+func (p *ProphetProfile) IsOnline(now time.Time) bool {
+	return p.Online && now.Sub(p.LastSeenAt) < OnlineTimeout
+}
+
+// ProphetSummary is a prophet's name with the parts of their profile shown
+// alongside their courses
+type ProphetSummary struct {
+	UserID            string
+	Name              string
+	AvatarURL         string
+	Verified          bool
+	Online            bool
+	Specialties       []Specialty
+	Languages         []string
+	YearsOfExperience int
+}
+
+// NewProphetSummary summarises a prophet; prophets who never filled in their
+// profile only have a name
+func NewProphetSummary(user *User, now time.Time) *ProphetSummary {
+	summary := &ProphetSummary{UserID: user.ID, Name: user.FullName}
+	if p := user.Profile; p != nil {
+		summary.AvatarURL = p.AvatarURL
+		summary.Verified = p.Verified
+		summary.Online = p.IsOnline(now)
+		summary.Specialties = p.Specialties
+		summary.Languages = p.Languages
+		summary.YearsOfExperience = p.YearsOfExperience
+	}
+	return summary
+}
+
+// UpdateProphetProfileInput holds the fields a prophet edits on their
+// profile; nil fields are left as they are
+type UpdateProphetProfileInput struct {
+	Bio               *string
+	Specialties       *[]string
+	Languages         *[]string
+	YearsOfExperience *int
+	AvatarURL         *string
+}
+
+// ParseSpecialties checks each specialty is a course type, dropping repeats
+func ParseSpecialties(values []string) ([]Specialty, error) {
+	parsed := make([]Specialty, 0, len(values))
+	seen := make(map[Specialty]bool, len(values))
+	for _, v := range values {
+		s := Specialty(strings.ToLower(strings.TrimSpace(v)))
+		if !specialties[s] {
+			return nil, ErrInvalidSpecialty
+		}
+		if !seen[s] {
+			seen[s] = true
+			parsed = append(parsed, s)
+		}
+	}
+	return parsed, nil
+}
+
+// ParseLanguages trims the languages a prophet reads in, dropping blanks
+// and repeats
+func ParseLanguages(values []string) ([]string, error) {
+	parsed := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		lang := strings.TrimSpace(v)
+		key := strings.ToLower(lang)
+		if lang == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		parsed = append(parsed, lang)
+	}
+	if len(parsed) > MaxLanguages {
+		return nil, ErrTooManyLanguages
+	}
+	return parsed, nil
+}
+
+func ValidateBio(bio string) error {
+	if len([]rune(bio)) > MaxBioLength {
+		return ErrBioTooLong
+	}
+	return nil
+}
+
+func ValidateYearsOfExperience(years int) error {
+	if years < 0 || years > MaxYearsOfExperience {
+		return ErrInvalidExperience
+	}
+	return nil
+}
+
+func ValidateAvatarURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidAvatarURL
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid Firebase ID token")
+	ErrInvalidSession = errors.New("invalid, expired or revoked refresh token")
+)
+
+// Session is a platform refresh token issued to a user. It is stored under
+// the token's ID so it can be used once, then revoked and replaced.
+type Session struct {
+	ID        string
+	UserID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Suspension is why and when an admin suspended a prophet. Course-service
+// hides a suspended prophet's courses until they are reinstated.
+type Suspension struct {
+	Reason      string
+	SuspendedBy string
+	SuspendedAt time.Time
+}
+
+var (
+	ErrSuspensionReasonRequired = errors.New("a reason is required to suspend a prophet")
+	ErrProphetSuspended         = errors.New("prophet is suspended")
+)
+
+// UserSearch narrows the users admins list; empty fields match every user
+type UserSearch struct {
+	// Query matches part of a user's name or email, ignoring case
+	Query     string
+	Role      UserRole
+	Suspended *bool
+}
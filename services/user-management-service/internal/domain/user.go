@@ -1,19 +1,50 @@
 // internal/domain/user.go
 package domain
 
+import "errors"
+
 type UserRole string
 
 const (
 	USER_ROLE_PROPHET  UserRole = "prophet"
 	USER_ROLE_CUSTOMER UserRole = "customer"
+	USER_ROLE_ADMIN    UserRole = "admin"
 	USER_ROLE_UNKNOWN  UserRole = "unknown"
 )
 
+var (
+	ErrInvalidRole         = errors.New("role must be customer, prophet or admin")
+	ErrInvalidRegisterRole = errors.New("role must be customer or prophet")
+)
+
+// ParseUserRole checks role is one a user can have
+func ParseUserRole(role string) (UserRole, error) {
+	switch r := UserRole(role); r {
+	case USER_ROLE_CUSTOMER, USER_ROLE_PROPHET, USER_ROLE_ADMIN:
+		return r, nil
+	}
+	return "", ErrInvalidRole
+}
+
+// ParseRegisterRole checks role is one users can sign up with; only admins
+// make other users admins
+func ParseRegisterRole(role string) (UserRole, error) {
+	switch r := UserRole(role); r {
+	case USER_ROLE_CUSTOMER, USER_ROLE_PROPHET:
+		return r, nil
+	}
+	return "", ErrInvalidRegisterRole
+}
+
 type User struct {
 	ID       string
 	FullName string
 	Email    string
 	Role     string
+	// Profile is only kept for prophets, once they first edit it
+	Profile *ProphetProfile
+	// Suspension is set while an admin has the prophet suspended
+	Suspension *Suspension
 }
 
 type ProphetName struct {
@@ -3,6 +3,8 @@ package ports
 
 import (
 	"context"
+
+	"github.com/wnmay/horo/shared/jwt"
 )
 
 type Claims struct {
@@ -19,4 +21,7 @@ type AuthPort interface {
 type AuthService interface {
 	GetClaims(ctx context.Context, token string) (*Claims, error)
 	SetCustomClaims(ctx context.Context, uid string, customClaims map[string]interface{}) error
+	IssueSession(ctx context.Context, idToken string) (*jwt.TokenPair, error)
+	RefreshSession(ctx context.Context, refreshToken string) (*jwt.TokenPair, error)
+	RevokeSession(ctx context.Context, refreshToken string, all bool) error
 }
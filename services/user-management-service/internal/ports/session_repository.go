@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+)
+
+type SessionRepositoryPort interface {
+	Save(ctx context.Context, session domain.Session) error
+	// Revoke revokes a session, reporting whether it was still active
+	Revoke(ctx context.Context, sessionID string, at time.Time) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID string, at time.Time) error
+}
@@ -4,14 +4,24 @@ import (
 	"context"
 
 	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 )
 
 type UserManagementService interface {
 	Register(ctx context.Context, idToken, fullName, role string) error
 	GetMe(ctx context.Context, userID string) (*domain.User, error)
 	UpdateFullName(ctx context.Context, userID string, newUsername string) (*domain.User, error)
+	SetUserRole(ctx context.Context, userID string, role string) (*domain.User, error)
 	GetProphetNames(ctx context.Context, userIDs []string) ([]*domain.ProphetName, error)
 	GetProphetName(ctx context.Context, userID string) (string, error)
 	SearchProphetIdsByName(ctx context.Context, prophetName string) ([]*domain.ProphetName, error)
 	MapUserNames(ctx context.Context, userIDs []string) ([]*domain.UserName, error)
+	GetProphetProfile(ctx context.Context, userID string) (*domain.User, error)
+	UpdateProphetProfile(ctx context.Context, userID string, input domain.UpdateProphetProfileInput) (*domain.User, error)
+	SetProphetOnline(ctx context.Context, userID string, online bool) (*domain.User, error)
+	SetProphetVerified(ctx context.Context, userID string, verified bool) (*domain.User, error)
+	MapProphetSummaries(ctx context.Context, userIDs []string) ([]*domain.ProphetSummary, error)
+	SearchUsers(ctx context.Context, search domain.UserSearch, page contract.PageRequest) (contract.Page[*domain.User], error)
+	SuspendProphet(ctx context.Context, userID string, adminID string, reason string) (*domain.User, error)
+	ReinstateProphet(ctx context.Context, userID string) (*domain.User, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/wnmay/horo/services/user-management-service/internal/domain"
+	"github.com/wnmay/horo/shared/contract"
 )
 
 type UserRepositoryPort interface {
@@ -13,4 +14,9 @@ type UserRepositoryPort interface {
 	FindProphetNames(ctx context.Context, userIDs []string) ([]*domain.ProphetName, error)
 	SearchProphetIdsByName(ctx context.Context, prophetName string) ([]*domain.ProphetName, error)
 	MapUserNames(ctx context.Context, userIDs []string) ([]*domain.UserName, error)
+	FindProphetsByIDs(ctx context.Context, userIDs []string) ([]*domain.User, error)
+	// Search pages through the users matching search, ordered by ID
+	Search(ctx context.Context, search domain.UserSearch, page contract.PageRequest) (contract.Page[*domain.User], error)
+	// SetSuspension suspends the user, or reinstates them when suspension is nil
+	SetSuspension(ctx context.Context, userID string, suspension *domain.Suspension) (*domain.User, error)
 }
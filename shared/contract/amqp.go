@@ -1,20 +1,32 @@
 package contract
 
 // AmqpMessage is the message structure for AMQP.
+// MessageID is stamped by the publisher when left empty and stays the same
+// across redeliveries, so consumers can use it to detect duplicates.
 type AmqpMessage struct {
-	OwnerID string `json:"ownerId"`
-	Data    []byte `json:"data"`
+	MessageID string `json:"messageId,omitempty"`
+	OwnerID   string `json:"ownerId"`
+	Data      []byte `json:"data"`
 }
 
 // Routing keys - using consistent event/command patterns
 const (
 	OrderCreatedEvent  = "order.created"
 	OrderCompletedEvent = "order.completed"
+	OrderSessionCompletedEvent = "order.session_completed"
 	OrderPaymentBoundEvent = "order.payment.bound"
 	OrderPaidEvent = "order.paid"
+	OrderCancelledEvent = "order.cancelled"
+	OrderDisputedEvent = "order.disputed"
+	OrderDisputeResolvedEvent = "order.dispute_resolved"
+	OrderRescheduledEvent = "order.rescheduled"
+	SubscriptionRenewedEvent = "subscription.renewed"
+	SubscriptionLapsedEvent = "subscription.lapsed"
 	PaymentSuccessEvent = "payment.completed"
 	PaymentCreatedEvent = "payment.created"
 	PaymentSettledEvent = "payment.settled"
+	PaymentFailedEvent = "payment.failed"
+	PaymentRefundedEvent = "payment.refunded"
 	ChatMessageIncomingEvent = "chat.message.incoming"
 	ChatMessageOutgoingEvent = "chat.message.outgoing"
 )
\ No newline at end of file
@@ -1,2 +1,86 @@
 // http contract
-package contract
\ No newline at end of file
+package contract
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// PageRequest asks for one page of a list endpoint. Cursor is opaque to
+// clients: they pass back the previous page's NextCursor, or nothing for the
+// first page.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// Page is one page of a list endpoint. NextCursor is empty on the last page,
+// and Total is only filled in by lists that can count cheaply.
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("limit must be a positive number")
+)
+
+// ParsePageRequest reads the cursor and limit query parameters. The limit
+// defaults to DefaultPageLimit and is capped at MaxPageLimit.
+func ParsePageRequest(cursor, limit string) (PageRequest, error) {
+	page := PageRequest{Cursor: cursor, Limit: DefaultPageLimit}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return PageRequest{}, ErrInvalidLimit
+		}
+		page.Limit = min(n, MaxPageLimit)
+	}
+	return page, nil
+}
+
+// EncodeCursor turns a position in a list, usually the sort keys of the last
+// item returned, into an opaque cursor
+func EncodeCursor(position any) string {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads back a position encoded by EncodeCursor
+func DecodeCursor(cursor string, position any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, position); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// NewPage builds a page from up to limit+1 items fetched from the cursor on.
+// The extra item is not returned; it only shows there is a next page, whose
+// cursor is the position of the last item kept.
+func NewPage[T any](items []T, limit int, positionOf func(T) any) Page[T] {
+	page := Page[T]{Data: items}
+	if len(items) > limit {
+		page.Data = items[:limit]
+		page.NextCursor = EncodeCursor(positionOf(items[limit-1]))
+	}
+	if page.Data == nil {
+		page.Data = []T{}
+	}
+	return page
+}
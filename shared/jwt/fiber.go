@@ -0,0 +1,46 @@
+package jwt
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Identity headers services read the calling user from. HeaderIdentity
+// carries the gateway's signed assertion the others are set from.
+const (
+	HeaderIdentity  = "X-Identity-Token"
+	HeaderUserID    = "X-User-Id"
+	HeaderUserEmail = "X-User-Email"
+	HeaderUserRole  = "X-User-Role"
+)
+
+// IdentityFromToken sets each request's X-User-Id, X-User-Email and
+// X-User-Role headers from the identity assertion in its X-Identity-Token
+// header, replacing whatever the caller sent, so handlers reading the headers
+// only see users the gateway authenticated. Requests without an assertion are
+// passed on as anonymous; routes that need a user already refuse requests
+// without one. Requests with an invalid or expired assertion get 401.
+func IdentityFromToken() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		headers := &c.Request().Header
+		headers.Del(HeaderUserID)
+		headers.Del(HeaderUserEmail)
+		headers.Del(HeaderUserRole)
+
+		token := c.Get(HeaderIdentity)
+		if token == "" {
+			return c.Next()
+		}
+		id, err := VerifyIdentity(token)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid identity assertion",
+			})
+		}
+
+		headers.Set(HeaderUserID, id.UserID)
+		headers.Set(HeaderUserEmail, id.Email)
+		headers.Set(HeaderUserRole, id.Role)
+		c.SetUserContext(WithIdentity(c.UserContext(), id))
+		return c.Next()
+	}
+}
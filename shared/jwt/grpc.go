@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// identityMetadataKey carries identity assertions in gRPC metadata, and
+// serviceMetadataKey service tokens
+const (
+	identityMetadataKey = "x-identity-token"
+	serviceMetadataKey  = "x-service-token"
+)
+
+// roleAdmin may make any user-scoped call
+const roleAdmin = "admin"
+
+// IssueServiceToken signs a short-lived token for a call this service makes
+// to the audience service for itself, rather than for a user. It is signed
+// with this service's own key, so no other service can make one in its name.
+func IssueServiceToken(audience string) (string, error) {
+	if config.ServicePrivateKey == nil {
+		return "", ErrNoSigningKey
+	}
+	claims := JWTClaims{
+		TokenType:        TokenTypeService,
+		RegisteredClaims: registeredClaims(config.Service, time.Now().Add(config.IdentityTTL)),
+	}
+	claims.Audience = jwt.ClaimStrings{audience}
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(config.ServicePrivateKey)
+}
+
+// VerifyServiceToken checks a service token addressed to this service against
+// the key of the service it names as its sender, and returns that service
+func VerifyServiceToken(token string) (string, error) {
+	var claims JWTClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		key, ok := config.ServicePublicKeys[claims.Subject]
+		if !ok {
+			return nil, fmt.Errorf("no key for service %q", claims.Subject)
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(config.Issuer),
+		jwt.WithAudience(config.Service),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", err
+	}
+	if claims.TokenType != TokenTypeService {
+		return "", ErrWrongTokenType
+	}
+	return claims.Subject, nil
+}
+
+type serviceKey struct{}
+
+// WithService returns a copy of ctx carrying the name of the calling service
+func WithService(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+// ServiceFromContext returns the service that made a call for itself, if any
+func ServiceFromContext(ctx context.Context) (string, bool) {
+	service, ok := ctx.Value(serviceKey{}).(string)
+	return service, ok && service != ""
+}
+
+// UnaryServerIdentity verifies who a call is from: a user, by the identity
+// assertion the gateway attached, or another service calling for itself, by
+// its service token. Handlers read them with IdentityFromContext and
+// ServiceFromContext. Anonymous calls and calls with an invalid token are
+// refused as Unauthenticated.
+func UnaryServerIdentity() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if tokens := md.Get(identityMetadataKey); len(tokens) > 0 {
+			id, err := VerifyIdentity(tokens[0])
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid identity assertion")
+			}
+			return handler(WithIdentity(ctx, id), req)
+		}
+		if tokens := md.Get(serviceMetadataKey); len(tokens) > 0 {
+			service, err := VerifyServiceToken(tokens[0])
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid service token")
+			}
+			return handler(WithService(ctx, service), req)
+		}
+		return nil, status.Error(codes.Unauthenticated, "call has no identity")
+	}
+}
+
+// UnaryClientIdentity makes calls to the audience service on behalf of the
+// identity in their context, set with WithIdentity, by attaching a fresh
+// assertion for it. Calls without one are made as this service.
+func UnaryClientIdentity(audience string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		id, ok := IdentityFromContext(ctx)
+		if !ok {
+			return unaryService(ctx, audience, method, req, reply, cc, invoker, opts...)
+		}
+		token, err := IssueIdentity(*id, audience)
+		if err != nil {
+			return err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, identityMetadataKey, token)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryClientService makes calls to the audience service as this service,
+// by attaching a fresh service token
+func UnaryClientService(audience string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return unaryService(ctx, audience, method, req, reply, cc, invoker, opts...)
+	}
+}
+
+func unaryService(ctx context.Context, audience, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	token, err := IssueServiceToken(audience)
+	if err != nil {
+		return err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, serviceMetadataKey, token)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// AuthorizeUser lets a user-scoped call through if it is made for an admin or
+// one of users. Calls for anyone else are refused as PermissionDenied; a
+// service calling for itself does not stand in for a user.
+func AuthorizeUser(ctx context.Context, users ...string) error {
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "call has no identity")
+	}
+	if id.Role == roleAdmin || slices.Contains(users, id.UserID) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "call is not allowed for this user")
+}
+
+// FromService reports whether the call was made by one of services for itself
+func FromService(ctx context.Context, services ...string) bool {
+	service, ok := ServiceFromContext(ctx)
+	return ok && slices.Contains(services, service)
+}
+
+// RequireService refuses calls not made by one of services for itself
+func RequireService(ctx context.Context, services ...string) error {
+	if FromService(ctx, services...) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "call is not allowed for this caller")
+}
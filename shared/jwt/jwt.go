@@ -2,45 +2,200 @@ package jwt
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/wnmay/horo/shared/env"
 )
 
 type Config struct {
-	Secret string
-	Issuer string
+	// Platform tokens are signed with TokenPrivateKey, which only
+	// user-management has, and checked with TokenPublicKey
+	TokenPrivateKey ed25519.PrivateKey
+	TokenPublicKey  ed25519.PublicKey
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	IdentityTTL     time.Duration
+	// Service is the name of the running service, which identity assertions
+	// must be addressed to
+	Service string
+	// Identity assertions are signed with IdentityPrivateKey, which only the
+	// gateway has, and checked with IdentityPublicKey
+	IdentityPrivateKey ed25519.PrivateKey
+	IdentityPublicKey  ed25519.PublicKey
+	// Service tokens are signed with ServicePrivateKey, which only Service
+	// has, and checked with the calling service's key in ServicePublicKeys
+	ServicePrivateKey ed25519.PrivateKey
+	ServicePublicKeys map[string]ed25519.PublicKey
 }
 
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+	DefaultIdentityTTL     = 30 * time.Second
+)
+
 var config Config
 
 func Init(cfg Config) {
-	if cfg.Secret == "" {
-		panic("jwt: Secret cannot be empty")
+	if cfg.TokenPublicKey == nil {
+		panic("jwt: TokenPublicKey cannot be empty")
 	}
 	if cfg.Issuer == "" {
 		cfg.Issuer = "default-issuer"
 	}
+	if cfg.AccessTokenTTL <= 0 {
+		cfg.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if cfg.RefreshTokenTTL <= 0 {
+		cfg.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	if cfg.IdentityTTL <= 0 {
+		cfg.IdentityTTL = DefaultIdentityTTL
+	}
 	config = cfg
 }
 
+// Platform tokens are access tokens, which clients send the gateway, or
+// refresh tokens, which user-management exchanges for new pairs. Identity
+// assertions are what the gateway sends services in their place, and service
+// tokens what services send each other when calling for themselves.
+const (
+	TokenTypeAccess   = "access"
+	TokenTypeRefresh  = "refresh"
+	TokenTypeIdentity = "identity"
+	TokenTypeService  = "service"
+)
+
+var (
+	ErrWrongTokenType = errors.New("wrong token type")
+	ErrNoSigningKey   = errors.New("jwt: this service cannot sign these tokens")
+)
+
 type JWTClaims struct {
 	UserID     string `json:"user_id"`
 	ProphetID  string `json:"prophet_id"`
 	CustomerID string `json:"customer_id"`
 	Email      string `json:"email,omitempty"`
 	Role       string `json:"role"`
+	TokenType  string `json:"token_type,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func ValidateJWT(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(config.Secret), nil
+// TokenPair is a platform session: a short-lived access token sent with every
+// request, and a long-lived refresh token for getting the next pair.
+// RefreshTokenID is the refresh token's jti, under which it is stored so it
+// can be revoked.
+type TokenPair struct {
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenID        string    `json:"-"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// IssueAccessToken signs an access token carrying the user's ID and role
+func IssueAccessToken(userID, email, role string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(config.AccessTokenTTL)
+	token, err := sign(JWTClaims{
+		UserID:           userID,
+		Email:            email,
+		Role:             role,
+		TokenType:        TokenTypeAccess,
+		RegisteredClaims: registeredClaims(userID, expiresAt),
 	})
+	return token, expiresAt, err
+}
+
+// IssueTokenPair signs a new access and refresh token for the user. The
+// refresh token carries no role, so the role is looked up again on refresh.
+func IssueTokenPair(userID, email, role string) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := IssueAccessToken(userID, email, role)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresAt := time.Now().Add(config.RefreshTokenTTL)
+	refreshClaims := JWTClaims{
+		UserID:           userID,
+		TokenType:        TokenTypeRefresh,
+		RegisteredClaims: registeredClaims(userID, refreshExpiresAt),
+	}
+	refreshToken, err := sign(refreshClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenID:        refreshClaims.ID,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// ValidateAccessToken checks an access token and returns who it is for
+func ValidateAccessToken(tokenString string) (*JWTClaims, error) {
+	return validateTokenType(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken checks a refresh token's signature and expiry; whether
+// it has been revoked is up to the caller
+func ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
+	return validateTokenType(tokenString, TokenTypeRefresh)
+}
+
+func validateTokenType(tokenString, tokenType string) (*JWTClaims, error) {
+	claims, err := ExtractClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Issuer != config.Issuer {
+		return nil, fmt.Errorf("invalid issuer")
+	}
+	if claims.TokenType != tokenType || claims.UserID == "" {
+		return nil, ErrWrongTokenType
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	return claims, nil
+}
+
+func registeredClaims(userID string, expiresAt time.Time) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    config.Issuer,
+		Subject:   userID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+}
+
+// sign signs a platform token. Only user-management has the key to.
+func sign(claims JWTClaims) (string, error) {
+	if config.TokenPrivateKey == nil {
+		return "", ErrNoSigningKey
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(config.TokenPrivateKey)
+}
+
+func platformKey(*jwt.Token) (interface{}, error) {
+	return config.TokenPublicKey, nil
+}
+
+func ValidateJWT(tokenString string) (string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, platformKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
 	if err != nil {
 		return "", fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -56,12 +211,8 @@ func ValidateJWT(tokenString string) (string, error) {
 }
 
 func ExtractClaims(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(config.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, platformKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -107,3 +258,42 @@ func getFirebaseAuthClient(ctx context.Context) (*auth.Client, error) {
 	}
 	return firebaseAuthClient, nil
 }
+
+// InitFromEnv initialises platform tokens from TOKEN_PRIVATE_KEY, which only
+// user-management is given, TOKEN_PUBLIC_KEY, JWT_ISSUER,
+// JWT_ACCESS_TTL_SECONDS and JWT_REFRESH_TTL_SECONDS; identity assertions
+// addressed to service from IDENTITY_PRIVATE_KEY, which only the gateway is
+// given, and IDENTITY_PUBLIC_KEY; and service tokens from SERVICE_PRIVATE_KEY
+// and the SERVICE_PUBLIC_KEY_<NAME> of the services it takes calls from. All
+// keys are Ed25519 and PEM encoded. It stops the service if its keys are not
+// set, unless JWT_ALLOW_DEV_KEYS=true lets it fall back to the well-known
+// local development ones.
+func InitFromEnv(service string) {
+	allowDev := env.GetBool("JWT_ALLOW_DEV_KEYS", false)
+	tokenPrivateKey, tokenPublicKey, err := keyPairFromEnv("TOKEN_PRIVATE_KEY", "TOKEN_PUBLIC_KEY", localTokenSeed, allowDev)
+	if err != nil {
+		log.Fatalf("jwt: %v", err)
+	}
+	identityPrivateKey, identityPublicKey, err := keyPairFromEnv("IDENTITY_PRIVATE_KEY", "IDENTITY_PUBLIC_KEY", localIdentitySeed, allowDev)
+	if err != nil {
+		log.Fatalf("jwt: %v", err)
+	}
+	servicePrivateKey, servicePublicKeys, err := serviceKeysFromEnv(service, allowDev)
+	if err != nil {
+		log.Fatalf("jwt: %v", err)
+	}
+	Init(Config{
+		TokenPrivateKey: tokenPrivateKey,
+		TokenPublicKey:  tokenPublicKey,
+		Issuer:          env.GetString("JWT_ISSUER", "horo"),
+		AccessTokenTTL:  time.Duration(env.GetInt("JWT_ACCESS_TTL_SECONDS", 0)) * time.Second,
+		RefreshTokenTTL: time.Duration(env.GetInt("JWT_REFRESH_TTL_SECONDS", 0)) * time.Second,
+		IdentityTTL:     time.Duration(env.GetInt("JWT_IDENTITY_TTL_SECONDS", 0)) * time.Second,
+
+		Service:            service,
+		IdentityPrivateKey: identityPrivateKey,
+		IdentityPublicKey:  identityPublicKey,
+		ServicePrivateKey:  servicePrivateKey,
+		ServicePublicKeys:  servicePublicKeys,
+	})
+}
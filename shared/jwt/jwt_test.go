@@ -0,0 +1,167 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return public, private
+}
+
+// initTest configures the package as service, holding the private keys given
+func initTest(t *testing.T, service string, tokenKey, serviceKey ed25519.PrivateKey, tokenPublic ed25519.PublicKey, servicePublic map[string]ed25519.PublicKey) {
+	t.Helper()
+	previous := config
+	t.Cleanup(func() { config = previous })
+	Init(Config{
+		TokenPrivateKey:   tokenKey,
+		TokenPublicKey:    tokenPublic,
+		Issuer:            "horo-test",
+		Service:           service,
+		ServicePrivateKey: serviceKey,
+		ServicePublicKeys: servicePublic,
+	})
+}
+
+func TestPlatformTokens(t *testing.T) {
+	tokenPublic, tokenKey := newKey(t)
+	initTest(t, ServiceUserManagement, tokenKey, nil, tokenPublic, nil)
+
+	pair, err := IssueTokenPair("user-1", "user@example.com", "admin")
+	if err != nil {
+		t.Fatalf("IssueTokenPair() error = %v", err)
+	}
+	claims, err := ValidateAccessToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Role != "admin" {
+		t.Errorf("ValidateAccessToken() = %+v", claims)
+	}
+	if _, err := ValidateAccessToken(pair.RefreshToken); !errors.Is(err, ErrWrongTokenType) {
+		t.Errorf("ValidateAccessToken(refresh token) error = %v, want ErrWrongTokenType", err)
+	}
+
+	// Other services only have the public key
+	initTest(t, ServiceOrder, nil, nil, tokenPublic, nil)
+	if _, _, err := IssueAccessToken("user-1", "user@example.com", "admin"); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("IssueAccessToken() without the private key error = %v, want ErrNoSigningKey", err)
+	}
+	if _, err := ValidateAccessToken(pair.AccessToken); err != nil {
+		t.Errorf("ValidateAccessToken() with the public key error = %v", err)
+	}
+
+	forged := func(method jwt.SigningMethod, key interface{}) string {
+		token, err := jwt.NewWithClaims(method, JWTClaims{
+			UserID:           "user-1",
+			Role:             "admin",
+			TokenType:        TokenTypeAccess,
+			RegisteredClaims: registeredClaims("user-1", time.Now().Add(time.Hour)),
+		}).SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return token
+	}
+	_, otherKey := newKey(t)
+	for name, token := range map[string]string{
+		"HS256":     forged(jwt.SigningMethodHS256, []byte("horo-local-jwt-secret")),
+		"other key": forged(jwt.SigningMethodEdDSA, otherKey),
+	} {
+		if _, err := ValidateAccessToken(token); err == nil {
+			t.Errorf("ValidateAccessToken(%s) accepted a forged token", name)
+		}
+	}
+}
+
+func TestServiceTokens(t *testing.T) {
+	tokenPublic, _ := newKey(t)
+	orderPublic, orderKey := newKey(t)
+	coursePublic, courseKey := newKey(t)
+	publicKeys := map[string]ed25519.PublicKey{ServiceOrder: orderPublic, ServiceCourse: coursePublic}
+
+	initTest(t, ServiceOrder, nil, orderKey, tokenPublic, publicKeys)
+	toPayment, err := IssueServiceToken(ServicePayment)
+	if err != nil {
+		t.Fatalf("IssueServiceToken() error = %v", err)
+	}
+	toCourse, err := IssueServiceToken(ServiceCourse)
+	if err != nil {
+		t.Fatalf("IssueServiceToken() error = %v", err)
+	}
+
+	// A token naming order-service but signed with course-service's key
+	initTest(t, ServiceCourse, nil, courseKey, tokenPublic, publicKeys)
+	config.Service = ServiceOrder
+	impersonated, err := IssueServiceToken(ServicePayment)
+	if err != nil {
+		t.Fatalf("IssueServiceToken() error = %v", err)
+	}
+
+	initTest(t, ServicePayment, nil, nil, tokenPublic, publicKeys)
+	if service, err := VerifyServiceToken(toPayment); err != nil || service != ServiceOrder {
+		t.Errorf("VerifyServiceToken() = %q, %v, want %q", service, err, ServiceOrder)
+	}
+	if _, err := VerifyServiceToken(toCourse); err == nil {
+		t.Error("VerifyServiceToken() accepted a token addressed to another service")
+	}
+	if _, err := VerifyServiceToken(impersonated); err == nil {
+		t.Error("VerifyServiceToken() accepted a token signed with another service's key")
+	}
+
+	// Services with no key configured are not trusted
+	initTest(t, ServicePayment, nil, nil, tokenPublic, map[string]ed25519.PublicKey{ServiceCourse: coursePublic})
+	if _, err := VerifyServiceToken(toPayment); err == nil {
+		t.Error("VerifyServiceToken() accepted a token from a service with no key")
+	}
+}
+
+func TestAuthorizeUser(t *testing.T) {
+	asUser := func(userID, role string) context.Context {
+		return WithIdentity(context.Background(), &Identity{UserID: userID, Role: role})
+	}
+	asService := WithService(context.Background(), ServiceCourse)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{name: "listed user", ctx: asUser("user-1", "customer"), want: codes.OK},
+		{name: "admin", ctx: asUser("admin-1", roleAdmin), want: codes.OK},
+		{name: "other user", ctx: asUser("user-2", "customer"), want: codes.PermissionDenied},
+		{name: "service", ctx: asService, want: codes.Unauthenticated},
+		{name: "anonymous", ctx: context.Background(), want: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(AuthorizeUser(tt.ctx, "user-1")); got != tt.want {
+				t.Errorf("AuthorizeUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := RequireService(asService, ServiceOrder); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("RequireService(other service) = %v, want PermissionDenied", err)
+	}
+	if err := RequireService(asService, ServiceOrder, ServiceCourse); err != nil {
+		t.Errorf("RequireService(listed service) = %v", err)
+	}
+	if err := RequireService(asUser("admin-1", roleAdmin), ServiceCourse); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("RequireService(admin) = %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/wnmay/horo/shared/env"
+)
+
+// Seeds the key pairs are derived from when none are configured, so services
+// run locally without configuration; deployments must set their own
+const (
+	localTokenSeed    = "horo-local-token-key"
+	localIdentitySeed = "horo-local-identity-key"
+	localServiceSeed  = "horo-local-service-key/"
+)
+
+// services are the services that may call each other for themselves
+var services = []string{
+	ServiceAPIGateway,
+	ServiceChat,
+	ServiceCourse,
+	ServiceOrder,
+	ServicePayment,
+	ServiceUserManagement,
+}
+
+// keyPairFromEnv reads an Ed25519 key pair from the PEM encoded privateVar,
+// which only the service the keys belong to is given, and publicVar. The
+// public key defaults to the private key's; with neither set, allowDev falls
+// back to the local key pair derived from seed.
+func keyPairFromEnv(privateVar, publicVar, seed string, allowDev bool) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	var privateKey ed25519.PrivateKey
+	var publicKey ed25519.PublicKey
+	var err error
+
+	if data := env.GetString(privateVar, ""); data != "" {
+		privateKey, err = ParsePrivateKey([]byte(data))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", privateVar, err)
+		}
+		publicKey = privateKey.Public().(ed25519.PublicKey)
+	}
+	if data := env.GetString(publicVar, ""); data != "" {
+		publicKey, err = ParsePublicKey([]byte(data))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", publicVar, err)
+		}
+	}
+	if privateKey != nil && !privateKey.Public().(ed25519.PublicKey).Equal(publicKey) {
+		return nil, nil, fmt.Errorf("%s does not match %s", publicVar, privateVar)
+	}
+
+	if publicKey == nil {
+		if !allowDev {
+			return nil, nil, fmt.Errorf("%s is not set; set JWT_ALLOW_DEV_KEYS=true to use the local development key", publicVar)
+		}
+		log.Printf("jwt: %s is not set, using the local development key", publicVar)
+		privateKey, publicKey = localKeyPair(seed)
+	}
+	return privateKey, publicKey, nil
+}
+
+// serviceKeysFromEnv reads the key this service signs its own calls with
+// from SERVICE_PRIVATE_KEY, and the keys of the services it trusts calls
+// from from SERVICE_PUBLIC_KEY_<NAME>, such as SERVICE_PUBLIC_KEY_ORDER_SERVICE.
+// Services without a key are not trusted, unless allowDev falls back to their
+// local key.
+func serviceKeysFromEnv(service string, allowDev bool) (ed25519.PrivateKey, map[string]ed25519.PublicKey, error) {
+	var privateKey ed25519.PrivateKey
+	if data := env.GetString("SERVICE_PRIVATE_KEY", ""); data != "" {
+		key, err := ParsePrivateKey([]byte(data))
+		if err != nil {
+			return nil, nil, fmt.Errorf("SERVICE_PRIVATE_KEY: %w", err)
+		}
+		privateKey = key
+	} else if allowDev {
+		log.Println("jwt: SERVICE_PRIVATE_KEY is not set, using the local development key")
+		privateKey, _ = localKeyPair(localServiceSeed + service)
+	} else {
+		return nil, nil, errors.New("SERVICE_PRIVATE_KEY is not set; set JWT_ALLOW_DEV_KEYS=true to use the local development key")
+	}
+
+	publicKeys := make(map[string]ed25519.PublicKey)
+	for _, name := range services {
+		variable := servicePublicKeyVar(name)
+		if data := env.GetString(variable, ""); data != "" {
+			key, err := ParsePublicKey([]byte(data))
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", variable, err)
+			}
+			publicKeys[name] = key
+		} else if allowDev {
+			_, publicKeys[name] = localKeyPair(localServiceSeed + name)
+		}
+	}
+	return privateKey, publicKeys, nil
+}
+
+// servicePublicKeyVar is the variable a service's public key is read from
+func servicePublicKeyVar(service string) string {
+	return "SERVICE_PUBLIC_KEY_" + strings.ToUpper(strings.ReplaceAll(service, "-", "_"))
+}
+
+func localKeyPair(seed string) (ed25519.PrivateKey, ed25519.PublicKey) {
+	sum := sha256.Sum256([]byte(seed))
+	privateKey := ed25519.NewKeyFromSeed(sum[:])
+	return privateKey, privateKey.Public().(ed25519.PublicKey)
+}
+
+// ParsePrivateKey reads a PEM encoded PKCS #8 Ed25519 private key, as
+// written by `openssl genpkey -algorithm ed25519`
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an Ed25519 key")
+	}
+	return key, nil
+}
+
+// ParsePublicKey reads a PEM encoded PKIX Ed25519 public key, as written by
+// `openssl pkey -pubout`
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an Ed25519 key")
+	}
+	return key, nil
+}
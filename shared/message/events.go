@@ -1,5 +1,7 @@
 package message
 
+import "github.com/wnmay/horo/shared/money"
+
 const (
 	CreatePaymentQueue       = "create_payment_queue"
 	UpdateOrderStatusQueue   = "update_order_status_queue"
@@ -10,49 +12,189 @@ const (
 	SettlePaymentQueue       = "settle_payment_queue"
 	NotifyCreatePayment      = "notify_create_payment"
 	NotifyOrderCompleted     = "notify_order_completed"
+	RefundPaymentQueue       = "refund_payment_queue"
+	CourseCompletionQueue    = "course_completion_queue"
+	CancelRefundedOrderQueue = "cancel_refunded_order_queue"
 )
 
 // ---- DATA STRUCTURES ----
 
 type OrderData struct {
-	OrderID    string  `json:"orderId"`
-	CustomerID string  `json:"customerId"`
-	Status     string  `json:"status"`
-	Amount     float64 `json:"amount"`
+	OrderID    string `json:"orderId"`
+	CustomerID string `json:"customerId"`
+	Status     string `json:"status"`
+	// Amount and Currency are what the customer is charged. Price and
+	// PriceCurrency are the course's list price, converted at ExchangeRate
+	// after the promotion discount is taken off. Discount is in Currency.
+	Amount          money.Decimal `json:"amount"`
+	Currency        string        `json:"currency"`
+	Price           money.Decimal `json:"price"`
+	PriceCurrency   string        `json:"priceCurrency"`
+	ExchangeRate    money.Decimal `json:"exchangeRate"`
+	Discount        money.Decimal `json:"discount"`
+	CouponCode      string        `json:"couponCode,omitempty"`
+	CourseID        string        `json:"courseId"`
+	CourseName      string        `json:"courseName"`
+	CourseType      string        `json:"courseType,omitempty"`
+	ProphetID       string        `json:"prophetId"`
+	DurationMinutes int           `json:"durationMinutes"`
+	RoomID          string        `json:"roomId"`
+	// Sessions is how many sessions the order bought; bundles sell several
+	Sessions int `json:"sessions"`
+	// Reserved session, times in RFC3339 UTC
+	BookingID       string `json:"bookingId,omitempty"`
+	SessionStart    string `json:"sessionStart,omitempty"`
+	SessionEnd      string `json:"sessionEnd,omitempty"`
+	SessionTimezone string `json:"sessionTimezone,omitempty"`
 }
 
 type OrderCompletedData struct {
-	OrderID     string `json:"orderId"`
-	CourseID    string `json:"courseId"`
-	CourseName  string `json:"courseName"`
-	OrderStatus string `json:"orderStatus"`
-	ProphetID   string `json:"prophetId"`
-	CustomerID  string `json:"customerId"`
-	RoomID      string `json:"roomId"`
+	OrderID     string        `json:"orderId"`
+	CourseID    string        `json:"courseId"`
+	CourseName  string        `json:"courseName"`
+	CourseType  string        `json:"courseType,omitempty"`
+	OrderStatus string        `json:"orderStatus"`
+	ProphetID   string        `json:"prophetId"`
+	CustomerID  string        `json:"customerId"`
+	RoomID      string        `json:"roomId"`
+	Amount      money.Decimal `json:"amount"`
+	Currency    string        `json:"currency"`
+	// Session is the number of the session just completed, out of Sessions.
+	// Bundles publish order.session_completed for every session but the last.
+	Session  int `json:"session"`
+	Sessions int `json:"sessions"`
+	// SubscriptionID is set on orders billing a subscription period
+	SubscriptionID string `json:"subscriptionId,omitempty"`
 }
 
 type OrderPaymentBoundData struct {
-	OrderID       string  `json:"orderId"`
-	PaymentID     string  `json:"paymentId"`
-	RoomID        string  `json:"roomId"`
-	CustomerID    string  `json:"customerId"`
-	OrderStatus   string  `json:"orderStatus"`
-	CourseID      string  `json:"courseId"`
-	CourseName    string  `json:"courseName"`
-	Amount        float64 `json:"amount"`
-	PaymentStatus string  `json:"paymentStatus"`
+	OrderID       string        `json:"orderId"`
+	PaymentID     string        `json:"paymentId"`
+	RoomID        string        `json:"roomId"`
+	CustomerID    string        `json:"customerId"`
+	OrderStatus   string        `json:"orderStatus"`
+	CourseID      string        `json:"courseId"`
+	CourseName    string        `json:"courseName"`
+	Amount        money.Decimal `json:"amount"`
+	Currency      string        `json:"currency"`
+	Discount      money.Decimal `json:"discount"`
+	CouponCode    string        `json:"couponCode,omitempty"`
+	ProphetID     string        `json:"prophetId"`
+	PaymentStatus string        `json:"paymentStatus"`
 }
 
 type OrderPaidData struct {
-	OrderID       string  `json:"orderId"`
-	PaymentID     string  `json:"paymentId"`
-	RoomID        string  `json:"roomId"`
-	CustomerID    string  `json:"customerId"`
-	CourseID      string  `json:"courseId"`
-	OrderStatus   string  `json:"orderStatus"`
-	CourseName    string  `json:"courseName"`
-	Amount        float64 `json:"amount"`
-	PaymentStatus string  `json:"paymentStatus"`
+	OrderID       string        `json:"orderId"`
+	PaymentID     string        `json:"paymentId"`
+	RoomID        string        `json:"roomId"`
+	CustomerID    string        `json:"customerId"`
+	CourseID      string        `json:"courseId"`
+	OrderStatus   string        `json:"orderStatus"`
+	CourseName    string        `json:"courseName"`
+	Amount        money.Decimal `json:"amount"`
+	Currency      string        `json:"currency"`
+	Discount      money.Decimal `json:"discount"`
+	CouponCode    string        `json:"couponCode,omitempty"`
+	ProphetID     string        `json:"prophetId"`
+	PaymentStatus string        `json:"paymentStatus"`
+}
+
+type OrderCancelledData struct {
+	OrderID        string        `json:"orderId"`
+	PaymentID      string        `json:"paymentId"`
+	RoomID         string        `json:"roomId"`
+	CustomerID     string        `json:"customerId"`
+	CourseID       string        `json:"courseId"`
+	CourseName     string        `json:"courseName"`
+	ProphetID      string        `json:"prophetId"`
+	Amount         money.Decimal `json:"amount"`
+	Currency       string        `json:"currency"`
+	OrderStatus    string        `json:"orderStatus"`
+	PreviousStatus string        `json:"previousStatus"`
+	CancelledBy    string        `json:"cancelledBy"` // customer | prophet | subscription
+	Reason         string        `json:"reason"`
+	SubscriptionID string        `json:"subscriptionId,omitempty"`
+}
+
+type OrderDisputedData struct {
+	OrderID    string        `json:"orderId"`
+	PaymentID  string        `json:"paymentId"`
+	RoomID     string        `json:"roomId"`
+	CustomerID string        `json:"customerId"`
+	CourseID   string        `json:"courseId"`
+	CourseName string        `json:"courseName"`
+	ProphetID  string        `json:"prophetId"`
+	Amount     money.Decimal `json:"amount"`
+	Currency   string        `json:"currency"`
+	Reason     string        `json:"reason"`
+	DisputedAt string        `json:"disputedAt"` // RFC3339
+}
+
+type OrderDisputeResolvedData struct {
+	OrderID     string        `json:"orderId"`
+	PaymentID   string        `json:"paymentId"`
+	RoomID      string        `json:"roomId"`
+	CustomerID  string        `json:"customerId"`
+	CourseID    string        `json:"courseId"`
+	CourseName  string        `json:"courseName"`
+	ProphetID   string        `json:"prophetId"`
+	Amount      money.Decimal `json:"amount"`
+	Currency    string        `json:"currency"`
+	OrderStatus string        `json:"orderStatus"`
+	Resolution  string        `json:"resolution"` // RELEASED | REFUNDED
+	ResolvedBy  string        `json:"resolvedBy"`
+	Note        string        `json:"note"`
+	ResolvedAt  string        `json:"resolvedAt"` // RFC3339
+}
+
+// OrderRescheduledData is published when the customer moves a booked session.
+// Times are RFC3339; PreviousStart is when the session was booked for before.
+type OrderRescheduledData struct {
+	OrderID         string `json:"orderId"`
+	RoomID          string `json:"roomId"`
+	CustomerID      string `json:"customerId"`
+	CourseID        string `json:"courseId"`
+	CourseName      string `json:"courseName"`
+	ProphetID       string `json:"prophetId"`
+	BookingID       string `json:"bookingId"`
+	Session         int    `json:"session"`
+	Sessions        int    `json:"sessions"`
+	PreviousStart   string `json:"previousStart,omitempty"`
+	SessionStart    string `json:"sessionStart"`
+	SessionEnd      string `json:"sessionEnd"`
+	SessionTimezone string `json:"sessionTimezone"`
+}
+
+// SubscriptionData is published with subscription.renewed and
+// subscription.lapsed. A renewal carries the period just paid for and its
+// order; a lapse carries the reason the subscription ended.
+type SubscriptionData struct {
+	SubscriptionID string        `json:"subscriptionId"`
+	CustomerID     string        `json:"customerId"`
+	ProphetID      string        `json:"prophetId"`
+	CourseID       string        `json:"courseId"`
+	CourseName     string        `json:"courseName"`
+	RoomID         string        `json:"roomId"`
+	Status         string        `json:"status"`
+	Period         int           `json:"period"`
+	PeriodStart    string        `json:"periodStart,omitempty"`
+	PeriodEnd      string        `json:"periodEnd,omitempty"`
+	OrderID        string        `json:"orderId,omitempty"`
+	Amount         money.Decimal `json:"amount"`
+	Currency       string        `json:"currency"`
+	Reason         string        `json:"reason,omitempty"` // cancelled | unpaid
+}
+
+type PaymentRefundedData struct {
+	PaymentID string        `json:"paymentId"`
+	OrderID   string        `json:"orderId"`
+	ProphetID string        `json:"prophetId"`
+	Status    string        `json:"status"`
+	Amount    money.Decimal `json:"amount"` // refunded; bundles cancelled part way keep their settled sessions
+	Currency  string        `json:"currency"`
+	// SettlementReversed is true when the prophet had already been credited
+	// and the refund took the amount back out of their balance
+	SettlementReversed bool `json:"settlementReversed"`
 }
 
 type PaymentSuccessData struct {
@@ -69,13 +211,30 @@ type ChatMessageIncomingData struct {
 }
 
 type PaymentPublishedData struct {
-	PaymentID  string  `json:"paymentId"`
-	OrderID    string  `json:"orderId"`
-	ProphetID  string  `json:"prophetId"`
-	CourseID   string  `json:"courseId"`
-	CustomerID string  `json:"customerId"`
-	Status     string  `json:"status"`
-	Amount     float64 `json:"amount"`
+	PaymentID  string        `json:"paymentId"`
+	OrderID    string        `json:"orderId"`
+	ProphetID  string        `json:"prophetId"`
+	CourseID   string        `json:"courseId"`
+	CustomerID string        `json:"customerId"`
+	Status     string        `json:"status"`
+	Amount     money.Decimal `json:"amount"`
+	Currency   string        `json:"currency"`
+}
+
+// PaymentSettledData reports how a settled payment was split between the
+// platform fee and the prophet. Amount is the gross, kept for older consumers.
+type PaymentSettledData struct {
+	PaymentID   string        `json:"paymentId"`
+	OrderID     string        `json:"orderId"`
+	ProphetID   string        `json:"prophetId"`
+	CourseType  string        `json:"courseType,omitempty"`
+	Status      string        `json:"status"`
+	Amount      money.Decimal `json:"amount"`
+	GrossAmount money.Decimal `json:"grossAmount"`
+	FeeAmount   money.Decimal `json:"feeAmount"`
+	NetAmount   money.Decimal `json:"netAmount"`
+	Currency    string        `json:"currency"`
+	FeeRuleID   string        `json:"feeRuleId,omitempty"`
 }
 
 type ChatMessageOutgoingData struct {
@@ -106,25 +265,76 @@ type OrderCompletedNotificationData struct {
 }
 
 type OrderPaymentBoundNotificationData struct {
-	OrderID       string  `json:"orderId"`
-	PaymentID     string  `json:"paymentId"`
-	RoomID        string  `json:"roomId"`
-	CustomerID    string  `json:"customerId"`
-	CourseID      string  `json:"courseId"`
-	OrderStatus   string  `json:"orderStatus"`
-	CourseName    string  `json:"courseName"`
-	Amount        float64 `json:"amount"`
-	PaymentStatus string  `json:"paymentStatus"`
+	OrderID       string        `json:"orderId"`
+	PaymentID     string        `json:"paymentId"`
+	RoomID        string        `json:"roomId"`
+	CustomerID    string        `json:"customerId"`
+	CourseID      string        `json:"courseId"`
+	OrderStatus   string        `json:"orderStatus"`
+	CourseName    string        `json:"courseName"`
+	Amount        money.Decimal `json:"amount"`
+	Currency      string        `json:"currency"`
+	Discount      money.Decimal `json:"discount"`
+	CouponCode    string        `json:"couponCode,omitempty"`
+	PaymentStatus string        `json:"paymentStatus"`
 }
 
 type OrderPaidNotificationData struct {
-	OrderID       string  `json:"orderId"`
-	PaymentID     string  `json:"paymentId"`
-	RoomID        string  `json:"roomId"`
-	CustomerID    string  `json:"customerId"`
-	CourseID      string  `json:"courseId"`
-	OrderStatus   string  `json:"orderStatus"`
-	CourseName    string  `json:"courseName"`
-	Amount        float64 `json:"amount"`
-	PaymentStatus string  `json:"paymentStatus"`
+	OrderID       string        `json:"orderId"`
+	PaymentID     string        `json:"paymentId"`
+	RoomID        string        `json:"roomId"`
+	CustomerID    string        `json:"customerId"`
+	CourseID      string        `json:"courseId"`
+	OrderStatus   string        `json:"orderStatus"`
+	CourseName    string        `json:"courseName"`
+	Amount        money.Decimal `json:"amount"`
+	Currency      string        `json:"currency"`
+	Discount      money.Decimal `json:"discount"`
+	CouponCode    string        `json:"couponCode,omitempty"`
+	PaymentStatus string        `json:"paymentStatus"`
+}
+
+type OrderBookedNotificationData struct {
+	OrderID         string `json:"orderId"`
+	CourseID        string `json:"courseId"`
+	CourseName      string `json:"courseName"`
+	BookingID       string `json:"bookingId"`
+	SessionStart    string `json:"sessionStart"`
+	SessionEnd      string `json:"sessionEnd"`
+	SessionTimezone string `json:"sessionTimezone"`
+}
+
+type OrderDisputedNotificationData struct {
+	OrderID    string `json:"orderId"`
+	CourseID   string `json:"courseId"`
+	CourseName string `json:"courseName"`
+	Reason     string `json:"reason"`
+}
+
+type OrderDisputeResolvedNotificationData struct {
+	OrderID     string `json:"orderId"`
+	CourseID    string `json:"courseId"`
+	CourseName  string `json:"courseName"`
+	OrderStatus string `json:"orderStatus"`
+	Resolution  string `json:"resolution"`
+	Note        string `json:"note"`
+}
+
+type OrderCancelledNotificationData struct {
+	OrderID     string `json:"orderId"`
+	CourseID    string `json:"courseId"`
+	CourseName  string `json:"courseName"`
+	OrderStatus string `json:"orderStatus"`
+	CancelledBy string `json:"cancelledBy"`
+	Reason      string `json:"reason"`
+}
+
+type SubscriptionNotificationData struct {
+	SubscriptionID string `json:"subscriptionId"`
+	CourseID       string `json:"courseId"`
+	CourseName     string `json:"courseName"`
+	Status         string `json:"status"`
+	Period         int    `json:"period"`
+	PeriodEnd      string `json:"periodEnd,omitempty"`
+	Reason         string `json:"reason,omitempty"`
 }
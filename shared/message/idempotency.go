@@ -0,0 +1,61 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/wnmay/horo/shared/contract"
+)
+
+// ErrMessageInProgress is returned for a delivery whose message another
+// consumer instance is still handling; retrying it later finds the outcome
+var ErrMessageInProgress = errors.New("message is being processed by another consumer")
+
+// ProcessedMessageStore is the ledger of deliveries each consumer has handled
+type ProcessedMessageStore interface {
+	// Claim records messageID as handled by consumer and runs fn, keeping the
+	// record only if fn succeeds. It reports false without running fn when
+	// the message was handled already.
+	Claim(ctx context.Context, consumer, messageID string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Idempotent wraps handler so a delivery whose message ID the consumer has
+// already handled is acknowledged without running handler again. The ID is
+// claimed before handler runs, so two deliveries of a message cannot both
+// run it, and released if handler fails, so failed attempts are retried.
+func Idempotent(store ProcessedMessageStore, consumer string, handler MessageHandler) MessageHandler {
+	return func(ctx context.Context, d amqp.Delivery) error {
+		messageID := deliveryMessageID(d)
+		if messageID == "" {
+			log.Printf("Message on %s has no message ID, processing without idempotency check", consumer)
+			return handler(ctx, d)
+		}
+
+		handled, err := store.Claim(ctx, consumer, messageID, func(ctx context.Context) error {
+			return handler(ctx, d)
+		})
+		if err != nil {
+			return err
+		}
+		if !handled {
+			log.Printf("Skipping duplicate message %s for %s", messageID, consumer)
+		}
+		return nil
+	}
+}
+
+// deliveryMessageID prefers the AMQP property and falls back to the envelope
+func deliveryMessageID(d amqp.Delivery) string {
+	if d.MessageId != "" {
+		return d.MessageId
+	}
+
+	var amqpMessage contract.AmqpMessage
+	if err := json.Unmarshal(d.Body, &amqpMessage); err != nil {
+		return ""
+	}
+	return amqpMessage.MessageID
+}
@@ -0,0 +1,123 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const processedMessagesCollection = "processed_messages"
+
+// claimLease is how long a claimed message may go unfinished before another
+// consumer instance takes it over, assuming the claimer died
+const claimLease = 5 * time.Minute
+
+// processedMessageDocument is a claimed message; ProcessedAt is set once its
+// handler has succeeded
+type processedMessageDocument struct {
+	Consumer    string     `bson:"consumer"`
+	MessageID   string     `bson:"message_id"`
+	ClaimedAt   time.Time  `bson:"claimed_at"`
+	ProcessedAt *time.Time `bson:"processed_at,omitempty"`
+}
+
+// MongoProcessedMessageStore keeps the processed-message ledger in MongoDB.
+// Without multi-document transactions the claim cannot share the handler's
+// writes, so handlers must still tolerate running again after a crash.
+type MongoProcessedMessageStore struct {
+	collection *mongo.Collection
+}
+
+var _ ProcessedMessageStore = (*MongoProcessedMessageStore)(nil)
+
+func NewMongoProcessedMessageStore(ctx context.Context, db *mongo.Database) (*MongoProcessedMessageStore, error) {
+	collection := db.Collection(processedMessagesCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "consumer", Value: 1}, {Key: "message_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create processed messages index: %w", err)
+	}
+
+	return &MongoProcessedMessageStore{collection: collection}, nil
+}
+
+// Claim inserts the message's document before running fn, so a concurrent
+// delivery fails on the unique index instead of running fn too. The claim is
+// released if fn fails, and taken over once its lease runs out.
+func (s *MongoProcessedMessageStore) Claim(ctx context.Context, consumer, messageID string, fn func(ctx context.Context) error) (bool, error) {
+	// Mongo keeps milliseconds, and the claim is matched on its time later
+	now := time.Now().Truncate(time.Millisecond)
+
+	_, err := s.collection.InsertOne(ctx, processedMessageDocument{
+		Consumer:  consumer,
+		MessageID: messageID,
+		ClaimedAt: now,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		err = s.takeOver(ctx, consumer, messageID, now)
+	}
+	if err != nil {
+		if errors.Is(err, errAlreadyProcessed) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Only this claim is touched, in case it was taken over meanwhile
+	ours := bson.M{"consumer": consumer, "message_id": messageID, "claimed_at": now, "processed_at": nil}
+	if err := fn(ctx); err != nil {
+		if _, releaseErr := s.collection.DeleteOne(ctx, ours); releaseErr != nil {
+			return false, fmt.Errorf("%w (and failed to release claim on message %s: %v)", err, messageID, releaseErr)
+		}
+		return false, err
+	}
+
+	if _, err := s.collection.UpdateOne(ctx, ours, bson.M{"$set": bson.M{"processed_at": time.Now()}}); err != nil {
+		return false, fmt.Errorf("failed to record processed message %s: %w", messageID, err)
+	}
+	return true, nil
+}
+
+// errAlreadyProcessed reports a claimed message whose handler succeeded
+var errAlreadyProcessed = errors.New("message already processed")
+
+// takeOver claims a message another delivery claimed first. It fails with
+// errAlreadyProcessed when that delivery finished, and ErrMessageInProgress
+// while its lease has not run out.
+func (s *MongoProcessedMessageStore) takeOver(ctx context.Context, consumer, messageID string, now time.Time) error {
+	stale := bson.M{
+		"consumer":     consumer,
+		"message_id":   messageID,
+		"processed_at": nil,
+		"claimed_at":   bson.M{"$lt": now.Add(-claimLease)},
+	}
+	err := s.collection.FindOneAndUpdate(ctx, stale, bson.M{"$set": bson.M{"claimed_at": now}}).Err()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to claim message %s: %w", messageID, err)
+	}
+
+	var existing processedMessageDocument
+	err = s.collection.FindOne(ctx, bson.M{"consumer": consumer, "message_id": messageID}).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Released since the insert failed; the retry claims it afresh
+		return ErrMessageInProgress
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check processed message %s: %w", messageID, err)
+	}
+	if existing.ProcessedAt != nil {
+		return errAlreadyProcessed
+	}
+	return ErrMessageInProgress
+}
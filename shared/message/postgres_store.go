@@ -0,0 +1,68 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type processedMessageModel struct {
+	Consumer    string    `gorm:"primaryKey;type:varchar(255)"`
+	MessageID   string    `gorm:"primaryKey;type:varchar(255)"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
+
+func (processedMessageModel) TableName() string { return "processed_messages" }
+
+// Transactor is a service's transaction manager. Its repositories join the
+// transaction carried by the ctx passed to fn, which Conn returns.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	Conn(ctx context.Context) *gorm.DB
+}
+
+// PostgresProcessedMessageStore keeps the processed-message ledger in
+// Postgres, in the same transaction as the handler's own writes
+type PostgresProcessedMessageStore struct {
+	tx Transactor
+}
+
+var _ ProcessedMessageStore = (*PostgresProcessedMessageStore)(nil)
+
+func NewPostgresProcessedMessageStore(db *gorm.DB, tx Transactor) (*PostgresProcessedMessageStore, error) {
+	if err := db.AutoMigrate(&processedMessageModel{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate processed messages table: %w", err)
+	}
+	return &PostgresProcessedMessageStore{tx: tx}, nil
+}
+
+// Claim inserts the message's row and runs fn in one transaction. A second
+// delivery's insert waits on the row until the first commits, and then finds
+// it, or rolls back, and then takes over.
+func (s *PostgresProcessedMessageStore) Claim(ctx context.Context, consumer, messageID string, fn func(ctx context.Context) error) (bool, error) {
+	claimed := false
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		model := processedMessageModel{
+			Consumer:    consumer,
+			MessageID:   messageID,
+			ProcessedAt: time.Now(),
+		}
+		result := s.tx.Conn(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil {
+			return fmt.Errorf("failed to claim message %s: %w", messageID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		claimed = true
+		return fn(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
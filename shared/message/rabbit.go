@@ -3,9 +3,11 @@ package message
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/wnmay/horo/shared/contract"
 	"github.com/wnmay/horo/shared/retry"
@@ -17,9 +19,14 @@ const (
 	DeadLetterExchange = "dlx"
 )
 
+// ErrMessageRejected marks publish failures caused by the message itself,
+// which retrying will not fix, as opposed to the broker being unreachable
+var ErrMessageRejected = errors.New("message rejected")
+
 type RabbitMQ struct {
-	conn    *amqp.Connection
-	Channel *amqp.Channel
+	conn     *amqp.Connection
+	Channel  *amqp.Channel
+	confirms bool
 }
 
 func NewRabbitMQ(uri string) (*RabbitMQ, error) {
@@ -155,12 +162,17 @@ func (r *RabbitMQ) ConsumeMessages(queueName string, handler MessageHandler) err
 func (r *RabbitMQ) PublishMessage(ctx context.Context, routingKey string, message contract.AmqpMessage) error {
 	log.Printf("Publishing message with routing key: %s", routingKey)
 
+	if message.MessageID == "" {
+		message.MessageID = uuid.NewString()
+	}
+
 	jsonMsg, err := json.Marshal(message)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %v", err)
+		return fmt.Errorf("%w: failed to marshal message: %v", ErrMessageRejected, err)
 	}
 
 	msg := amqp.Publishing{
+		MessageId:    message.MessageID,
 		DeliveryMode: amqp.Persistent,
 		ContentType:  "application/json",
 		Body:         jsonMsg,
@@ -170,13 +182,48 @@ func (r *RabbitMQ) PublishMessage(ctx context.Context, routingKey string, messag
 }
 
 func (r *RabbitMQ) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
-	return r.Channel.PublishWithContext(ctx,
+	if !r.confirms {
+		return r.Channel.PublishWithContext(ctx,
+			exchange,   // exchange
+			routingKey, // routing key
+			false,      // mandatory
+			false,      // immediate
+			msg,
+		)
+	}
+
+	confirmation, err := r.Channel.PublishWithDeferredConfirmWithContext(ctx,
 		exchange,   // exchange
 		routingKey, // routing key
 		false,      // mandatory
 		false,      // immediate
 		msg,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Block until the broker has taken responsibility for the message
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed waiting for publish confirm: %v", err)
+	}
+	if !acked {
+		return fmt.Errorf("%w: message with routing key %s was nacked by the broker", ErrMessageRejected, routingKey)
+	}
+
+	return nil
+}
+
+// EnablePublishConfirms puts the channel into confirm mode. Afterwards
+// PublishMessage only returns once the broker has acknowledged the message,
+// which callers that need at-least-once delivery rely on.
+func (r *RabbitMQ) EnablePublishConfirms() error {
+	if err := r.Channel.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publish confirms: %v", err)
+	}
+	r.confirms = true
+	return nil
 }
 
 func (r *RabbitMQ) setupDeadLetterExchange() error {
@@ -0,0 +1,67 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency is the platform currency. Amounts recorded before prices
+// carried a currency are in it.
+const DefaultCurrency = "THB"
+
+var (
+	ErrInvalidCurrency  = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+)
+
+// minorUnits lists currencies whose minor unit is not two digits
+var minorUnits = map[string]int32{
+	"BHD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"VND": 0,
+}
+
+// NormalizeCurrency upper-cases a currency code and checks its shape. An
+// empty code means DefaultCurrency.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+		}
+	}
+	return code, nil
+}
+
+// MinorUnits is the number of fractional digits the currency is charged in
+func MinorUnits(currency string) int32 {
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+	return 2
+}
+
+// MinorUnit is one minor unit of the currency, the smallest amount it can be
+// charged in
+func MinorUnit(currency string) Decimal {
+	units := unit
+	for i := MinorUnits(currency); i > 0; i-- {
+		units /= 10
+	}
+	return Decimal{units: units}
+}
+
+// RoundTo rounds an amount to the currency's minor unit
+func RoundTo(amount Decimal, currency string) Decimal {
+	return amount.Round(MinorUnits(currency))
+}
@@ -0,0 +1,446 @@
+/*
+Package money provides an exact decimal type for monetary amounts and
+exchange rates, and helpers for ISO 4217 currency codes.
+*/
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scale is the number of fractional digits a Decimal keeps. It is enough for
+// every currency's minor unit and for exchange rates.
+const Scale = 8
+
+const unit int64 = 100_000_000
+
+var (
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrOverflow is returned when a value or result does not fit a Decimal,
+	// whose range is about ±92 billion
+	ErrOverflow = errors.New("decimal overflow")
+)
+
+// Decimal is an exact base-10 number with up to Scale fractional digits,
+// stored as a scaled integer. The zero value is 0.
+type Decimal struct {
+	units int64
+}
+
+var Zero = Decimal{}
+
+// NewFromInt converts i; it panics with ErrOverflow if i is out of range.
+// Use FromInt for values that are not known to be small.
+func NewFromInt(i int64) Decimal {
+	d, err := FromInt(i)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// FromInt converts i, or returns ErrOverflow if i is out of range
+func FromInt(i int64) (Decimal, error) {
+	if i > math.MaxInt64/unit || i < math.MinInt64/unit {
+		return Zero, fmt.Errorf("%w: %d", ErrOverflow, i)
+	}
+	return Decimal{units: i * unit}, nil
+}
+
+// NewFromFloat converts f, rounding to Scale fractional digits. It is meant
+// for reading values from float64 APIs such as protobuf doubles, and returns
+// ErrOverflow for NaN, infinities and values out of range.
+func NewFromFloat(f float64) (Decimal, error) {
+	scaled := math.Round(f * float64(unit))
+	// float64(math.MaxInt64) rounds up to 2^63, which no longer fits
+	if math.IsNaN(scaled) || scaled >= math.MaxInt64 || scaled < math.MinInt64 {
+		return Zero, fmt.Errorf("%w: %g", ErrOverflow, f)
+	}
+	return Decimal{units: int64(scaled)}, nil
+}
+
+// Parse reads a plain decimal such as "-12.50". Digits beyond Scale are
+// rounded half away from zero.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Zero, fmt.Errorf("%w: empty string", ErrInvalidDecimal)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return Zero, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+			}
+		}
+	}
+
+	roundUp := false
+	if len(frac) > Scale {
+		roundUp = frac[Scale] >= '5'
+		frac = frac[:Scale]
+	}
+	frac += strings.Repeat("0", Scale-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || (roundUp && n == math.MaxInt64) {
+		return Zero, fmt.Errorf("%w: %q is out of range", ErrOverflow, s)
+	}
+	if roundUp {
+		n++
+	}
+	if negative {
+		n = -n
+	}
+	return Decimal{units: n}, nil
+}
+
+// MustParse is Parse for constants; it panics on invalid input
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// The arithmetic methods panic with ErrOverflow rather than wrap around, so
+// an out of range amount never goes unnoticed. The Checked variants return
+// the error instead, for operands that are not known to be in range.
+
+func (d Decimal) Add(o Decimal) Decimal { return must(d.CheckedAdd(o)) }
+func (d Decimal) Sub(o Decimal) Decimal { return must(d.CheckedSub(o)) }
+func (d Decimal) Neg() Decimal          { return must(Zero.CheckedSub(d)) }
+
+func (d Decimal) Abs() Decimal {
+	if d.units < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Mul multiplies exactly and rounds the product to Scale digits
+func (d Decimal) Mul(o Decimal) Decimal { return must(d.CheckedMul(o)) }
+
+// Div divides and rounds the quotient to Scale digits. Dividing by zero
+// panics like integer division.
+func (d Decimal) Div(o Decimal) Decimal {
+	if o.units == 0 {
+		panic("money: division by zero")
+	}
+	return must(d.CheckedDiv(o))
+}
+
+func (d Decimal) CheckedAdd(o Decimal) (Decimal, error) {
+	sum := d.units + o.units
+	if (o.units > 0 && sum < d.units) || (o.units < 0 && sum > d.units) {
+		return Zero, fmt.Errorf("%w: %s + %s", ErrOverflow, d, o)
+	}
+	return Decimal{units: sum}, nil
+}
+
+func (d Decimal) CheckedSub(o Decimal) (Decimal, error) {
+	diff := d.units - o.units
+	if (o.units > 0 && diff > d.units) || (o.units < 0 && diff < d.units) {
+		return Zero, fmt.Errorf("%w: %s - %s", ErrOverflow, d, o)
+	}
+	return Decimal{units: diff}, nil
+}
+
+func (d Decimal) CheckedMul(o Decimal) (Decimal, error) {
+	product := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(o.units))
+	units, ok := toUnits(roundQuo(product, big.NewInt(unit)))
+	if !ok {
+		return Zero, fmt.Errorf("%w: %s * %s", ErrOverflow, d, o)
+	}
+	return Decimal{units: units}, nil
+}
+
+// CheckedDiv returns ErrInvalidDecimal when dividing by zero
+func (d Decimal) CheckedDiv(o Decimal) (Decimal, error) {
+	if o.units == 0 {
+		return Zero, fmt.Errorf("%w: division by zero", ErrInvalidDecimal)
+	}
+	numerator := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(unit))
+	units, ok := toUnits(roundQuo(numerator, big.NewInt(o.units)))
+	if !ok {
+		return Zero, fmt.Errorf("%w: %s / %s", ErrOverflow, d, o)
+	}
+	return Decimal{units: units}, nil
+}
+
+// Round rounds half away from zero to the given number of fractional digits.
+// It panics with ErrOverflow if rounding up leaves the range.
+func (d Decimal) Round(places int32) Decimal {
+	units, ok := toUnits(d.roundedUnits(places))
+	if !ok {
+		panic(fmt.Errorf("%w: rounding %s", ErrOverflow, d))
+	}
+	return Decimal{units: units}
+}
+
+// roundedUnits is d rounded to places, in units
+func (d Decimal) roundedUnits(places int32) *big.Int {
+	if places >= Scale {
+		return big.NewInt(d.units)
+	}
+	if places < 0 {
+		places = 0
+	}
+	step := big.NewInt(int64(math.Pow10(int(Scale - places))))
+	rounded := roundQuo(big.NewInt(d.units), step)
+	return rounded.Mul(rounded, step)
+}
+
+// roundQuo divides n by q rounding half away from zero
+func roundQuo(n, q *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(n, q, new(big.Int))
+	if new(big.Int).Abs(new(big.Int).Mul(rem, big.NewInt(2))).Cmp(new(big.Int).Abs(q)) >= 0 {
+		if (n.Sign() < 0) != (q.Sign() < 0) {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// toUnits reports whether n fits a Decimal
+func toUnits(n *big.Int) (int64, bool) {
+	if !n.IsInt64() {
+		return 0, false
+	}
+	return n.Int64(), true
+}
+
+func must(d Decimal, err error) Decimal {
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than o
+func (d Decimal) Cmp(o Decimal) int {
+	switch {
+	case d.units < o.units:
+		return -1
+	case d.units > o.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) Equal(o Decimal) bool       { return d.units == o.units }
+func (d Decimal) LessThan(o Decimal) bool    { return d.units < o.units }
+func (d Decimal) GreaterThan(o Decimal) bool { return d.units > o.units }
+func (d Decimal) IsZero() bool               { return d.units == 0 }
+func (d Decimal) IsPositive() bool           { return d.units > 0 }
+func (d Decimal) IsNegative() bool           { return d.units < 0 }
+
+func Min(a, b Decimal) Decimal {
+	if a.LessThan(b) {
+		return a
+	}
+	return b
+}
+
+func Max(a, b Decimal) Decimal {
+	if a.GreaterThan(b) {
+		return a
+	}
+	return b
+}
+
+// Float64 approximates d for display or float64 APIs
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / float64(unit)
+}
+
+// String formats d without trailing fractional zeros, e.g. "12.5"
+func (d Decimal) String() string {
+	s := d.StringFixed(Scale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// StringFixed formats d rounded to exactly places fractional digits
+func (d Decimal) StringFixed(places int32) string {
+	if places > Scale {
+		places = Scale
+	}
+	if places < 0 {
+		places = 0
+	}
+	// Rounded in big.Int so values near the range limits still format
+	units := d.roundedUnits(places)
+	sign := ""
+	if units.Sign() < 0 {
+		sign = "-"
+		units.Neg(units)
+	}
+	whole, frac := new(big.Int).QuoRem(units, big.NewInt(unit), new(big.Int))
+	if places == 0 {
+		return fmt.Sprintf("%s%s", sign, whole)
+	}
+	frac.Quo(frac, big.NewInt(int64(math.Pow10(int(Scale-places)))))
+	return fmt.Sprintf("%s%s.%0*d", sign, whole, places, frac.Int64())
+}
+
+// MarshalJSON writes d as a JSON number so existing clients keep reading it
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number, a quoted decimal or null
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*d = Zero
+		return nil
+	}
+	parsed, err := parseNumber(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// parseNumber also accepts exponent notation, which JSON encoders may emit
+func parseNumber(s string) (Decimal, error) {
+	if !strings.ContainsAny(s, "eE") {
+		return Parse(s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	return NewFromFloat(f)
+}
+
+// Value stores d as a decimal string so NUMERIC columns keep it exact
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan reads NUMERIC, text and float columns
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case int64:
+		parsed, err := FromInt(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case float64:
+		parsed, err := NewFromFloat(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case []byte:
+		parsed, err := parseNumber(string(v))
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case string:
+		parsed, err := parseNumber(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidDecimal, src)
+	}
+}
+
+// MarshalBSONValue stores d as a Decimal128 so MongoDB compares and sorts it
+// numerically alongside older double values
+func (d Decimal) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	dec, err := primitive.ParseDecimal128(d.String())
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(dec)
+}
+
+// UnmarshalBSONValue reads Decimal128, double, integer and string values
+func (d *Decimal) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*d = Zero
+		return nil
+	case bsontype.Decimal128:
+		parsed, err := parseNumber(raw.Decimal128().String())
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case bsontype.Double:
+		parsed, err := NewFromFloat(raw.Double())
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case bsontype.Int32:
+		*d = NewFromInt(int64(raw.Int32()))
+		return nil
+	case bsontype.Int64:
+		parsed, err := FromInt(raw.Int64())
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case bsontype.String:
+		parsed, err := Parse(raw.StringValue())
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot decode BSON %s", ErrInvalidDecimal, t)
+	}
+}
@@ -0,0 +1,344 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "0", want: "0"},
+		{in: "12.50", want: "12.5"},
+		{in: "-12.50", want: "-12.5"},
+		{in: "+3", want: "3"},
+		{in: ".5", want: "0.5"},
+		{in: "7.", want: "7"},
+		{in: " 1.25 ", want: "1.25"},
+		{in: "0.123456784", want: "0.12345678"},
+		{in: "0.123456785", want: "0.12345679"},
+		{in: "-0.123456785", want: "-0.12345679"},
+		{in: "92233720368.54775807", want: "92233720368.54775807"},
+		{in: "", wantErr: ErrInvalidDecimal},
+		{in: "-", wantErr: ErrInvalidDecimal},
+		{in: ".", wantErr: ErrInvalidDecimal},
+		{in: "1e5", wantErr: ErrInvalidDecimal},
+		{in: "1.2.3", wantErr: ErrInvalidDecimal},
+		{in: "abc", wantErr: ErrInvalidDecimal},
+		{in: "92233720368.54775808", wantErr: ErrOverflow},
+		{in: "92233720368.547758075", wantErr: ErrOverflow},
+		{in: "100000000000", wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int32
+		want   string
+	}{
+		{in: "1.005", places: 2, want: "1.01"},
+		{in: "1.004", places: 2, want: "1"},
+		{in: "-1.005", places: 2, want: "-1.01"},
+		{in: "2.5", places: 0, want: "3"},
+		{in: "-2.5", places: 0, want: "-3"},
+		{in: "2.5", places: -1, want: "3"},
+		{in: "0.12345678", places: 8, want: "0.12345678"},
+		{in: "0.12345678", places: 12, want: "0.12345678"},
+	}
+	for _, tt := range tests {
+		if got := MustParse(tt.in).Round(tt.places); got.String() != tt.want {
+			t.Errorf("%s.Round(%d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestStringFixed(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int32
+		want   string
+	}{
+		{in: "12.5", places: 2, want: "12.50"},
+		{in: "-0.005", places: 2, want: "-0.01"},
+		{in: "3", places: 0, want: "3"},
+		{in: "92233720368.54775807", places: 2, want: "92233720368.55"},
+		{in: "-92233720368.54775807", places: 0, want: "-92233720369"},
+	}
+	for _, tt := range tests {
+		if got := MustParse(tt.in).StringFixed(tt.places); got != tt.want {
+			t.Errorf("%s.StringFixed(%d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		mul    string
+		div    string
+		divErr error
+		mulErr error
+	}{
+		{a: "12.5", b: "2", mul: "25", div: "6.25"},
+		{a: "1", b: "3", mul: "3", div: "0.33333333"},
+		{a: "2", b: "3", mul: "6", div: "0.66666667"},
+		{a: "-2", b: "3", mul: "-6", div: "-0.66666667"},
+		{a: "0.00000001", b: "0.5", mul: "0.00000001", div: "0.00000002"},
+		{a: "-0.00000001", b: "0.5", mul: "-0.00000001", div: "-0.00000002"},
+		{a: "1", b: "0", mul: "0", divErr: ErrInvalidDecimal},
+		{a: "1000000", b: "1000000", mulErr: ErrOverflow, div: "1"},
+		{a: "90000000000", b: "0.5", mul: "45000000000", divErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		a, b := MustParse(tt.a), MustParse(tt.b)
+
+		mul, err := a.CheckedMul(b)
+		if tt.mulErr != nil {
+			if !errors.Is(err, tt.mulErr) {
+				t.Errorf("%s * %s error = %v, want %v", tt.a, tt.b, err, tt.mulErr)
+			}
+		} else if err != nil || mul.String() != tt.mul {
+			t.Errorf("%s * %s = %s, %v, want %s", tt.a, tt.b, mul, err, tt.mul)
+		}
+
+		div, err := a.CheckedDiv(b)
+		if tt.divErr != nil {
+			if !errors.Is(err, tt.divErr) {
+				t.Errorf("%s / %s error = %v, want %v", tt.a, tt.b, err, tt.divErr)
+			}
+		} else if err != nil || div.String() != tt.div {
+			t.Errorf("%s / %s = %s, %v, want %s", tt.a, tt.b, div, err, tt.div)
+		}
+	}
+}
+
+func TestAddSubOverflow(t *testing.T) {
+	max := Decimal{units: math.MaxInt64}
+	min := Decimal{units: math.MinInt64}
+	one := NewFromInt(1)
+
+	if _, err := max.CheckedAdd(one); !errors.Is(err, ErrOverflow) {
+		t.Errorf("max + 1 error = %v, want ErrOverflow", err)
+	}
+	if _, err := min.CheckedSub(one); !errors.Is(err, ErrOverflow) {
+		t.Errorf("min - 1 error = %v, want ErrOverflow", err)
+	}
+	if _, err := Zero.CheckedSub(min); !errors.Is(err, ErrOverflow) {
+		t.Errorf("-min error = %v, want ErrOverflow", err)
+	}
+	if got, err := max.CheckedSub(one); err != nil || got.units != math.MaxInt64-unit {
+		t.Errorf("max - 1 = %s, %v", got, err)
+	}
+	if got, err := min.CheckedAdd(max); err != nil || got.units != -1 {
+		t.Errorf("min + max = %s, %v", got, err)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrOverflow) {
+			t.Errorf("max.Add(1) panicked with %v, want ErrOverflow", err)
+		}
+	}()
+	max.Add(one)
+}
+
+func TestFromIntAndFloat(t *testing.T) {
+	if _, err := FromInt(math.MaxInt64 / unit); err != nil {
+		t.Errorf("FromInt(max) error = %v", err)
+	}
+	if _, err := FromInt(math.MaxInt64/unit + 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("FromInt(max+1) error = %v, want ErrOverflow", err)
+	}
+
+	floats := []struct {
+		in      float64
+		want    string
+		wantErr bool
+	}{
+		{in: 12.5, want: "12.5"},
+		{in: -0.1, want: "-0.1"},
+		{in: 0.123456785, want: "0.12345679"},
+		{in: 1e11, wantErr: true},
+		{in: -1e11, wantErr: true},
+		{in: math.Inf(1), wantErr: true},
+		{in: math.NaN(), wantErr: true},
+	}
+	for _, tt := range floats {
+		got, err := NewFromFloat(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrOverflow) {
+				t.Errorf("NewFromFloat(%g) error = %v, want ErrOverflow", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("NewFromFloat(%g) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+var roundTripValues = []string{"0", "12.5", "-12.5", "0.00000001", "92233720368.54775807", "-92233720368.54775807"}
+
+func TestJSONRoundTrip(t *testing.T) {
+	type wrapper struct {
+		Amount Decimal `json:"amount"`
+	}
+	for _, in := range roundTripValues {
+		data, err := json.Marshal(wrapper{Amount: MustParse(in)})
+		if err != nil {
+			t.Fatalf("Marshal(%s) error = %v", in, err)
+		}
+		var out wrapper
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", data, err)
+		}
+		if out.Amount.String() != in {
+			t.Errorf("JSON round trip of %s = %s", in, out.Amount)
+		}
+	}
+
+	inputs := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: `"12.50"`, want: "12.5"},
+		{in: `null`, want: "0"},
+		{in: `1.5e2`, want: "150"},
+		{in: `1e20`, wantErr: ErrOverflow},
+		{in: `"x"`, wantErr: ErrInvalidDecimal},
+	}
+	for _, tt := range inputs {
+		var d Decimal
+		err := json.Unmarshal([]byte(tt.in), &d)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unmarshal(%s) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || d.String() != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, %v, want %s", tt.in, d, err, tt.want)
+		}
+	}
+}
+
+func TestBSONRoundTrip(t *testing.T) {
+	type wrapper struct {
+		Amount Decimal `bson:"amount"`
+	}
+	for _, in := range roundTripValues {
+		data, err := bson.Marshal(wrapper{Amount: MustParse(in)})
+		if err != nil {
+			t.Fatalf("Marshal(%s) error = %v", in, err)
+		}
+		var out wrapper
+		if err := bson.Unmarshal(data, &out); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", in, err)
+		}
+		if out.Amount.String() != in {
+			t.Errorf("BSON round trip of %s = %s", in, out.Amount)
+		}
+	}
+
+	dec, _ := primitive.ParseDecimal128("1E+20")
+	inputs := []struct {
+		in      interface{}
+		want    string
+		wantErr error
+	}{
+		{in: 12.5, want: "12.5"},
+		{in: int32(7), want: "7"},
+		{in: int64(-7), want: "-7"},
+		{in: "3.25", want: "3.25"},
+		{in: nil, want: "0"},
+		{in: 1e20, wantErr: ErrOverflow},
+		{in: int64(math.MaxInt64), wantErr: ErrOverflow},
+		{in: dec, wantErr: ErrOverflow},
+	}
+	for _, tt := range inputs {
+		data, err := bson.Marshal(bson.M{"amount": tt.in})
+		if err != nil {
+			t.Fatalf("Marshal(%v) error = %v", tt.in, err)
+		}
+		var out wrapper
+		err = bson.Unmarshal(data, &out)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unmarshal(%v) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || out.Amount.String() != tt.want {
+			t.Errorf("Unmarshal(%v) = %s, %v, want %s", tt.in, out.Amount, err, tt.want)
+		}
+	}
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	for _, in := range roundTripValues {
+		value, err := MustParse(in).Value()
+		if err != nil {
+			t.Fatalf("Value(%s) error = %v", in, err)
+		}
+		var out Decimal
+		if err := out.Scan([]byte(value.(string))); err != nil {
+			t.Fatalf("Scan(%v) error = %v", value, err)
+		}
+		if out.String() != in {
+			t.Errorf("SQL round trip of %s = %s", in, out)
+		}
+	}
+
+	inputs := []struct {
+		in      interface{}
+		want    string
+		wantErr error
+	}{
+		{in: "12.50", want: "12.5"},
+		{in: int64(3), want: "3"},
+		{in: 0.25, want: "0.25"},
+		{in: nil, want: "0"},
+		{in: int64(math.MaxInt64), wantErr: ErrOverflow},
+		{in: 1e20, wantErr: ErrOverflow},
+		{in: []byte("1e20"), wantErr: ErrOverflow},
+		{in: true, wantErr: ErrInvalidDecimal},
+	}
+	for _, tt := range inputs {
+		var d Decimal
+		err := d.Scan(tt.in)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Scan(%v) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || d.String() != tt.want {
+			t.Errorf("Scan(%v) = %s, %v, want %s", tt.in, d, err, tt.want)
+		}
+	}
+}
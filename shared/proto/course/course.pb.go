@@ -75,14 +75,18 @@ func (Duration) EnumDescriptor() ([]byte, []int) {
 }
 
 type Course struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProphetId     string                 `protobuf:"bytes,2,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
-	Coursename    string                 `protobuf:"bytes,3,opt,name=coursename,proto3" json:"coursename,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
-	Duration      Duration               `protobuf:"varint,6,opt,name=duration,proto3,enum=course.Duration" json:"duration,omitempty"`
-	CreatedTime   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProphetId   string                 `protobuf:"bytes,2,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
+	Coursename  string                 `protobuf:"bytes,3,opt,name=coursename,proto3" json:"coursename,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Price       float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Duration    Duration               `protobuf:"varint,6,opt,name=duration,proto3,enum=course.Duration" json:"duration,omitempty"`
+	CreatedTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	Coursetype  string                 `protobuf:"bytes,8,opt,name=coursetype,proto3" json:"coursetype,omitempty"`
+	Currency    string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Sessions sold together at price; 1 is a single reading
+	Sessions      int32 `protobuf:"varint,10,opt,name=sessions,proto3" json:"sessions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -166,6 +170,27 @@ func (x *Course) GetCreatedTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Course) GetCoursetype() string {
+	if x != nil {
+		return x.Coursetype
+	}
+	return ""
+}
+
+func (x *Course) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Course) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
 type CreateCourseRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProphetId     string                 `protobuf:"bytes,1,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
@@ -173,6 +198,8 @@ type CreateCourseRequest struct {
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
 	Duration      Duration               `protobuf:"varint,5,opt,name=duration,proto3,enum=course.Duration" json:"duration,omitempty"`
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Sessions      int32                  `protobuf:"varint,7,opt,name=sessions,proto3" json:"sessions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -242,6 +269,20 @@ func (x *CreateCourseRequest) GetDuration() Duration {
 	return Duration_DURATION_UNSPECIFIED
 }
 
+func (x *CreateCourseRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateCourseRequest) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
 type CreateCourseResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Course        *Course                `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
@@ -462,11 +503,328 @@ func (x *ListCoursesByProphetResponse) GetCourses() []*Course {
 	return nil
 }
 
+type Booking struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CourseId      string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	ProphetId     string                 `protobuf:"bytes,3,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
+	CustomerId    string                 `protobuf:"bytes,4,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,5,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Timezone      string                 `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Session       int32                  `protobuf:"varint,10,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Booking) Reset() {
+	*x = Booking{}
+	mi := &file_course_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Booking) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Booking) ProtoMessage() {}
+
+func (x *Booking) ProtoReflect() protoreflect.Message {
+	mi := &file_course_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Booking.ProtoReflect.Descriptor instead.
+func (*Booking) Descriptor() ([]byte, []int) {
+	return file_course_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Booking) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Booking) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *Booking) GetProphetId() string {
+	if x != nil {
+		return x.ProphetId
+	}
+	return ""
+}
+
+func (x *Booking) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Booking) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Booking) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Booking) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Booking) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Booking) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Booking) GetSession() int32 {
+	if x != nil {
+		return x.Session
+	}
+	return 0
+}
+
+type ReserveSlotRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CourseId   string                 `protobuf:"bytes,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	CustomerId string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	OrderId    string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	StartTime  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Which of the order's sessions to book, starting at 1
+	Session       int32 `protobuf:"varint,5,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveSlotRequest) Reset() {
+	*x = ReserveSlotRequest{}
+	mi := &file_course_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveSlotRequest) ProtoMessage() {}
+
+func (x *ReserveSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_course_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveSlotRequest.ProtoReflect.Descriptor instead.
+func (*ReserveSlotRequest) Descriptor() ([]byte, []int) {
+	return file_course_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReserveSlotRequest) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *ReserveSlotRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *ReserveSlotRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ReserveSlotRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ReserveSlotRequest) GetSession() int32 {
+	if x != nil {
+		return x.Session
+	}
+	return 0
+}
+
+type ReserveSlotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Booking       *Booking               `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveSlotResponse) Reset() {
+	*x = ReserveSlotResponse{}
+	mi := &file_course_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveSlotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveSlotResponse) ProtoMessage() {}
+
+func (x *ReserveSlotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_course_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveSlotResponse.ProtoReflect.Descriptor instead.
+func (*ReserveSlotResponse) Descriptor() ([]byte, []int) {
+	return file_course_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReserveSlotResponse) GetBooking() *Booking {
+	if x != nil {
+		return x.Booking
+	}
+	return nil
+}
+
+type ReleaseSlotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     string                 `protobuf:"bytes,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseSlotRequest) Reset() {
+	*x = ReleaseSlotRequest{}
+	mi := &file_course_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseSlotRequest) ProtoMessage() {}
+
+func (x *ReleaseSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_course_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseSlotRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseSlotRequest) Descriptor() ([]byte, []int) {
+	return file_course_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReleaseSlotRequest) GetBookingId() string {
+	if x != nil {
+		return x.BookingId
+	}
+	return ""
+}
+
+type ReleaseSlotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseSlotResponse) Reset() {
+	*x = ReleaseSlotResponse{}
+	mi := &file_course_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseSlotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseSlotResponse) ProtoMessage() {}
+
+func (x *ReleaseSlotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_course_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseSlotResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseSlotResponse) Descriptor() ([]byte, []int) {
+	return file_course_proto_rawDescGZIP(), []int{11}
+}
+
 var File_course_proto protoreflect.FileDescriptor
 
 const file_course_proto_rawDesc = "" +
 	"\n" +
-	"\fcourse.proto\x12\x06course\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfc\x01\n" +
+	"\fcourse.proto\x12\x06course\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd4\x02\n" +
 	"\x06Course\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -477,7 +835,13 @@ const file_course_proto_rawDesc = "" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
 	"\x05price\x18\x05 \x01(\x01R\x05price\x12,\n" +
 	"\bduration\x18\x06 \x01(\x0e2\x10.course.DurationR\bduration\x12=\n" +
-	"\fcreated_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedTime\"\xba\x01\n" +
+	"\fcreated_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedTime\x12\x1e\n" +
+	"\n" +
+	"coursetype\x18\b \x01(\tR\n" +
+	"coursetype\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bsessions\x18\n" +
+	" \x01(\x05R\bsessions\"\xf2\x01\n" +
 	"\x13CreateCourseRequest\x12\x1d\n" +
 	"\n" +
 	"prophet_id\x18\x01 \x01(\tR\tprophetId\x12\x1e\n" +
@@ -486,7 +850,9 @@ const file_course_proto_rawDesc = "" +
 	"coursename\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
 	"\x05price\x18\x04 \x01(\x01R\x05price\x12,\n" +
-	"\bduration\x18\x05 \x01(\x0e2\x10.course.DurationR\bduration\">\n" +
+	"\bduration\x18\x05 \x01(\x0e2\x10.course.DurationR\bduration\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bsessions\x18\a \x01(\x05R\bsessions\">\n" +
 	"\x14CreateCourseResponse\x12&\n" +
 	"\x06course\x18\x01 \x01(\v2\x0e.course.CourseR\x06course\"&\n" +
 	"\x14GetCourseByIDRequest\x12\x0e\n" +
@@ -497,16 +863,47 @@ const file_course_proto_rawDesc = "" +
 	"\n" +
 	"prophet_id\x18\x01 \x01(\tR\tprophetId\"H\n" +
 	"\x1cListCoursesByProphetResponse\x12(\n" +
-	"\acourses\x18\x01 \x03(\v2\x0e.course.CourseR\acourses*W\n" +
+	"\acourses\x18\x01 \x03(\v2\x0e.course.CourseR\acourses\"\xd1\x02\n" +
+	"\aBooking\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\x12\x1d\n" +
+	"\n" +
+	"prophet_id\x18\x03 \x01(\tR\tprophetId\x12\x1f\n" +
+	"\vcustomer_id\x18\x04 \x01(\tR\n" +
+	"customerId\x12\x19\n" +
+	"\border_id\x18\x05 \x01(\tR\aorderId\x129\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1a\n" +
+	"\btimezone\x18\b \x01(\tR\btimezone\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x18\n" +
+	"\asession\x18\n" +
+	" \x01(\x05R\asession\"\xc2\x01\n" +
+	"\x12ReserveSlotRequest\x12\x1b\n" +
+	"\tcourse_id\x18\x01 \x01(\tR\bcourseId\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x129\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12\x18\n" +
+	"\asession\x18\x05 \x01(\x05R\asession\"@\n" +
+	"\x13ReserveSlotResponse\x12)\n" +
+	"\abooking\x18\x01 \x01(\v2\x0f.course.BookingR\abooking\"3\n" +
+	"\x12ReleaseSlotRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\tR\tbookingId\"\x15\n" +
+	"\x13ReleaseSlotResponse*W\n" +
 	"\bDuration\x12\x18\n" +
 	"\x14DURATION_UNSPECIFIED\x10\x00\x12\x0f\n" +
 	"\vDURATION_15\x10\x0f\x12\x0f\n" +
 	"\vDURATION_30\x10\x1e\x12\x0f\n" +
-	"\vDURATION_60\x10<2\x8b\x02\n" +
+	"\vDURATION_60\x10<2\x9b\x03\n" +
 	"\rCourseService\x12I\n" +
 	"\fCreateCourse\x12\x1b.course.CreateCourseRequest\x1a\x1c.course.CreateCourseResponse\x12L\n" +
 	"\rGetCourseByID\x12\x1c.course.GetCourseByIDRequest\x1a\x1d.course.GetCourseByIDResponse\x12a\n" +
-	"\x14ListCoursesByProphet\x12#.course.ListCoursesByProphetRequest\x1a$.course.ListCoursesByProphetResponseB2Z0github.com/wnmay/horo/shared/proto/course;courseb\x06proto3"
+	"\x14ListCoursesByProphet\x12#.course.ListCoursesByProphetRequest\x1a$.course.ListCoursesByProphetResponse\x12F\n" +
+	"\vReserveSlot\x12\x1a.course.ReserveSlotRequest\x1a\x1b.course.ReserveSlotResponse\x12F\n" +
+	"\vReleaseSlot\x12\x1a.course.ReleaseSlotRequest\x1a\x1b.course.ReleaseSlotResponseB2Z0github.com/wnmay/horo/shared/proto/course;courseb\x06proto3"
 
 var (
 	file_course_proto_rawDescOnce sync.Once
@@ -521,7 +918,7 @@ func file_course_proto_rawDescGZIP() []byte {
 }
 
 var file_course_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_course_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_course_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_course_proto_goTypes = []any{
 	(Duration)(0),                        // 0: course.Duration
 	(*Course)(nil),                       // 1: course.Course
@@ -531,26 +928,39 @@ var file_course_proto_goTypes = []any{
 	(*GetCourseByIDResponse)(nil),        // 5: course.GetCourseByIDResponse
 	(*ListCoursesByProphetRequest)(nil),  // 6: course.ListCoursesByProphetRequest
 	(*ListCoursesByProphetResponse)(nil), // 7: course.ListCoursesByProphetResponse
-	(*timestamppb.Timestamp)(nil),        // 8: google.protobuf.Timestamp
+	(*Booking)(nil),                      // 8: course.Booking
+	(*ReserveSlotRequest)(nil),           // 9: course.ReserveSlotRequest
+	(*ReserveSlotResponse)(nil),          // 10: course.ReserveSlotResponse
+	(*ReleaseSlotRequest)(nil),           // 11: course.ReleaseSlotRequest
+	(*ReleaseSlotResponse)(nil),          // 12: course.ReleaseSlotResponse
+	(*timestamppb.Timestamp)(nil),        // 13: google.protobuf.Timestamp
 }
 var file_course_proto_depIdxs = []int32{
-	0, // 0: course.Course.duration:type_name -> course.Duration
-	8, // 1: course.Course.created_time:type_name -> google.protobuf.Timestamp
-	0, // 2: course.CreateCourseRequest.duration:type_name -> course.Duration
-	1, // 3: course.CreateCourseResponse.course:type_name -> course.Course
-	1, // 4: course.GetCourseByIDResponse.course:type_name -> course.Course
-	1, // 5: course.ListCoursesByProphetResponse.courses:type_name -> course.Course
-	2, // 6: course.CourseService.CreateCourse:input_type -> course.CreateCourseRequest
-	4, // 7: course.CourseService.GetCourseByID:input_type -> course.GetCourseByIDRequest
-	6, // 8: course.CourseService.ListCoursesByProphet:input_type -> course.ListCoursesByProphetRequest
-	3, // 9: course.CourseService.CreateCourse:output_type -> course.CreateCourseResponse
-	5, // 10: course.CourseService.GetCourseByID:output_type -> course.GetCourseByIDResponse
-	7, // 11: course.CourseService.ListCoursesByProphet:output_type -> course.ListCoursesByProphetResponse
-	9, // [9:12] is the sub-list for method output_type
-	6, // [6:9] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: course.Course.duration:type_name -> course.Duration
+	13, // 1: course.Course.created_time:type_name -> google.protobuf.Timestamp
+	0,  // 2: course.CreateCourseRequest.duration:type_name -> course.Duration
+	1,  // 3: course.CreateCourseResponse.course:type_name -> course.Course
+	1,  // 4: course.GetCourseByIDResponse.course:type_name -> course.Course
+	1,  // 5: course.ListCoursesByProphetResponse.courses:type_name -> course.Course
+	13, // 6: course.Booking.start_time:type_name -> google.protobuf.Timestamp
+	13, // 7: course.Booking.end_time:type_name -> google.protobuf.Timestamp
+	13, // 8: course.ReserveSlotRequest.start_time:type_name -> google.protobuf.Timestamp
+	8,  // 9: course.ReserveSlotResponse.booking:type_name -> course.Booking
+	2,  // 10: course.CourseService.CreateCourse:input_type -> course.CreateCourseRequest
+	4,  // 11: course.CourseService.GetCourseByID:input_type -> course.GetCourseByIDRequest
+	6,  // 12: course.CourseService.ListCoursesByProphet:input_type -> course.ListCoursesByProphetRequest
+	9,  // 13: course.CourseService.ReserveSlot:input_type -> course.ReserveSlotRequest
+	11, // 14: course.CourseService.ReleaseSlot:input_type -> course.ReleaseSlotRequest
+	3,  // 15: course.CourseService.CreateCourse:output_type -> course.CreateCourseResponse
+	5,  // 16: course.CourseService.GetCourseByID:output_type -> course.GetCourseByIDResponse
+	7,  // 17: course.CourseService.ListCoursesByProphet:output_type -> course.ListCoursesByProphetResponse
+	10, // 18: course.CourseService.ReserveSlot:output_type -> course.ReserveSlotResponse
+	12, // 19: course.CourseService.ReleaseSlot:output_type -> course.ReleaseSlotResponse
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_course_proto_init() }
@@ -564,7 +974,7 @@ func file_course_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_course_proto_rawDesc), len(file_course_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,8 @@ const (
 	CourseService_CreateCourse_FullMethodName         = "/course.CourseService/CreateCourse"
 	CourseService_GetCourseByID_FullMethodName        = "/course.CourseService/GetCourseByID"
 	CourseService_ListCoursesByProphet_FullMethodName = "/course.CourseService/ListCoursesByProphet"
+	CourseService_ReserveSlot_FullMethodName          = "/course.CourseService/ReserveSlot"
+	CourseService_ReleaseSlot_FullMethodName          = "/course.CourseService/ReleaseSlot"
 )
 
 // CourseServiceClient is the client API for CourseService service.
@@ -31,6 +33,8 @@ type CourseServiceClient interface {
 	CreateCourse(ctx context.Context, in *CreateCourseRequest, opts ...grpc.CallOption) (*CreateCourseResponse, error)
 	GetCourseByID(ctx context.Context, in *GetCourseByIDRequest, opts ...grpc.CallOption) (*GetCourseByIDResponse, error)
 	ListCoursesByProphet(ctx context.Context, in *ListCoursesByProphetRequest, opts ...grpc.CallOption) (*ListCoursesByProphetResponse, error)
+	ReserveSlot(ctx context.Context, in *ReserveSlotRequest, opts ...grpc.CallOption) (*ReserveSlotResponse, error)
+	ReleaseSlot(ctx context.Context, in *ReleaseSlotRequest, opts ...grpc.CallOption) (*ReleaseSlotResponse, error)
 }
 
 type courseServiceClient struct {
@@ -71,6 +75,26 @@ func (c *courseServiceClient) ListCoursesByProphet(ctx context.Context, in *List
 	return out, nil
 }
 
+func (c *courseServiceClient) ReserveSlot(ctx context.Context, in *ReserveSlotRequest, opts ...grpc.CallOption) (*ReserveSlotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveSlotResponse)
+	err := c.cc.Invoke(ctx, CourseService_ReserveSlot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseServiceClient) ReleaseSlot(ctx context.Context, in *ReleaseSlotRequest, opts ...grpc.CallOption) (*ReleaseSlotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseSlotResponse)
+	err := c.cc.Invoke(ctx, CourseService_ReleaseSlot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CourseServiceServer is the server API for CourseService service.
 // All implementations must embed UnimplementedCourseServiceServer
 // for forward compatibility.
@@ -78,6 +102,8 @@ type CourseServiceServer interface {
 	CreateCourse(context.Context, *CreateCourseRequest) (*CreateCourseResponse, error)
 	GetCourseByID(context.Context, *GetCourseByIDRequest) (*GetCourseByIDResponse, error)
 	ListCoursesByProphet(context.Context, *ListCoursesByProphetRequest) (*ListCoursesByProphetResponse, error)
+	ReserveSlot(context.Context, *ReserveSlotRequest) (*ReserveSlotResponse, error)
+	ReleaseSlot(context.Context, *ReleaseSlotRequest) (*ReleaseSlotResponse, error)
 	mustEmbedUnimplementedCourseServiceServer()
 }
 
@@ -97,6 +123,12 @@ func (UnimplementedCourseServiceServer) GetCourseByID(context.Context, *GetCours
 func (UnimplementedCourseServiceServer) ListCoursesByProphet(context.Context, *ListCoursesByProphetRequest) (*ListCoursesByProphetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCoursesByProphet not implemented")
 }
+func (UnimplementedCourseServiceServer) ReserveSlot(context.Context, *ReserveSlotRequest) (*ReserveSlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveSlot not implemented")
+}
+func (UnimplementedCourseServiceServer) ReleaseSlot(context.Context, *ReleaseSlotRequest) (*ReleaseSlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseSlot not implemented")
+}
 func (UnimplementedCourseServiceServer) mustEmbedUnimplementedCourseServiceServer() {}
 func (UnimplementedCourseServiceServer) testEmbeddedByValue()                       {}
 
@@ -172,6 +204,42 @@ func _CourseService_ListCoursesByProphet_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CourseService_ReserveSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseServiceServer).ReserveSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseService_ReserveSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseServiceServer).ReserveSlot(ctx, req.(*ReserveSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseService_ReleaseSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseServiceServer).ReleaseSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseService_ReleaseSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseServiceServer).ReleaseSlot(ctx, req.(*ReleaseSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CourseService_ServiceDesc is the grpc.ServiceDesc for CourseService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +259,14 @@ var CourseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListCoursesByProphet",
 			Handler:    _CourseService_ListCoursesByProphet_Handler,
 		},
+		{
+			MethodName: "ReserveSlot",
+			Handler:    _CourseService_ReserveSlot_Handler,
+		},
+		{
+			MethodName: "ReleaseSlot",
+			Handler:    _CourseService_ReleaseSlot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "course.proto",
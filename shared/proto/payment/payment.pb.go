@@ -0,0 +1,955 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.0
+// source: payment.proto
+
+package payment
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PaymentStatus int32
+
+const (
+	PaymentStatus_PAYMENT_STATUS_UNSPECIFIED PaymentStatus = 0
+	PaymentStatus_PAYMENT_STATUS_PENDING     PaymentStatus = 1
+	PaymentStatus_PAYMENT_STATUS_COMPLETED   PaymentStatus = 2
+	PaymentStatus_PAYMENT_STATUS_SETTLED     PaymentStatus = 3
+	PaymentStatus_PAYMENT_STATUS_FAILED      PaymentStatus = 4
+	PaymentStatus_PAYMENT_STATUS_REFUNDED    PaymentStatus = 5
+)
+
+// Enum value maps for PaymentStatus.
+var (
+	PaymentStatus_name = map[int32]string{
+		0: "PAYMENT_STATUS_UNSPECIFIED",
+		1: "PAYMENT_STATUS_PENDING",
+		2: "PAYMENT_STATUS_COMPLETED",
+		3: "PAYMENT_STATUS_SETTLED",
+		4: "PAYMENT_STATUS_FAILED",
+		5: "PAYMENT_STATUS_REFUNDED",
+	}
+	PaymentStatus_value = map[string]int32{
+		"PAYMENT_STATUS_UNSPECIFIED": 0,
+		"PAYMENT_STATUS_PENDING":     1,
+		"PAYMENT_STATUS_COMPLETED":   2,
+		"PAYMENT_STATUS_SETTLED":     3,
+		"PAYMENT_STATUS_FAILED":      4,
+		"PAYMENT_STATUS_REFUNDED":    5,
+	}
+)
+
+func (x PaymentStatus) Enum() *PaymentStatus {
+	p := new(PaymentStatus)
+	*p = x
+	return p
+}
+
+func (x PaymentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_payment_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentStatus) Type() protoreflect.EnumType {
+	return &file_payment_proto_enumTypes[0]
+}
+
+func (x PaymentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentStatus.Descriptor instead.
+func (PaymentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{0}
+}
+
+type Payment struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId   string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId     string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ProphetId   string                 `protobuf:"bytes,3,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
+	Amount      float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status      PaymentStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=payment.PaymentStatus" json:"status,omitempty"`
+	Provider    string                 `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	ProviderRef string                 `protobuf:"bytes,7,opt,name=provider_ref,json=providerRef,proto3" json:"provider_ref,omitempty"`
+	CreatedTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	UpdatedTime *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_time,json=updatedTime,proto3" json:"updated_time,omitempty"`
+	// Fee split recorded when the payment settles
+	CourseType  string  `protobuf:"bytes,10,opt,name=course_type,json=courseType,proto3" json:"course_type,omitempty"`
+	GrossAmount float64 `protobuf:"fixed64,11,opt,name=gross_amount,json=grossAmount,proto3" json:"gross_amount,omitempty"`
+	FeeAmount   float64 `protobuf:"fixed64,12,opt,name=fee_amount,json=feeAmount,proto3" json:"fee_amount,omitempty"`
+	NetAmount   float64 `protobuf:"fixed64,13,opt,name=net_amount,json=netAmount,proto3" json:"net_amount,omitempty"`
+	FeeRuleId   string  `protobuf:"bytes,14,opt,name=fee_rule_id,json=feeRuleId,proto3" json:"fee_rule_id,omitempty"`
+	// ISO 4217 code all amounts are in
+	Currency      string `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payment) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Payment) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Payment) GetProphetId() string {
+	if x != nil {
+		return x.ProphetId
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetStatus() PaymentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Payment) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Payment) GetProviderRef() string {
+	if x != nil {
+		return x.ProviderRef
+	}
+	return ""
+}
+
+func (x *Payment) GetCreatedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTime
+	}
+	return nil
+}
+
+func (x *Payment) GetUpdatedTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedTime
+	}
+	return nil
+}
+
+func (x *Payment) GetCourseType() string {
+	if x != nil {
+		return x.CourseType
+	}
+	return ""
+}
+
+func (x *Payment) GetGrossAmount() float64 {
+	if x != nil {
+		return x.GrossAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetFeeAmount() float64 {
+	if x != nil {
+		return x.FeeAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetNetAmount() float64 {
+	if x != nil {
+		return x.NetAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetFeeRuleId() string {
+	if x != nil {
+		return x.FeeRuleId
+	}
+	return ""
+}
+
+func (x *Payment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreatePaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePaymentRequest) Reset() {
+	*x = CreatePaymentRequest{}
+	mi := &file_payment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentRequest) ProtoMessage() {}
+
+func (x *CreatePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreatePaymentRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreatePaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePaymentResponse) Reset() {
+	*x = CreatePaymentResponse{}
+	mi := &file_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentResponse) ProtoMessage() {}
+
+func (x *CreatePaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentResponse.ProtoReflect.Descriptor instead.
+func (*CreatePaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreatePaymentResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+type GetPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentRequest) Reset() {
+	*x = GetPaymentRequest{}
+	mi := &file_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentRequest) ProtoMessage() {}
+
+func (x *GetPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPaymentRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type GetPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentResponse) Reset() {
+	*x = GetPaymentResponse{}
+	mi := &file_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentResponse) ProtoMessage() {}
+
+func (x *GetPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPaymentResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+type GetPaymentByOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentByOrderRequest) Reset() {
+	*x = GetPaymentByOrderRequest{}
+	mi := &file_payment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentByOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentByOrderRequest) ProtoMessage() {}
+
+func (x *GetPaymentByOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentByOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentByOrderRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPaymentByOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetPaymentByOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentByOrderResponse) Reset() {
+	*x = GetPaymentByOrderResponse{}
+	mi := &file_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentByOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentByOrderResponse) ProtoMessage() {}
+
+func (x *GetPaymentByOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentByOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentByOrderResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPaymentByOrderResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+type GetProphetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProphetId     string                 `protobuf:"bytes,1,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProphetBalanceRequest) Reset() {
+	*x = GetProphetBalanceRequest{}
+	mi := &file_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProphetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProphetBalanceRequest) ProtoMessage() {}
+
+func (x *GetProphetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProphetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetProphetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetProphetBalanceRequest) GetProphetId() string {
+	if x != nil {
+		return x.ProphetId
+	}
+	return ""
+}
+
+type CurrencyBalance struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Currency         string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Balance          float64                `protobuf:"fixed64,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	AvailableBalance float64                `protobuf:"fixed64,3,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"`
+	GrossSettled     float64                `protobuf:"fixed64,4,opt,name=gross_settled,json=grossSettled,proto3" json:"gross_settled,omitempty"`
+	PlatformFees     float64                `protobuf:"fixed64,5,opt,name=platform_fees,json=platformFees,proto3" json:"platform_fees,omitempty"`
+	NetSettled       float64                `protobuf:"fixed64,6,opt,name=net_settled,json=netSettled,proto3" json:"net_settled,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CurrencyBalance) Reset() {
+	*x = CurrencyBalance{}
+	mi := &file_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyBalance) ProtoMessage() {}
+
+func (x *CurrencyBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyBalance.ProtoReflect.Descriptor instead.
+func (*CurrencyBalance) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CurrencyBalance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CurrencyBalance) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetAvailableBalance() float64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetGrossSettled() float64 {
+	if x != nil {
+		return x.GrossSettled
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetPlatformFees() float64 {
+	if x != nil {
+		return x.PlatformFees
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetNetSettled() float64 {
+	if x != nil {
+		return x.NetSettled
+	}
+	return 0
+}
+
+type GetProphetBalanceResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProphetId string                 `protobuf:"bytes,1,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
+	// Balance in the platform default currency, kept for older clients
+	Balance       float64            `protobuf:"fixed64,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Balances      []*CurrencyBalance `protobuf:"bytes,7,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProphetBalanceResponse) Reset() {
+	*x = GetProphetBalanceResponse{}
+	mi := &file_payment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProphetBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProphetBalanceResponse) ProtoMessage() {}
+
+func (x *GetProphetBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProphetBalanceResponse.ProtoReflect.Descriptor instead.
+func (*GetProphetBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetProphetBalanceResponse) GetProphetId() string {
+	if x != nil {
+		return x.ProphetId
+	}
+	return ""
+}
+
+func (x *GetProphetBalanceResponse) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *GetProphetBalanceResponse) GetBalances() []*CurrencyBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type ListPaymentsByProphetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProphetId     string                 `protobuf:"bytes,1,opt,name=prophet_id,json=prophetId,proto3" json:"prophet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsByProphetRequest) Reset() {
+	*x = ListPaymentsByProphetRequest{}
+	mi := &file_payment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsByProphetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsByProphetRequest) ProtoMessage() {}
+
+func (x *ListPaymentsByProphetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsByProphetRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentsByProphetRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListPaymentsByProphetRequest) GetProphetId() string {
+	if x != nil {
+		return x.ProphetId
+	}
+	return ""
+}
+
+type ListPaymentsByProphetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsByProphetResponse) Reset() {
+	*x = ListPaymentsByProphetResponse{}
+	mi := &file_payment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsByProphetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsByProphetResponse) ProtoMessage() {}
+
+func (x *ListPaymentsByProphetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsByProphetResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentsByProphetResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListPaymentsByProphetResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+var File_payment_proto protoreflect.FileDescriptor
+
+const file_payment_proto_rawDesc = "" +
+	"\n" +
+	"\rpayment.proto\x12\apayment\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa5\x04\n" +
+	"\aPayment\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"prophet_id\x18\x03 \x01(\tR\tprophetId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12.\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x16.payment.PaymentStatusR\x06status\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x12!\n" +
+	"\fprovider_ref\x18\a \x01(\tR\vproviderRef\x12=\n" +
+	"\fcreated_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedTime\x12=\n" +
+	"\fupdated_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vupdatedTime\x12\x1f\n" +
+	"\vcourse_type\x18\n" +
+	" \x01(\tR\n" +
+	"courseType\x12!\n" +
+	"\fgross_amount\x18\v \x01(\x01R\vgrossAmount\x12\x1d\n" +
+	"\n" +
+	"fee_amount\x18\f \x01(\x01R\tfeeAmount\x12\x1d\n" +
+	"\n" +
+	"net_amount\x18\r \x01(\x01R\tnetAmount\x12\x1e\n" +
+	"\vfee_rule_id\x18\x0e \x01(\tR\tfeeRuleId\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\"e\n" +
+	"\x14CreatePaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"C\n" +
+	"\x15CreatePaymentResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"2\n" +
+	"\x11GetPaymentRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\"@\n" +
+	"\x12GetPaymentResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"5\n" +
+	"\x18GetPaymentByOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"G\n" +
+	"\x19GetPaymentByOrderResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"9\n" +
+	"\x18GetProphetBalanceRequest\x12\x1d\n" +
+	"\n" +
+	"prophet_id\x18\x01 \x01(\tR\tprophetId\"\xdf\x01\n" +
+	"\x0fCurrencyBalance\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x01R\abalance\x12+\n" +
+	"\x11available_balance\x18\x03 \x01(\x01R\x10availableBalance\x12#\n" +
+	"\rgross_settled\x18\x04 \x01(\x01R\fgrossSettled\x12#\n" +
+	"\rplatform_fees\x18\x05 \x01(\x01R\fplatformFees\x12\x1f\n" +
+	"\vnet_settled\x18\x06 \x01(\x01R\n" +
+	"netSettled\"\x90\x01\n" +
+	"\x19GetProphetBalanceResponse\x12\x1d\n" +
+	"\n" +
+	"prophet_id\x18\x01 \x01(\tR\tprophetId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x01R\abalance\x124\n" +
+	"\bbalances\x18\a \x03(\v2\x18.payment.CurrencyBalanceR\bbalancesJ\x04\b\x03\x10\a\"=\n" +
+	"\x1cListPaymentsByProphetRequest\x12\x1d\n" +
+	"\n" +
+	"prophet_id\x18\x01 \x01(\tR\tprophetId\"M\n" +
+	"\x1dListPaymentsByProphetResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments*\xbd\x01\n" +
+	"\rPaymentStatus\x12\x1e\n" +
+	"\x1aPAYMENT_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PAYMENT_STATUS_PENDING\x10\x01\x12\x1c\n" +
+	"\x18PAYMENT_STATUS_COMPLETED\x10\x02\x12\x1a\n" +
+	"\x16PAYMENT_STATUS_SETTLED\x10\x03\x12\x19\n" +
+	"\x15PAYMENT_STATUS_FAILED\x10\x04\x12\x1b\n" +
+	"\x17PAYMENT_STATUS_REFUNDED\x10\x052\xc7\x03\n" +
+	"\x0ePaymentService\x12N\n" +
+	"\rCreatePayment\x12\x1d.payment.CreatePaymentRequest\x1a\x1e.payment.CreatePaymentResponse\x12E\n" +
+	"\n" +
+	"GetPayment\x12\x1a.payment.GetPaymentRequest\x1a\x1b.payment.GetPaymentResponse\x12Z\n" +
+	"\x11GetPaymentByOrder\x12!.payment.GetPaymentByOrderRequest\x1a\".payment.GetPaymentByOrderResponse\x12Z\n" +
+	"\x11GetProphetBalance\x12!.payment.GetProphetBalanceRequest\x1a\".payment.GetProphetBalanceResponse\x12f\n" +
+	"\x15ListPaymentsByProphet\x12%.payment.ListPaymentsByProphetRequest\x1a&.payment.ListPaymentsByProphetResponseB4Z2github.com/wnmay/horo/shared/proto/payment;paymentb\x06proto3"
+
+var (
+	file_payment_proto_rawDescOnce sync.Once
+	file_payment_proto_rawDescData []byte
+)
+
+func file_payment_proto_rawDescGZIP() []byte {
+	file_payment_proto_rawDescOnce.Do(func() {
+		file_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)))
+	})
+	return file_payment_proto_rawDescData
+}
+
+var file_payment_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_payment_proto_goTypes = []any{
+	(PaymentStatus)(0),                    // 0: payment.PaymentStatus
+	(*Payment)(nil),                       // 1: payment.Payment
+	(*CreatePaymentRequest)(nil),          // 2: payment.CreatePaymentRequest
+	(*CreatePaymentResponse)(nil),         // 3: payment.CreatePaymentResponse
+	(*GetPaymentRequest)(nil),             // 4: payment.GetPaymentRequest
+	(*GetPaymentResponse)(nil),            // 5: payment.GetPaymentResponse
+	(*GetPaymentByOrderRequest)(nil),      // 6: payment.GetPaymentByOrderRequest
+	(*GetPaymentByOrderResponse)(nil),     // 7: payment.GetPaymentByOrderResponse
+	(*GetProphetBalanceRequest)(nil),      // 8: payment.GetProphetBalanceRequest
+	(*CurrencyBalance)(nil),               // 9: payment.CurrencyBalance
+	(*GetProphetBalanceResponse)(nil),     // 10: payment.GetProphetBalanceResponse
+	(*ListPaymentsByProphetRequest)(nil),  // 11: payment.ListPaymentsByProphetRequest
+	(*ListPaymentsByProphetResponse)(nil), // 12: payment.ListPaymentsByProphetResponse
+	(*timestamppb.Timestamp)(nil),         // 13: google.protobuf.Timestamp
+}
+var file_payment_proto_depIdxs = []int32{
+	0,  // 0: payment.Payment.status:type_name -> payment.PaymentStatus
+	13, // 1: payment.Payment.created_time:type_name -> google.protobuf.Timestamp
+	13, // 2: payment.Payment.updated_time:type_name -> google.protobuf.Timestamp
+	1,  // 3: payment.CreatePaymentResponse.payment:type_name -> payment.Payment
+	1,  // 4: payment.GetPaymentResponse.payment:type_name -> payment.Payment
+	1,  // 5: payment.GetPaymentByOrderResponse.payment:type_name -> payment.Payment
+	9,  // 6: payment.GetProphetBalanceResponse.balances:type_name -> payment.CurrencyBalance
+	1,  // 7: payment.ListPaymentsByProphetResponse.payments:type_name -> payment.Payment
+	2,  // 8: payment.PaymentService.CreatePayment:input_type -> payment.CreatePaymentRequest
+	4,  // 9: payment.PaymentService.GetPayment:input_type -> payment.GetPaymentRequest
+	6,  // 10: payment.PaymentService.GetPaymentByOrder:input_type -> payment.GetPaymentByOrderRequest
+	8,  // 11: payment.PaymentService.GetProphetBalance:input_type -> payment.GetProphetBalanceRequest
+	11, // 12: payment.PaymentService.ListPaymentsByProphet:input_type -> payment.ListPaymentsByProphetRequest
+	3,  // 13: payment.PaymentService.CreatePayment:output_type -> payment.CreatePaymentResponse
+	5,  // 14: payment.PaymentService.GetPayment:output_type -> payment.GetPaymentResponse
+	7,  // 15: payment.PaymentService.GetPaymentByOrder:output_type -> payment.GetPaymentByOrderResponse
+	10, // 16: payment.PaymentService.GetProphetBalance:output_type -> payment.GetProphetBalanceResponse
+	12, // 17: payment.PaymentService.ListPaymentsByProphet:output_type -> payment.ListPaymentsByProphetResponse
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_payment_proto_init() }
+func file_payment_proto_init() {
+	if File_payment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_payment_proto_goTypes,
+		DependencyIndexes: file_payment_proto_depIdxs,
+		EnumInfos:         file_payment_proto_enumTypes,
+		MessageInfos:      file_payment_proto_msgTypes,
+	}.Build()
+	File_payment_proto = out.File
+	file_payment_proto_goTypes = nil
+	file_payment_proto_depIdxs = nil
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.10
+// 	protoc-gen-go v1.36.6
 // 	protoc        v6.33.0
 // source: proto/user_management.proto
 
@@ -27,6 +27,7 @@ const (
 	UserRole_USER_ROLE_UNKNOWN  UserRole = 0
 	UserRole_USER_ROLE_PROPHET  UserRole = 1
 	UserRole_USER_ROLE_CUSTOMER UserRole = 2
+	UserRole_USER_ROLE_ADMIN    UserRole = 3
 )
 
 // Enum value maps for UserRole.
@@ -35,11 +36,13 @@ var (
 		0: "USER_ROLE_UNKNOWN",
 		1: "USER_ROLE_PROPHET",
 		2: "USER_ROLE_CUSTOMER",
+		3: "USER_ROLE_ADMIN",
 	}
 	UserRole_value = map[string]int32{
 		"USER_ROLE_UNKNOWN":  0,
 		"USER_ROLE_PROPHET":  1,
 		"USER_ROLE_CUSTOMER": 2,
+		"USER_ROLE_ADMIN":    3,
 	}
 )
 
@@ -526,6 +529,291 @@ func (x *UserData) GetRole() UserRole {
 	return UserRole_USER_ROLE_UNKNOWN
 }
 
+// ProphetSummary is the part of a prophet's profile shown alongside their courses
+type ProphetSummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	AvatarUrl         string                 `protobuf:"bytes,3,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Verified          bool                   `protobuf:"varint,4,opt,name=verified,proto3" json:"verified,omitempty"`
+	Online            bool                   `protobuf:"varint,5,opt,name=online,proto3" json:"online,omitempty"`
+	Specialties       []string               `protobuf:"bytes,6,rep,name=specialties,proto3" json:"specialties,omitempty"` // course types, e.g. "love"
+	Languages         []string               `protobuf:"bytes,7,rep,name=languages,proto3" json:"languages,omitempty"`
+	YearsOfExperience int32                  `protobuf:"varint,8,opt,name=years_of_experience,json=yearsOfExperience,proto3" json:"years_of_experience,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ProphetSummary) Reset() {
+	*x = ProphetSummary{}
+	mi := &file_proto_user_management_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProphetSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProphetSummary) ProtoMessage() {}
+
+func (x *ProphetSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_management_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProphetSummary.ProtoReflect.Descriptor instead.
+func (*ProphetSummary) Descriptor() ([]byte, []int) {
+	return file_proto_user_management_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProphetSummary) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ProphetSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProphetSummary) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *ProphetSummary) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *ProphetSummary) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+func (x *ProphetSummary) GetSpecialties() []string {
+	if x != nil {
+		return x.Specialties
+	}
+	return nil
+}
+
+func (x *ProphetSummary) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *ProphetSummary) GetYearsOfExperience() int32 {
+	if x != nil {
+		return x.YearsOfExperience
+	}
+	return 0
+}
+
+type GetProphetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProphetProfileRequest) Reset() {
+	*x = GetProphetProfileRequest{}
+	mi := &file_proto_user_management_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProphetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProphetProfileRequest) ProtoMessage() {}
+
+func (x *GetProphetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_management_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProphetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProphetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_management_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetProphetProfileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetProphetProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Summary       *ProphetSummary        `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	Bio           string                 `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProphetProfileResponse) Reset() {
+	*x = GetProphetProfileResponse{}
+	mi := &file_proto_user_management_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProphetProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProphetProfileResponse) ProtoMessage() {}
+
+func (x *GetProphetProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_management_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProphetProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetProphetProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_management_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetProphetProfileResponse) GetSummary() *ProphetSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *GetProphetProfileResponse) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+type MapProphetSummariesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MapProphetSummariesRequest) Reset() {
+	*x = MapProphetSummariesRequest{}
+	mi := &file_proto_user_management_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapProphetSummariesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapProphetSummariesRequest) ProtoMessage() {}
+
+func (x *MapProphetSummariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_management_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapProphetSummariesRequest.ProtoReflect.Descriptor instead.
+func (*MapProphetSummariesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_management_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MapProphetSummariesRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type MapProphetSummariesResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Prophets      map[string]*ProphetSummary `protobuf:"bytes,1,rep,name=prophets,proto3" json:"prophets,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // keyed by user_id; unknown IDs are left out
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MapProphetSummariesResponse) Reset() {
+	*x = MapProphetSummariesResponse{}
+	mi := &file_proto_user_management_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapProphetSummariesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapProphetSummariesResponse) ProtoMessage() {}
+
+func (x *MapProphetSummariesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_management_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapProphetSummariesResponse.ProtoReflect.Descriptor instead.
+func (*MapProphetSummariesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_management_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MapProphetSummariesResponse) GetProphets() map[string]*ProphetSummary {
+	if x != nil {
+		return x.Prophets
+	}
+	return nil
+}
+
 var File_proto_user_management_proto protoreflect.FileDescriptor
 
 const file_proto_user_management_proto_rawDesc = "" +
@@ -559,16 +847,41 @@ const file_proto_user_management_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\v2\x18.usermanagement.UserDataR\x05value:\x028\x01\"L\n" +
 	"\bUserData\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
-	"\x04role\x18\x02 \x01(\x0e2\x18.usermanagement.UserRoleR\x04role*P\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x18.usermanagement.UserRoleR\x04role\"\x80\x02\n" +
+	"\x0eProphetSummary\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x03 \x01(\tR\tavatarUrl\x12\x1a\n" +
+	"\bverified\x18\x04 \x01(\bR\bverified\x12\x16\n" +
+	"\x06online\x18\x05 \x01(\bR\x06online\x12 \n" +
+	"\vspecialties\x18\x06 \x03(\tR\vspecialties\x12\x1c\n" +
+	"\tlanguages\x18\a \x03(\tR\tlanguages\x12.\n" +
+	"\x13years_of_experience\x18\b \x01(\x05R\x11yearsOfExperience\"3\n" +
+	"\x18GetProphetProfileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"g\n" +
+	"\x19GetProphetProfileResponse\x128\n" +
+	"\asummary\x18\x01 \x01(\v2\x1e.usermanagement.ProphetSummaryR\asummary\x12\x10\n" +
+	"\x03bio\x18\x02 \x01(\tR\x03bio\"7\n" +
+	"\x1aMapProphetSummariesRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\"\xd1\x01\n" +
+	"\x1bMapProphetSummariesResponse\x12U\n" +
+	"\bprophets\x18\x01 \x03(\v29.usermanagement.MapProphetSummariesResponse.ProphetsEntryR\bprophets\x1a[\n" +
+	"\rProphetsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x124\n" +
+	"\x05value\x18\x02 \x01(\v2\x1e.usermanagement.ProphetSummaryR\x05value:\x028\x01*e\n" +
 	"\bUserRole\x12\x15\n" +
 	"\x11USER_ROLE_UNKNOWN\x10\x00\x12\x15\n" +
 	"\x11USER_ROLE_PROPHET\x10\x01\x12\x16\n" +
-	"\x12USER_ROLE_CUSTOMER\x10\x022\xa0\x03\n" +
+	"\x12USER_ROLE_CUSTOMER\x10\x02\x12\x13\n" +
+	"\x0fUSER_ROLE_ADMIN\x10\x032\xfa\x04\n" +
 	"\vUserService\x12b\n" +
 	"\x0fMapProphetNames\x12&.usermanagement.MapProphetNamesRequest\x1a'.usermanagement.MapProphetNamesResponse\x12_\n" +
 	"\x0eGetProphetName\x12%.usermanagement.GetProphetNameRequest\x1a&.usermanagement.GetProphetNameResponse\x12q\n" +
 	"\x14GetProphetIdsByNames\x12+.usermanagement.GetProphetIdsByNamesRequest\x1a,.usermanagement.GetProphetIdsByNamesResponse\x12Y\n" +
-	"\fMapUserNames\x12#.usermanagement.MapUserNamesRequest\x1a$.usermanagement.MapUserNamesResponseBBZ@github.com/wnmay/horo/shared/proto/usermanagement;usermanagementb\x06proto3"
+	"\fMapUserNames\x12#.usermanagement.MapUserNamesRequest\x1a$.usermanagement.MapUserNamesResponse\x12h\n" +
+	"\x11GetProphetProfile\x12(.usermanagement.GetProphetProfileRequest\x1a).usermanagement.GetProphetProfileResponse\x12n\n" +
+	"\x13MapProphetSummaries\x12*.usermanagement.MapProphetSummariesRequest\x1a+.usermanagement.MapProphetSummariesResponseBBZ@github.com/wnmay/horo/shared/proto/usermanagement;usermanagementb\x06proto3"
 
 var (
 	file_proto_user_management_proto_rawDescOnce sync.Once
@@ -583,7 +896,7 @@ func file_proto_user_management_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_user_management_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_user_management_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_user_management_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_proto_user_management_proto_goTypes = []any{
 	(UserRole)(0),                        // 0: usermanagement.UserRole
 	(*MapProphetNamesRequest)(nil),       // 1: usermanagement.MapProphetNamesRequest
@@ -596,28 +909,41 @@ var file_proto_user_management_proto_goTypes = []any{
 	(*MapUserNamesRequest)(nil),          // 8: usermanagement.MapUserNamesRequest
 	(*MapUserNamesResponse)(nil),         // 9: usermanagement.MapUserNamesResponse
 	(*UserData)(nil),                     // 10: usermanagement.UserData
-	nil,                                  // 11: usermanagement.MapProphetNamesResponse.ProphetNamesEntry
-	nil,                                  // 12: usermanagement.MapUserNamesResponse.UsersEntry
+	(*ProphetSummary)(nil),               // 11: usermanagement.ProphetSummary
+	(*GetProphetProfileRequest)(nil),     // 12: usermanagement.GetProphetProfileRequest
+	(*GetProphetProfileResponse)(nil),    // 13: usermanagement.GetProphetProfileResponse
+	(*MapProphetSummariesRequest)(nil),   // 14: usermanagement.MapProphetSummariesRequest
+	(*MapProphetSummariesResponse)(nil),  // 15: usermanagement.MapProphetSummariesResponse
+	nil,                                  // 16: usermanagement.MapProphetNamesResponse.ProphetNamesEntry
+	nil,                                  // 17: usermanagement.MapUserNamesResponse.UsersEntry
+	nil,                                  // 18: usermanagement.MapProphetSummariesResponse.ProphetsEntry
 }
 var file_proto_user_management_proto_depIdxs = []int32{
-	11, // 0: usermanagement.MapProphetNamesResponse.prophet_names:type_name -> usermanagement.MapProphetNamesResponse.ProphetNamesEntry
+	16, // 0: usermanagement.MapProphetNamesResponse.prophet_names:type_name -> usermanagement.MapProphetNamesResponse.ProphetNamesEntry
 	3,  // 1: usermanagement.GetProphetIdsByNamesResponse.prophet_data:type_name -> usermanagement.ProphetData
-	12, // 2: usermanagement.MapUserNamesResponse.users:type_name -> usermanagement.MapUserNamesResponse.UsersEntry
+	17, // 2: usermanagement.MapUserNamesResponse.users:type_name -> usermanagement.MapUserNamesResponse.UsersEntry
 	0,  // 3: usermanagement.UserData.role:type_name -> usermanagement.UserRole
-	10, // 4: usermanagement.MapUserNamesResponse.UsersEntry.value:type_name -> usermanagement.UserData
-	1,  // 5: usermanagement.UserService.MapProphetNames:input_type -> usermanagement.MapProphetNamesRequest
-	4,  // 6: usermanagement.UserService.GetProphetName:input_type -> usermanagement.GetProphetNameRequest
-	6,  // 7: usermanagement.UserService.GetProphetIdsByNames:input_type -> usermanagement.GetProphetIdsByNamesRequest
-	8,  // 8: usermanagement.UserService.MapUserNames:input_type -> usermanagement.MapUserNamesRequest
-	2,  // 9: usermanagement.UserService.MapProphetNames:output_type -> usermanagement.MapProphetNamesResponse
-	5,  // 10: usermanagement.UserService.GetProphetName:output_type -> usermanagement.GetProphetNameResponse
-	7,  // 11: usermanagement.UserService.GetProphetIdsByNames:output_type -> usermanagement.GetProphetIdsByNamesResponse
-	9,  // 12: usermanagement.UserService.MapUserNames:output_type -> usermanagement.MapUserNamesResponse
-	9,  // [9:13] is the sub-list for method output_type
-	5,  // [5:9] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	11, // 4: usermanagement.GetProphetProfileResponse.summary:type_name -> usermanagement.ProphetSummary
+	18, // 5: usermanagement.MapProphetSummariesResponse.prophets:type_name -> usermanagement.MapProphetSummariesResponse.ProphetsEntry
+	10, // 6: usermanagement.MapUserNamesResponse.UsersEntry.value:type_name -> usermanagement.UserData
+	11, // 7: usermanagement.MapProphetSummariesResponse.ProphetsEntry.value:type_name -> usermanagement.ProphetSummary
+	1,  // 8: usermanagement.UserService.MapProphetNames:input_type -> usermanagement.MapProphetNamesRequest
+	4,  // 9: usermanagement.UserService.GetProphetName:input_type -> usermanagement.GetProphetNameRequest
+	6,  // 10: usermanagement.UserService.GetProphetIdsByNames:input_type -> usermanagement.GetProphetIdsByNamesRequest
+	8,  // 11: usermanagement.UserService.MapUserNames:input_type -> usermanagement.MapUserNamesRequest
+	12, // 12: usermanagement.UserService.GetProphetProfile:input_type -> usermanagement.GetProphetProfileRequest
+	14, // 13: usermanagement.UserService.MapProphetSummaries:input_type -> usermanagement.MapProphetSummariesRequest
+	2,  // 14: usermanagement.UserService.MapProphetNames:output_type -> usermanagement.MapProphetNamesResponse
+	5,  // 15: usermanagement.UserService.GetProphetName:output_type -> usermanagement.GetProphetNameResponse
+	7,  // 16: usermanagement.UserService.GetProphetIdsByNames:output_type -> usermanagement.GetProphetIdsByNamesResponse
+	9,  // 17: usermanagement.UserService.MapUserNames:output_type -> usermanagement.MapUserNamesResponse
+	13, // 18: usermanagement.UserService.GetProphetProfile:output_type -> usermanagement.GetProphetProfileResponse
+	15, // 19: usermanagement.UserService.MapProphetSummaries:output_type -> usermanagement.MapProphetSummariesResponse
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_management_proto_init() }
@@ -631,7 +957,7 @@ func file_proto_user_management_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_management_proto_rawDesc), len(file_proto_user_management_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   12,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
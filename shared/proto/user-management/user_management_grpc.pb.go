@@ -23,6 +23,8 @@ const (
 	UserService_GetProphetName_FullMethodName       = "/usermanagement.UserService/GetProphetName"
 	UserService_GetProphetIdsByNames_FullMethodName = "/usermanagement.UserService/GetProphetIdsByNames"
 	UserService_MapUserNames_FullMethodName         = "/usermanagement.UserService/MapUserNames"
+	UserService_GetProphetProfile_FullMethodName    = "/usermanagement.UserService/GetProphetProfile"
+	UserService_MapProphetSummaries_FullMethodName  = "/usermanagement.UserService/MapProphetSummaries"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -33,6 +35,8 @@ type UserServiceClient interface {
 	GetProphetName(ctx context.Context, in *GetProphetNameRequest, opts ...grpc.CallOption) (*GetProphetNameResponse, error)
 	GetProphetIdsByNames(ctx context.Context, in *GetProphetIdsByNamesRequest, opts ...grpc.CallOption) (*GetProphetIdsByNamesResponse, error)
 	MapUserNames(ctx context.Context, in *MapUserNamesRequest, opts ...grpc.CallOption) (*MapUserNamesResponse, error)
+	GetProphetProfile(ctx context.Context, in *GetProphetProfileRequest, opts ...grpc.CallOption) (*GetProphetProfileResponse, error)
+	MapProphetSummaries(ctx context.Context, in *MapProphetSummariesRequest, opts ...grpc.CallOption) (*MapProphetSummariesResponse, error)
 }
 
 type userServiceClient struct {
@@ -83,6 +87,26 @@ func (c *userServiceClient) MapUserNames(ctx context.Context, in *MapUserNamesRe
 	return out, nil
 }
 
+func (c *userServiceClient) GetProphetProfile(ctx context.Context, in *GetProphetProfileRequest, opts ...grpc.CallOption) (*GetProphetProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProphetProfileResponse)
+	err := c.cc.Invoke(ctx, UserService_GetProphetProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) MapProphetSummaries(ctx context.Context, in *MapProphetSummariesRequest, opts ...grpc.CallOption) (*MapProphetSummariesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MapProphetSummariesResponse)
+	err := c.cc.Invoke(ctx, UserService_MapProphetSummaries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -91,6 +115,8 @@ type UserServiceServer interface {
 	GetProphetName(context.Context, *GetProphetNameRequest) (*GetProphetNameResponse, error)
 	GetProphetIdsByNames(context.Context, *GetProphetIdsByNamesRequest) (*GetProphetIdsByNamesResponse, error)
 	MapUserNames(context.Context, *MapUserNamesRequest) (*MapUserNamesResponse, error)
+	GetProphetProfile(context.Context, *GetProphetProfileRequest) (*GetProphetProfileResponse, error)
+	MapProphetSummaries(context.Context, *MapProphetSummariesRequest) (*MapProphetSummariesResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -113,6 +139,12 @@ func (UnimplementedUserServiceServer) GetProphetIdsByNames(context.Context, *Get
 func (UnimplementedUserServiceServer) MapUserNames(context.Context, *MapUserNamesRequest) (*MapUserNamesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MapUserNames not implemented")
 }
+func (UnimplementedUserServiceServer) GetProphetProfile(context.Context, *GetProphetProfileRequest) (*GetProphetProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProphetProfile not implemented")
+}
+func (UnimplementedUserServiceServer) MapProphetSummaries(context.Context, *MapProphetSummariesRequest) (*MapProphetSummariesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MapProphetSummaries not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -206,6 +238,42 @@ func _UserService_MapUserNames_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetProphetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProphetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetProphetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetProphetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetProphetProfile(ctx, req.(*GetProphetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_MapProphetSummaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapProphetSummariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).MapProphetSummaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_MapProphetSummaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).MapProphetSummaries(ctx, req.(*MapProphetSummariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +297,14 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MapUserNames",
 			Handler:    _UserService_MapUserNames_Handler,
 		},
+		{
+			MethodName: "GetProphetProfile",
+			Handler:    _UserService_GetProphetProfile_Handler,
+		},
+		{
+			MethodName: "MapProphetSummaries",
+			Handler:    _UserService_MapProphetSummaries_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user_management.proto",
@@ -30,6 +30,12 @@ type OrderSummary = {
   amount: number;
 };
 
+type AvailableSlot = {
+  start_at: string;
+  end_at: string;
+  timezone: string;
+};
+
 type FetchOk =
   | { data: []; message: string }
   | { data: OrderSummary[]; message: string };
@@ -54,6 +60,8 @@ export default function RightPanel({
   const [paying, setPaying] = useState(false);
   const [prophetDone, setProphetDone] = useState(false);
   const [customerDone, setCutomerDone] = useState(false);
+  const [slots, setSlots] = useState<AvailableSlot[]>([]);
+  const [startTime, setStartTime] = useState("");
   const { messages, connected } = useWebSocketCtx();
 
   const refreshOrder = useCallback(async () => {
@@ -127,11 +135,38 @@ export default function RightPanel({
 
   const canCreate = useMemo(() => role === "customer" && !order, [role, order]);
 
+  // Orders book a session, so offer the course's open slots first
+  useEffect(() => {
+    if (!canCreate) return;
+    let alive = true;
+    (async () => {
+      try {
+        const res = await api.get(`/api/courses/${courseId}/slots`);
+        const open: AvailableSlot[] = res.data?.data ?? [];
+        if (!alive) return;
+        setSlots(open);
+        setStartTime((prev) =>
+          open.some((s) => s.start_at === prev) ? prev : open[0]?.start_at ?? ""
+        );
+      } catch (e: any) {
+        if (alive) setError(e?.message ?? "Failed to fetch available times");
+      }
+    })();
+    return () => {
+      alive = false;
+    };
+  }, [canCreate, courseId]);
+
   const onCreateOrder = useCallback(async () => {
+    if (!startTime) return;
     try {
       setCreating(true);
       setError(null);
-      const res = await api.post(`/api/orders`, { courseId, roomId });
+      const res = await api.post(`/api/orders`, {
+        courseId,
+        roomId,
+        startTime,
+      });
       const created: { data: OrderSummary } = res.data;
       setOrder(created.data);
     } catch (e: any) {
@@ -139,7 +174,7 @@ export default function RightPanel({
     } finally {
       setCreating(false);
     }
-  }, [courseId, roomId]);
+  }, [courseId, roomId, startTime]);
 
   const onPay = useCallback(async () => {
     if (!order) return;
@@ -327,13 +362,32 @@ export default function RightPanel({
               No order in this room yet.
             </p>
             {canCreate ? (
-              <button
-                onClick={onCreateOrder}
-                disabled={creating}
-                className="w-full rounded-xl px-4 py-3 font-semibold bg-blue-600 text-white hover:bg-blue-700 disabled:opacity-60"
-              >
-                {creating ? "Creating..." : "Create new order"}
-              </button>
+              <div className="space-y-3">
+                {slots.length > 0 ? (
+                  <select
+                    value={startTime}
+                    onChange={(e) => setStartTime(e.target.value)}
+                    className="w-full rounded-xl border border-gray-200 px-3 py-2 text-sm"
+                  >
+                    {slots.map((slot) => (
+                      <option key={slot.start_at} value={slot.start_at}>
+                        {new Date(slot.start_at).toLocaleString()}
+                      </option>
+                    ))}
+                  </select>
+                ) : (
+                  <p className="text-xs text-gray-500">
+                    No open times in the next 7 days.
+                  </p>
+                )}
+                <button
+                  onClick={onCreateOrder}
+                  disabled={creating || !startTime}
+                  className="w-full rounded-xl px-4 py-3 font-semibold bg-blue-600 text-white hover:bg-blue-700 disabled:opacity-60"
+                >
+                  {creating ? "Creating..." : "Create new order"}
+                </button>
+              </div>
             ) : (
               <p className="text-xs text-gray-500">
                 Waiting for customer to create an order.